@@ -5,10 +5,13 @@ imported directly from huaweicloud.
 package acceptance
 
 import (
+	"context"
 	"os"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
 	hwacceptance "github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/acceptance"
 
 	"github.com/FlexibleEngineCloud/terraform-provider-flexibleengine/flexibleengine"
@@ -47,8 +50,17 @@ var testAccProvider *schema.Provider
 
 func init() {
 	testAccProvider = flexibleengine.Provider()
-	// update TestAccProvider in huaweicloud acceptance package
-	hwacceptance.TestAccProvider = testAccProvider
+	// the resource checks in huaweicloud acceptance package expect the huaweicloud Config
+	// as the meta, so TestAccProvider holds the one embedded in the provider Config
+	hwacceptance.TestAccProvider = &schema.Provider{}
+	configure := testAccProvider.ConfigureContextFunc
+	testAccProvider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+		meta, diags := configure(ctx, d)
+		if c, ok := meta.(*flexibleengine.Config); ok {
+			hwacceptance.TestAccProvider.SetMeta(&c.Config)
+		}
+		return meta, diags
+	}
 
 	TestAccProviderFactories = map[string]func() (*schema.Provider, error){
 		"flexibleengine": func() (*schema.Provider, error) {
@@ -57,6 +69,11 @@ func init() {
 	}
 }
 
+// testAccConfig returns the huaweicloud Config embedded in the configured provider
+func testAccConfig() *config.Config {
+	return &testAccProvider.Meta().(*flexibleengine.Config).Config
+}
+
 func testAccPreCheckRequiredEnvVars(t *testing.T) {
 	if OS_REGION_NAME == "" {
 		t.Fatal("OS_REGION_NAME must be set for acceptance tests")
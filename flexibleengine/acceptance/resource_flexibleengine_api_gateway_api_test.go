@@ -9,7 +9,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 
 	"github.com/chnsz/golangsdk/openstack/apigw/shared/v1/apis"
-	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
 )
 
 func TestAccApiGatewayAPI_basic(t *testing.T) {
@@ -61,7 +60,7 @@ func TestAccApiGatewayAPI_basic(t *testing.T) {
 }
 
 func testAccCheckApiGatewayApiDestroy(s *terraform.State) error {
-	config := testAccProvider.Meta().(*config.Config)
+	config := testAccConfig()
 	apigwClient, err := config.ApiGatewayV1Client(OS_REGION_NAME)
 	if err != nil {
 		return fmt.Errorf("Error creating api gateway client: %s", err)
@@ -92,7 +91,7 @@ func testAccCheckApiGatewayApiExists(n string) resource.TestCheckFunc {
 			return fmt.Errorf("No ID is set")
 		}
 
-		config := testAccProvider.Meta().(*config.Config)
+		config := testAccConfig()
 		apigwClient, err := config.ApiGatewayV1Client(OS_REGION_NAME)
 		if err != nil {
 			return fmt.Errorf("Error creating api gateway client: %s", err)
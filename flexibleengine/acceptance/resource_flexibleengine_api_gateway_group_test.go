@@ -9,7 +9,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 
 	"github.com/chnsz/golangsdk/openstack/apigw/shared/v1/groups"
-	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
 )
 
 func TestAccApiGatewayGroup_basic(t *testing.T) {
@@ -48,7 +47,7 @@ func TestAccApiGatewayGroup_basic(t *testing.T) {
 }
 
 func testAccCheckApiGatewayGroupDestroy(s *terraform.State) error {
-	config := testAccProvider.Meta().(*config.Config)
+	config := testAccConfig()
 	apigwClient, err := config.ApiGatewayV1Client(OS_REGION_NAME)
 	if err != nil {
 		return fmt.Errorf("Error creating api gateway client: %s", err)
@@ -79,7 +78,7 @@ func testAccCheckApiGatewayGroupExists(n string) resource.TestCheckFunc {
 			return fmt.Errorf("No ID is set")
 		}
 
-		config := testAccProvider.Meta().(*config.Config)
+		config := testAccConfig()
 		apigwClient, err := config.ApiGatewayV1Client(OS_REGION_NAME)
 		if err != nil {
 			return fmt.Errorf("Error creating api gateway client: %s", err)
@@ -9,7 +9,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 
 	"github.com/chnsz/golangsdk/openstack/cbr/v3/policies"
-	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
 )
 
 func TestAccCBRV3Policy_basic(t *testing.T) {
@@ -147,7 +146,7 @@ func TestAccCBRV3Policy_replication(t *testing.T) {
 }
 
 func testAccCheckCBRPolicyDestroy(s *terraform.State) error {
-	conf := testAccProvider.Meta().(*config.Config)
+	conf := testAccConfig()
 	client, err := conf.CbrV3Client(OS_REGION_NAME)
 	if err != nil {
 		return fmt.Errorf("Error creating FlexibleEngine CBR client: %s", err)
@@ -178,7 +177,7 @@ func testAccCheckCBRPolicyExists(n string, policy *policies.Policy) resource.Tes
 			return fmt.Errorf("No ID is set")
 		}
 
-		conf := testAccProvider.Meta().(*config.Config)
+		conf := testAccConfig()
 		client, err := conf.CbrV3Client(OS_REGION_NAME)
 		if err != nil {
 			return fmt.Errorf("Error creating FlexibleEngine CBR client: %s", err)
@@ -8,7 +8,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 
 	"github.com/chnsz/golangsdk/openstack/cbr/v3/vaults"
-	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/acceptance"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/cbr"
 )
@@ -222,7 +221,7 @@ func TestAccCBRV3Vault_ReplicaTurbo(t *testing.T) {
 }
 
 func testAccCheckCBRVaultDestroy(s *terraform.State) error {
-	conf := testAccProvider.Meta().(*config.Config)
+	conf := testAccConfig()
 	client, err := conf.CbrV3Client(OS_REGION_NAME)
 	if err != nil {
 		return fmt.Errorf("Error creating FlexibleEngine CBR client: %s", err)
@@ -253,7 +252,7 @@ func testAccCheckCBRVaultExists(n string, vault *vaults.Vault) resource.TestChec
 			return fmt.Errorf("No ID is set")
 		}
 
-		conf := testAccProvider.Meta().(*config.Config)
+		conf := testAccConfig()
 		client, err := conf.CbrV3Client(OS_REGION_NAME)
 		if err != nil {
 			return fmt.Errorf("Error creating FlexibleEngine CBR client: %s", err)
@@ -70,7 +70,7 @@ func TestAccEnterpriseProject_basic(t *testing.T) {
 }
 
 func testAccCheckEnterpriseProjectDestroy(s *terraform.State) error {
-	conf := testAccProvider.Meta().(*config.Config)
+	conf := testAccConfig()
 	epsClient, err := conf.EnterpriseProjectClient(OS_REGION_NAME)
 	if err != nil {
 		return fmt.Errorf("Unable to create EPS client : %s", err)
@@ -9,7 +9,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 
 	model "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/rds/v3/model"
-	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/acceptance"
 )
 
@@ -49,7 +48,7 @@ func TestAccRdsAccount_basic(t *testing.T) {
 }
 
 func testAccCheckRdsAccountDestroy(s *terraform.State) error {
-	c := testAccProvider.Meta().(*config.Config)
+	c := testAccConfig()
 	client, err := c.HcRdsV3Client(OS_REGION_NAME)
 	if err != nil {
 		return fmt.Errorf("Error creating RDS client: %s", err)
@@ -108,7 +107,7 @@ func testAccCheckRdsAccountExists(n string) resource.TestCheckFunc {
 			return fmt.Errorf("no ID is set")
 		}
 
-		c := testAccProvider.Meta().(*config.Config)
+		c := testAccConfig()
 		client, err := c.HcRdsV3Client(OS_REGION_NAME)
 		if err != nil {
 			return fmt.Errorf("error creating RDS client: %s", err)
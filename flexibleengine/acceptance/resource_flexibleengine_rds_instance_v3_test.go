@@ -9,7 +9,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 
 	"github.com/chnsz/golangsdk/openstack/rds/v3/instances"
-	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/rds"
 )
 
@@ -67,7 +66,7 @@ func TestAccRdsInstanceV3_basic(t *testing.T) {
 
 func testAccCheckRdsInstanceV3Destroy(rsType string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		conf := testAccProvider.Meta().(*config.Config)
+		conf := testAccConfig()
 		client, err := conf.RdsV3Client(OS_REGION_NAME)
 		if err != nil {
 			return fmt.Errorf("Error creating flexibleengine rds client: %s", err)
@@ -103,7 +102,7 @@ func testAccCheckRdsInstanceV3Exists(name string, instance *instances.RdsInstanc
 			return fmt.Errorf("No ID is set")
 		}
 
-		conf := testAccProvider.Meta().(*config.Config)
+		conf := testAccConfig()
 		client, err := conf.RdsV3Client(OS_REGION_NAME)
 		if err != nil {
 			return fmt.Errorf("Error creating flexibleengine rds client: %s", err)
@@ -7,7 +7,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	model "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/tms/v1/model"
-	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
 )
 
 func TestAccTmsTag_basic(t *testing.T) {
@@ -31,7 +30,7 @@ func TestAccTmsTag_basic(t *testing.T) {
 }
 
 func testAccCheckTmsTagDestroy(s *terraform.State) error {
-	conf := testAccProvider.Meta().(*config.Config)
+	conf := testAccConfig()
 	client, err := conf.HcTmsV1Client(OS_REGION_NAME)
 	if err != nil {
 		return fmt.Errorf("Error creating TMS client: %s", err)
@@ -59,7 +58,7 @@ func testAccCheckTmsTagDestroy(s *terraform.State) error {
 
 func testAccCheckTmsTagExists(key, value string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		conf := testAccProvider.Meta().(*config.Config)
+		conf := testAccConfig()
 		client, err := conf.HcTmsV1Client(OS_REGION_NAME)
 		if err != nil {
 			return fmt.Errorf("Error creating TMS client: %s", err)
@@ -10,7 +10,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 
 	"github.com/chnsz/golangsdk/openstack/networking/v1/subnets"
-	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
 )
 
 func TestAccVpcSubnetV1_basic(t *testing.T) {
@@ -106,7 +105,7 @@ func TestAccVpcSubnetV1_ipv6(t *testing.T) {
 }
 
 func testAccCheckVpcSubnetV1Destroy(s *terraform.State) error {
-	conf := testAccProvider.Meta().(*config.Config)
+	conf := testAccConfig()
 	subnetClient, err := conf.NetworkingV1Client(OS_REGION_NAME)
 	if err != nil {
 		return fmt.Errorf("Error creating FlexibleEngine vpc client: %s", err)
@@ -136,7 +135,7 @@ func testAccCheckVpcSubnetV1Exists(n string, subnet *subnets.Subnet) resource.Te
 			return fmt.Errorf("No ID is set")
 		}
 
-		conf := testAccProvider.Meta().(*config.Config)
+		conf := testAccConfig()
 		subnetClient, err := conf.NetworkingV1Client(OS_REGION_NAME)
 		if err != nil {
 			return fmt.Errorf("Error creating FlexibleEngine Vpc client: %s", err)
@@ -0,0 +1,380 @@
+package flexibleengine
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chnsz/golangsdk"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const auditRedacted = "***"
+
+// auditResourceKey is the context key of the resource which sends the request
+type auditResourceKey struct{}
+
+// auditResource identifies the resource or data source which sends the request.
+// Terraform does not pass the configuration address to providers, so the resource
+// is identified by its type.
+type auditResource struct {
+	Type string
+}
+
+// auditRecord is written as a JSON line for each HTTP request
+type auditRecord struct {
+	Time           string            `json:"time"`
+	Method         string            `json:"method"`
+	URL            string            `json:"url"`
+	Service        string            `json:"service"`
+	Status         int               `json:"status,omitempty"`
+	Error          string            `json:"error,omitempty"`
+	LatencyMs      int64             `json:"latency_ms"`
+	RequestID      string            `json:"request_id,omitempty"`
+	ResourceType   string            `json:"resource_type,omitempty"`
+	RequestHeaders map[string]string `json:"request_headers,omitempty"`
+	RequestBody    interface{}       `json:"request_body,omitempty"`
+}
+
+var (
+	// the headers which hold credentials and are masked entirely
+	auditSecretHeaders = []string{
+		"X-Auth-Token", "X-Subject-Token", "X-Security-Token", "X-Obs-Security-Token",
+		"X-Amz-Security-Token", "Cookie", "Set-Cookie",
+	}
+	// the headers which identify the request in the responses
+	auditRequestIDHeaders = []string{
+		"X-Request-Id", "X-Openstack-Request-Id", "X-Compute-Request-Id", "X-Obs-Request-Id", "X-Amz-Request-Id",
+	}
+	// the normalized names of the fields and query parameters which are masked
+	auditSecretFields = []string{
+		"password", "passwd", "pwd", "adminpass", "secret", "token", "privatekey", "signature", "accesskeyid",
+	}
+
+	auditSignaturePattern = regexp.MustCompile(`(Signature=)[^,\s]+`)
+)
+
+// auditLogger is a http.RoundTripper which writes a JSON line with the redacted details
+// of every request to the audit log file.
+type auditLogger struct {
+	next http.RoundTripper
+
+	lock sync.Mutex
+	out  io.Writer
+
+	// the Configs of the resource types, see auditMeta
+	configsLock sync.Mutex
+	configs     map[string]*Config
+}
+
+var (
+	// the audit loggers which are closed when the provider shuts down
+	auditLoggers     []*auditLogger
+	auditLoggersLock sync.Mutex
+)
+
+func newAuditLogger(next http.RoundTripper, path string) (*auditLogger, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("Error opening the HTTP audit log %s: %s", path, err)
+	}
+
+	audit := &auditLogger{next: next, out: file, configs: make(map[string]*Config)}
+	auditLoggersLock.Lock()
+	auditLoggers = append(auditLoggers, audit)
+	auditLoggersLock.Unlock()
+	return audit, nil
+}
+
+// CloseAuditLogs closes the HTTP audit log files, it should be called when the provider shuts down.
+func CloseAuditLogs() {
+	auditLoggersLock.Lock()
+	defer auditLoggersLock.Unlock()
+
+	for _, audit := range auditLoggers {
+		audit.close()
+	}
+	auditLoggers = nil
+}
+
+func (a *auditLogger) close() {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if closer, ok := a.out.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			log.Printf("[WARN] failed to close the HTTP audit log: %s", err)
+		}
+	}
+	a.out = nil
+}
+
+// RoundTrip implements the http.RoundTripper interface
+func (a *auditLogger) RoundTrip(request *http.Request) (*http.Response, error) {
+	record := auditRecord{
+		Method:         request.Method,
+		URL:            redactAuditURL(request.URL),
+		Service:        endpointServiceName(request.URL.Host),
+		RequestHeaders: redactAuditHeaders(request.Header),
+		RequestBody:    auditRequestBody(request),
+	}
+	if resource, ok := request.Context().Value(auditResourceKey{}).(*auditResource); ok {
+		record.ResourceType = resource.Type
+	}
+
+	start := time.Now()
+	resp, err := a.next.RoundTrip(request)
+	record.Time = start.UTC().Format(time.RFC3339Nano)
+	record.LatencyMs = time.Since(start).Milliseconds()
+	if err != nil {
+		record.Error = err.Error()
+	} else {
+		record.Status = resp.StatusCode
+		for _, name := range auditRequestIDHeaders {
+			if id := resp.Header.Get(name); id != "" {
+				record.RequestID = id
+				break
+			}
+		}
+	}
+
+	a.write(&record)
+	return resp, err
+}
+
+func (a *auditLogger) write(record *auditRecord) {
+	line, err := json.Marshal(record)
+	if err != nil {
+		log.Printf("[WARN] failed to marshal the HTTP audit record: %s", err)
+		return
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if a.out == nil {
+		log.Printf("[WARN] the HTTP audit log is closed, the record is dropped: %s", line)
+		return
+	}
+	if _, err := a.out.Write(append(line, '\n')); err != nil {
+		log.Printf("[WARN] failed to write the HTTP audit log: %s", err)
+	}
+}
+
+// auditRequestBody returns the redacted JSON body of the request, the other bodies are not recorded
+func auditRequestBody(request *http.Request) interface{} {
+	if request.Body == nil || request.Body == http.NoBody ||
+		!strings.Contains(request.Header.Get("Content-Type"), "json") {
+		return nil
+	}
+
+	var data []byte
+	var err error
+	if request.GetBody != nil {
+		var body io.ReadCloser
+		if body, err = request.GetBody(); err == nil {
+			data, err = io.ReadAll(body)
+			body.Close()
+		}
+	} else {
+		data, err = io.ReadAll(request.Body)
+		request.Body.Close()
+		request.Body = io.NopCloser(bytes.NewReader(data))
+	}
+	if err != nil || len(data) == 0 {
+		return nil
+	}
+
+	var body interface{}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return nil
+	}
+	return redactAuditValue(body)
+}
+
+// redactAuditValue masks the values of the secret fields in the JSON value
+func redactAuditValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if isAuditSecret(key) {
+				v[key] = auditRedacted
+			} else {
+				v[key] = redactAuditValue(item)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactAuditValue(item)
+		}
+	}
+	return value
+}
+
+func redactAuditHeaders(header http.Header) map[string]string {
+	result := make(map[string]string, len(header))
+	for name := range header {
+		result[name] = header.Get(name)
+	}
+
+	for _, name := range auditSecretHeaders {
+		if _, ok := result[http.CanonicalHeaderKey(name)]; ok {
+			result[http.CanonicalHeaderKey(name)] = auditRedacted
+		}
+	}
+	if auth, ok := result["Authorization"]; ok {
+		result["Authorization"] = redactAuthorization(auth)
+	}
+	return result
+}
+
+// redactAuthorization masks the signature of the AK/SK authorization header, e.g.
+// "SDK-HMAC-SHA256 Credential=AK/20230113/sdk_request, SignedHeaders=host, Signature=xxx" or "OBS AK:xxx"
+func redactAuthorization(value string) string {
+	if auditSignaturePattern.MatchString(value) {
+		return auditSignaturePattern.ReplaceAllString(value, "${1}"+auditRedacted)
+	}
+	if i := strings.LastIndex(value, ":"); i > 0 {
+		return value[:i+1] + auditRedacted
+	}
+	return auditRedacted
+}
+
+func redactAuditURL(u *url.URL) string {
+	redacted := *u
+	if redacted.User != nil {
+		redacted.User = url.User(redacted.User.Username())
+	}
+
+	query := redacted.Query()
+	var masked bool
+	for key := range query {
+		if isAuditSecret(key) {
+			query.Set(key, auditRedacted)
+			masked = true
+		}
+	}
+	if masked {
+		redacted.RawQuery = query.Encode()
+	}
+	return redacted.String()
+}
+
+func isAuditSecret(name string) bool {
+	normalized := strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(name))
+	for _, field := range auditSecretFields {
+		if strings.Contains(normalized, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// wrapAuditResource binds the resource type to the requests which are sent by the
+// CRUD functions of the resource, it has no effect if the audit log is not enabled.
+func wrapAuditResource(resourceType string, r *schema.Resource) {
+	wrap := func(f func(*schema.ResourceData, interface{}) error) func(*schema.ResourceData, interface{}) error {
+		if f == nil {
+			return nil
+		}
+		return func(d *schema.ResourceData, meta interface{}) error {
+			return f(d, auditMeta(meta, resourceType))
+		}
+	}
+	wrapContext := func(f func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics) func(
+		context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+		if f == nil {
+			return nil
+		}
+		return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			return f(ctx, d, auditMeta(meta, resourceType))
+		}
+	}
+
+	r.Create, r.Read, r.Update, r.Delete = wrap(r.Create), wrap(r.Read), wrap(r.Update), wrap(r.Delete)
+	r.CreateContext, r.ReadContext = wrapContext(r.CreateContext), wrapContext(r.ReadContext)
+	r.UpdateContext, r.DeleteContext = wrapContext(r.UpdateContext), wrapContext(r.DeleteContext)
+	r.CreateWithoutTimeout, r.ReadWithoutTimeout = wrapContext(r.CreateWithoutTimeout), wrapContext(r.ReadWithoutTimeout)
+	r.UpdateWithoutTimeout, r.DeleteWithoutTimeout = wrapContext(r.UpdateWithoutTimeout), wrapContext(r.DeleteWithoutTimeout)
+}
+
+// auditMeta returns the Config of the resource type, whose clients bind the resource to the
+// context of the requests. The Config is derived from the provider Config once for each
+// resource type when the resource is used first, and shared by all the calls of the type.
+func auditMeta(meta interface{}, resourceType string) interface{} {
+	config, ok := meta.(*Config)
+	if !ok || config.audit == nil || config.auditResource != nil {
+		return meta
+	}
+
+	audit := config.audit
+	audit.configsLock.Lock()
+	defer audit.configsLock.Unlock()
+
+	if scoped, ok := audit.configs[resourceType]; ok {
+		return scoped
+	}
+	resource := &auditResource{Type: resourceType}
+	scoped := *config
+	scoped.auditResource = resource
+	scoped.HwClient = auditProviderClient(config.HwClient, resource)
+	scoped.DomainClient = auditProviderClient(config.DomainClient, resource)
+	audit.configs[resourceType] = &scoped
+	return &scoped
+}
+
+// auditHTTPClient returns the HTTP client which records the requests of the clients which are
+// not built on golangsdk, e.g. the S3 client, or nil if the audit log is not enabled.
+func auditHTTPClient(c *Config) *http.Client {
+	if c.audit == nil {
+		return nil
+	}
+
+	var rt http.RoundTripper = c.audit
+	if c.auditResource != nil {
+		rt = &auditResourceTagger{next: rt, resource: c.auditResource}
+	}
+	return &http.Client{Transport: rt}
+}
+
+func auditProviderClient(client *golangsdk.ProviderClient, resource *auditResource) *golangsdk.ProviderClient {
+	if client == nil {
+		return nil
+	}
+
+	copied := *client
+	copied.HTTPClient.Transport = &auditResourceTagger{next: client.HTTPClient.Transport, resource: resource}
+	if client.ReauthFunc != nil {
+		// the token is refreshed in the original client, copy it after the reauthentication
+		copied.ReauthFunc = func() error {
+			if err := client.ReauthFunc(); err != nil {
+				return err
+			}
+			copied.SetToken(client.Token())
+			return nil
+		}
+	}
+	return &copied
+}
+
+// auditResourceTagger binds the resource to the context of the requests
+type auditResourceTagger struct {
+	next     http.RoundTripper
+	resource *auditResource
+}
+
+// RoundTrip implements the http.RoundTripper interface
+func (t *auditResourceTagger) RoundTrip(request *http.Request) (*http.Response, error) {
+	ctx := context.WithValue(request.Context(), auditResourceKey{}, t.resource)
+	return t.next.RoundTrip(request.WithContext(ctx))
+}
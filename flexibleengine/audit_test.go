@@ -0,0 +1,185 @@
+package flexibleengine
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chnsz/golangsdk"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestAuditLogger_redaction(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-0001")
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	client := &http.Client{Transport: &auditLogger{next: http.DefaultTransport, out: &out}}
+
+	body := `{"server":{"name":"test","adminPass":"Secret123","metadata":{"admin_pass":"Secret123"}},` +
+		`"user":{"user_pwd":"Secret123","password":"Secret123"}}`
+	request, _ := http.NewRequest("POST", server.URL+"/v2.1/servers?token=abc&limit=1", strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-Auth-Token", "token-value")
+	request.Header.Set("Authorization", "SDK-HMAC-SHA256 Credential=AK/20230113/sdk_request, SignedHeaders=host, Signature=abcdef")
+
+	resp, err := client.Do(request)
+	if err != nil {
+		t.Fatalf("Error sending the request: %s", err)
+	}
+	resp.Body.Close()
+
+	line := out.String()
+	for _, secret := range []string{"Secret123", "token-value", "abcdef", "token=abc"} {
+		if strings.Contains(line, secret) {
+			t.Errorf("the audit record contains the secret %q: %s", secret, line)
+		}
+	}
+
+	var record auditRecord
+	if err := json.Unmarshal([]byte(line), &record); err != nil {
+		t.Fatalf("Error parsing the audit record: %s", err)
+	}
+	if record.Method != "POST" || record.Status != http.StatusAccepted || record.RequestID != "req-0001" {
+		t.Errorf("unexpected audit record: %s", line)
+	}
+	if record.RequestHeaders["Authorization"] != "SDK-HMAC-SHA256 Credential=AK/20230113/sdk_request, SignedHeaders=host, Signature=***" {
+		t.Errorf("unexpected authorization header: %s", record.RequestHeaders["Authorization"])
+	}
+	if !strings.Contains(record.URL, "limit=1") {
+		t.Errorf("expected the non-secret query parameters to be kept: %s", record.URL)
+	}
+}
+
+func TestRedactAuthorization(t *testing.T) {
+	cases := map[string]string{
+		"OBS AK:c2lnbmF0dXJl": "OBS AK:***",
+		"AWS AK:c2lnbmF0dXJl": "AWS AK:***",
+		"SDK-HMAC-SHA256 Credential=AK/20230113/sdk_request, Signature=x": "SDK-HMAC-SHA256 Credential=AK/20230113/sdk_request, Signature=***",
+		"Bearer token": "***",
+	}
+
+	for value, expected := range cases {
+		if redacted := redactAuthorization(value); redacted != expected {
+			t.Errorf("expected %q to be redacted as %q, got %q", value, expected, redacted)
+		}
+	}
+}
+
+func TestRedactAuditURL(t *testing.T) {
+	u, _ := url.Parse("https://bucket.oss.eu-west-0.example.com/object?AWSAccessKeyId=AK&Signature=xyz&Expires=1")
+	redacted := redactAuditURL(u)
+	if strings.Contains(redacted, "xyz") || !strings.Contains(redacted, "Expires=1") {
+		t.Errorf("unexpected redacted URL: %s", redacted)
+	}
+
+	u, _ = url.Parse("https://obs.example.com/?versioning")
+	if redacted := redactAuditURL(u); redacted != "https://obs.example.com/?versioning" {
+		t.Errorf("expected the URL to be unchanged, got %s", redacted)
+	}
+}
+
+func TestWrapAuditResource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "audit.log")
+	audit, err := newAuditLogger(http.DefaultTransport, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := &Config{audit: audit}
+	config.HwClient = &golangsdk.ProviderClient{HTTPClient: http.Client{Transport: audit}}
+
+	var metas []interface{}
+	r := &schema.Resource{
+		Schema: map[string]*schema.Schema{},
+		Read: func(d *schema.ResourceData, meta interface{}) error {
+			if meta.(*Config).audit != audit {
+				t.Errorf("expected the extended settings to be shared with the Config of the resource type")
+			}
+			metas = append(metas, meta)
+			resp, err := meta.(*Config).HwClient.HTTPClient.Get(server.URL)
+			if err == nil {
+				resp.Body.Close()
+			}
+			return err
+		},
+	}
+	wrapAuditResource("flexibleengine_test", r)
+
+	d := r.TestResourceData()
+	d.SetId("test-id")
+	for i := 0; i < 2; i++ {
+		if err := r.Read(d, config); err != nil {
+			t.Fatalf("Error reading the resource: %s", err)
+		}
+	}
+	// the Config of the resource type is derived once and shared by the calls
+	if metas[0] != metas[1] || metas[0] == config {
+		t.Errorf("expected the calls to share the Config of the resource type")
+	}
+	// requests sent outside of the resources are not bound to any resource
+	if resp, err := config.HwClient.HTTPClient.Get(server.URL); err == nil {
+		resp.Body.Close()
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var records []auditRecord
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record auditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("Error parsing the audit record: %s", err)
+		}
+		records = append(records, record)
+	}
+
+	if len(records) != 3 {
+		t.Fatalf("expected 3 audit records, got %d", len(records))
+	}
+	for _, record := range records[:2] {
+		if record.ResourceType != "flexibleengine_test" {
+			t.Errorf("unexpected resource type of the audit record: %#v", record)
+		}
+	}
+	if records[2].ResourceType != "" {
+		t.Errorf("expected no resource type in the audit record: %#v", records[2])
+	}
+}
+
+func TestCloseAuditLogs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	audit, err := newAuditLogger(http.DefaultTransport, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file := audit.out.(*os.File)
+	CloseAuditLogs()
+	if audit.out != nil {
+		t.Fatalf("expected the audit log to be closed")
+	}
+	if _, err := file.Write([]byte("{}\n")); err == nil {
+		t.Errorf("expected the audit log file to be closed")
+	}
+
+	// the records after the shutdown are dropped instead of failing the requests
+	audit.write(&auditRecord{Method: "GET"})
+}
@@ -20,11 +20,7 @@ import (
 func GetCredentials(c *Config) (*awsCredentials.Credentials, error) {
 	// build a chain provider, lazy-evaluated by aws-sdk
 	providers := []awsCredentials.Provider{
-		&awsCredentials.StaticProvider{Value: awsCredentials.Value{
-			AccessKeyID:     c.AccessKey,
-			SecretAccessKey: c.SecretKey,
-			SessionToken:    c.SecurityToken,
-		}},
+		&configCredentialsProvider{config: c},
 		&awsCredentials.EnvProvider{},
 		&awsCredentials.SharedCredentialsProvider{
 			Filename: "",
@@ -86,6 +82,34 @@ func GetCredentials(c *Config) (*awsCredentials.Credentials, error) {
 	return awsCredentials.NewChainCredentials(providers), nil
 }
 
+// configCredentialsProvider provides the current credentials of the provider Config, which
+// are read again for every request when they come from the credential process.
+type configCredentialsProvider struct {
+	config *Config
+}
+
+// Retrieve implements the awsCredentials.Provider interface
+func (p *configCredentialsProvider) Retrieve() (awsCredentials.Value, error) {
+	credentials, err := p.config.currentCredentials()
+	if err != nil {
+		return awsCredentials.Value{ProviderName: "ConfigProvider"}, err
+	}
+	if credentials.AccessKey == "" || credentials.SecretKey == "" {
+		return awsCredentials.Value{ProviderName: "ConfigProvider"}, awsCredentials.ErrStaticCredentialsEmpty
+	}
+	return awsCredentials.Value{
+		AccessKeyID:     credentials.AccessKey,
+		SecretAccessKey: credentials.SecretKey,
+		SessionToken:    credentials.SecurityToken,
+		ProviderName:    "ConfigProvider",
+	}, nil
+}
+
+// IsExpired implements the awsCredentials.Provider interface
+func (p *configCredentialsProvider) IsExpired() bool {
+	return p.config.credentials != nil && p.config.AssumeRoleAgency == ""
+}
+
 func setOptionalEndpoint(cfg *aws.Config) string {
 	endpoint := os.Getenv("AWS_METADATA_URL")
 	if endpoint != "" {
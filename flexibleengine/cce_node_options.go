@@ -0,0 +1,598 @@
+package flexibleengine
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/chnsz/golangsdk/openstack/cce/v3/clusters"
+	"github.com/chnsz/golangsdk/openstack/cce/v3/nodes"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// The typed options of CCE nodes and node pools: the runtime and storage are sent in the node
+// spec, the kubelet settings and docker_base_size are sent in the extendParam of the node spec.
+
+const (
+	// cceContainerdMinVersion is the lowest cluster version which supports the containerd runtime
+	cceContainerdMinVersion = "v1.23"
+	// cceNodeStorageMinVersion is the lowest cluster version which supports the storage selectors and groups
+	cceNodeStorageMinVersion = "v1.19"
+	// cceNodeKubeletMinVersion is the lowest cluster version which supports the kubelet settings
+	cceNodeKubeletMinVersion = "v1.19"
+)
+
+// cceNodeOptionKeys are the arguments of the typed node options
+var cceNodeOptionKeys = []string{"runtime", "storage", "kubelet", "docker_base_size"}
+
+// cceEvictionSignals are the eviction signals of kubelet which can be used in eviction_hard
+var cceEvictionSignals = []string{
+	"memory.available", "nodefs.available", "nodefs.inodesFree",
+	"imagefs.available", "imagefs.inodesFree", "pid.available",
+}
+
+// cceVirtualSpaceSizeRegexp matches the size of a virtual space, which is an integer percentage
+var cceVirtualSpaceSizeRegexp = regexp.MustCompile(`^[1-9][0-9]?%$|^100%$`)
+
+// cceNodeOptionsGetter reads the node options from a ResourceData or a ResourceDiff
+type cceNodeOptionsGetter interface {
+	Get(key string) interface{}
+}
+
+func resourceCCENodeRuntimeSchema(forceNew bool) *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		Computed: true,
+		ForceNew: forceNew,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Type:         schema.TypeString,
+					Required:     true,
+					ForceNew:     forceNew,
+					ValidateFunc: validation.StringInSlice([]string{"docker", "containerd"}, false),
+				},
+			},
+		},
+	}
+}
+
+func resourceCCENodeStorageSchema(forceNew bool) *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		Computed: true,
+		ForceNew: forceNew,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"selectors": {
+					Type:     schema.TypeList,
+					Required: true,
+					ForceNew: forceNew,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"name": {
+								Type:     schema.TypeString,
+								Required: true,
+								ForceNew: forceNew,
+							},
+							"type": {
+								Type:         schema.TypeString,
+								Optional:     true,
+								ForceNew:     forceNew,
+								Default:      "evs",
+								ValidateFunc: validation.StringInSlice([]string{"evs", "local"}, false),
+							},
+							"match_label_size": {
+								Type:     schema.TypeString,
+								Optional: true,
+								ForceNew: forceNew,
+							},
+							"match_label_volume_type": {
+								Type:     schema.TypeString,
+								Optional: true,
+								ForceNew: forceNew,
+							},
+							"match_label_metadata_encrypted": {
+								Type:         schema.TypeString,
+								Optional:     true,
+								ForceNew:     forceNew,
+								ValidateFunc: validation.StringInSlice([]string{"0", "1"}, false),
+							},
+							"match_label_metadata_cmkid": {
+								Type:     schema.TypeString,
+								Optional: true,
+								ForceNew: forceNew,
+							},
+							"match_label_count": {
+								Type:     schema.TypeString,
+								Optional: true,
+								ForceNew: forceNew,
+							},
+						},
+					},
+				},
+				"groups": {
+					Type:     schema.TypeList,
+					Required: true,
+					ForceNew: forceNew,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"name": {
+								Type:     schema.TypeString,
+								Required: true,
+								ForceNew: forceNew,
+							},
+							"cce_managed": {
+								Type:     schema.TypeBool,
+								Optional: true,
+								ForceNew: forceNew,
+							},
+							"selector_names": {
+								Type:     schema.TypeList,
+								Required: true,
+								ForceNew: forceNew,
+								Elem:     &schema.Schema{Type: schema.TypeString},
+							},
+							"virtual_spaces": {
+								Type:     schema.TypeList,
+								Required: true,
+								ForceNew: forceNew,
+								Elem: &schema.Resource{
+									Schema: map[string]*schema.Schema{
+										"name": {
+											Type:         schema.TypeString,
+											Required:     true,
+											ForceNew:     forceNew,
+											ValidateFunc: validation.StringInSlice([]string{"kubernetes", "runtime", "user"}, false),
+										},
+										"size": {
+											Type:         schema.TypeString,
+											Required:     true,
+											ForceNew:     forceNew,
+											ValidateFunc: validation.StringMatch(cceVirtualSpaceSizeRegexp, "size must be a percentage like 90%"),
+										},
+										"lvm_lv_type": {
+											Type:         schema.TypeString,
+											Optional:     true,
+											ForceNew:     forceNew,
+											ValidateFunc: validation.StringInSlice([]string{"linear", "striped"}, false),
+										},
+										"lvm_path": {
+											Type:     schema.TypeString,
+											Optional: true,
+											ForceNew: forceNew,
+										},
+										"runtime_lv_type": {
+											Type:         schema.TypeString,
+											Optional:     true,
+											ForceNew:     forceNew,
+											ValidateFunc: validation.StringInSlice([]string{"linear", "striped"}, false),
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func resourceCCENodeKubeletSchema(forceNew bool) *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		ForceNew: forceNew,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"cpu_manager_policy": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					ForceNew:     forceNew,
+					ValidateFunc: validation.StringInSlice([]string{"none", "static"}, false),
+				},
+				"eviction_hard": {
+					Type:     schema.TypeMap,
+					Optional: true,
+					ForceNew: forceNew,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+				},
+				"image_gc_high_threshold": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					ForceNew:     forceNew,
+					ValidateFunc: validation.IntBetween(1, 100),
+				},
+				"image_gc_low_threshold": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					ForceNew:     forceNew,
+					ValidateFunc: validation.IntBetween(1, 100),
+				},
+			},
+		},
+	}
+}
+
+func resourceCCENodeDockerBaseSizeSchema(forceNew bool) *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeInt,
+		Optional:     true,
+		ForceNew:     forceNew,
+		ValidateFunc: validation.IntBetween(10, 500),
+	}
+}
+
+func buildCCENodeRuntime(raw []interface{}) *nodes.RunTimeSpec {
+	if len(raw) == 0 || raw[0] == nil {
+		return nil
+	}
+	return &nodes.RunTimeSpec{
+		Name: raw[0].(map[string]interface{})["name"].(string),
+	}
+}
+
+func buildCCENodeStorage(raw []interface{}) *nodes.StorageSpec {
+	if len(raw) == 0 || raw[0] == nil {
+		return nil
+	}
+	rawMap := raw[0].(map[string]interface{})
+
+	selectorsRaw := rawMap["selectors"].([]interface{})
+	selectors := make([]nodes.StorageSelectorsSpec, len(selectorsRaw))
+	for i, v := range selectorsRaw {
+		selector := v.(map[string]interface{})
+		selectors[i] = nodes.StorageSelectorsSpec{
+			Name:        selector["name"].(string),
+			StorageType: selector["type"].(string),
+			MatchLabels: nodes.MatchLabelsSpec{
+				Size:              selector["match_label_size"].(string),
+				VolumeType:        selector["match_label_volume_type"].(string),
+				MetadataEncrypted: selector["match_label_metadata_encrypted"].(string),
+				MetadataCmkid:     selector["match_label_metadata_cmkid"].(string),
+				Count:             selector["match_label_count"].(string),
+			},
+		}
+	}
+
+	groupsRaw := rawMap["groups"].([]interface{})
+	groups := make([]nodes.StorageGroupsSpec, len(groupsRaw))
+	for i, v := range groupsRaw {
+		group := v.(map[string]interface{})
+		spacesRaw := group["virtual_spaces"].([]interface{})
+		spaces := make([]nodes.VirtualSpacesSpec, len(spacesRaw))
+		for j, s := range spacesRaw {
+			space := s.(map[string]interface{})
+			spaces[j] = nodes.VirtualSpacesSpec{
+				Name: space["name"].(string),
+				Size: space["size"].(string),
+			}
+			if lvType := space["lvm_lv_type"].(string); lvType != "" {
+				spaces[j].LVMConfig = &nodes.LVMConfigSpec{
+					LvType: lvType,
+					Path:   space["lvm_path"].(string),
+				}
+			}
+			if lvType := space["runtime_lv_type"].(string); lvType != "" {
+				spaces[j].RuntimeConfig = &nodes.RuntimeConfigSpec{
+					LvType: lvType,
+				}
+			}
+		}
+
+		groups[i] = nodes.StorageGroupsSpec{
+			Name:          group["name"].(string),
+			CceManaged:    group["cce_managed"].(bool),
+			SelectorNames: expandStringList(group["selector_names"].([]interface{})),
+			VirtualSpaces: spaces,
+		}
+	}
+
+	return &nodes.StorageSpec{
+		StorageSelectors: selectors,
+		StorageGroups:    groups,
+	}
+}
+
+// buildCCENodeKubeletParams returns the extendParam fields of the kubelet settings
+func buildCCENodeKubeletParams(raw []interface{}) map[string]interface{} {
+	params := make(map[string]interface{})
+	if len(raw) == 0 || raw[0] == nil {
+		return params
+	}
+	rawMap := raw[0].(map[string]interface{})
+
+	if v := rawMap["cpu_manager_policy"].(string); v != "" {
+		params["cpu-manager-policy"] = v
+	}
+	if v := rawMap["eviction_hard"].(map[string]interface{}); len(v) > 0 {
+		signals := make([]string, 0, len(v))
+		for signal := range v {
+			signals = append(signals, signal)
+		}
+		sort.Strings(signals)
+
+		thresholds := make([]string, len(signals))
+		for i, signal := range signals {
+			thresholds[i] = fmt.Sprintf("%s<%s", signal, v[signal].(string))
+		}
+		params["eviction-hard"] = strings.Join(thresholds, ",")
+	}
+	if v := rawMap["image_gc_high_threshold"].(int); v > 0 {
+		params["image-gc-high-threshold"] = v
+	}
+	if v := rawMap["image_gc_low_threshold"].(int); v > 0 {
+		params["image-gc-low-threshold"] = v
+	}
+	return params
+}
+
+// getCCEExtendParamInt returns the number in the extendParam, which is a number or a string
+func getCCEExtendParamInt(extendParam map[string]interface{}, key string) int {
+	switch v := extendParam[key].(type) {
+	case float64:
+		return int(v)
+	case int:
+		return v
+	case string:
+		n, _ := strconv.Atoi(v)
+		return n
+	default:
+		return 0
+	}
+}
+
+func flattenCCENodeRuntime(runtime *nodes.RunTimeSpec) []map[string]interface{} {
+	if runtime == nil || runtime.Name == "" {
+		return nil
+	}
+	return []map[string]interface{}{
+		{"name": runtime.Name},
+	}
+}
+
+func flattenCCENodeStorage(storage *nodes.StorageSpec) []map[string]interface{} {
+	if storage == nil {
+		return nil
+	}
+
+	selectors := make([]map[string]interface{}, len(storage.StorageSelectors))
+	for i, selector := range storage.StorageSelectors {
+		selectors[i] = map[string]interface{}{
+			"name":                           selector.Name,
+			"type":                           selector.StorageType,
+			"match_label_size":               selector.MatchLabels.Size,
+			"match_label_volume_type":        selector.MatchLabels.VolumeType,
+			"match_label_metadata_encrypted": selector.MatchLabels.MetadataEncrypted,
+			"match_label_metadata_cmkid":     selector.MatchLabels.MetadataCmkid,
+			"match_label_count":              selector.MatchLabels.Count,
+		}
+	}
+
+	groups := make([]map[string]interface{}, len(storage.StorageGroups))
+	for i, group := range storage.StorageGroups {
+		spaces := make([]map[string]interface{}, len(group.VirtualSpaces))
+		for j, space := range group.VirtualSpaces {
+			spaces[j] = map[string]interface{}{
+				"name": space.Name,
+				"size": space.Size,
+			}
+			if space.LVMConfig != nil {
+				spaces[j]["lvm_lv_type"] = space.LVMConfig.LvType
+				spaces[j]["lvm_path"] = space.LVMConfig.Path
+			}
+			if space.RuntimeConfig != nil {
+				spaces[j]["runtime_lv_type"] = space.RuntimeConfig.LvType
+			}
+		}
+
+		groups[i] = map[string]interface{}{
+			"name":           group.Name,
+			"cce_managed":    group.CceManaged,
+			"selector_names": group.SelectorNames,
+			"virtual_spaces": spaces,
+		}
+	}
+
+	return []map[string]interface{}{
+		{
+			"selectors": selectors,
+			"groups":    groups,
+		},
+	}
+}
+
+func flattenCCENodeKubelet(extendParam map[string]interface{}) []map[string]interface{} {
+	policy, _ := extendParam["cpu-manager-policy"].(string)
+	eviction, _ := extendParam["eviction-hard"].(string)
+	highThreshold := getCCEExtendParamInt(extendParam, "image-gc-high-threshold")
+	lowThreshold := getCCEExtendParamInt(extendParam, "image-gc-low-threshold")
+	if policy == "" && eviction == "" && highThreshold == 0 && lowThreshold == 0 {
+		return nil
+	}
+
+	evictionHard := make(map[string]string)
+	for _, threshold := range strings.Split(eviction, ",") {
+		if parts := strings.SplitN(threshold, "<", 2); len(parts) == 2 {
+			evictionHard[parts[0]] = parts[1]
+		}
+	}
+
+	return []map[string]interface{}{
+		{
+			"cpu_manager_policy":      policy,
+			"eviction_hard":           evictionHard,
+			"image_gc_high_threshold": highThreshold,
+			"image_gc_low_threshold":  lowThreshold,
+		},
+	}
+}
+
+// setCCENodeOptions saves the typed node options from the node spec
+func setCCENodeOptions(d *schema.ResourceData, spec nodes.Spec) error {
+	if err := d.Set("runtime", flattenCCENodeRuntime(spec.RunTime)); err != nil {
+		return fmt.Errorf("Error saving runtime: %s", err)
+	}
+	if err := d.Set("storage", flattenCCENodeStorage(spec.Storage)); err != nil {
+		return fmt.Errorf("Error saving storage: %s", err)
+	}
+	if err := d.Set("kubelet", flattenCCENodeKubelet(spec.ExtendParam)); err != nil {
+		return fmt.Errorf("Error saving kubelet: %s", err)
+	}
+	if err := d.Set("docker_base_size", getCCEExtendParamInt(spec.ExtendParam, "dockerBaseSize")); err != nil {
+		return fmt.Errorf("Error saving docker_base_size: %s", err)
+	}
+	return nil
+}
+
+// validateCCENodeOptions checks the typed node options against each other and against
+// the version of the cluster which the nodes belong to.
+func validateCCENodeOptions(d cceNodeOptionsGetter, clusterVersion string) error {
+	var runtime string
+	if raw := d.Get("runtime").([]interface{}); len(raw) > 0 && raw[0] != nil {
+		runtime = raw[0].(map[string]interface{})["name"].(string)
+	}
+	if runtime == "containerd" && compareCCEClusterVersion(clusterVersion, cceContainerdMinVersion) < 0 {
+		return fmt.Errorf("the containerd runtime is only supported by clusters of %s or later, "+
+			"but the cluster version is %s", cceContainerdMinVersion, clusterVersion)
+	}
+	if d.Get("docker_base_size").(int) > 0 && runtime == "containerd" {
+		return fmt.Errorf("docker_base_size can only be specified with the docker runtime")
+	}
+
+	if raw := d.Get("storage").([]interface{}); len(raw) > 0 && raw[0] != nil {
+		if compareCCEClusterVersion(clusterVersion, cceNodeStorageMinVersion) < 0 {
+			return fmt.Errorf("storage is only supported by clusters of %s or later, but the cluster version is %s",
+				cceNodeStorageMinVersion, clusterVersion)
+		}
+		if err := validateCCENodeStorage(buildCCENodeStorage(raw)); err != nil {
+			return err
+		}
+	}
+
+	if raw := d.Get("kubelet").([]interface{}); len(raw) > 0 && raw[0] != nil {
+		if compareCCEClusterVersion(clusterVersion, cceNodeKubeletMinVersion) < 0 {
+			return fmt.Errorf("kubelet is only supported by clusters of %s or later, but the cluster version is %s",
+				cceNodeKubeletMinVersion, clusterVersion)
+		}
+		kubelet := raw[0].(map[string]interface{})
+		high := kubelet["image_gc_high_threshold"].(int)
+		low := kubelet["image_gc_low_threshold"].(int)
+		if high > 0 && low > 0 && low >= high {
+			return fmt.Errorf("image_gc_low_threshold (%d) must be lower than image_gc_high_threshold (%d)", low, high)
+		}
+		for signal := range kubelet["eviction_hard"].(map[string]interface{}) {
+			if !strSliceContains(cceEvictionSignals, signal) {
+				return fmt.Errorf("invalid eviction signal %q in eviction_hard, valid signals are %s",
+					signal, strings.Join(cceEvictionSignals, ", "))
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateCCENodeStorage checks the references between the storage selectors and groups and
+// the virtual spaces of each group.
+func validateCCENodeStorage(storage *nodes.StorageSpec) error {
+	selectors := make(map[string]bool)
+	localSelectors := 0
+	for _, selector := range storage.StorageSelectors {
+		if selectors[selector.Name] {
+			return fmt.Errorf("the name of storage selector %s is duplicated", selector.Name)
+		}
+		selectors[selector.Name] = false
+		if selector.StorageType == "local" {
+			localSelectors++
+		}
+	}
+	if localSelectors > 1 {
+		return fmt.Errorf("only one storage selector of the local type is allowed")
+	}
+
+	managedGroups := 0
+	for _, group := range storage.StorageGroups {
+		for _, name := range group.SelectorNames {
+			used, ok := selectors[name]
+			if !ok {
+				return fmt.Errorf("storage group %s refers to an undefined selector %s", group.Name, name)
+			}
+			if used {
+				return fmt.Errorf("storage selector %s can only be used by one group", name)
+			}
+			selectors[name] = true
+		}
+
+		spaces := make(map[string]bool)
+		total := 0
+		for _, space := range group.VirtualSpaces {
+			spaces[space.Name] = true
+			if space.Name == "runtime" && space.RuntimeConfig == nil {
+				return fmt.Errorf("the runtime virtual space of storage group %s requires runtime_lv_type", group.Name)
+			}
+			if space.Name != "runtime" && space.LVMConfig == nil {
+				return fmt.Errorf("the %s virtual space of storage group %s requires lvm_lv_type", space.Name, group.Name)
+			}
+			size, _ := strconv.Atoi(strings.TrimSuffix(space.Size, "%"))
+			total += size
+		}
+		if total > 100 {
+			return fmt.Errorf("the virtual spaces of storage group %s exceed 100%%", group.Name)
+		}
+
+		if group.CceManaged {
+			managedGroups++
+			if !spaces["kubernetes"] || !spaces["runtime"] {
+				return fmt.Errorf("the CCE managed storage group %s requires the kubernetes and runtime virtual spaces",
+					group.Name)
+			}
+		}
+	}
+	if managedGroups != 1 {
+		return fmt.Errorf("exactly one storage group must be CCE managed, got %d", managedGroups)
+	}
+
+	return nil
+}
+
+// customizeDiffCCENodeOptions validates the changed node options when the cluster is known,
+// otherwise they are validated before the nodes are created.
+func customizeDiffCCENodeOptions(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() != "" && !d.HasChanges(cceNodeOptionKeys...) {
+		return nil
+	}
+	clusterID := d.Get("cluster_id").(string)
+	if !d.NewValueKnown("cluster_id") || clusterID == "" {
+		return nil
+	}
+
+	config := meta.(*Config)
+	region := config.Region
+	if v, ok := d.GetOk("region"); ok {
+		region = v.(string)
+	}
+	return checkCCENodeOptions(config, region, clusterID, d)
+}
+
+// checkCCENodeOptions validates the node options against the version of the cluster
+func checkCCENodeOptions(config *Config, region, clusterID string, d cceNodeOptionsGetter) error {
+	client, err := config.CceV3Client(region)
+	if err != nil {
+		return fmt.Errorf("Error creating flexibleengine CCE client: %s", err)
+	}
+	cluster, err := clusters.Get(client, clusterID).Extract()
+	if err != nil {
+		return fmt.Errorf("Error retrieving flexibleengine CCE cluster %s: %s", clusterID, err)
+	}
+	return validateCCENodeOptions(d, cluster.Spec.Version)
+}
@@ -4,10 +4,12 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
 
 	"github.com/chnsz/golangsdk"
 	"github.com/chnsz/golangsdk/openstack/blockstorage/v2/volumes"
 	bms "github.com/chnsz/golangsdk/openstack/bms/v2/servers"
+	"github.com/chnsz/golangsdk/openstack/compute/v2/extensions/bootfromvolume"
 	"github.com/chnsz/golangsdk/openstack/compute/v2/flavors"
 	"github.com/chnsz/golangsdk/openstack/compute/v2/servers"
 	"github.com/chnsz/golangsdk/openstack/ecs/v1/block_devices"
@@ -19,6 +21,13 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
+// the system metadata of the instance which encrypts the system disk with a KMS key
+const (
+	instanceSystemMetadataPrefix = "__system__"
+	instanceMetadataEncrypted    = "__system__encrypted"
+	instanceMetadataCmkID        = "__system__cmkid"
+)
+
 func resourceComputeSecGroupsV2(d *schema.ResourceData) []string {
 	rawSecGroups := d.Get("security_groups").(*schema.Set).List()
 	secgroups := make([]string, len(rawSecGroups))
@@ -36,6 +45,59 @@ func resourceComputeMetadataV2(d *schema.ResourceData) map[string]string {
 	return m
 }
 
+// flattenComputeMetadataV2 removes the system metadata which is not managed by the metadata argument
+func flattenComputeMetadataV2(metadata map[string]string) map[string]string {
+	m := make(map[string]string)
+	for key, val := range metadata {
+		if !strings.HasPrefix(key, instanceSystemMetadataPrefix) {
+			m[key] = val
+		}
+	}
+	return m
+}
+
+func hasInstanceSystemDiskOpts(d *schema.ResourceData) bool {
+	for _, key := range []string{"system_disk_size", "system_disk_type", "system_disk_kms_key_id"} {
+		if _, ok := d.GetOk(key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// resourceInstanceSystemDiskV2 builds the boot volume which is created from the image,
+// the size of the volume defaults to the minimum disk size of the image.
+func resourceInstanceSystemDiskV2(d *schema.ResourceData, client *golangsdk.ServiceClient,
+	imageID string) ([]bootfromvolume.BlockDevice, error) {
+	size, err := getInstanceSystemDiskSize(d, client, imageID)
+	if err != nil {
+		return nil, err
+	}
+
+	systemDisk := bootfromvolume.BlockDevice{
+		SourceType:          bootfromvolume.SourceImage,
+		UUID:                imageID,
+		DestinationType:     bootfromvolume.DestinationVolume,
+		BootIndex:           0,
+		DeleteOnTermination: true,
+		VolumeSize:          size,
+		VolumeType:          d.Get("system_disk_type").(string),
+	}
+	return []bootfromvolume.BlockDevice{systemDisk}, nil
+}
+
+func getInstanceSystemDiskSize(d *schema.ResourceData, client *golangsdk.ServiceClient, imageID string) (int, error) {
+	if size := d.Get("system_disk_size").(int); size != 0 {
+		return size, nil
+	}
+
+	image, err := images.Get(client, imageID).Extract()
+	if err != nil {
+		return 0, fmt.Errorf("Error retrieving FlexibleEngine image %s: %s", imageID, err)
+	}
+	return image.MinDiskGigabytes, nil
+}
+
 func checkBlockDeviceConfig(d *schema.ResourceData) error {
 	if vL, ok := d.GetOk("block_device"); ok {
 		for _, v := range vL.([]interface{}) {
@@ -320,30 +382,30 @@ func getInstanceNetworkInfo(
 	return networkInfo, nil
 }
 
-func flattenInstanceVolumeAttached(
-	d *schema.ResourceData, meta interface{}, server *cloudservers.CloudServer) ([]map[string]interface{}, string, error) {
+func flattenInstanceVolumeAttached(d *schema.ResourceData, meta interface{},
+	server *cloudservers.CloudServer) ([]map[string]interface{}, *volumes.Volume, error) {
 
 	config := meta.(*Config)
 	ecsClient, err := config.ComputeV1Client(GetRegion(d, config))
 	blockStorageClient, err := config.BlockStorageV2Client(GetRegion(d, config))
 	if err != nil {
-		return nil, "", fmt.Errorf("Error creating FlexibleEngine client: %s", err)
+		return nil, nil, fmt.Errorf("Error creating FlexibleEngine client: %s", err)
 	}
 
-	var systemDiskID string = ""
+	var systemDisk *volumes.Volume
 	bds := make([]map[string]interface{}, len(server.VolumeAttached))
 	for i, b := range server.VolumeAttached {
 		// retrieve volume `size` and `type`
 		volumeInfo, err := volumes.Get(blockStorageClient, b.ID).Extract()
 		if err != nil {
-			return nil, "", err
+			return nil, nil, err
 		}
 		log.Printf("[DEBUG] Retrieved volume %s: %#v", b.ID, volumeInfo)
 
 		// retrieve volume `pci_address`
 		va, err := block_devices.Get(ecsClient, server.ID, b.ID).Extract()
 		if err != nil {
-			return nil, "", err
+			return nil, nil, err
 		}
 		log.Printf("[DEBUG] Retrieved block device %s: %#v", b.ID, va)
 
@@ -356,8 +418,8 @@ func flattenInstanceVolumeAttached(
 		}
 
 		if va.BootIndex == 0 {
-			systemDiskID = b.ID
+			systemDisk = volumeInfo
 		}
 	}
-	return bds, systemDiskID, nil
+	return bds, systemDisk, nil
 }
@@ -1,19 +1,18 @@
 package flexibleengine
 
 import (
-	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
 	"log"
-	"math"
 	"net/http"
 	"strings"
-	"time"
 
 	"github.com/chnsz/golangsdk"
 	huaweisdk "github.com/chnsz/golangsdk/openstack"
 	"github.com/chnsz/golangsdk/openstack/identity/v3/domains"
+	"github.com/chnsz/golangsdk/openstack/obs"
+	iam_model "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/iam/v3/model"
 	huaweiconfig "github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/helper/pathorcontents"
 )
@@ -21,8 +20,36 @@ import (
 // PublicType indicates that an endpoint is "public" in service catalog
 const PublicType golangsdk.Availability = golangsdk.AvailabilityPublic
 
-// Config is the alias of huaweicloud Config
-type Config = huaweiconfig.Config
+const (
+	// defaultAssumeRoleDuration is the validity period (in seconds) of the temporary credentials
+	// obtained through the IAM agency, defaults to 24 hours.
+	defaultAssumeRoleDuration = 24 * 60 * 60
+)
+
+// Config is the provider configuration of FlexibleEngine, it embeds the huaweicloud Config
+// and holds the settings which are not covered by it.
+type Config struct {
+	huaweiconfig.Config
+
+	// AssumeRoleDuration is the validity period (in seconds) of the temporary credentials
+	AssumeRoleDuration int
+	// DefaultTags will be merged into the tags of every taggable resource
+	DefaultTags map[string]string
+	// RequestsPerSecond is the maximum rate of the requests per service, 0 means unlimited
+	RequestsPerSecond int
+	// MaxConcurrentRequests is the maximum number of concurrent requests per service, 0 means unlimited
+	MaxConcurrentRequests int
+	// AuditLogPath is the file which the HTTP audit records are written to
+	AuditLogPath string
+	// CredentialProcess is the command which outputs the temporary AK/SK
+	CredentialProcess string
+
+	throttle    *requestThrottle
+	audit       *auditLogger
+	credentials *credentialProcess
+	// the resource type which the clients bind to the requests, see auditMeta
+	auditResource *auditResource
+}
 
 // LoadAndValidate overwrites the the c.LoadAndValidate
 func LoadAndValidate(c *Config) error {
@@ -30,6 +57,22 @@ func LoadAndValidate(c *Config) error {
 		return fmt.Errorf("max_retries should be a positive value")
 	}
 
+	// the temporary AK/SK of the credential process take the place of the other credentials
+	if c.CredentialProcess != "" && c.credentials == nil {
+		source, err := newCredentialProcess(c.CredentialProcess)
+		if err != nil {
+			return err
+		}
+		// the clients are built with the initial credentials, which are signed again by
+		// credentialSigner once they are refreshed
+		c.credentials = source
+		c.AccessKey = source.credentials.AccessKey
+		c.SecretKey = source.credentials.SecretKey
+		c.SecurityToken = source.credentials.SecurityToken
+		c.Token = ""
+		c.Password = ""
+	}
+
 	err := fmt.Errorf("Must config token or aksk or username password to be authorized")
 
 	if c.Token != "" {
@@ -48,6 +91,17 @@ func LoadAndValidate(c *Config) error {
 		return err
 	}
 
+	// Assume role, the agency is assumed with AK/SK like huaweicloud
+	if c.AssumeRoleAgency != "" {
+		if c.AccessKey == "" || c.SecretKey == "" {
+			return fmt.Errorf("assume_role requires the AK/SK credentials")
+		}
+		err = buildClientByAgency(c)
+		if err != nil {
+			return err
+		}
+	}
+
 	if c.HwClient != nil && c.HwClient.ProjectID != "" {
 		c.RegionProjectIDMap[c.Region] = c.HwClient.ProjectID
 	}
@@ -108,28 +162,6 @@ func generateTLSConfig(c *Config) (*tls.Config, error) {
 	return config, nil
 }
 
-func retryBackoffFunc(ctx context.Context, respErr *golangsdk.ErrUnexpectedResponseCode, e error, retries uint) error {
-	minutes := int(math.Pow(2, float64(retries)))
-	if minutes > 30 { // won't wait more than 30 minutes
-		minutes = 30
-	}
-
-	log.Printf("[WARN] Received StatusTooManyRequests response code, try to sleep %d minutes", minutes)
-	sleep := time.Duration(minutes) * time.Minute
-
-	if ctx != nil {
-		select {
-		case <-time.After(sleep):
-		case <-ctx.Done():
-			return e
-		}
-	} else {
-		time.Sleep(sleep)
-	}
-
-	return nil
-}
-
 func genClient(c *Config, ao golangsdk.AuthOptionsProvider) (*golangsdk.ProviderClient, error) {
 	client, err := huaweisdk.NewClient(ao.GetIdentityEndpoint())
 	if err != nil {
@@ -139,17 +171,33 @@ func genClient(c *Config, ao golangsdk.AuthOptionsProvider) (*golangsdk.Provider
 	// Set UserAgent
 	client.UserAgent.Prepend("terraform-provider-flexibleengine")
 
-	config, err := generateTLSConfig(c)
-	if err != nil {
-		return nil, err
+	// the clients of the Config share the same throttle, so the limits apply to all requests of a service
+	if c.throttle == nil {
+		config, err := generateTLSConfig(c)
+		if err != nil {
+			return nil, err
+		}
+		var transport http.RoundTripper = &http.Transport{Proxy: http.ProxyFromEnvironment, TLSClientConfig: config}
+		if c.AuditLogPath != "" {
+			if c.audit, err = newAuditLogger(transport, c.AuditLogPath); err != nil {
+				return nil, err
+			}
+			transport = c.audit
+		}
+		c.throttle = newRequestThrottle(transport, c.RequestsPerSecond, c.MaxConcurrentRequests, c.MaxRetries)
+	}
+
+	var rt http.RoundTripper = &huaweiconfig.LogRoundTripper{
+		Rt:         c.throttle,
+		MaxRetries: c.MaxRetries,
+	}
+	// the credentials of the agency are used instead once the role is assumed
+	if c.credentials != nil && c.AssumeRoleAgency == "" {
+		rt = &credentialSigner{next: rt, source: c.credentials}
 	}
-	transport := &http.Transport{Proxy: http.ProxyFromEnvironment, TLSClientConfig: config}
 
 	client.HTTPClient = http.Client{
-		Transport: &huaweiconfig.LogRoundTripper{
-			Rt:         transport,
-			MaxRetries: c.MaxRetries,
-		},
+		Transport: rt,
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
 			if client.AKSKAuthOptions.AccessKey != "" {
 				golangsdk.ReSign(req, golangsdk.SignOptions{
@@ -161,11 +209,6 @@ func genClient(c *Config, ao golangsdk.AuthOptionsProvider) (*golangsdk.Provider
 		},
 	}
 
-	if c.MaxRetries > 0 {
-		client.MaxBackoffRetries = uint(c.MaxRetries)
-		client.RetryBackoffFunc = retryBackoffFunc
-	}
-
 	// Validate authentication normally.
 	err = huaweisdk.Authenticate(client, ao)
 	if err != nil {
@@ -215,6 +258,10 @@ func buildClientByAKSK(c *Config) error {
 		ao.IdentityEndpoint = c.IdentityEndpoint
 		ao.AccessKey = c.AccessKey
 		ao.SecretKey = c.SecretKey
+		if c.SecurityToken != "" {
+			ao.SecurityToken = c.SecurityToken
+			ao.WithUserCatalog = true
+		}
 	}
 	return genClients(c, pao, dao)
 }
@@ -243,6 +290,64 @@ func buildClientByPassword(c *Config) error {
 	return genClients(c, pao, dao)
 }
 
+// buildClientByAgency exchanges the AK/SK for temporary AK/SK and security token through the IAM
+// agency with the IAM client of huaweicloud, and then rebuilds all clients with the temporary
+// credentials. Unlike huaweicloud, the validity period of the credentials is configurable.
+func buildClientByAgency(c *Config) error {
+	client, err := c.HcIamV3Client(c.Region)
+	if err != nil {
+		return fmt.Errorf("Error creating FlexibleEngine IAM client: %s", err)
+	}
+
+	duration := int32(c.AssumeRoleDuration)
+	if duration == 0 {
+		duration = defaultAssumeRoleDuration
+	}
+	domainName := c.AssumeRoleDomain
+	request := &iam_model.CreateTemporaryAccessKeyByAgencyRequest{
+		Body: &iam_model.CreateTemporaryAccessKeyByAgencyRequestBody{
+			Auth: &iam_model.AgencyAuth{
+				Identity: &iam_model.AgencyAuthIdentity{
+					Methods: []iam_model.AgencyAuthIdentityMethods{
+						iam_model.GetAgencyAuthIdentityMethodsEnum().ASSUME_ROLE,
+					},
+					AssumeRole: &iam_model.IdentityAssumerole{
+						AgencyName:      c.AssumeRoleAgency,
+						DomainName:      &domainName,
+						DurationSeconds: &duration,
+					},
+				},
+			},
+		},
+	}
+	response, err := client.CreateTemporaryAccessKeyByAgency(request)
+	if err == nil && response.Credential == nil {
+		err = fmt.Errorf("the credential is missing in the response")
+	}
+	if err != nil {
+		return fmt.Errorf("Error creating temporary access key by agency %s: %s", c.AssumeRoleAgency, err)
+	}
+	log.Printf("[DEBUG] the temporary access key of agency %s will expire at %s",
+		c.AssumeRoleAgency, response.Credential.ExpiresAt)
+
+	c.AccessKey = response.Credential.Access
+	c.SecretKey = response.Credential.Secret
+	c.SecurityToken = response.Credential.Securitytoken
+
+	// the clients are scoped to the domain which the agency belongs to,
+	// so we should reload the domain and projects from the new credentials
+	if c.AssumeRoleDomain != c.DomainName {
+		c.DomainName = c.AssumeRoleDomain
+		c.DomainID = ""
+		c.TenantID = ""
+	}
+	for k := range c.RegionProjectIDMap {
+		delete(c.RegionProjectIDMap, k)
+	}
+
+	return buildClientByAKSK(c)
+}
+
 func genClients(c *Config, pao, dao golangsdk.AuthOptionsProvider) error {
 	client, err := genClient(c, pao)
 	if err != nil {
@@ -258,9 +363,18 @@ func genClients(c *Config, pao, dao golangsdk.AuthOptionsProvider) error {
 }
 
 func getDomainID(c *Config) (string, error) {
+	domain, err := getAuthDomain(c)
+	if err != nil {
+		return "", err
+	}
+	return domain.ID, nil
+}
+
+// getAuthDomain returns the domain which the credentials belong to
+func getAuthDomain(c *Config) (*domains.Domain, error) {
 	identityClient, err := c.IdentityV3Client(c.Region)
 	if err != nil {
-		return "", fmt.Errorf("Error creating FlexibleEngine identity client: %s", err)
+		return nil, fmt.Errorf("Error creating FlexibleEngine identity client: %s", err)
 	}
 
 	identityClient.ResourceBase += "auth/"
@@ -268,23 +382,70 @@ func getDomainID(c *Config) (string, error) {
 	// the List request does not support query options
 	allPages, err := domains.List(identityClient, nil).AllPages()
 	if err != nil {
-		return "", fmt.Errorf("List domains failed, err=%s", err)
+		return nil, fmt.Errorf("List domains failed, err=%s", err)
 	}
 
 	all, err := domains.ExtractDomains(allPages)
 	if err != nil {
-		return "", fmt.Errorf("Extract domains failed, err=%s", err)
+		return nil, fmt.Errorf("Extract domains failed, err=%s", err)
 	}
 
 	if len(all) == 0 {
-		return "", fmt.Errorf("domain was not found")
+		return nil, fmt.Errorf("domain was not found")
 	}
 
 	if c.DomainName != "" && c.DomainName != all[0].Name {
-		return "", fmt.Errorf("domain %s was not found, got %s", c.DomainName, all[0].Name)
+		return nil, fmt.Errorf("domain %s was not found, got %s", c.DomainName, all[0].Name)
+	}
+
+	return &all[0], nil
+}
+
+// currentCredentials returns the AK/SK and security token which the provider signs the requests
+// with, the ones of the credential process are refreshed if they are about to expire.
+// The credentials of the agency are used instead once the role is assumed.
+func (c *Config) currentCredentials() (processCredentials, error) {
+	if c.credentials != nil && c.AssumeRoleAgency == "" {
+		return c.credentials.get()
+	}
+	return processCredentials{
+		AccessKey:     c.AccessKey,
+		SecretKey:     c.SecretKey,
+		SecurityToken: c.SecurityToken,
+	}, nil
+}
+
+// ObjectStorageClient overrides the one of the huaweicloud Config to sign the OBS requests
+// with the current credentials.
+func (c *Config) ObjectStorageClient(region string) (*obs.ObsClient, error) {
+	client, err := c.Config.ObjectStorageClient(region)
+	if err != nil {
+		return nil, err
 	}
+	return refreshObsCredentials(c, client)
+}
 
-	return all[0].ID, nil
+// ObjectStorageClientWithSignature overrides the one of the huaweicloud Config to sign the OBS
+// requests with the current credentials.
+func (c *Config) ObjectStorageClientWithSignature(region string) (*obs.ObsClient, error) {
+	client, err := c.Config.ObjectStorageClientWithSignature(region)
+	if err != nil {
+		return nil, err
+	}
+	return refreshObsCredentials(c, client)
+}
+
+func refreshObsCredentials(c *Config, client *obs.ObsClient) (*obs.ObsClient, error) {
+	if c.credentials == nil {
+		return client, nil
+	}
+
+	credentials, err := c.currentCredentials()
+	if err != nil {
+		return nil, err
+	}
+	client.Refresh(credentials.AccessKey, credentials.SecretKey, credentials.SecurityToken)
+	return client, nil
 }
 
 func orchestrationV1Client(c *Config, region string) (*golangsdk.ServiceClient, error) {
@@ -325,6 +486,15 @@ func wafDedicatedv1Client(c *Config, region string) (*golangsdk.ServiceClient, e
 	return wafClient, nil
 }
 
+// dehV1Client is for Dedicated Host which is not in the service catalog,
+// the endpoint is the same as ECS except the service name and version.
+func dehV1Client(c *Config, region string) (*golangsdk.ServiceClient, error) {
+	return huaweisdk.NewDeHServiceV1(c.HwClient, golangsdk.EndpointOpts{
+		Region:       determineRegion(c, region),
+		Availability: PublicType,
+	})
+}
+
 func determineRegion(c *Config, region string) string {
 	// If a resource-level region was not specified, and a provider-level region was set,
 	// use the provider-level region.
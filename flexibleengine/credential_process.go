@@ -0,0 +1,147 @@
+package flexibleengine
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chnsz/golangsdk"
+)
+
+// credentialRefreshWindow is the period before the expiry in which the credentials are refreshed
+const credentialRefreshWindow = 5 * time.Minute
+
+// processCredentials is the JSON output of the credential process
+type processCredentials struct {
+	AccessKey     string    `json:"access_key"`
+	SecretKey     string    `json:"secret_key"`
+	SecurityToken string    `json:"security_token"`
+	ExpiresAt     time.Time `json:"expires_at"`
+}
+
+// credentialProcess runs an external command to get the temporary AK/SK, and runs it again
+// before the credentials expire. The live credentials are only kept here behind the lock,
+// the consumers get them through Config.currentCredentials.
+type credentialProcess struct {
+	command string
+
+	lock        sync.Mutex
+	credentials processCredentials
+}
+
+// newCredentialProcess runs the command to get the initial credentials
+func newCredentialProcess(command string) (*credentialProcess, error) {
+	p := &credentialProcess{command: command}
+	if err := p.refresh(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// get returns the credentials which are refreshed if they are about to expire
+func (p *credentialProcess) get() (processCredentials, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	expiresAt := p.credentials.ExpiresAt
+	if expiresAt.IsZero() || time.Until(expiresAt) > credentialRefreshWindow {
+		return p.credentials, nil
+	}
+
+	if err := p.refresh(); err != nil {
+		if time.Now().Before(expiresAt) {
+			log.Printf("[WARN] failed to refresh the credentials, the current ones will expire at %s: %s",
+				expiresAt.Format(time.RFC3339), err)
+			return p.credentials, nil
+		}
+		return p.credentials, err
+	}
+	return p.credentials, nil
+}
+
+// refresh runs the command and updates the credentials, the lock must be held by the caller
+// except for the initialization.
+func (p *credentialProcess) refresh() error {
+	credentials, err := runCredentialProcess(p.command)
+	if err != nil {
+		return err
+	}
+	log.Printf("[DEBUG] the credentials of the credential process will expire at %s",
+		credentials.ExpiresAt.Format(time.RFC3339))
+
+	p.credentials = *credentials
+	return nil
+}
+
+func runCredentialProcess(command string) (*processCredentials, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd.exe", "/C", command)
+	} else {
+		cmd = exec.Command("sh", "-c", command)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("Error running the credential process: %s: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	var credentials processCredentials
+	if err := json.Unmarshal(stdout.Bytes(), &credentials); err != nil {
+		return nil, fmt.Errorf("Error parsing the output of the credential process: %s", err)
+	}
+	if credentials.AccessKey == "" || credentials.SecretKey == "" {
+		return nil, fmt.Errorf("the output of the credential process must contain access_key and secret_key")
+	}
+	if !credentials.ExpiresAt.IsZero() && time.Until(credentials.ExpiresAt) <= credentialRefreshWindow {
+		return nil, fmt.Errorf("the credentials of the credential process expire too soon: %s",
+			credentials.ExpiresAt.Format(time.RFC3339))
+	}
+	return &credentials, nil
+}
+
+// credentialSigner signs the requests again if the credentials have been refreshed since
+// the clients were created. Only the requests signed with AK/SK by golangsdk are handled,
+// the OBS requests are signed by the OBS client with Config.currentCredentials.
+type credentialSigner struct {
+	next   http.RoundTripper
+	source *credentialProcess
+}
+
+// RoundTrip implements the http.RoundTripper interface
+func (s *credentialSigner) RoundTrip(request *http.Request) (*http.Response, error) {
+	auth := request.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, golangsdk.SignAlgorithmHMACSHA256) {
+		return s.next.RoundTrip(request)
+	}
+
+	credentials, err := s.source.get()
+	if err != nil {
+		return nil, err
+	}
+	if strings.Contains(auth, "Credential="+credentials.AccessKey+"/") &&
+		request.Header.Get("X-Security-Token") == credentials.SecurityToken {
+		return s.next.RoundTrip(request)
+	}
+
+	signed := request.Clone(request.Context())
+	if credentials.SecurityToken != "" {
+		signed.Header.Set("X-Security-Token", credentials.SecurityToken)
+	} else {
+		signed.Header.Del("X-Security-Token")
+	}
+	golangsdk.ReSign(signed, golangsdk.SignOptions{
+		AccessKey: credentials.AccessKey,
+		SecretKey: credentials.SecretKey,
+	})
+	return s.next.RoundTrip(signed)
+}
@@ -0,0 +1,121 @@
+package flexibleengine
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chnsz/golangsdk"
+)
+
+// testCredentialProcessCommand returns a command which outputs a new access key on each run
+func testCredentialProcessCommand(t *testing.T, expiresIn time.Duration) string {
+	dir := t.TempDir()
+	counter := filepath.Join(dir, "counter")
+	script := filepath.Join(dir, "credentials.sh")
+	content := fmt.Sprintf(`#!/bin/sh
+echo x >> %s
+n=$(wc -l < %s | tr -d ' ')
+echo '{"access_key":"AK'$n'","secret_key":"SK'$n'","security_token":"token'$n'","expires_at":"%s"}'
+`, counter, counter, time.Now().Add(expiresIn).UTC().Format(time.RFC3339))
+	if err := os.WriteFile(script, []byte(content), 0700); err != nil {
+		t.Fatal(err)
+	}
+	return script
+}
+
+func TestCredentialProcess_run(t *testing.T) {
+	if _, err := os.Stat("/bin/sh"); err != nil {
+		t.Skip("the test requires a POSIX shell")
+	}
+
+	source, err := newCredentialProcess(testCredentialProcessCommand(t, time.Hour))
+	if err != nil {
+		t.Fatalf("Error running the credential process: %s", err)
+	}
+	config := &Config{credentials: source}
+	config.AccessKey = "AK1"
+
+	// the credentials are kept until they are about to expire
+	credentials, _ := config.currentCredentials()
+	if credentials.AccessKey != "AK1" || credentials.SecretKey != "SK1" || credentials.SecurityToken != "token1" {
+		t.Fatalf("unexpected credentials: %s/%s/%s", credentials.AccessKey, credentials.SecretKey,
+			credentials.SecurityToken)
+	}
+	source.credentials.ExpiresAt = time.Now().Add(time.Minute)
+	if credentials, _ := config.currentCredentials(); credentials.AccessKey != "AK2" {
+		t.Fatalf("expected the credentials to be refreshed, got %s", credentials.AccessKey)
+	}
+	// the refreshed credentials are not written to the Config
+	if config.AccessKey != "AK1" {
+		t.Fatalf("expected the Config to be kept, got %s", config.AccessKey)
+	}
+}
+
+func TestCredentialProcess_errors(t *testing.T) {
+	if _, err := os.Stat("/bin/sh"); err != nil {
+		t.Skip("the test requires a POSIX shell")
+	}
+
+	cases := map[string]string{
+		"exit 1":                     "Error running the credential process",
+		"echo not-json":              "Error parsing the output",
+		`echo '{"access_key":"AK"}'`: "must contain access_key and secret_key",
+		`echo '{"access_key":"AK","secret_key":"SK","expires_at":"2020-01-01T00:00:00Z"}'`: "expire too soon",
+	}
+
+	for command, expected := range cases {
+		_, err := runCredentialProcess(command)
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected the error of %q to contain %q, got %v", command, expected, err)
+		}
+	}
+}
+
+func TestCredentialSigner(t *testing.T) {
+	if _, err := os.Stat("/bin/sh"); err != nil {
+		t.Skip("the test requires a POSIX shell")
+	}
+
+	var authorization, token string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		token = r.Header.Get("X-Security-Token")
+	}))
+	defer server.Close()
+
+	source, err := newCredentialProcess(testCredentialProcessCommand(t, time.Hour))
+	if err != nil {
+		t.Fatalf("Error running the credential process: %s", err)
+	}
+	client := &http.Client{Transport: &credentialSigner{next: http.DefaultTransport, source: source}}
+
+	send := func() {
+		request, _ := http.NewRequest("GET", server.URL, nil)
+		golangsdk.Sign(request, golangsdk.SignOptions{AccessKey: "AK1", SecretKey: "SK1"})
+		request.Header.Set("X-Security-Token", "token1")
+		resp, err := client.Do(request)
+		if err != nil {
+			t.Fatalf("Error sending the request: %s", err)
+		}
+		resp.Body.Close()
+	}
+
+	send()
+	if !strings.Contains(authorization, "Credential=AK1/") || token != "token1" {
+		t.Fatalf("expected the request to be sent as it is, got %s with token %s", authorization, token)
+	}
+
+	// the requests signed with the expired credentials are signed again
+	source.credentials.ExpiresAt = time.Now().Add(time.Minute)
+	send()
+	if !strings.Contains(authorization, "Credential=AK2/") || token != "token2" {
+		t.Fatalf("expected the request to be signed with the new credentials, got %s with token %s",
+			authorization, token)
+	}
+}
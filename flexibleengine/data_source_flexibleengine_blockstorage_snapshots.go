@@ -0,0 +1,149 @@
+package flexibleengine
+
+import (
+	"context"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/helper/hashcode"
+
+	"github.com/chnsz/golangsdk/openstack/blockstorage/v2/snapshots"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceBlockStorageSnapshots() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceBlockStorageSnapshotsRead,
+
+		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"volume_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"status": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"most_recent": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"ids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"snapshots": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"description": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"volume_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"size": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"status": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"metadata": {
+							Type:     schema.TypeMap,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"created_at": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceBlockStorageSnapshotsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	region := GetRegion(d, config)
+	blockStorageClient, err := config.BlockStorageV2Client(region)
+	if err != nil {
+		return diag.Errorf("Error creating FlexibleEngine block storage client: %s", err)
+	}
+
+	listOpts := snapshots.ListOpts{
+		VolumeID: d.Get("volume_id").(string),
+		Name:     d.Get("name").(string),
+		Status:   d.Get("status").(string),
+	}
+
+	pages, err := snapshots.List(blockStorageClient, listOpts).AllPages()
+	if err != nil {
+		return diag.Errorf("Unable to retrieve snapshots: %s", err)
+	}
+	allSnapshots, err := snapshots.ExtractSnapshots(pages)
+	if err != nil {
+		return diag.Errorf("Unable to extract snapshots: %s", err)
+	}
+	log.Printf("[DEBUG] fetching %d snapshots.", len(allSnapshots))
+
+	// the snapshots are sorted from the newest to the oldest
+	sort.SliceStable(allSnapshots, func(i, j int) bool {
+		return allSnapshots[i].CreatedAt.After(allSnapshots[j].CreatedAt)
+	})
+	if d.Get("most_recent").(bool) && len(allSnapshots) > 1 {
+		allSnapshots = allSnapshots[:1]
+	}
+
+	ids := make([]string, len(allSnapshots))
+	result := make([]map[string]interface{}, len(allSnapshots))
+	for i, s := range allSnapshots {
+		ids[i] = s.ID
+		result[i] = map[string]interface{}{
+			"id":          s.ID,
+			"name":        s.Name,
+			"description": s.Description,
+			"volume_id":   s.VolumeID,
+			"size":        s.Size,
+			"status":      s.Status,
+			"metadata":    s.Metadata,
+			"created_at":  s.CreatedAt.Format(time.RFC3339),
+		}
+	}
+
+	d.SetId(hashcode.Strings(ids))
+	d.Set("region", region)
+	d.Set("ids", ids)
+	if err := d.Set("snapshots", result); err != nil {
+		return diag.Errorf("Error setting snapshot list: %s", err)
+	}
+
+	return nil
+}
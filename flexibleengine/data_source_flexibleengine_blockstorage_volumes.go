@@ -0,0 +1,202 @@
+package flexibleengine
+
+import (
+	"context"
+	"log"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/helper/hashcode"
+
+	"github.com/chnsz/golangsdk/openstack/evs/v2/cloudvolumes"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceBlockStorageVolumes() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceBlockStorageVolumesRead,
+
+		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"availability_zone": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"status": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"tags": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"ids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"volumes": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"description": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"size": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"volume_type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"availability_zone": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"status": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"bootable": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"multiattach": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"encrypted": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"instance_ids": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"tags": {
+							Type:     schema.TypeMap,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"enterprise_project_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"created_at": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// filterBlockStorageVolumes returns the volumes which have all the tags in the filter
+func filterBlockStorageVolumes(d *schema.ResourceData, volumes []cloudvolumes.Volume) ([]cloudvolumes.Volume, []string) {
+	tagFilter := d.Get("tags").(map[string]interface{})
+	result := make([]cloudvolumes.Volume, 0, len(volumes))
+	ids := make([]string, 0, len(volumes))
+
+	for _, volume := range volumes {
+		matched := true
+		for key, value := range tagFilter {
+			if v, ok := volume.Tags[key]; !ok || v != value.(string) {
+				matched = false
+				break
+			}
+		}
+		if !matched {
+			continue
+		}
+		result = append(result, volume)
+		ids = append(ids, volume.ID)
+	}
+
+	return result, ids
+}
+
+func flattenBlockStorageVolumes(volumes []cloudvolumes.Volume) []map[string]interface{} {
+	result := make([]map[string]interface{}, len(volumes))
+	for i, volume := range volumes {
+		instanceIDs := make([]string, len(volume.Attachments))
+		for j, attachment := range volume.Attachments {
+			instanceIDs[j] = attachment.ServerID
+		}
+
+		result[i] = map[string]interface{}{
+			"id":                    volume.ID,
+			"name":                  volume.Name,
+			"description":           volume.Description,
+			"size":                  volume.Size,
+			"volume_type":           volume.VolumeType,
+			"availability_zone":     volume.AvailabilityZone,
+			"status":                volume.Status,
+			"bootable":              volume.Bootable == "true",
+			"multiattach":           volume.Multiattach,
+			"encrypted":             volume.Encrypted,
+			"instance_ids":          instanceIDs,
+			"tags":                  volume.Tags,
+			"enterprise_project_id": volume.EnterpriseProjectID,
+			"created_at":            volume.CreatedAt,
+		}
+	}
+	return result
+}
+
+func dataSourceBlockStorageVolumesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*Config)
+	region := GetRegion(d, config)
+	blockStorageClient, err := config.BlockStorageV2Client(region)
+	if err != nil {
+		return diag.Errorf("Error creating FlexibleEngine block storage client: %s", err)
+	}
+
+	listOpts := cloudvolumes.ListOpts{
+		Name:             d.Get("name").(string),
+		AvailabilityZone: d.Get("availability_zone").(string),
+		Status:           d.Get("status").(string),
+	}
+
+	pages, err := cloudvolumes.List(blockStorageClient, listOpts).AllPages()
+	if err != nil {
+		return diag.Errorf("Unable to retrieve volumes: %s", err)
+	}
+	allVolumes, err := cloudvolumes.ExtractVolumes(pages)
+	if err != nil {
+		return diag.Errorf("Unable to extract volumes: %s", err)
+	}
+
+	log.Printf("[DEBUG] fetching %d volumes.", len(allVolumes))
+	volumes, ids := filterBlockStorageVolumes(d, allVolumes)
+
+	d.SetId(hashcode.Strings(ids))
+	d.Set("region", region)
+	d.Set("ids", ids)
+	if err := d.Set("volumes", flattenBlockStorageVolumes(volumes)); err != nil {
+		return diag.Errorf("Error setting volume list: %s", err)
+	}
+
+	return nil
+}
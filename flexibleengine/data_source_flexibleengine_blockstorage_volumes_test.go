@@ -0,0 +1,54 @@
+package flexibleengine
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccBlockStorageVolumesDataSource_basic(t *testing.T) {
+	rName := fmt.Sprintf("tf-acc-test-%s", acctest.RandString(5))
+	dataSourceName := "data.flexibleengine_blockstorage_volumes.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBlockStorageVolumesDataSource_basic(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "ids.#", "1"),
+					resource.TestCheckResourceAttr(dataSourceName, "volumes.0.name", rName),
+					resource.TestCheckResourceAttr(dataSourceName, "volumes.0.size", "10"),
+					resource.TestCheckResourceAttr(dataSourceName, "volumes.0.tags.owner", rName),
+					resource.TestCheckResourceAttrPair(dataSourceName, "volumes.0.id",
+						"flexibleengine_blockstorage_volume_v2.volume_1", "id"),
+				),
+			},
+		},
+	})
+}
+
+func testAccBlockStorageVolumesDataSource_basic(rName string) string {
+	return fmt.Sprintf(`
+resource "flexibleengine_blockstorage_volume_v2" "volume_1" {
+  name              = "%[1]s"
+  size              = 10
+  availability_zone = "%[2]s"
+
+  tags = {
+    owner = "%[1]s"
+  }
+}
+
+data "flexibleengine_blockstorage_volumes" "test" {
+  availability_zone = flexibleengine_blockstorage_volume_v2.volume_1.availability_zone
+
+  tags = {
+    owner = "%[1]s"
+  }
+}
+`, rName, OS_AVAILABILITY_ZONE)
+}
@@ -0,0 +1,185 @@
+package flexibleengine
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/chnsz/golangsdk/openstack/identity/v3.0/credentials"
+	iamusers "github.com/chnsz/golangsdk/openstack/identity/v3.0/users"
+	"github.com/chnsz/golangsdk/openstack/identity/v3/projects"
+	"github.com/chnsz/golangsdk/openstack/identity/v3/tokens"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// the authentication methods of the provider
+const (
+	authMethodToken             = "token"
+	authMethodPassword          = "password"
+	authMethodAKSK              = "aksk"
+	authMethodTemporaryAKSK     = "temporary_aksk"
+	authMethodAssumeRole        = "assume_role"
+	authMethodCredentialProcess = "credential_process"
+)
+
+func dataSourceCallerIdentity() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceCallerIdentityRead,
+
+		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"domain_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"domain_name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"project_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"project_name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"user_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"user_name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"auth_method": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceCallerIdentityRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	region := GetRegion(d, config)
+
+	domainID, domainName := config.DomainID, config.DomainName
+	if domainID == "" || domainName == "" {
+		domain, err := getAuthDomain(config)
+		if err != nil {
+			return fmt.Errorf("Error retrieving the domain of the credentials: %s", err)
+		}
+		domainID, domainName = domain.ID, domain.Name
+	}
+
+	projectID, projectName, err := callerIdentityProject(config, region)
+	if err != nil {
+		return err
+	}
+
+	method := callerIdentityAuthMethod(config)
+	userID, userName := callerIdentityUser(config, method)
+
+	d.SetId(domainID)
+	d.Set("region", region)
+	d.Set("domain_id", domainID)
+	d.Set("domain_name", domainName)
+	d.Set("project_id", projectID)
+	d.Set("project_name", projectName)
+	d.Set("user_id", userID)
+	d.Set("user_name", userName)
+	d.Set("auth_method", method)
+
+	return nil
+}
+
+// callerIdentityProject returns the project of the region which the provider works in
+func callerIdentityProject(config *Config, region string) (string, string, error) {
+	if region != config.Region || config.HwClient.ProjectID == "" {
+		// the projects are named after the regions
+		projectID := config.GetProjectID(region)
+		if projectID == "" {
+			return "", "", fmt.Errorf("Error retrieving the project of region %s", region)
+		}
+		return projectID, region, nil
+	}
+
+	projectID := config.HwClient.ProjectID
+	if config.TenantName != "" {
+		return projectID, config.TenantName, nil
+	}
+
+	// only tenant_id is specified
+	identityClient, err := config.IdentityV3Client(region)
+	if err != nil {
+		return "", "", fmt.Errorf("Error creating FlexibleEngine identity client: %s", err)
+	}
+	project, err := projects.Get(identityClient, projectID).Extract()
+	if err != nil {
+		log.Printf("[WARN] failed to retrieve the name of project %s: %s", projectID, err)
+		return projectID, "", nil
+	}
+	return projectID, project.Name, nil
+}
+
+func callerIdentityAuthMethod(config *Config) string {
+	switch {
+	case config.AssumeRoleAgency != "":
+		return authMethodAssumeRole
+	case config.credentials != nil:
+		return authMethodCredentialProcess
+	case config.Token != "":
+		return authMethodToken
+	case config.AccessKey != "" && config.SecurityToken != "":
+		return authMethodTemporaryAKSK
+	case config.AccessKey != "":
+		return authMethodAKSK
+	default:
+		return authMethodPassword
+	}
+}
+
+// callerIdentityUser returns the ID and name of the IAM user. The temporary credentials
+// can not be traced back to the user, so the user is left empty in that case. The lookup
+// errors are not fatal as the user may not have the permissions of IAM.
+func callerIdentityUser(config *Config, method string) (string, string) {
+	switch method {
+	case authMethodToken, authMethodPassword:
+		identityClient, err := config.IdentityV3Client(config.Region)
+		if err != nil {
+			log.Printf("[WARN] Error creating FlexibleEngine identity client: %s", err)
+			return "", ""
+		}
+		user, err := tokens.Get(identityClient, config.HwClient.Token()).ExtractUser()
+		if err != nil {
+			log.Printf("[WARN] failed to retrieve the user of the token: %s", err)
+			return "", ""
+		}
+		return user.ID, user.Name
+
+	case authMethodAKSK:
+		iamClient, err := config.IAMV3Client(config.Region)
+		if err != nil {
+			log.Printf("[WARN] Error creating FlexibleEngine IAM client: %s", err)
+			return "", ""
+		}
+		credential, err := credentials.Get(iamClient, config.AccessKey).Extract()
+		if err != nil {
+			log.Printf("[WARN] failed to retrieve the user of the access key: %s", err)
+			return "", ""
+		}
+		user, err := iamusers.Get(iamClient, credential.UserID).Extract()
+		if err != nil {
+			log.Printf("[WARN] failed to retrieve the name of user %s: %s", credential.UserID, err)
+			return credential.UserID, ""
+		}
+		return user.ID, user.Name
+	}
+
+	log.Printf("[DEBUG] the user can not be retrieved with the %s authentication", method)
+	return "", ""
+}
@@ -0,0 +1,32 @@
+package flexibleengine
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccCallerIdentityDataSource_basic(t *testing.T) {
+	dataSourceName := "data.flexibleengine_caller_identity.current"
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: TestAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCallerIdentityDataSource_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(dataSourceName, "domain_id"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "domain_name"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "project_id"),
+					resource.TestCheckResourceAttr(dataSourceName, "region", OS_REGION_NAME),
+					resource.TestCheckResourceAttrSet(dataSourceName, "auth_method"),
+				),
+			},
+		},
+	})
+}
+
+const testAccCallerIdentityDataSource_basic = `
+data "flexibleengine_caller_identity" "current" {}
+`
@@ -245,12 +245,14 @@ func dataSourceComputeInstanceRead(d *schema.ResourceData, meta interface{}) err
 
 	// Set volume attached
 	if len(server.VolumeAttached) > 0 {
-		volumes, rootID, err := flattenInstanceVolumeAttached(d, config, &server)
+		volumes, systemDisk, err := flattenInstanceVolumeAttached(d, config, &server)
 		if err != nil {
 			return nil
 		}
 		d.Set("block_device", volumes)
-		d.Set("system_disk_id", rootID)
+		if systemDisk != nil {
+			d.Set("system_disk_id", systemDisk.ID)
+		}
 	}
 
 	// Set instance tags
@@ -0,0 +1,101 @@
+package flexibleengine
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/chnsz/golangsdk"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// dehHostType is the host type returned by the DeH API, it is not provided by the SDK
+type dehHostType struct {
+	HostType     string `json:"host_type"`
+	HostTypeName string `json:"host_type_name"`
+}
+
+func dataSourceDeHHostTypes() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceDeHHostTypesRead,
+
+		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"availability_zone": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"host_type": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"host_types": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"host_type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"host_type_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceDeHHostTypesRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	region := GetRegion(d, config)
+	dehClient, err := dehV1Client(config, region)
+	if err != nil {
+		return fmt.Errorf("Error creating FlexibleEngine DeH client: %s", err)
+	}
+
+	az := d.Get("availability_zone").(string)
+	url := dehClient.ServiceURL("availability-zone", az, "dedicated-host-types")
+
+	var r golangsdk.Result
+	_, r.Err = dehClient.Get(url, &r.Body, nil)
+	var allTypes []dehHostType
+	if r.Err == nil {
+		r.Err = r.ExtractIntoSlicePtr(&allTypes, "dedicated_host_types")
+	}
+	if r.Err != nil {
+		return fmt.Errorf("Error querying FlexibleEngine DeH host types in %s: %s", az, r.Err)
+	}
+	log.Printf("[DEBUG] Retrieved DeH host types in %s: %#v", az, allTypes)
+
+	hostType := d.Get("host_type").(string)
+	hostTypes := make([]map[string]interface{}, 0, len(allTypes))
+	for _, t := range allTypes {
+		if hostType != "" && t.HostType != hostType {
+			continue
+		}
+		hostTypes = append(hostTypes, map[string]interface{}{
+			"host_type":      t.HostType,
+			"host_type_name": t.HostTypeName,
+		})
+	}
+
+	if len(hostTypes) < 1 {
+		return fmt.Errorf("Your query returned no results. " +
+			"Please change your search criteria and try again.")
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", region, az))
+	d.Set("region", region)
+	if err := d.Set("host_types", hostTypes); err != nil {
+		return fmt.Errorf("Error setting DeH host types: %s", err)
+	}
+
+	return nil
+}
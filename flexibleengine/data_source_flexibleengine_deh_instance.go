@@ -0,0 +1,163 @@
+package flexibleengine
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/chnsz/golangsdk/openstack/common/tags"
+	"github.com/chnsz/golangsdk/openstack/deh/v1/hosts"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceDeHInstance() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceDeHInstanceRead,
+
+		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"availability_zone": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"host_type": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"status": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"auto_placement": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"host_type_name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"vcpus": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"cores": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"sockets": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"memory": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"available_vcpus": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"available_memory": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"available_flavors": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"instance_total": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"instance_uuids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"allocated_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"tags": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func dataSourceDeHInstanceRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	region := GetRegion(d, config)
+	dehClient, err := dehV1Client(config, region)
+	if err != nil {
+		return fmt.Errorf("Error creating FlexibleEngine DeH client: %s", err)
+	}
+
+	listOpts := hosts.ListOpts{
+		ID:       d.Get("id").(string),
+		Name:     d.Get("name").(string),
+		Az:       d.Get("availability_zone").(string),
+		HostType: d.Get("host_type").(string),
+		State:    d.Get("status").(string),
+	}
+	pages, err := hosts.List(dehClient, listOpts).AllPages()
+	if err != nil {
+		return fmt.Errorf("Error querying FlexibleEngine DeH instances: %s", err)
+	}
+	allHosts, err := hosts.ExtractHosts(pages)
+	if err != nil {
+		return fmt.Errorf("Error extracting FlexibleEngine DeH instances: %s", err)
+	}
+
+	if len(allHosts) < 1 {
+		return fmt.Errorf("Your query returned no results. " +
+			"Please change your search criteria and try again.")
+	}
+	if len(allHosts) > 1 {
+		return fmt.Errorf("Your query returned more than one result." +
+			" Please try a more specific search criteria")
+	}
+
+	host := allHosts[0]
+	log.Printf("[DEBUG] Retrieved DeH instance %s: %#v", host.ID, host)
+
+	d.SetId(host.ID)
+	d.Set("region", region)
+	d.Set("name", host.Name)
+	d.Set("availability_zone", host.Az)
+	d.Set("auto_placement", host.AutoPlacement)
+	d.Set("status", host.State)
+	d.Set("available_vcpus", host.AvailableVcpus)
+	d.Set("available_memory", host.AvailableMemory)
+	d.Set("instance_total", host.InstanceTotal)
+	d.Set("instance_uuids", host.InstanceUuids)
+	d.Set("allocated_at", host.AllocatedAt)
+	setDeHHostProperties(d, &host.HostProperties)
+
+	if resourceTags, err := tags.Get(dehClient, dehTagType, host.ID).Extract(); err == nil {
+		d.Set("tags", tagsToMap(resourceTags.Tags))
+	} else {
+		log.Printf("[WARN] Error fetching tags of DeH instance %s: %s", host.ID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package flexibleengine
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDeHInstanceDataSource_basic(t *testing.T) {
+	rName := fmt.Sprintf("tf-acc-test-%s", acctest.RandString(5))
+	dataSourceName := "data.flexibleengine_deh_instance.test"
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDeHInstanceDataSource_basic(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(dataSourceName, "id",
+						"flexibleengine_deh_instance.test", "id"),
+					resource.TestCheckResourceAttrPair(dataSourceName, "host_type",
+						"flexibleengine_deh_instance.test", "host_type"),
+					resource.TestCheckResourceAttr(dataSourceName, "name", rName),
+					resource.TestCheckResourceAttr(dataSourceName, "status", "available"),
+					resource.TestCheckResourceAttr(dataSourceName, "tags.foo", "bar"),
+					resource.TestCheckResourceAttrSet("data.flexibleengine_deh_host_types.test",
+						"host_types.0.host_type_name"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDeHInstanceDataSource_basic(rName string) string {
+	return fmt.Sprintf(`
+%s
+
+data "flexibleengine_deh_instance" "test" {
+  name = flexibleengine_deh_instance.test.name
+}
+`, testAccDeHInstance_basic(rName, "on", "bar"))
+}
@@ -0,0 +1,125 @@
+package flexibleengine
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/chnsz/golangsdk"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// the resource types which are supported by the enterprise project migration API
+const (
+	epsResourceTypeECS         = "ecs"
+	epsResourceTypeEVS         = "disk"
+	epsResourceTypeEIP         = "eip"
+	epsResourceTypeNAT         = "nat_gateways"
+	epsResourceTypeDCS         = "dcs"
+	epsResourceTypeDDS         = "dds"
+	epsResourceTypeCSS         = "css"
+	epsResourceTypeCCE         = "cce-cluster"
+	epsResourceTypeSFSTurbo    = "sfsturbo"
+	epsResourceTypeOBS         = "bucket"
+	epsResourceTypeELB         = "elb_lb"
+	defaultEnterpriseProjectID = "0"
+)
+
+// enterpriseProjectSchema returns the schema to use for enterprise_project_id.
+// The provider-level enterprise_project_id will be used if it was not specified.
+func enterpriseProjectSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+		Computed: true,
+	}
+}
+
+// setEnterpriseProjectDiff is a CustomizeDiffFunc which plans the migration to the provider-level
+// enterprise_project_id when the resource does not specify one and the provider default was changed.
+func setEnterpriseProjectDiff(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	rawConfig := d.GetRawConfig()
+	if d.Id() == "" || rawConfig.IsNull() || !rawConfig.GetAttr("enterprise_project_id").IsNull() {
+		return nil
+	}
+
+	epsID := meta.(*Config).EnterpriseProjectID
+	if epsID == "" {
+		epsID = defaultEnterpriseProjectID
+	}
+	current := d.Get("enterprise_project_id").(string)
+	if current == "" {
+		current = defaultEnterpriseProjectID
+	}
+	if epsID == current {
+		return nil
+	}
+
+	return d.SetNew("enterprise_project_id", epsID)
+}
+
+// setTagsAllAndEnterpriseProjectDiff is a CustomizeDiffFunc which calls both setTagsAllDiff and
+// setEnterpriseProjectDiff, it is used by the taggable resources which support enterprise projects.
+func setTagsAllAndEnterpriseProjectDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if err := setEnterpriseProjectDiff(ctx, d, meta); err != nil {
+		return err
+	}
+	return setTagsAllDiff(ctx, d, meta)
+}
+
+type migrateResourceOpts struct {
+	ResourceType string `json:"resource_type" required:"true"`
+	ResourceID   string `json:"resource_id" required:"true"`
+	RegionID     string `json:"region_id,omitempty"`
+	ProjectID    string `json:"project_id,omitempty"`
+	Associated   bool   `json:"associated"`
+}
+
+// migrateEnterpriseProject moves the resource to the target enterprise project in place.
+// The project ID will not be sent for the global resources, such as OBS buckets.
+func migrateEnterpriseProject(config *Config, region, epsID, resourceType, resourceID string, global bool) error {
+	if epsID == "" {
+		epsID = defaultEnterpriseProjectID
+	}
+
+	client, err := config.EnterpriseProjectClient(region)
+	if err != nil {
+		return fmt.Errorf("Error creating FlexibleEngine EPS client: %s", err)
+	}
+
+	opts := migrateResourceOpts{
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		RegionID:     region,
+		Associated:   true,
+	}
+	if !global {
+		opts.ProjectID = config.GetProjectID(region)
+	}
+
+	reqBody, err := golangsdk.BuildRequestBody(opts, "")
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[DEBUG] migrating %s %s to enterprise project %s: %#v", resourceType, resourceID, epsID, reqBody)
+	url := client.ServiceURL("enterprise-projects", epsID, "resources-migrate")
+	_, err = client.Post(url, reqBody, nil, &golangsdk.RequestOpts{
+		OkCodes: []int{204},
+	})
+	if err != nil {
+		return fmt.Errorf("Error migrating %s %s to enterprise project %s: %s", resourceType, resourceID, epsID, err)
+	}
+
+	return nil
+}
+
+// updateEnterpriseProject is a helper to migrate a resource when enterprise_project_id was changed.
+func updateEnterpriseProject(d *schema.ResourceData, config *Config, resourceType, resourceID string, global bool) error {
+	if !d.HasChange("enterprise_project_id") {
+		return nil
+	}
+
+	return migrateEnterpriseProject(config, GetRegion(d, config), d.Get("enterprise_project_id").(string),
+		resourceType, resourceID, global)
+}
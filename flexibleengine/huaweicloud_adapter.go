@@ -0,0 +1,106 @@
+package flexibleengine
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// huaweiMeta returns the huaweicloud Config embedded in the provider meta, the meta is
+// returned as it is when the provider is not configured.
+func huaweiMeta(meta interface{}) interface{} {
+	if c, ok := meta.(*Config); ok {
+		return &c.Config
+	}
+	return meta
+}
+
+// huaweiResource adapts the resources and data sources of the huaweicloud packages, which
+// expect the huaweicloud Config as the meta, to the provider.
+func huaweiResource(r *schema.Resource) *schema.Resource {
+	if create := r.Create; create != nil {
+		r.Create = func(d *schema.ResourceData, meta interface{}) error {
+			return create(d, huaweiMeta(meta))
+		}
+	}
+	if read := r.Read; read != nil {
+		r.Read = func(d *schema.ResourceData, meta interface{}) error {
+			return read(d, huaweiMeta(meta))
+		}
+	}
+	if update := r.Update; update != nil {
+		r.Update = func(d *schema.ResourceData, meta interface{}) error {
+			return update(d, huaweiMeta(meta))
+		}
+	}
+	if del := r.Delete; del != nil {
+		r.Delete = func(d *schema.ResourceData, meta interface{}) error {
+			return del(d, huaweiMeta(meta))
+		}
+	}
+	if exists := r.Exists; exists != nil {
+		r.Exists = func(d *schema.ResourceData, meta interface{}) (bool, error) {
+			return exists(d, huaweiMeta(meta))
+		}
+	}
+
+	r.CreateContext = huaweiContextFunc(r.CreateContext)
+	r.ReadContext = huaweiContextFunc(r.ReadContext)
+	r.UpdateContext = huaweiContextFunc(r.UpdateContext)
+	r.DeleteContext = huaweiContextFunc(r.DeleteContext)
+	r.CreateWithoutTimeout = huaweiContextFunc(r.CreateWithoutTimeout)
+	r.ReadWithoutTimeout = huaweiContextFunc(r.ReadWithoutTimeout)
+	r.UpdateWithoutTimeout = huaweiContextFunc(r.UpdateWithoutTimeout)
+	r.DeleteWithoutTimeout = huaweiContextFunc(r.DeleteWithoutTimeout)
+
+	if customizeDiff := r.CustomizeDiff; customizeDiff != nil {
+		r.CustomizeDiff = func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			return customizeDiff(ctx, d, huaweiMeta(meta))
+		}
+	}
+
+	if migrate := r.MigrateState; migrate != nil {
+		r.MigrateState = func(v int, is *terraform.InstanceState, meta interface{}) (*terraform.InstanceState, error) {
+			return migrate(v, is, huaweiMeta(meta))
+		}
+	}
+	for i := range r.StateUpgraders {
+		if upgrade := r.StateUpgraders[i].Upgrade; upgrade != nil {
+			r.StateUpgraders[i].Upgrade = func(ctx context.Context, rawState map[string]interface{},
+				meta interface{}) (map[string]interface{}, error) {
+				return upgrade(ctx, rawState, huaweiMeta(meta))
+			}
+		}
+	}
+
+	if r.Importer != nil {
+		importer := *r.Importer
+		if state := importer.State; state != nil {
+			importer.State = func(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+				return state(d, huaweiMeta(meta))
+			}
+		}
+		if stateContext := importer.StateContext; stateContext != nil {
+			importer.StateContext = func(ctx context.Context, d *schema.ResourceData,
+				meta interface{}) ([]*schema.ResourceData, error) {
+				return stateContext(ctx, d, huaweiMeta(meta))
+			}
+		}
+		r.Importer = &importer
+	}
+
+	return r
+}
+
+// huaweiContextFunc wraps the context aware CRUD function, all of them have the same signature
+func huaweiContextFunc(fn func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics,
+) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	if fn == nil {
+		return nil
+	}
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		return fn(ctx, d, huaweiMeta(meta))
+	}
+}
@@ -32,8 +32,8 @@ func waitForLBV2Listener(lbClient *golangsdk.ServiceClient, id string, target st
 		Pending:    pending,
 		Refresh:    resourceLBV2ListenerRefreshFunc(lbClient, id),
 		Timeout:    timeout,
-		Delay:      5 * time.Second,
-		MinTimeout: 1 * time.Second,
+		Delay:      stateRefreshDelay(5 * time.Second),
+		MinTimeout: stateRefreshInterval(1 * time.Second),
 	}
 
 	_, err := stateConf.WaitForState()
@@ -72,8 +72,8 @@ func waitForLBV2LoadBalancer(lbClient *golangsdk.ServiceClient, id string, targe
 		Pending:    pending,
 		Refresh:    resourceLBV2LoadBalancerRefreshFunc(lbClient, id),
 		Timeout:    timeout,
-		Delay:      5 * time.Second,
-		MinTimeout: 1 * time.Second,
+		Delay:      stateRefreshDelay(5 * time.Second),
+		MinTimeout: stateRefreshInterval(1 * time.Second),
 	}
 
 	_, err := stateConf.WaitForState()
@@ -111,8 +111,8 @@ func waitForLBV2Member(lbClient *golangsdk.ServiceClient, poolID, memberID strin
 		Pending:    pending,
 		Refresh:    resourceLBV2MemberRefreshFunc(lbClient, poolID, memberID),
 		Timeout:    timeout,
-		Delay:      5 * time.Second,
-		MinTimeout: 1 * time.Second,
+		Delay:      stateRefreshDelay(5 * time.Second),
+		MinTimeout: stateRefreshInterval(1 * time.Second),
 	}
 
 	_, err := stateConf.WaitForState()
@@ -151,8 +151,8 @@ func waitForLBV2Monitor(lbClient *golangsdk.ServiceClient, id string, target str
 		Pending:    pending,
 		Refresh:    resourceLBV2MonitorRefreshFunc(lbClient, id),
 		Timeout:    timeout,
-		Delay:      5 * time.Second,
-		MinTimeout: 1 * time.Second,
+		Delay:      stateRefreshDelay(5 * time.Second),
+		MinTimeout: stateRefreshInterval(1 * time.Second),
 	}
 
 	_, err := stateConf.WaitForState()
@@ -191,8 +191,8 @@ func waitForLBV2Pool(lbClient *golangsdk.ServiceClient, id string, target string
 		Pending:    pending,
 		Refresh:    resourceLBV2PoolRefreshFunc(lbClient, id),
 		Timeout:    timeout,
-		Delay:      5 * time.Second,
-		MinTimeout: 1 * time.Second,
+		Delay:      stateRefreshDelay(5 * time.Second),
+		MinTimeout: stateRefreshInterval(1 * time.Second),
 	}
 
 	_, err := stateConf.WaitForState()
@@ -378,8 +378,8 @@ func waitForLBV2L7Policy(lbClient *golangsdk.ServiceClient, parentListener *list
 		Pending:    pending,
 		Refresh:    resourceLBV2L7PolicyRefreshFunc(lbClient, lbID, l7policy),
 		Timeout:    timeout,
-		Delay:      1 * time.Second,
-		MinTimeout: 1 * time.Second,
+		Delay:      stateRefreshDelay(1 * time.Second),
+		MinTimeout: stateRefreshInterval(1 * time.Second),
 	}
 
 	_, err := stateConf.WaitForState()
@@ -462,8 +462,8 @@ func waitForLBV2L7Rule(lbClient *golangsdk.ServiceClient, parentListener *listen
 		Pending:    pending,
 		Refresh:    resourceLBV2L7RuleRefreshFunc(lbClient, lbID, parentL7policy.ID, l7rule),
 		Timeout:    timeout,
-		Delay:      1 * time.Second,
-		MinTimeout: 1 * time.Second,
+		Delay:      stateRefreshDelay(1 * time.Second),
+		MinTimeout: stateRefreshInterval(1 * time.Second),
 	}
 
 	_, err := stateConf.WaitForState()
@@ -0,0 +1,107 @@
+package flexibleengine
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+// registerBMS registers the flavor and type tag APIs of the bare metal servers,
+// the servers are managed by the nova APIs registered with the ECS APIs.
+func (c *mockCloud) registerBMS() {
+	c.handle("ecs", "GET", "/v2.1/{project}/flavors/{id}", func(req *mockRequest) *mockResponse {
+		return mockJSON(http.StatusOK, map[string]interface{}{
+			"flavor": map[string]interface{}{"id": req.Params["id"], "name": req.Params["id"]},
+		})
+	})
+	c.handle("ecs", "PUT", "/v2.1/{project}/servers/{id}/tags", func(req *mockRequest) *mockResponse {
+		server, ok := c.get("servers", req.Params["id"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+
+		server["type_tags"] = req.Body["tags"]
+		return mockJSON(http.StatusOK, map[string]interface{}{"tags": req.Body["tags"]})
+	})
+}
+
+func TestMockComputeBMSInstanceV2_basic(t *testing.T) {
+	t.Parallel()
+
+	c := newMockCloud(t)
+	resourceName := "flexibleengine_compute_bms_server_v2.test"
+
+	c.resourceTest(t, resource.TestCase{
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			c.checkDestroyed("servers"),
+			c.checkDestroyed("volumes"),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockComputeBMSInstanceV2_basic("default", "foo", "bar"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "image_id", mockImageID),
+					resource.TestCheckResourceAttr(resourceName, "flavor_name", "physical.o2.medium"),
+					resource.TestCheckResourceAttr(resourceName, "network.0.name", mockNetworkName),
+					resource.TestCheckResourceAttr(resourceName, "security_groups.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "metadata.foo", "bar"),
+					resource.TestCheckResourceAttr(resourceName, "tags.foo", "bar"),
+					c.checkStored("servers", resourceName, "type_tags", "[__type_baremetal]"),
+				),
+			},
+			{
+				Config: testMockComputeBMSInstanceV2_basic("web", "foo", "baz"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "security_groups.#", "1"),
+					resource.TestCheckTypeSetElemAttr(resourceName, "security_groups.*", "web"),
+					resource.TestCheckResourceAttr(resourceName, "metadata.foo", "baz"),
+					resource.TestCheckResourceAttr(resourceName, "tags.foo", "baz"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateVerifyIgnore: []string{
+					"stop_before_destroy",
+					"admin_pass",
+				},
+			},
+		},
+	})
+}
+
+func testMockComputeBMSInstanceV2_basic(secgroup, key, value string) string {
+	return fmt.Sprintf(`
+resource "flexibleengine_compute_bms_server_v2" "test" {
+  name              = "mock-bms"
+  image_id          = "%s"
+  flavor_id         = "physical.o2.medium"
+  availability_zone = "eu-west-0a"
+  security_groups   = ["%s"]
+
+  network {
+    uuid = "%s"
+  }
+
+  block_device {
+    source_type      = "image"
+    destination_type = "volume"
+    uuid             = "%s"
+    volume_size      = 100
+    volume_type      = "SSD"
+    boot_index       = 0
+  }
+
+  metadata = {
+    %s = "%s"
+  }
+
+  tags = {
+    %s = "%s"
+  }
+}
+`, mockImageID, secgroup, mockNetworkID, mockImageID, key, value, key, value)
+}
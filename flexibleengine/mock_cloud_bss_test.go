@@ -0,0 +1,109 @@
+package flexibleengine
+
+import (
+	"net/http"
+)
+
+// registerBSS registers the order and subscription APIs of the BSS service. The orders
+// are paid automatically, and the resources are released when they are unsubscribed.
+func (c *mockCloud) registerBSS() {
+	c.handle("bss", "GET", "/v2/orders/customer-orders/details/{id}", func(req *mockRequest) *mockResponse {
+		order, ok := c.get("orders", req.Params["id"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+
+		status := order["status"]
+		// the order is processing on the first query
+		order["status"] = 5
+		return mockJSON(http.StatusOK, map[string]interface{}{
+			"order_info": map[string]interface{}{"order_id": order["id"], "status": status},
+		})
+	})
+	c.handle("bss", "POST", "/v2/orders/suscriptions/resources/query", func(req *mockRequest) *mockResponse {
+		data := []interface{}{}
+		resourceIDs, _ := req.Body["resource_ids"].([]interface{})
+		for _, raw := range resourceIDs {
+			if subscription, ok := c.get("subscriptions", raw.(string)); ok {
+				expirePolicy := 0
+				if subscription["expire_policy"] == "on_demand" {
+					expirePolicy = 1
+				}
+				data = append(data, map[string]interface{}{
+					"resource_id":      raw,
+					"is_main_resource": 1,
+					"status":           2,
+					"expire_policy":    expirePolicy,
+				})
+			}
+		}
+		orderID, _ := req.Body["order_id"].(string)
+		if order, ok := c.get("orders", orderID); ok && order["status"] == 5 {
+			data = append(data, map[string]interface{}{
+				"resource_id":      order["resource_id"],
+				"is_main_resource": 1,
+				"status":           2,
+			})
+		}
+		return mockJSON(http.StatusOK, map[string]interface{}{"total_count": len(data), "data": data})
+	})
+	c.handle("bss", "POST", "/v2/orders/subscriptions/resources/unsubscribe", func(req *mockRequest) *mockResponse {
+		resourceIDs, _ := req.Body["resource_ids"].([]interface{})
+		for _, raw := range resourceIDs {
+			if _, ok := c.get("subscriptions", raw.(string)); !ok {
+				return mockError(req.Service, http.StatusBadRequest)
+			}
+		}
+
+		var orderIDs []interface{}
+		for _, raw := range resourceIDs {
+			subscription, _ := c.get("subscriptions", raw.(string))
+			subscription["release"].(func())()
+			c.remove("subscriptions", raw.(string))
+			orderIDs = append(orderIDs, c.newID())
+		}
+		return mockJSON(http.StatusOK, map[string]interface{}{"order_ids": orderIDs})
+	})
+	autoRenew := func(enabled bool) mockHandler {
+		return func(req *mockRequest) *mockResponse {
+			subscription, ok := c.get("subscriptions", req.Params["id"])
+			if !ok {
+				return mockError(req.Service, http.StatusBadRequest)
+			}
+			subscription["auto_renew"] = enabled
+			return mockStatus(http.StatusNoContent)
+		}
+	}
+	c.handle("bss", "POST", "/v2/orders/subscriptions/resources/autorenew/{id}", autoRenew(true))
+	c.handle("bss", "DELETE", "/v2/orders/subscriptions/resources/autorenew/{id}", autoRenew(false))
+	c.handle("bss", "POST", "/v2/orders/subscriptions/resources/to-on-demand", func(req *mockRequest) *mockResponse {
+		resourceIDs, _ := req.Body["resource_ids"].([]interface{})
+		for _, raw := range resourceIDs {
+			subscription, ok := c.get("subscriptions", raw.(string))
+			if !ok || req.Body["operation"] != "SET_UP" {
+				return mockError(req.Service, http.StatusBadRequest)
+			}
+			subscription["expire_policy"] = "on_demand"
+		}
+		return mockStatus(http.StatusNoContent)
+	})
+}
+
+// newOrder places the order of the yearly/monthly resource and returns the ID of the
+// order, the release function is called when the resource is unsubscribed.
+func (c *mockCloud) newOrder(resourceID string, autoRenew bool, release func()) string {
+	orderID := c.newID()
+	c.put("orders", orderID, map[string]interface{}{
+		"id":          orderID,
+		"status":      3,
+		"resource_id": resourceID,
+	})
+	c.put("subscriptions", resourceID, map[string]interface{}{
+		"id":            resourceID,
+		"order_id":      orderID,
+		"auto_renew":    autoRenew,
+		"expire_policy": "grace_period",
+		"release":       release,
+	})
+	return orderID
+}
@@ -0,0 +1,1507 @@
+package flexibleengine
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// mockCCEVersions are the cluster versions supported by the CCE backend in ascending order
+var mockCCEVersions = []string{"v1.19", "v1.21", "v1.23", "v1.25"}
+
+// registerCCE registers the CCE cluster APIs. The pre-upgrade check and upgrade tasks
+// complete at the first query, an upgrade is only accepted after a successful check.
+func (c *mockCloud) registerCCE() {
+	const prefix = "/api/v3/projects/{project}/clusters"
+
+	clusterResponse := func(cluster map[string]interface{}) map[string]interface{} {
+		spec := mockCopy(cluster["spec"].(map[string]interface{}))
+		spec["version"] = cluster["version"]
+		spec["description"] = cluster["description"]
+		extendParam, _ := spec["extendParam"].(map[string]interface{})
+		extendParam = mockCopy(extendParam)
+		extendParam["enterpriseProjectId"] = cluster["enterprise_project_id"]
+		spec["extendParam"] = extendParam
+
+		endpoints := []interface{}{
+			map[string]interface{}{"url": "https://192.168.0.10:5443", "type": "Internal"},
+		}
+		if eip, ok := extendParam["clusterExternalIP"].(string); ok && eip != "" {
+			endpoints = append(endpoints, map[string]interface{}{"url": "https://" + eip + ":5443", "type": "External"})
+		}
+
+		return map[string]interface{}{
+			"kind":       "Cluster",
+			"apiVersion": "v3",
+			"metadata": map[string]interface{}{
+				"uid":         cluster["id"],
+				"name":        cluster["name"],
+				"labels":      cluster["labels"],
+				"annotations": cluster["annotations"],
+			},
+			"spec": spec,
+			"status": map[string]interface{}{
+				"phase":     cluster["phase"],
+				"endpoints": endpoints,
+			},
+		}
+	}
+
+	// clusterCertResponse returns the kubeconfig with the certificates of the cluster
+	clusterCertResponse := func(cluster map[string]interface{}) map[string]interface{} {
+		clusterList := []interface{}{
+			map[string]interface{}{
+				"name": "internalCluster",
+				"cluster": map[string]interface{}{
+					"server":                     "https://192.168.0.10:5443",
+					"certificate-authority-data": "bW9jay1jYQ==",
+				},
+			},
+		}
+		contexts := []interface{}{
+			map[string]interface{}{
+				"name":    "internal",
+				"context": map[string]interface{}{"cluster": "internalCluster", "user": "user"},
+			},
+		}
+		extendParam, _ := cluster["spec"].(map[string]interface{})["extendParam"].(map[string]interface{})
+		if eip, ok := extendParam["clusterExternalIP"].(string); ok && eip != "" {
+			clusterList = append(clusterList, map[string]interface{}{
+				"name": "externalCluster",
+				"cluster": map[string]interface{}{
+					"server":                   "https://" + eip + ":5443",
+					"insecure-skip-tls-verify": true,
+				},
+			})
+			contexts = append(contexts, map[string]interface{}{
+				"name":    "external",
+				"context": map[string]interface{}{"cluster": "externalCluster", "user": "user"},
+			})
+		}
+
+		return map[string]interface{}{
+			"kind":        "Config",
+			"apiVersion":  "v1",
+			"preferences": map[string]interface{}{},
+			"clusters":    clusterList,
+			"users": []interface{}{
+				map[string]interface{}{
+					"name": "user",
+					"user": map[string]interface{}{
+						"client-certificate-data": "bW9jay1jZXJ0",
+						"client-key-data":         "bW9jay1rZXk=",
+					},
+				},
+			},
+			"contexts":        contexts,
+			"current-context": "internal",
+		}
+	}
+	taskResponse := func(task map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"uid":               task["id"],
+				"creationTimestamp": task["created_at"],
+			},
+			"spec": map[string]interface{}{
+				"version":       task["version"],
+				"targetVersion": task["target_version"],
+			},
+			"status": map[string]interface{}{
+				"phase":   task["phase"],
+				"message": task["message"],
+			},
+		}
+	}
+	newTask := func(kind, clusterID string, object map[string]interface{}) map[string]interface{} {
+		id := c.newID()
+		object["id"] = id
+		object["cluster_id"] = clusterID
+		object["created_at"] = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(c.seq) * time.Second).Format(time.RFC3339)
+		c.put(kind, id, object)
+		return object
+	}
+
+	c.handle("cce", "POST", prefix, func(req *mockRequest) *mockResponse {
+		metadata, _ := req.Body["metadata"].(map[string]interface{})
+		spec, _ := req.Body["spec"].(map[string]interface{})
+		if metadata["name"] == nil || spec == nil || spec["flavor"] == nil || spec["type"] == nil {
+			return mockError(req.Service, http.StatusBadRequest)
+		}
+
+		version, _ := spec["version"].(string)
+		if version == "" {
+			version = mockCCEVersions[len(mockCCEVersions)-1]
+		}
+		description, _ := spec["description"].(string)
+		extendParam, _ := spec["extendParam"].(map[string]interface{})
+		epsID, _ := extendParam["enterpriseProjectId"].(string)
+		if epsID == "" {
+			epsID = defaultEnterpriseProjectID
+		}
+		spec = mockCopy(spec)
+		hostNetwork := mockCopy(spec["hostNetwork"].(map[string]interface{}))
+		hostNetwork["SecurityGroup"] = mockSecurityGroupID
+		spec["hostNetwork"] = hostNetwork
+
+		id := c.newID()
+		cluster := map[string]interface{}{
+			"id":                    id,
+			"name":                  metadata["name"],
+			"labels":                metadata["labels"],
+			"annotations":           metadata["annotations"],
+			"spec":                  spec,
+			"version":               version,
+			"description":           description,
+			"phase":                 "Creating",
+			"enterprise_project_id": epsID,
+		}
+		c.put("cce_clusters", id, cluster)
+		return mockJSON(http.StatusCreated, clusterResponse(cluster))
+	})
+	c.handle("cce", "GET", prefix+"/{id}", func(req *mockRequest) *mockResponse {
+		cluster, ok := c.get("cce_clusters", req.Params["id"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+		switch cluster["phase"] {
+		case "Creating", "Awaking":
+			cluster["phase"] = "Available"
+		case "Hibernating":
+			cluster["phase"] = "Hibernation"
+		}
+		return mockJSON(http.StatusOK, clusterResponse(cluster))
+	})
+	c.handle("cce", "PUT", prefix+"/{id}", func(req *mockRequest) *mockResponse {
+		cluster, ok := c.get("cce_clusters", req.Params["id"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+		if spec, ok := req.Body["spec"].(map[string]interface{}); ok {
+			if description, ok := spec["description"]; ok {
+				cluster["description"] = description
+			}
+		}
+		return mockJSON(http.StatusOK, clusterResponse(cluster))
+	})
+	c.handle("cce", "DELETE", prefix+"/{id}", func(req *mockRequest) *mockResponse {
+		cluster, ok := c.get("cce_clusters", req.Params["id"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+		c.remove("cce_clusters", req.Params["id"])
+		return mockJSON(http.StatusOK, clusterResponse(cluster))
+	})
+	// the certificates are generated with the duration, the external context
+	// is only returned when an EIP is bound to the cluster
+	c.handle("cce", "POST", prefix+"/{id}/clustercert", func(req *mockRequest) *mockResponse {
+		cluster, ok := c.get("cce_clusters", req.Params["id"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+		duration, _ := req.Body["duration"].(float64)
+		if duration != -1 && (duration < 1 || duration > 1827) {
+			return mockError(req.Service, http.StatusBadRequest)
+		}
+		cluster["cert_duration"] = int(duration)
+		issued, _ := cluster["cert_issued"].(int)
+		cluster["cert_issued"] = issued + 1
+
+		return mockJSON(http.StatusOK, clusterCertResponse(cluster))
+	})
+	c.handle("cce", "GET", prefix+"/{id}/clustercert", func(req *mockRequest) *mockResponse {
+		cluster, ok := c.get("cce_clusters", req.Params["id"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+		return mockJSON(http.StatusOK, clusterCertResponse(cluster))
+	})
+	c.handle("cce", "POST", prefix+"/{id}/operation/hibernate", func(req *mockRequest) *mockResponse {
+		cluster, ok := c.get("cce_clusters", req.Params["id"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+		if cluster["phase"] != "Available" {
+			return mockError(req.Service, http.StatusBadRequest)
+		}
+		cluster["phase"] = "Hibernating"
+		return mockJSON(http.StatusOK, map[string]interface{}{})
+	})
+	c.handle("cce", "POST", prefix+"/{id}/operation/awake", func(req *mockRequest) *mockResponse {
+		cluster, ok := c.get("cce_clusters", req.Params["id"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+		if cluster["phase"] != "Hibernation" {
+			return mockError(req.Service, http.StatusBadRequest)
+		}
+		cluster["phase"] = "Awaking"
+		return mockJSON(http.StatusOK, map[string]interface{}{})
+	})
+
+	c.handle("cce", "POST", prefix+"/{id}/operation/precheck", func(req *mockRequest) *mockResponse {
+		cluster, ok := c.get("cce_clusters", req.Params["id"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+		spec, _ := req.Body["spec"].(map[string]interface{})
+		target, _ := spec["targetVersion"].(string)
+
+		result, message := "Failed", fmt.Sprintf("version %s is not supported", target)
+		for _, v := range mockCCEVersions {
+			if v == target && compareCCEClusterVersion(target, cluster["version"].(string)) > 0 {
+				result, message = "Success", ""
+			}
+		}
+		task := newTask("cce_precheck_tasks", req.Params["id"], map[string]interface{}{
+			"version":        cluster["version"],
+			"target_version": target,
+			"phase":          "Init",
+			"result":         result,
+			"message":        message,
+		})
+		return mockJSON(http.StatusOK, taskResponse(task))
+	})
+	c.handle("cce", "GET", prefix+"/{id}/operation/precheck/tasks/{task}", func(req *mockRequest) *mockResponse {
+		task, ok := c.get("cce_precheck_tasks", req.Params["task"])
+		if !ok || task["cluster_id"] != req.Params["id"] {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+		task["phase"] = task["result"]
+		return mockJSON(http.StatusOK, taskResponse(task))
+	})
+
+	c.handle("cce", "POST", prefix+"/{id}/operation/upgrade", func(req *mockRequest) *mockResponse {
+		cluster, ok := c.get("cce_clusters", req.Params["id"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+		spec, _ := req.Body["spec"].(map[string]interface{})
+		action, _ := spec["clusterUpgradeAction"].(map[string]interface{})
+		target, _ := action["targetVersion"].(string)
+
+		checked := false
+		for _, task := range c.store["cce_precheck_tasks"] {
+			if task["cluster_id"] == req.Params["id"] && task["target_version"] == target && task["phase"] == "Success" {
+				checked = true
+			}
+		}
+		if !checked {
+			return mockError(req.Service, http.StatusBadRequest)
+		}
+
+		nodePoolOrder, _ := action["nodePoolOrder"].(map[string]interface{})
+		cluster["phase"] = "Upgrading"
+		task := newTask("cce_upgrade_tasks", req.Params["id"], map[string]interface{}{
+			"version":         cluster["version"],
+			"target_version":  target,
+			"node_pool_order": nodePoolOrder,
+			"phase":           "Running",
+		})
+		return mockJSON(http.StatusOK, taskResponse(task))
+	})
+	c.handle("cce", "GET", prefix+"/{id}/operation/upgrade/tasks", func(req *mockRequest) *mockResponse {
+		ids := make([]string, 0)
+		for id, task := range c.store["cce_upgrade_tasks"] {
+			if task["cluster_id"] == req.Params["id"] {
+				ids = append(ids, id)
+			}
+		}
+		sort.Strings(ids)
+
+		items := make([]interface{}, len(ids))
+		for i, id := range ids {
+			items[i] = taskResponse(c.store["cce_upgrade_tasks"][id])
+		}
+		return mockJSON(http.StatusOK, map[string]interface{}{"items": items})
+	})
+	c.handle("cce", "GET", prefix+"/{id}/operation/upgrade/tasks/{task}", func(req *mockRequest) *mockResponse {
+		task, ok := c.get("cce_upgrade_tasks", req.Params["task"])
+		if !ok || task["cluster_id"] != req.Params["id"] {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+		if task["phase"] == "Running" {
+			// the master is upgraded first, then the nodes of the node pools in the order
+			if cluster, ok := c.get("cce_clusters", req.Params["id"]); ok {
+				cluster["version"] = task["target_version"]
+				cluster["phase"] = "Available"
+			}
+			nodePoolOrder, _ := task["node_pool_order"].(map[string]interface{})
+			for poolID := range nodePoolOrder {
+				if pool, ok := c.get("cce_node_pools", poolID); ok {
+					pool["version"] = task["target_version"]
+				}
+			}
+			task["phase"] = "Success"
+		}
+		return mockJSON(http.StatusOK, taskResponse(task))
+	})
+
+	c.registerCCENodePools(prefix)
+}
+
+// registerCCENodePools registers the node pool and node APIs. The nodes of a pool are created
+// with its template when the pool is scaled up, a node of a pool can only be deleted after
+// it is cordoned and drained. The peak node count and the fewest schedulable nodes of a pool
+// are recorded for the checks of rolling updates.
+func (c *mockCloud) registerCCENodePools(prefix string) {
+	poolNodes := func(poolID string) []string {
+		ids := make([]string, 0)
+		for id, node := range c.store["cce_nodes"] {
+			if node["pool_id"] == poolID {
+				ids = append(ids, id)
+			}
+		}
+		sort.Strings(ids)
+		return ids
+	}
+	poolResponse := func(pool map[string]interface{}) map[string]interface{} {
+		spec, _ := pool["spec"].(map[string]interface{})
+		if spec == nil {
+			spec = map[string]interface{}{"type": "vm"}
+		}
+		return map[string]interface{}{
+			"kind":       "NodePool",
+			"apiVersion": "v3",
+			"metadata":   map[string]interface{}{"uid": pool["id"], "name": pool["name"]},
+			"spec":       spec,
+			"status": map[string]interface{}{
+				"phase":       pool["phase"],
+				"currentNode": len(poolNodes(pool["id"].(string))),
+			},
+		}
+	}
+	nodeResponse := func(node map[string]interface{}) map[string]interface{} {
+		// the nodes of node pools are backed by servers with the same ID
+		serverID := node["server_id"]
+		if serverID == nil {
+			serverID = node["id"]
+		}
+		// the password is not returned
+		login, _ := node["login"].(map[string]interface{})
+		spec := map[string]interface{}{
+			"flavor":  node["flavor"],
+			"os":      node["os"],
+			"az":      node["az"],
+			"login":   map[string]interface{}{"sshKey": login["sshKey"]},
+			"k8sTags": node["labels"],
+			"taints":  node["taints"],
+		}
+		if node["max_pods"] != nil {
+			spec["extendParam"] = map[string]interface{}{"maxPods": node["max_pods"]}
+		}
+		return map[string]interface{}{
+			"kind":       "Node",
+			"apiVersion": "v3",
+			"metadata": map[string]interface{}{
+				"uid":         node["id"],
+				"name":        node["name"],
+				"annotations": map[string]interface{}{cceNodePoolAnnotation: node["pool_id"]},
+			},
+			"spec": spec,
+			"status": map[string]interface{}{
+				"phase":     node["phase"],
+				"ServerID":  serverID,
+				"privateIP": node["private_ip"],
+			},
+		}
+	}
+	autoscalingValid := func(spec map[string]interface{}) bool {
+		count, _ := spec["initialNodeCount"].(float64)
+		autoscaling, _ := spec["autoscaling"].(map[string]interface{})
+		if enable, _ := autoscaling["enable"].(bool); !enable {
+			return count >= 0
+		}
+		minCount, _ := autoscaling["minNodeCount"].(float64)
+		maxCount, _ := autoscaling["maxNodeCount"].(float64)
+		return count >= minCount && count <= maxCount
+	}
+	// readyNodes returns the number of active and schedulable nodes of the pool
+	readyNodes := func(poolID string) int {
+		ready := 0
+		for _, id := range poolNodes(poolID) {
+			node := c.store["cce_nodes"][id]
+			if node["phase"] == "Active" && node["unschedulable"] != true {
+				ready++
+			}
+		}
+		return ready
+	}
+	// scale adds or removes the nodes of the pool to match its initial node count
+	scale := func(pool map[string]interface{}) {
+		spec := pool["spec"].(map[string]interface{})
+		template, _ := spec["nodeTemplate"].(map[string]interface{})
+		count := int(spec["initialNodeCount"].(float64))
+		ids := poolNodes(pool["id"].(string))
+		for i := len(ids); i < count; i++ {
+			id := c.newID()
+			c.put("cce_nodes", id, map[string]interface{}{
+				"id":         id,
+				"cluster_id": pool["cluster_id"],
+				"pool_id":    pool["id"],
+				"name":       fmt.Sprintf("%s-%d", pool["name"], c.seq),
+				"flavor":     template["flavor"],
+				"os":         template["os"],
+				"az":         template["az"],
+				"phase":      "Installing",
+			})
+		}
+		for i := count; i < len(ids); i++ {
+			c.remove("cce_nodes", ids[i])
+		}
+		if peak, _ := pool["peak_nodes"].(int); count > peak {
+			pool["peak_nodes"] = count
+		}
+	}
+
+	c.handle("cce", "POST", prefix+"/{id}/nodepools", func(req *mockRequest) *mockResponse {
+		if _, ok := c.get("cce_clusters", req.Params["id"]); !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+		metadata, _ := req.Body["metadata"].(map[string]interface{})
+		spec, _ := req.Body["spec"].(map[string]interface{})
+		template, _ := spec["nodeTemplate"].(map[string]interface{})
+		if metadata["name"] == nil || template["flavor"] == nil || !autoscalingValid(spec) {
+			return mockError(req.Service, http.StatusBadRequest)
+		}
+		if osName, _ := template["os"].(string); osName == "" {
+			template["os"] = "EulerOS 2.5"
+		}
+
+		id := c.newID()
+		pool := map[string]interface{}{
+			"id":         id,
+			"cluster_id": req.Params["id"],
+			"name":       metadata["name"],
+			"version":    c.store["cce_clusters"][req.Params["id"]]["version"],
+			"spec":       spec,
+			"phase":      "Synchronizing",
+		}
+		c.put("cce_node_pools", id, pool)
+		scale(pool)
+		// the initial nodes are ready when the node pool is created
+		for _, nodeID := range poolNodes(id) {
+			c.store["cce_nodes"][nodeID]["phase"] = "Active"
+		}
+		return mockJSON(http.StatusCreated, poolResponse(pool))
+	})
+	c.handle("cce", "GET", prefix+"/{id}/nodepools", func(req *mockRequest) *mockResponse {
+		ids := make([]string, 0)
+		for id, pool := range c.store["cce_node_pools"] {
+			if pool["cluster_id"] == req.Params["id"] {
+				ids = append(ids, id)
+			}
+		}
+		sort.Strings(ids)
+
+		items := make([]interface{}, len(ids))
+		for i, id := range ids {
+			items[i] = poolResponse(c.store["cce_node_pools"][id])
+		}
+		return mockJSON(http.StatusOK, map[string]interface{}{"kind": "List", "apiVersion": "v3", "items": items})
+	})
+	c.handle("cce", "GET", prefix+"/{id}/nodepools/{pool}", func(req *mockRequest) *mockResponse {
+		pool, ok := c.get("cce_node_pools", req.Params["pool"])
+		if !ok || pool["cluster_id"] != req.Params["id"] {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+		if pool["phase"] == "Synchronizing" {
+			pool["phase"] = ""
+		}
+		return mockJSON(http.StatusOK, poolResponse(pool))
+	})
+	c.handle("cce", "PUT", prefix+"/{id}/nodepools/{pool}", func(req *mockRequest) *mockResponse {
+		pool, ok := c.get("cce_node_pools", req.Params["pool"])
+		if !ok || pool["cluster_id"] != req.Params["id"] {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+		spec, _ := req.Body["spec"].(map[string]interface{})
+		if !autoscalingValid(spec) {
+			return mockError(req.Service, http.StatusBadRequest)
+		}
+
+		if metadata, ok := req.Body["metadata"].(map[string]interface{}); ok {
+			pool["name"] = metadata["name"]
+		}
+		current, _ := pool["spec"].(map[string]interface{})
+		newSpec := mockCopy(current)
+		mockMerge(newSpec, spec)
+		pool["spec"] = newSpec
+		pool["phase"] = "Synchronizing"
+		scale(pool)
+		return mockJSON(http.StatusOK, poolResponse(pool))
+	})
+	c.handle("cce", "DELETE", prefix+"/{id}/nodepools/{pool}", func(req *mockRequest) *mockResponse {
+		pool, ok := c.get("cce_node_pools", req.Params["pool"])
+		if !ok || pool["cluster_id"] != req.Params["id"] {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+		for _, id := range poolNodes(req.Params["pool"]) {
+			c.remove("cce_nodes", id)
+		}
+		c.remove("cce_node_pools", req.Params["pool"])
+		return mockJSON(http.StatusOK, poolResponse(pool))
+	})
+
+	c.handle("cce", "GET", prefix+"/{id}/nodes", func(req *mockRequest) *mockResponse {
+		ids := make([]string, 0)
+		for id, node := range c.store["cce_nodes"] {
+			if node["cluster_id"] == req.Params["id"] {
+				ids = append(ids, id)
+			}
+		}
+		sort.Strings(ids)
+
+		items := make([]interface{}, len(ids))
+		for i, id := range ids {
+			items[i] = nodeResponse(c.store["cce_nodes"][id])
+		}
+		return mockJSON(http.StatusOK, map[string]interface{}{"kind": "List", "apiVersion": "v3", "items": items})
+	})
+	c.handle("cce", "GET", prefix+"/{id}/nodes/{node}", func(req *mockRequest) *mockResponse {
+		node, ok := c.get("cce_nodes", req.Params["node"])
+		if !ok || node["cluster_id"] != req.Params["id"] {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+		if node["phase"] == "Installing" {
+			node["phase"] = "Active"
+		}
+		return mockJSON(http.StatusOK, nodeResponse(node))
+	})
+	c.handle("cce", "DELETE", prefix+"/{id}/nodes/{node}", func(req *mockRequest) *mockResponse {
+		node, ok := c.get("cce_nodes", req.Params["node"])
+		if !ok || node["cluster_id"] != req.Params["id"] {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+		if pool, ok := c.get("cce_node_pools", fmt.Sprint(node["pool_id"])); ok {
+			if node["drained"] != true {
+				return mockError(req.Service, http.StatusBadRequest)
+			}
+			spec := mockCopy(pool["spec"].(map[string]interface{}))
+			spec["initialNodeCount"] = spec["initialNodeCount"].(float64) - 1
+			pool["spec"] = spec
+		}
+		c.remove("cce_nodes", req.Params["node"])
+		return mockJSON(http.StatusOK, nodeResponse(node))
+	})
+
+	// installNode applies the spec of an added or reset node and returns the ID of the
+	// job which installs it, the node ID is the resource ID of the InstallNode sub job.
+	installNode := func(node map[string]interface{}, spec map[string]interface{}, jobType string) (string, bool) {
+		login, _ := spec["login"].(map[string]interface{})
+		osName, _ := spec["os"].(string)
+		if osName == "" || len(login) == 0 {
+			return "", false
+		}
+		options, _ := spec["k8sOptions"].(map[string]interface{})
+		if name, _ := spec["name"].(string); name != "" {
+			node["name"] = name
+		}
+		node["os"] = osName
+		node["login"] = login
+		node["labels"] = options["labels"]
+		node["taints"] = options["taints"]
+		node["max_pods"] = options["maxPods"]
+		node["phase"] = "Installing"
+
+		id := c.newID()
+		c.put("cce_jobs", id, map[string]interface{}{
+			"id":    id,
+			"type":  jobType,
+			"nodes": []interface{}{},
+			"phase": "Running",
+			"sub_jobs": []interface{}{
+				map[string]interface{}{
+					"metadata": map[string]interface{}{"uid": c.newID()},
+					"spec":     map[string]interface{}{"type": "InstallNode", "resourceID": node["id"]},
+				},
+			},
+		})
+		return id, true
+	}
+	c.handle("cce", "POST", prefix+"/{id}/nodes/add", func(req *mockRequest) *mockResponse {
+		if _, ok := c.get("cce_clusters", req.Params["id"]); !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+		items, _ := req.Body["nodeList"].([]interface{})
+		if len(items) != 1 {
+			return mockError(req.Service, http.StatusBadRequest)
+		}
+		item := items[0].(map[string]interface{})
+		serverID, _ := item["serverID"].(string)
+		server, ok := c.get("servers", serverID)
+		if !ok {
+			return mockError(req.Service, http.StatusBadRequest)
+		}
+		// a server can only be attached once
+		for _, node := range c.store["cce_nodes"] {
+			if node["server_id"] == serverID {
+				return mockError(req.Service, http.StatusBadRequest)
+			}
+		}
+
+		id := c.newID()
+		node := map[string]interface{}{
+			"id":         id,
+			"cluster_id": req.Params["id"],
+			"server_id":  serverID,
+			"name":       server["name"],
+			"flavor":     server["flavor_id"],
+			"az":         server["availability_zone"],
+			"private_ip": "192.168.0.10",
+		}
+		spec, _ := item["spec"].(map[string]interface{})
+		jobID, ok := installNode(node, spec, "AddNode")
+		if !ok {
+			return mockError(req.Service, http.StatusBadRequest)
+		}
+		c.put("cce_nodes", id, node)
+		return mockJSON(http.StatusOK, map[string]interface{}{"jobid": jobID})
+	})
+	c.handle("cce", "POST", prefix+"/{id}/nodes/reset", func(req *mockRequest) *mockResponse {
+		items, _ := req.Body["nodeList"].([]interface{})
+		if len(items) != 1 {
+			return mockError(req.Service, http.StatusBadRequest)
+		}
+		item := items[0].(map[string]interface{})
+		node, ok := c.get("cce_nodes", fmt.Sprint(item["nodeID"]))
+		if !ok || node["cluster_id"] != req.Params["id"] {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+		spec, _ := item["spec"].(map[string]interface{})
+		jobID, ok := installNode(node, spec, "ResetNode")
+		if !ok {
+			return mockError(req.Service, http.StatusBadRequest)
+		}
+		return mockJSON(http.StatusOK, map[string]interface{}{"jobid": jobID})
+	})
+	c.handle("cce", "PUT", prefix+"/{id}/nodes/{node}", func(req *mockRequest) *mockResponse {
+		node, ok := c.get("cce_nodes", req.Params["node"])
+		if !ok || node["cluster_id"] != req.Params["id"] {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+		if metadata, ok := req.Body["metadata"].(map[string]interface{}); ok {
+			node["name"] = metadata["name"]
+		}
+		return mockJSON(http.StatusOK, nodeResponse(node))
+	})
+
+	requestNodes := func(req *mockRequest) ([]map[string]interface{}, bool) {
+		spec, _ := req.Body["spec"].(map[string]interface{})
+		items, _ := spec["nodes"].([]interface{})
+		result := make([]map[string]interface{}, 0, len(items))
+		for _, item := range items {
+			uid, _ := item.(map[string]interface{})["uid"].(string)
+			node, ok := c.get("cce_nodes", uid)
+			if !ok || node["cluster_id"] != req.Params["id"] {
+				return nil, false
+			}
+			result = append(result, node)
+		}
+		return result, len(result) > 0
+	}
+	c.handle("cce", "POST", prefix+"/{id}/nodes/operation/cordon", func(req *mockRequest) *mockResponse {
+		cordoned, ok := requestNodes(req)
+		if !ok {
+			return mockError(req.Service, http.StatusBadRequest)
+		}
+		for _, node := range cordoned {
+			node["unschedulable"] = true
+			if pool, ok := c.get("cce_node_pools", fmt.Sprint(node["pool_id"])); ok {
+				ready := readyNodes(pool["id"].(string))
+				if fewest, ok := pool["fewest_ready_nodes"].(int); !ok || ready < fewest {
+					pool["fewest_ready_nodes"] = ready
+				}
+			}
+		}
+		return mockJSON(http.StatusOK, map[string]interface{}{})
+	})
+	c.handle("cce", "POST", prefix+"/{id}/nodes/operation/drain", func(req *mockRequest) *mockResponse {
+		drained, ok := requestNodes(req)
+		if !ok {
+			return mockError(req.Service, http.StatusBadRequest)
+		}
+		nodeIDs := make([]interface{}, len(drained))
+		for i, node := range drained {
+			if node["unschedulable"] != true {
+				return mockError(req.Service, http.StatusBadRequest)
+			}
+			nodeIDs[i] = node["id"]
+		}
+
+		id := c.newID()
+		c.put("cce_jobs", id, map[string]interface{}{
+			"id":    id,
+			"type":  "DrainNodes",
+			"nodes": nodeIDs,
+			"phase": "Running",
+		})
+		return mockJSON(http.StatusOK, map[string]interface{}{
+			"kind":       "Drain",
+			"apiVersion": "v3",
+			"status":     map[string]interface{}{"jobID": id},
+		})
+	})
+	// removing nodes keeps their servers
+	c.handle("cce", "PUT", prefix+"/{id}/nodes/operation/remove", func(req *mockRequest) *mockResponse {
+		removed, ok := requestNodes(req)
+		spec, _ := req.Body["spec"].(map[string]interface{})
+		if login, _ := spec["login"].(map[string]interface{}); !ok || len(login) == 0 {
+			return mockError(req.Service, http.StatusBadRequest)
+		}
+		for _, node := range removed {
+			if node["server_id"] == nil {
+				return mockError(req.Service, http.StatusBadRequest)
+			}
+		}
+		for _, node := range removed {
+			c.remove("cce_nodes", node["id"].(string))
+		}
+		return mockStatus(http.StatusOK)
+	})
+	c.handle("cce", "GET", "/api/v3/projects/{project}/jobs/{id}", func(req *mockRequest) *mockResponse {
+		job, ok := c.get("cce_jobs", req.Params["id"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+		if job["phase"] == "Running" {
+			for _, id := range job["nodes"].([]interface{}) {
+				if node, ok := c.get("cce_nodes", id.(string)); ok {
+					node["drained"] = true
+				}
+			}
+			job["phase"] = "Success"
+		}
+		return mockJSON(http.StatusOK, map[string]interface{}{
+			"kind":     "Job",
+			"metadata": map[string]interface{}{"uid": job["id"]},
+			"spec":     map[string]interface{}{"type": job["type"], "subJobs": job["sub_jobs"]},
+			"status":   map[string]interface{}{"phase": job["phase"]},
+		})
+	})
+}
+
+// putCCENodePool returns a check function which adds a node pool with the version of
+// the cluster to the CCE backend.
+func (c *mockCloud) putCCENodePool(clusterName, poolName string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[clusterName]
+		if !ok {
+			return fmt.Errorf("Not found: %s", clusterName)
+		}
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		cluster, ok := c.get("cce_clusters", rs.Primary.ID)
+		if !ok {
+			return fmt.Errorf("cluster %s not found in the mock cloud", rs.Primary.ID)
+		}
+		id := c.newID()
+		c.put("cce_node_pools", id, map[string]interface{}{
+			"id":         id,
+			"cluster_id": rs.Primary.ID,
+			"name":       poolName,
+			"version":    cluster["version"],
+		})
+		return nil
+	}
+}
+
+// checkCCENodePoolVersions returns a check function which verifies the version of
+// all node pools in the CCE backend.
+func (c *mockCloud) checkCCENodePoolVersions(expected string) resource.TestCheckFunc {
+	return func(_ *terraform.State) error {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		for id, pool := range c.store["cce_node_pools"] {
+			if pool["version"] != expected {
+				return fmt.Errorf("expect the version of node pool %s to be %s, but got %v", id, expected, pool["version"])
+			}
+		}
+		return nil
+	}
+}
+
+// checkCCENodePoolNodes returns a check function which verifies the number of the nodes
+// in the node pool and that all of them are created with the flavor and OS.
+func (c *mockCloud) checkCCENodePoolNodes(resourceName string, count int, flavor, osName string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("Not found: %s", resourceName)
+		}
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		actual := 0
+		for id, node := range c.store["cce_nodes"] {
+			if node["pool_id"] != rs.Primary.ID {
+				continue
+			}
+			actual++
+			if node["flavor"] != flavor || node["os"] != osName {
+				return fmt.Errorf("expect node %s to be created with %s and %s, but got %v and %v",
+					id, flavor, osName, node["flavor"], node["os"])
+			}
+		}
+		if actual != count {
+			return fmt.Errorf("expect %d nodes in node pool %s, but got %d", count, rs.Primary.ID, actual)
+		}
+		return nil
+	}
+}
+
+func TestMockCCEClusterV3_upgrade(t *testing.T) {
+	t.Parallel()
+
+	c := newMockCloud(t)
+	resourceName := "flexibleengine_cce_cluster_v3.test"
+
+	c.resourceTest(t, resource.TestCase{
+		CheckDestroy: c.checkDestroyed("cce_clusters"),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockCCEClusterV3_basic("v1.21", ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "cluster_version", "v1.21"),
+					resource.TestCheckResourceAttr(resourceName, "status", "Available"),
+					resource.TestCheckResourceAttr(resourceName, "upgrade_status", ""),
+					resource.TestCheckResourceAttr(resourceName, "security_group_id", mockSecurityGroupID),
+					c.putCCENodePool(resourceName, "mock-pool"),
+				),
+			},
+			{
+				Config: testMockCCEClusterV3_basic("v1.23", testMockCCEClusterV3_upgradePolicy),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "cluster_version", "v1.23"),
+					resource.TestCheckResourceAttr(resourceName, "status", "Available"),
+					resource.TestCheckResourceAttr(resourceName, "upgrade_status", "Success"),
+					resource.TestCheckResourceAttr(resourceName, "upgrade_policy.0.node_batch_size", "10"),
+					c.checkStored("cce_clusters", resourceName, "version", "v1.23"),
+					c.checkCCENodePoolVersions("v1.23"),
+				),
+			},
+			{
+				Config:      testMockCCEClusterV3_basic("v1.21", ""),
+				ExpectError: regexp.MustCompile("cluster_version can not be downgraded from v1.23 to v1.21"),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"upgrade_policy"},
+			},
+		},
+	})
+}
+
+func TestMockCCEClusterV3_upgradeMasterOnly(t *testing.T) {
+	t.Parallel()
+
+	c := newMockCloud(t)
+	resourceName := "flexibleengine_cce_cluster_v3.test"
+
+	c.resourceTest(t, resource.TestCase{
+		CheckDestroy: c.checkDestroyed("cce_clusters"),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockCCEClusterV3_basic("v1.21", ""),
+				Check:  c.putCCENodePool(resourceName, "mock-pool"),
+			},
+			{
+				Config: testMockCCEClusterV3_basic("v1.23", ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "cluster_version", "v1.23"),
+					resource.TestCheckResourceAttr(resourceName, "upgrade_status", "Success"),
+					c.checkCCENodePoolVersions("v1.21"),
+				),
+			},
+		},
+	})
+}
+
+func TestMockCCEClusterV3_precheckFailed(t *testing.T) {
+	t.Parallel()
+
+	c := newMockCloud(t)
+	resourceName := "flexibleengine_cce_cluster_v3.test"
+
+	c.resourceTest(t, resource.TestCase{
+		CheckDestroy: c.checkDestroyed("cce_clusters"),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockCCEClusterV3_basic("v1.21", ""),
+			},
+			{
+				Config:      testMockCCEClusterV3_basic("v1.22", ""),
+				ExpectError: regexp.MustCompile("version v1.22 is not supported"),
+			},
+			{
+				Config: testMockCCEClusterV3_basic("v1.21", ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "cluster_version", "v1.21"),
+					resource.TestCheckResourceAttr(resourceName, "upgrade_status", ""),
+					c.checkStored("cce_clusters", resourceName, "version", "v1.21"),
+				),
+			},
+		},
+	})
+}
+
+func TestMockCCEClusterV3_hibernate(t *testing.T) {
+	t.Parallel()
+
+	c := newMockCloud(t)
+	resourceName := "flexibleengine_cce_cluster_v3.test"
+
+	c.resourceTest(t, resource.TestCase{
+		CheckDestroy: c.checkDestroyed("cce_clusters"),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockCCEClusterV3_hibernate(true, 30, "external"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "hibernate", "true"),
+					resource.TestCheckResourceAttr(resourceName, "status", "Hibernation"),
+					resource.TestCheckResourceAttr(resourceName, "external_endpoint", "https://80.158.10.20:5443"),
+					resource.TestMatchResourceAttr(resourceName, "kube_config_raw",
+						regexp.MustCompile(`(?m)^current-context: external$`)),
+					resource.TestMatchResourceAttr(resourceName, "kube_config_raw",
+						regexp.MustCompile(`server: https://80.158.10.20:5443`)),
+					c.checkStored("cce_clusters", resourceName, "cert_duration", "30"),
+					c.checkStored("cce_clusters", resourceName, "cert_issued", "1"),
+				),
+			},
+			{
+				Config: testMockCCEClusterV3_hibernate(false, -1, "internal"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "hibernate", "false"),
+					resource.TestCheckResourceAttr(resourceName, "status", "Available"),
+					resource.TestMatchResourceAttr(resourceName, "kube_config_raw",
+						regexp.MustCompile(`(?m)^current-context: internal$`)),
+					resource.TestCheckResourceAttr(resourceName, "certificate_users.0.client_key_data", "bW9jay1rZXk="),
+					c.checkStored("cce_clusters", resourceName, "cert_duration", "-1"),
+					c.checkStored("cce_clusters", resourceName, "cert_issued", "2"),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"eip"},
+			},
+		},
+	})
+}
+
+func TestMockCCENodePool_rollingUpdate(t *testing.T) {
+	t.Parallel()
+
+	c := newMockCloud(t)
+	resourceName := "flexibleengine_cce_node_pool_v3.test"
+	var poolID string
+
+	c.resourceTest(t, resource.TestCase{
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			c.checkDestroyed("cce_node_pools"),
+			c.checkDestroyed("cce_nodes"),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockCCENodePool_rollingUpdate("s3.large.2", "EulerOS 2.5", "", 1, 0),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "flavor_id", "s3.large.2"),
+					resource.TestCheckResourceAttr(resourceName, "os", "EulerOS 2.5"),
+					resource.TestCheckResourceAttr(resourceName, "initial_node_count", "2"),
+					resource.TestCheckResourceAttr(resourceName, "rolling_update.0.max_surge", "1"),
+					c.checkCCENodePoolNodes(resourceName, 2, "s3.large.2", "EulerOS 2.5"),
+					testMockCheckResourceID(resourceName, &poolID),
+				),
+			},
+			{
+				Config: testMockCCENodePool_rollingUpdate("s3.xlarge.2", "EulerOS 2.9", "", 1, 0),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "flavor_id", "s3.xlarge.2"),
+					resource.TestCheckResourceAttr(resourceName, "os", "EulerOS 2.9"),
+					resource.TestCheckResourceAttr(resourceName, "initial_node_count", "2"),
+					resource.TestCheckResourceAttrPtr(resourceName, "id", &poolID),
+					c.checkCCENodePoolNodes(resourceName, 2, "s3.xlarge.2", "EulerOS 2.9"),
+					c.checkStored("cce_node_pools", resourceName, "peak_nodes", "3"),
+					c.checkStored("cce_node_pools", resourceName, "fewest_ready_nodes", "2"),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateIdFunc:       nodePoolImportStateIdFunc(resourceName),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"rolling_update"},
+			},
+		},
+	})
+}
+
+func TestMockCCENodePool_rollingUpdateNoSurge(t *testing.T) {
+	t.Parallel()
+
+	c := newMockCloud(t)
+	resourceName := "flexibleengine_cce_node_pool_v3.test"
+	var poolID string
+
+	c.resourceTest(t, resource.TestCase{
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			c.checkDestroyed("cce_node_pools"),
+			c.checkDestroyed("cce_nodes"),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockCCENodePool_rollingUpdate("s3.large.2", "EulerOS 2.5", "", 0, 1),
+				Check: resource.ComposeTestCheckFunc(
+					c.checkCCENodePoolNodes(resourceName, 2, "s3.large.2", "EulerOS 2.5"),
+					testMockCheckResourceID(resourceName, &poolID),
+				),
+			},
+			{
+				// the old nodes are replaced one by one without adding nodes first
+				Config: testMockCCENodePool_rollingUpdate("s3.xlarge.2", "EulerOS 2.5", "", 0, 1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "flavor_id", "s3.xlarge.2"),
+					resource.TestCheckResourceAttrPtr(resourceName, "id", &poolID),
+					c.checkCCENodePoolNodes(resourceName, 2, "s3.xlarge.2", "EulerOS 2.5"),
+					c.checkStored("cce_node_pools", resourceName, "peak_nodes", "2"),
+					c.checkStored("cce_node_pools", resourceName, "fewest_ready_nodes", "1"),
+				),
+			},
+		},
+	})
+}
+
+func TestMockCCENodePool_rollingUpdateBounds(t *testing.T) {
+	t.Parallel()
+
+	c := newMockCloud(t)
+	resourceName := "flexibleengine_cce_node_pool_v3.test"
+	var poolID string
+
+	c.resourceTest(t, resource.TestCase{
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			c.checkDestroyed("cce_node_pools"),
+			c.checkDestroyed("cce_nodes"),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockCCENodePool_rollingUpdate("s3.large.2", "EulerOS 2.5", testMockCCENodePool_scaling, 1, 0),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "scale_enable", "true"),
+					c.checkCCENodePoolNodes(resourceName, 2, "s3.large.2", "EulerOS 2.5"),
+					testMockCheckResourceID(resourceName, &poolID),
+				),
+			},
+			{
+				Config:      testMockCCENodePool_rollingUpdate("s3.xlarge.2", "EulerOS 2.5", testMockCCENodePool_scaling, 1, 0),
+				ExpectError: regexp.MustCompile("no node can be replaced within the bounds of the node pool"),
+			},
+			{
+				Config: testMockCCENodePool_rollingUpdate("s3.xlarge.2", "EulerOS 2.5", testMockCCENodePool_scaling, 1, 1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "flavor_id", "s3.xlarge.2"),
+					resource.TestCheckResourceAttrPtr(resourceName, "id", &poolID),
+					c.checkCCENodePoolNodes(resourceName, 2, "s3.xlarge.2", "EulerOS 2.5"),
+					c.checkStored("cce_node_pools", resourceName, "peak_nodes", "2"),
+					c.checkStored("cce_node_pools", resourceName, "fewest_ready_nodes", "1"),
+				),
+			},
+		},
+	})
+}
+
+func TestMockCCENodePool_replace(t *testing.T) {
+	t.Parallel()
+
+	c := newMockCloud(t)
+	resourceName := "flexibleengine_cce_node_pool_v3.test"
+	var poolID string
+
+	c.resourceTest(t, resource.TestCase{
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			c.checkDestroyed("cce_node_pools"),
+			c.checkDestroyed("cce_nodes"),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockCCENodePool_basic(40),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "root_volume.0.size", "40"),
+					testMockCheckResourceID(resourceName, &poolID),
+				),
+			},
+			{
+				// the node pool is replaced without rolling_update
+				Config: testMockCCENodePool_basic(50),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "root_volume.0.size", "50"),
+					testMockCheckResourceIDChanged(resourceName, &poolID),
+					c.checkStored("cce_node_pools", resourceName, "peak_nodes", "1"),
+				),
+			},
+		},
+	})
+}
+
+func TestMockCCENodePool_options(t *testing.T) {
+	t.Parallel()
+
+	c := newMockCloud(t)
+	resourceName := "flexibleengine_cce_node_pool_v3.test"
+
+	c.resourceTest(t, resource.TestCase{
+		CheckDestroy: c.checkDestroyed("cce_node_pools"),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockCCENodePool_options("v1.25", "containerd", 70),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "runtime.0.name", "containerd"),
+					resource.TestCheckResourceAttr(resourceName, "storage.0.selectors.0.name", "cceUse"),
+					resource.TestCheckResourceAttr(resourceName, "storage.0.selectors.0.match_label_size", "100"),
+					resource.TestCheckResourceAttr(resourceName, "storage.0.groups.0.cce_managed", "true"),
+					resource.TestCheckResourceAttr(resourceName, "storage.0.groups.0.virtual_spaces.#", "2"),
+					resource.TestCheckResourceAttr(resourceName,
+						"storage.0.groups.0.virtual_spaces.1.runtime_lv_type", "linear"),
+					resource.TestCheckResourceAttr(resourceName, "kubelet.0.cpu_manager_policy", "static"),
+					resource.TestCheckResourceAttr(resourceName, "kubelet.0.eviction_hard.memory.available", "100Mi"),
+					resource.TestCheckResourceAttr(resourceName, "kubelet.0.image_gc_high_threshold", "80"),
+					resource.TestCheckResourceAttr(resourceName, "kubelet.0.image_gc_low_threshold", "70"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateIdFunc: nodePoolImportStateIdFunc(resourceName),
+				ImportStateVerify: true,
+			},
+			{
+				Config:      testMockCCENodePool_options("v1.25", "containerd", 90),
+				ExpectError: regexp.MustCompile("image_gc_low_threshold \\(90\\) must be lower"),
+			},
+		},
+	})
+}
+
+func TestMockCCENodePool_optionsVersion(t *testing.T) {
+	t.Parallel()
+
+	c := newMockCloud(t)
+
+	c.resourceTest(t, resource.TestCase{
+		CheckDestroy: c.checkDestroyed("cce_node_pools"),
+		Steps: []resource.TestStep{
+			{
+				// the cluster version is checked before the node pool is created
+				Config:      testMockCCENodePool_options("v1.21", "containerd", 70),
+				ExpectError: regexp.MustCompile("containerd runtime is only supported by clusters of v1.23"),
+			},
+			{
+				Config: testMockCCENodePool_options("v1.21", "docker", 70),
+				Check: resource.TestCheckResourceAttr("flexibleengine_cce_node_pool_v3.test",
+					"runtime.0.name", "docker"),
+			},
+			{
+				// the cluster version is checked in the plan when the cluster exists
+				Config:      testMockCCENodePool_options("v1.21", "containerd", 70),
+				ExpectError: regexp.MustCompile("containerd runtime is only supported by clusters of v1.23"),
+			},
+		},
+	})
+}
+
+func TestMockCCENodeAttach_basic(t *testing.T) {
+	t.Parallel()
+
+	c := newMockCloud(t)
+	resourceName := "flexibleengine_cce_node_attach.test"
+	serverName := "flexibleengine_compute_instance_v2.test"
+	var nodeID string
+
+	c.resourceTest(t, resource.TestCase{
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			c.checkDestroyed("cce_nodes"),
+			c.checkDestroyed("servers"),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockCCENodeAttach_basic("EulerOS 2.9", "mock-node"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(resourceName, "server_id", serverName, "id"),
+					resource.TestCheckResourceAttr(resourceName, "name", "mock-node"),
+					resource.TestCheckResourceAttr(resourceName, "os", "EulerOS 2.9"),
+					resource.TestCheckResourceAttr(resourceName, "flavor_id", "s3.large.2"),
+					resource.TestCheckResourceAttr(resourceName, "availability_zone", "eu-west-0a"),
+					resource.TestCheckResourceAttr(resourceName, "status", "Active"),
+					resource.TestCheckResourceAttr(resourceName, "max_pods", "64"),
+					resource.TestCheckResourceAttr(resourceName, "labels.foo", "bar"),
+					resource.TestCheckResourceAttr(resourceName, "taints.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "taints.0.effect", "NoSchedule"),
+					resource.TestCheckResourceAttrSet(resourceName, "private_ip"),
+					testMockCheckResourceID(resourceName, &nodeID),
+				),
+			},
+			{
+				// the server is reinstalled in place
+				Config: testMockCCENodeAttach_basic("CentOS 7.6", "mock-node-update"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "os", "CentOS 7.6"),
+					resource.TestCheckResourceAttr(resourceName, "name", "mock-node-update"),
+					resource.TestCheckResourceAttrPtr(resourceName, "id", &nodeID),
+					c.checkStored("cce_nodes", resourceName, "os", "CentOS 7.6"),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateIdFunc:       nodePoolImportStateIdFunc(resourceName),
+				ImportStateVerifyIgnore: []string{"preinstall"},
+			},
+			{
+				// removing the node keeps the server
+				Config: testMockCCENodeAttach_server,
+				Check: resource.ComposeTestCheckFunc(
+					c.checkDestroyed("cce_nodes"),
+					c.checkStored("servers", serverName, "status", "ACTIVE"),
+				),
+			},
+		},
+	})
+}
+
+// testMockCheckResourceID saves the ID of the resource for the later steps
+func testMockCheckResourceID(resourceName string, id *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("Not found: %s", resourceName)
+		}
+		*id = rs.Primary.ID
+		return nil
+	}
+}
+
+// testMockCheckResourceIDChanged verifies that the resource is replaced since the ID is saved
+func testMockCheckResourceIDChanged(resourceName string, id *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("Not found: %s", resourceName)
+		}
+		if rs.Primary.ID == *id {
+			return fmt.Errorf("expect %s to be replaced, but the ID is still %s", resourceName, *id)
+		}
+		return nil
+	}
+}
+
+const testMockCCEClusterV3_upgradePolicy = `
+  upgrade_policy {
+    upgrade_node_pools = true
+    node_batch_size    = 10
+  }
+`
+
+func testMockCCEClusterV3_basic(version, upgradePolicy string) string {
+	return fmt.Sprintf(`
+resource "flexibleengine_cce_cluster_v3" "test" {
+  name                   = "mock-cce"
+  cluster_type           = "VirtualMachine"
+  cluster_version        = "%s"
+  flavor_id              = "cce.s1.small"
+  vpc_id                 = "%s"
+  subnet_id              = "%s"
+  container_network_type = "overlay_l2"
+%s}
+`, version, mockVpcID, mockSubnetID, upgradePolicy)
+}
+
+func testMockCCEClusterV3_hibernate(hibernate bool, duration int, contextName string) string {
+	return fmt.Sprintf(`
+resource "flexibleengine_cce_cluster_v3" "test" {
+  name                   = "mock-cce"
+  cluster_type           = "VirtualMachine"
+  flavor_id              = "cce.s1.small"
+  vpc_id                 = "%s"
+  subnet_id              = "%s"
+  container_network_type = "overlay_l2"
+  eip                    = "80.158.10.20"
+  hibernate              = %t
+  kube_config_duration   = %d
+  kube_config_context    = "%s"
+}
+`, mockVpcID, mockSubnetID, hibernate, duration, contextName)
+}
+
+const testMockCCENodePool_scaling = `
+  scale_enable   = true
+  min_node_count = 1
+  max_node_count = 2
+`
+
+func testMockCCENodePool_rollingUpdate(flavor, osName, scaling string, maxSurge, maxUnavailable int) string {
+	return fmt.Sprintf(`
+%s
+
+resource "flexibleengine_cce_node_pool_v3" "test" {
+  cluster_id         = flexibleengine_cce_cluster_v3.test.id
+  name               = "mock-pool"
+  flavor_id          = "%s"
+  os                 = "%s"
+  initial_node_count = 2
+  availability_zone  = "eu-west-0a"
+  key_pair           = "mock-keypair"
+%s
+  root_volume {
+    size       = 40
+    volumetype = "SSD"
+  }
+  data_volumes {
+    size       = 100
+    volumetype = "SSD"
+  }
+
+  rolling_update {
+    max_surge       = %d
+    max_unavailable = %d
+  }
+}
+`, testMockCCEClusterV3_basic("v1.25", ""), flavor, osName, scaling, maxSurge, maxUnavailable)
+}
+
+func testMockCCENodePool_basic(rootVolumeSize int) string {
+	return fmt.Sprintf(`
+%s
+
+resource "flexibleengine_cce_node_pool_v3" "test" {
+  cluster_id         = flexibleengine_cce_cluster_v3.test.id
+  name               = "mock-pool"
+  flavor_id          = "s3.large.2"
+  initial_node_count = 1
+  availability_zone  = "eu-west-0a"
+  key_pair           = "mock-keypair"
+
+  root_volume {
+    size       = %d
+    volumetype = "SSD"
+  }
+  data_volumes {
+    size       = 100
+    volumetype = "SSD"
+  }
+}
+`, testMockCCEClusterV3_basic("v1.25", ""), rootVolumeSize)
+}
+
+var testMockCCENodeAttach_server = fmt.Sprintf(`
+%s
+
+resource "flexibleengine_compute_instance_v2" "test" {
+  name              = "mock-reserved-ecs"
+  image_id          = "%s"
+  flavor_id         = "s3.large.2"
+  security_groups   = ["default"]
+  availability_zone = "eu-west-0a"
+
+  network {
+    uuid = "%s"
+  }
+}
+`, testMockCCEClusterV3_basic("v1.25", ""), mockImageID, mockNetworkID)
+
+func testMockCCENodeAttach_basic(osName, name string) string {
+	return fmt.Sprintf(`
+%s
+
+resource "flexibleengine_cce_node_attach" "test" {
+  cluster_id = flexibleengine_cce_cluster_v3.test.id
+  server_id  = flexibleengine_compute_instance_v2.test.id
+  name       = "%s"
+  os         = "%s"
+  key_pair   = "mock-keypair"
+  max_pods   = 64
+  preinstall = "echo hello"
+
+  labels = {
+    foo = "bar"
+  }
+  taints {
+    key    = "dedicated"
+    value  = "reserved"
+    effect = "NoSchedule"
+  }
+}
+`, testMockCCENodeAttach_server, name, osName)
+}
+
+func testMockCCENodePool_options(version, runtime string, imageGCLowThreshold int) string {
+	return fmt.Sprintf(`
+%s
+
+resource "flexibleengine_cce_node_pool_v3" "test" {
+  cluster_id         = flexibleengine_cce_cluster_v3.test.id
+  name               = "mock-pool"
+  flavor_id          = "s3.large.2"
+  initial_node_count = 1
+  availability_zone  = "eu-west-0a"
+  key_pair           = "mock-keypair"
+
+  root_volume {
+    size       = 40
+    volumetype = "SSD"
+  }
+  data_volumes {
+    size       = 100
+    volumetype = "SSD"
+  }
+
+  runtime {
+    name = "%s"
+  }
+
+  storage {
+    selectors {
+      name             = "cceUse"
+      match_label_size = "100"
+    }
+    groups {
+      name           = "vgpaas"
+      cce_managed    = true
+      selector_names = ["cceUse"]
+
+      virtual_spaces {
+        name        = "kubernetes"
+        size        = "10%%"
+        lvm_lv_type = "linear"
+      }
+      virtual_spaces {
+        name            = "runtime"
+        size            = "90%%"
+        runtime_lv_type = "linear"
+      }
+    }
+  }
+
+  kubelet {
+    cpu_manager_policy      = "static"
+    image_gc_high_threshold = 80
+    image_gc_low_threshold  = %d
+
+    eviction_hard = {
+      "memory.available" = "100Mi"
+      "nodefs.available" = "10%%"
+    }
+  }
+}
+`, testMockCCEClusterV3_basic(version, ""), runtime, imageGCLowThreshold)
+}
@@ -0,0 +1,206 @@
+package flexibleengine
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+const (
+	mockVpcID           = "e0b5c3d7-6f4a-4b1c-8d9e-8a7f6e5d4c30"
+	mockSecurityGroupID = "f1c6d4e8-7a5b-4c2d-9e0f-9b8a7f6e5d40"
+	mockDcsProductID    = "dcs.master_standby-h"
+)
+
+// registerDCS registers the instance and product APIs of the DCS service
+func (c *mockCloud) registerDCS() {
+	c.handle("dcs", "GET", "/v1.0/products", func(req *mockRequest) *mockResponse {
+		return mockJSON(http.StatusOK, map[string]interface{}{
+			"products": []interface{}{
+				map[string]interface{}{"product_id": mockDcsProductID, "spec_code": "dcs.master_standby"},
+				map[string]interface{}{"product_id": "dcs.single_node-h", "spec_code": "dcs.single_node"},
+			},
+		})
+	})
+
+	c.handle("dcs", "POST", "/v1.0/{project}/instances", func(req *mockRequest) *mockResponse {
+		if req.Body["name"] == nil || req.Body["product_id"] == nil {
+			return mockError(req.Service, http.StatusBadRequest)
+		}
+
+		capacity, _ := req.Body["capacity"].(float64)
+		major, minor := math.Modf(capacity)
+		var capacityMinor string
+		if minor != 0 {
+			capacityMinor = strconv.FormatFloat(minor, 'f', -1, 64)
+		}
+		port, _ := req.Body["port"].(float64)
+		if port == 0 {
+			port = 6379
+		}
+		epsID, _ := req.Body["enterprise_project_id"].(string)
+		if epsID == "" {
+			epsID = defaultEnterpriseProjectID
+		}
+
+		id := c.newID()
+		instance := map[string]interface{}{
+			"instance_id":           id,
+			"name":                  req.Body["name"],
+			"description":           req.Body["description"],
+			"engine":                req.Body["engine"],
+			"engine_version":        req.Body["engine_version"],
+			"capacity":              int(major),
+			"capacity_minor":        capacityMinor,
+			"status":                "CREATING",
+			"ip":                    fmt.Sprintf("192.168.20.%d", c.seq%250+2),
+			"port":                  int(port),
+			"vpc_id":                req.Body["vpc_id"],
+			"subnet_id":             req.Body["subnet_id"],
+			"security_group_id":     req.Body["security_group_id"],
+			"available_zones":       req.Body["available_zones"],
+			"product_id":            req.Body["product_id"],
+			"access_user":           req.Body["access_user"],
+			"maintain_begin":        "02:00:00",
+			"maintain_end":          "06:00:00",
+			"enterprise_project_id": epsID,
+		}
+		for _, key := range []string{"maintain_begin", "maintain_end"} {
+			if v, ok := req.Body[key].(string); ok && v != "" {
+				instance[key] = v
+			}
+		}
+		c.put("instances", id, instance)
+
+		return mockJSON(http.StatusOK, map[string]interface{}{"instance_id": id})
+	})
+	c.handle("dcs", "GET", "/v1.0/{project}/instances/{id}", func(req *mockRequest) *mockResponse {
+		instance, ok := c.get("instances", req.Params["id"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+
+		resp := mockCopy(instance)
+		switch instance["status"] {
+		case "CREATING":
+			instance["status"] = "RUNNING"
+		case "DELETING":
+			c.remove("instances", req.Params["id"])
+		}
+		return mockJSON(http.StatusOK, resp)
+	})
+	c.handle("dcs", "PUT", "/v1.0/{project}/instances/{id}", func(req *mockRequest) *mockResponse {
+		instance, ok := c.get("instances", req.Params["id"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+		if instance["status"] != "RUNNING" {
+			return mockError(req.Service, http.StatusBadRequest)
+		}
+
+		mockMerge(instance, req.Body)
+		return mockStatus(http.StatusNoContent)
+	})
+	c.handle("dcs", "DELETE", "/v1.0/{project}/instances/{id}", func(req *mockRequest) *mockResponse {
+		instance, ok := c.get("instances", req.Params["id"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+
+		instance["status"] = "DELETING"
+		return mockStatus(http.StatusNoContent)
+	})
+}
+
+func TestMockDcsInstanceV1_basic(t *testing.T) {
+	t.Parallel()
+
+	c := newMockCloud(t)
+	resourceName := "flexibleengine_dcs_instance_v1.test"
+
+	c.resourceTest(t, resource.TestCase{
+		CheckDestroy: c.checkDestroyed("instances"),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockDcsInstanceV1_basic("mock-dcs", "02:00:00", "06:00:00"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "name", "mock-dcs"),
+					resource.TestCheckResourceAttr(resourceName, "engine", "Redis"),
+					resource.TestCheckResourceAttr(resourceName, "engine_version", "3.0"),
+					resource.TestCheckResourceAttr(resourceName, "capacity", "2"),
+					resource.TestCheckResourceAttr(resourceName, "status", "RUNNING"),
+					resource.TestCheckResourceAttr(resourceName, "port", "6379"),
+					resource.TestCheckResourceAttr(resourceName, "product_id", mockDcsProductID),
+					resource.TestCheckResourceAttr(resourceName, "enterprise_project_id", "0"),
+					resource.TestCheckResourceAttr(resourceName, "region", mockRegion),
+					resource.TestCheckResourceAttrSet(resourceName, "ip"),
+				),
+			},
+			{
+				Config: testMockDcsInstanceV1_basic("mock-dcs-update", "06:00:00", "10:00:00"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "name", "mock-dcs-update"),
+					resource.TestCheckResourceAttr(resourceName, "maintain_begin", "06:00:00"),
+					resource.TestCheckResourceAttr(resourceName, "maintain_end", "10:00:00"),
+					c.checkStored("instances", resourceName, "maintain_begin", "06:00:00"),
+				),
+			},
+		},
+	})
+}
+
+func TestMockDcsInstanceV1_instanceType(t *testing.T) {
+	t.Parallel()
+
+	c := newMockCloud(t)
+	resourceName := "flexibleengine_dcs_instance_v1.test"
+
+	c.resourceTest(t, resource.TestCase{
+		CheckDestroy: c.checkDestroyed("instances"),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "flexibleengine_dcs_instance_v1" "test" {
+  name              = "mock-dcs"
+  engine            = "Redis"
+  engine_version    = "3.0"
+  password          = "Huawei_test"
+  capacity          = 0.5
+  instance_type     = "dcs.single_node"
+  vpc_id            = "%s"
+  network_id        = "%s"
+  security_group_id = "%s"
+  available_zones   = ["eu-west-0a"]
+}
+`, mockVpcID, mockSubnetID, mockSecurityGroupID),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "capacity", "0.5"),
+					resource.TestCheckResourceAttr(resourceName, "product_id", "dcs.single_node-h"),
+				),
+			},
+		},
+	})
+}
+
+func testMockDcsInstanceV1_basic(name, begin, end string) string {
+	return fmt.Sprintf(`
+resource "flexibleengine_dcs_instance_v1" "test" {
+  name              = "%s"
+  engine            = "Redis"
+  engine_version    = "3.0"
+  password          = "Huawei_test"
+  capacity          = 2
+  product_id        = "%s"
+  vpc_id            = "%s"
+  network_id        = "%s"
+  security_group_id = "%s"
+  available_zones   = ["eu-west-0a"]
+  maintain_begin    = "%s"
+  maintain_end      = "%s"
+}
+`, name, mockDcsProductID, mockVpcID, mockSubnetID, mockSecurityGroupID, begin, end)
+}
@@ -0,0 +1,289 @@
+package flexibleengine
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+// mockDeHHostTypes are the host types of dedicated hosts in every availability zone
+var mockDeHHostTypes = []map[string]interface{}{
+	{"host_type": "s3", "host_type_name": "General computing S3", "vcpus": 144, "memory": 786432},
+	{"host_type": "m3", "host_type_name": "Memory-optimized M3", "vcpus": 144, "memory": 1572864},
+}
+
+// registerDeH registers the dedicated host APIs. The instances on a host are the
+// servers in the ECS backend which are created with the DeH scheduler hints.
+func (c *mockCloud) registerDeH() {
+	hostResponse := func(host map[string]interface{}) map[string]interface{} {
+		var hostType map[string]interface{}
+		for _, t := range mockDeHHostTypes {
+			if t["host_type"] == host["host_type"] {
+				hostType = t
+			}
+		}
+
+		instances := []string{}
+		for id, server := range c.store["servers"] {
+			dehIDs, _ := server["deh_ids"].([]interface{})
+			for _, dehID := range dehIDs {
+				if dehID == host["id"] {
+					instances = append(instances, id)
+				}
+			}
+		}
+		sort.Strings(instances)
+
+		vcpus := hostType["vcpus"].(int)
+		memory := hostType["memory"].(int)
+		return map[string]interface{}{
+			"dedicated_host_id": host["id"],
+			"name":              host["name"],
+			"auto_placement":    host["auto_placement"],
+			"availability_zone": host["availability_zone"],
+			"project_id":        mockProjectID,
+			"state":             host["state"],
+			"available_vcpus":   vcpus - 2*len(instances),
+			"available_memory":  memory - 4096*len(instances),
+			"allocated_at":      "2022-01-01T00:00:00Z",
+			"instance_total":    len(instances),
+			"instance_uuids":    instances,
+			"host_properties": map[string]interface{}{
+				"host_type":      host["host_type"],
+				"host_type_name": hostType["host_type_name"],
+				"vcpus":          vcpus,
+				"cores":          vcpus / 2,
+				"sockets":        2,
+				"memory":         memory,
+				"available_instance_capacities": []interface{}{
+					map[string]interface{}{"flavor": host["host_type"].(string) + ".small.1"},
+					map[string]interface{}{"flavor": host["host_type"].(string) + ".large.2"},
+				},
+			},
+		}
+	}
+
+	c.handle("deh", "GET", "/v1.0/{project}/availability-zone/{az}/dedicated-host-types", func(req *mockRequest) *mockResponse {
+		hostTypes := make([]interface{}, len(mockDeHHostTypes))
+		for i, t := range mockDeHHostTypes {
+			hostTypes[i] = map[string]interface{}{"host_type": t["host_type"], "host_type_name": t["host_type_name"]}
+		}
+		return mockJSON(http.StatusOK, map[string]interface{}{"dedicated_host_types": hostTypes})
+	})
+
+	c.handle("deh", "POST", "/v1.0/{project}/dedicated-hosts", func(req *mockRequest) *mockResponse {
+		valid := false
+		for _, t := range mockDeHHostTypes {
+			if t["host_type"] == req.Body["host_type"] {
+				valid = true
+			}
+		}
+		if !valid || req.Body["quantity"] != float64(1) {
+			return mockError(req.Service, http.StatusBadRequest)
+		}
+
+		autoPlacement, _ := req.Body["auto_placement"].(string)
+		if autoPlacement == "" {
+			autoPlacement = "on"
+		}
+		id := c.newID()
+		c.put("dedicated_hosts", id, map[string]interface{}{
+			"id":                id,
+			"name":              req.Body["name"],
+			"host_type":         req.Body["host_type"],
+			"availability_zone": req.Body["availability_zone"],
+			"auto_placement":    autoPlacement,
+			"state":             "creating",
+		})
+		return mockJSON(http.StatusOK, map[string]interface{}{"dedicated_host_ids": []string{id}})
+	})
+	c.handle("deh", "GET", "/v1.0/{project}/dedicated-hosts", func(req *mockRequest) *mockResponse {
+		query := req.URL.Query()
+		filters := map[string]string{
+			"id":                query.Get("dedicated_host_id"),
+			"name":              query.Get("name"),
+			"availability_zone": query.Get("availability_zone"),
+			"host_type":         query.Get("host_type"),
+			"state":             query.Get("state"),
+		}
+
+		ids := make([]string, 0)
+		for id := range c.store["dedicated_hosts"] {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+
+		hostList := make([]interface{}, 0)
+		for _, id := range ids {
+			host := c.store["dedicated_hosts"][id]
+			match := true
+			for k, v := range filters {
+				if v != "" && fmt.Sprint(host[k]) != v {
+					match = false
+				}
+			}
+			if match {
+				hostList = append(hostList, hostResponse(host))
+			}
+		}
+		return mockJSON(http.StatusOK, map[string]interface{}{"dedicated_hosts": hostList})
+	})
+	c.handle("deh", "GET", "/v1.0/{project}/dedicated-hosts/{id}", func(req *mockRequest) *mockResponse {
+		host, ok := c.get("dedicated_hosts", req.Params["id"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+		if host["state"] == "creating" {
+			host["state"] = "available"
+		}
+		return mockJSON(http.StatusOK, map[string]interface{}{"dedicated_host": hostResponse(host)})
+	})
+	c.handle("deh", "PUT", "/v1.0/{project}/dedicated-hosts/{id}", func(req *mockRequest) *mockResponse {
+		host, ok := c.get("dedicated_hosts", req.Params["id"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+		mockMerge(host, req.Body["dedicated_host"])
+		return mockStatus(http.StatusNoContent)
+	})
+	c.handle("deh", "DELETE", "/v1.0/{project}/dedicated-hosts/{id}", func(req *mockRequest) *mockResponse {
+		if _, ok := c.get("dedicated_hosts", req.Params["id"]); !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+		c.remove("dedicated_hosts", req.Params["id"])
+		return mockStatus(http.StatusNoContent)
+	})
+
+	c.handleTags("deh", "/v1.0/{project}/dedicated-host-tags")
+}
+
+func TestMockDeHInstance_basic(t *testing.T) {
+	t.Parallel()
+
+	c := newMockCloud(t)
+	resourceName := "flexibleengine_deh_instance.test"
+
+	c.resourceTest(t, resource.TestCase{
+		CheckDestroy: c.checkDestroyed("dedicated_hosts"),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockDeHInstance_basic("mock-deh", "on", "bar"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "name", "mock-deh"),
+					resource.TestCheckResourceAttr(resourceName, "host_type", "s3"),
+					resource.TestCheckResourceAttr(resourceName, "host_type_name", "General computing S3"),
+					resource.TestCheckResourceAttr(resourceName, "auto_placement", "on"),
+					resource.TestCheckResourceAttr(resourceName, "status", "available"),
+					resource.TestCheckResourceAttr(resourceName, "vcpus", "144"),
+					resource.TestCheckResourceAttr(resourceName, "available_flavors.#", "2"),
+					resource.TestCheckResourceAttr(resourceName, "instance_total", "0"),
+					resource.TestCheckResourceAttr(resourceName, "tags.foo", "bar"),
+				),
+			},
+			{
+				Config: testMockDeHInstance_basic("mock-deh-update", "off", "baz"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "name", "mock-deh-update"),
+					resource.TestCheckResourceAttr(resourceName, "auto_placement", "off"),
+					resource.TestCheckResourceAttr(resourceName, "tags.foo", "baz"),
+					c.checkStored("dedicated_hosts", resourceName, "auto_placement", "off"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestMockDeHInstance_server(t *testing.T) {
+	t.Parallel()
+
+	c := newMockCloud(t)
+	dataSourceName := "data.flexibleengine_deh_instance.test"
+
+	c.resourceTest(t, resource.TestCase{
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			c.checkDestroyed("dedicated_hosts"),
+			c.checkDestroyed("servers"),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockDeHInstance_server,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.flexibleengine_deh_host_types.test", "host_types.#", "1"),
+					resource.TestCheckResourceAttr("data.flexibleengine_deh_host_types.test",
+						"host_types.0.host_type_name", "General computing S3"),
+					resource.TestCheckResourceAttr("flexibleengine_compute_instance_v2.test",
+						"scheduler_hints.#", "1"),
+					resource.TestCheckResourceAttrPair(dataSourceName, "id",
+						"flexibleengine_deh_instance.test", "id"),
+					resource.TestCheckResourceAttr(dataSourceName, "instance_total", "1"),
+					resource.TestCheckResourceAttrPair(dataSourceName, "instance_uuids.0",
+						"flexibleengine_compute_instance_v2.test", "id"),
+					resource.TestCheckResourceAttr(dataSourceName, "tags.owner", "mock"),
+				),
+			},
+		},
+	})
+}
+
+func testMockDeHInstance_basic(name, autoPlacement, tag string) string {
+	return fmt.Sprintf(`
+resource "flexibleengine_deh_instance" "test" {
+  name              = "%s"
+  availability_zone = "eu-west-0a"
+  host_type         = "s3"
+  auto_placement    = "%s"
+
+  tags = {
+    foo = "%s"
+  }
+}
+`, name, autoPlacement, tag)
+}
+
+var testMockDeHInstance_server = fmt.Sprintf(`
+data "flexibleengine_deh_host_types" "test" {
+  availability_zone = "eu-west-0a"
+  host_type         = "s3"
+}
+
+resource "flexibleengine_deh_instance" "test" {
+  name              = "mock-deh"
+  availability_zone = "eu-west-0a"
+  host_type         = "s3"
+
+  tags = {
+    owner = "mock"
+  }
+}
+
+resource "flexibleengine_compute_instance_v2" "test" {
+  name              = "mock-ecs-on-deh"
+  image_id          = "%s"
+  flavor_id         = "s3.small.1"
+  security_groups   = ["default"]
+  availability_zone = "eu-west-0a"
+
+  network {
+    uuid = "%s"
+  }
+
+  scheduler_hints {
+    tenancy = "dedicated"
+    deh_id  = flexibleengine_deh_instance.test.id
+  }
+}
+
+data "flexibleengine_deh_instance" "test" {
+  name = "mock-deh"
+
+  depends_on = [flexibleengine_compute_instance_v2.test]
+}
+`, mockImageID, mockNetworkID)
@@ -0,0 +1,837 @@
+package flexibleengine
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+const (
+	mockImageID     = "b7e2f0a4-3c1d-4e8f-9a6b-5d4c3b2a1f00"
+	mockNetworkID   = "c8f3a1b5-4d2e-4f9a-8b7c-6e5d4c3b2a10"
+	mockNetworkName = "mock-network"
+)
+
+// registerECS registers the nova and cloud server APIs used by the ECS resources, and the public image to boot from
+func (c *mockCloud) registerECS() {
+	c.put("images", mockImageID, map[string]interface{}{
+		"id":         mockImageID,
+		"name":       "OBS Ubuntu 20.04",
+		"status":     "active",
+		"visibility": "public",
+		"min_disk":   40,
+	})
+
+	createServer := func(req *mockRequest) *mockResponse {
+		opts, _ := req.Body["server"].(map[string]interface{})
+		if opts == nil {
+			return mockError(req.Service, http.StatusBadRequest)
+		}
+		if _, ok := c.get("images", fmt.Sprint(opts["imageRef"])); !ok {
+			return mockError(req.Service, http.StatusBadRequest)
+		}
+
+		id := c.newID()
+		var ports []interface{}
+		networks, _ := opts["networks"].([]interface{})
+		for i, raw := range networks {
+			network := raw.(map[string]interface{})
+			address := fmt.Sprintf("192.168.%d.%d", i, c.seq%250+2)
+			ports = append(ports, c.newPort(fmt.Sprint(network["uuid"]), id, address))
+		}
+
+		var secgroups []interface{}
+		rawGroups, _ := opts["security_groups"].([]interface{})
+		for _, raw := range rawGroups {
+			name := raw.(map[string]interface{})["name"]
+			secgroups = append(secgroups, map[string]interface{}{"name": name, "id": name})
+		}
+
+		metadata, _ := opts["metadata"].(map[string]interface{})
+		if metadata == nil {
+			metadata = make(map[string]interface{})
+		}
+		availabilityZone, _ := opts["availability_zone"].(string)
+		if availabilityZone == "" {
+			availabilityZone = mockRegion + "a"
+		}
+		keyName, _ := opts["key_name"].(string)
+
+		// the server is placed on the dedicated host in the scheduler hints
+		dehIDs := []interface{}{}
+		if hints, ok := req.Body["os:scheduler_hints"].(map[string]interface{}); ok {
+			if dehID, ok := hints["dedicated_host_id"].(string); ok {
+				dehIDs = append(dehIDs, dehID)
+			}
+		}
+
+		// the boot volume is created from the image with the system metadata,
+		// a system disk is created from the image when no mapping is given
+		var volumes []interface{}
+		mappings, _ := opts["block_device_mapping_v2"].([]interface{})
+		if len(mappings) == 0 && opts["imageRef"] != nil {
+			mappings = []interface{}{map[string]interface{}{
+				"source_type": "image",
+				"uuid":        opts["imageRef"],
+				"volume_size": 40,
+				"boot_index":  0,
+			}}
+		}
+		for i, raw := range mappings {
+			mapping := raw.(map[string]interface{})
+			volumeType, _ := mapping["volume_type"].(string)
+			if volumeType == "" {
+				volumeType = "SATA"
+			}
+			volumeMetadata := make(map[string]interface{})
+			if cmkID, ok := metadata["__system__cmkid"]; ok {
+				volumeMetadata["__system__encrypted"] = "1"
+				volumeMetadata["__system__cmkid"] = cmkID
+			}
+
+			imageMetadata := make(map[string]interface{})
+			if mapping["source_type"] == "image" {
+				imageMetadata["image_id"] = mapping["uuid"]
+			}
+
+			volumeID := c.newID()
+			attachment := map[string]interface{}{
+				"id":        volumeID,
+				"server_id": id,
+				"volume_id": volumeID,
+				"device":    fmt.Sprintf("/dev/sd%c", 'a'+i),
+			}
+			c.put("volumes", volumeID, map[string]interface{}{
+				"id":                    volumeID,
+				"status":                "in-use",
+				"size":                  mapping["volume_size"],
+				"availability_zone":     availabilityZone,
+				"volume_type":           volumeType,
+				"metadata":              volumeMetadata,
+				"volume_image_metadata": imageMetadata,
+				"bootable":              "true",
+				"attachments":           []interface{}{attachment},
+				"boot_index":            mapping["boot_index"],
+			})
+			volumes = append(volumes, volumeID)
+		}
+
+		c.put("servers", id, map[string]interface{}{
+			"id":                    id,
+			"name":                  opts["name"],
+			"status":                "BUILD",
+			"image_id":              opts["imageRef"],
+			"flavor_id":             opts["flavorRef"],
+			"availability_zone":     availabilityZone,
+			"key_name":              keyName,
+			"deh_ids":               dehIDs,
+			"metadata":              metadata,
+			"security_groups":       secgroups,
+			"ports":                 ports,
+			"volumes":               volumes,
+			"auto_recovery":         "false",
+			"charging_mode":         "0",
+			"enterprise_project_id": defaultEnterpriseProjectID,
+		})
+
+		return mockJSON(http.StatusAccepted, map[string]interface{}{
+			"server": map[string]interface{}{"id": id, "adminPass": "mock-password"},
+		})
+	}
+	c.handle("ecs", "POST", "/v2.1/{project}/servers", createServer)
+	c.handle("ecs", "POST", "/v2.1/{project}/os-volumes_boot", createServer)
+
+	releaseServer := func(id string) {
+		server, _ := c.get("servers", id)
+		for _, port := range server["ports"].([]interface{}) {
+			c.remove("ports", port.(string))
+		}
+		for _, volume := range server["volumes"].([]interface{}) {
+			c.remove("volumes", volume.(string))
+		}
+		c.remove("servers", id)
+	}
+	// the yearly/monthly servers are ordered by the v1.1 API, the request is converted
+	// to the nova one which creates the server and its boot volume
+	c.handle("ecs", "POST", "/v1.1/{project}/cloudservers", func(req *mockRequest) *mockResponse {
+		opts, _ := req.Body["server"].(map[string]interface{})
+		extendParam, _ := opts["extendparam"].(map[string]interface{})
+		rootVolume, _ := opts["root_volume"].(map[string]interface{})
+		if extendParam["chargingMode"] != "prePaid" || extendParam["isAutoPay"] != "true" || rootVolume == nil {
+			return mockError(req.Service, http.StatusBadRequest)
+		}
+		if opts["vpcid"] != mockVpcID {
+			return mockError(req.Service, http.StatusBadRequest)
+		}
+
+		var networks []interface{}
+		nics, _ := opts["nics"].([]interface{})
+		for _, raw := range nics {
+			networks = append(networks, map[string]interface{}{"uuid": raw.(map[string]interface{})["subnet_id"]})
+		}
+		metadata := make(map[string]interface{})
+		if volumeMetadata, ok := rootVolume["metadata"].(map[string]interface{}); ok {
+			mockMerge(metadata, volumeMetadata)
+		}
+		novaOpts := map[string]interface{}{
+			"name":              opts["name"],
+			"imageRef":          opts["imageRef"],
+			"flavorRef":         opts["flavorRef"],
+			"availability_zone": opts["availability_zone"],
+			"key_name":          opts["key_name"],
+			"networks":          networks,
+			"metadata":          metadata,
+			"block_device_mapping_v2": []interface{}{map[string]interface{}{
+				"boot_index":  0,
+				"volume_size": rootVolume["size"],
+				"volume_type": rootVolume["volumetype"],
+			}},
+		}
+		resp := createServer(&mockRequest{Request: req.Request, Service: req.Service, Body: map[string]interface{}{"server": novaOpts}})
+		if resp.Status != http.StatusAccepted {
+			return resp
+		}
+
+		id := resp.Body.(map[string]interface{})["server"].(map[string]interface{})["id"].(string)
+		server, _ := c.get("servers", id)
+		server["charging_mode"] = "1"
+		if epsID, ok := extendParam["enterprise_project_id"]; ok {
+			server["enterprise_project_id"] = epsID
+		}
+
+		orderID := c.newOrder(id, extendParam["isAutoRenew"] == "true", func() { releaseServer(id) })
+		return mockJSON(http.StatusOK, map[string]interface{}{"order_id": orderID, "job_id": c.newID()})
+	})
+	c.handle("ecs", "POST", "/v1/{project}/cloudservers/{id}/changechargemode", func(req *mockRequest) *mockResponse {
+		server, ok := c.get("servers", req.Params["id"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+		if req.Body["charging_mode"] != "prePaid" || req.Body["is_auto_pay"] != true || server["charging_mode"] == "1" {
+			return mockError(req.Service, http.StatusBadRequest)
+		}
+
+		server["charging_mode"] = "1"
+		id := req.Params["id"]
+		orderID := c.newOrder(id, req.Body["is_auto_renew"] == true, func() { releaseServer(id) })
+		return mockJSON(http.StatusOK, map[string]interface{}{"order_id": orderID})
+	})
+	c.handle("ecs", "GET", "/v2.1/{project}/servers/{id}", func(req *mockRequest) *mockResponse {
+		server, ok := c.get("servers", req.Params["id"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+
+		var addresses []interface{}
+		for _, raw := range server["ports"].([]interface{}) {
+			port, _ := c.get("ports", raw.(string))
+			fixedIP := port["fixed_ips"].([]interface{})[0].(map[string]interface{})
+			addresses = append(addresses, map[string]interface{}{
+				"version":                 4,
+				"addr":                    fixedIP["ip_address"],
+				"OS-EXT-IPS-MAC:mac_addr": port["mac_address"],
+				"OS-EXT-IPS:type":         "fixed",
+			})
+		}
+
+		// the image is in the boot volume when the server boots from a volume
+		imageID := server["image_id"]
+		if len(server["volumes"].([]interface{})) > 0 {
+			imageID = ""
+		}
+
+		resp := map[string]interface{}{
+			"id":                               server["id"],
+			"name":                             server["name"],
+			"status":                           server["status"],
+			"metadata":                         server["metadata"],
+			"tenant_id":                        mockProjectID,
+			"key_name":                         server["key_name"],
+			"flavor":                           map[string]interface{}{"id": server["flavor_id"]},
+			"image":                            map[string]interface{}{"id": imageID},
+			"security_groups":                  server["security_groups"],
+			"addresses":                        map[string]interface{}{mockNetworkName: addresses},
+			"OS-EXT-AZ:availability_zone":      server["availability_zone"],
+			"OS-EXT-SRV-ATTR:root_device_name": "/dev/sda",
+		}
+		switch server["status"] {
+		case "BUILD", "REBOOT", "HARD_REBOOT":
+			server["status"] = "ACTIVE"
+		}
+		return mockJSON(http.StatusOK, map[string]interface{}{"server": resp})
+	})
+	c.handle("ecs", "PUT", "/v2.1/{project}/servers/{id}", func(req *mockRequest) *mockResponse {
+		server, ok := c.get("servers", req.Params["id"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+
+		opts, _ := req.Body["server"].(map[string]interface{})
+		if name, ok := opts["name"]; ok {
+			server["name"] = name
+		}
+		return mockJSON(http.StatusOK, map[string]interface{}{
+			"server": map[string]interface{}{"id": server["id"], "name": server["name"], "status": server["status"]},
+		})
+	})
+	c.handle("ecs", "POST", "/v2.1/{project}/servers/{id}/metadata", func(req *mockRequest) *mockResponse {
+		server, ok := c.get("servers", req.Params["id"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+
+		metadata := server["metadata"].(map[string]interface{})
+		mockMerge(metadata, req.Body["metadata"])
+		return mockJSON(http.StatusOK, map[string]interface{}{"metadata": metadata})
+	})
+	c.handle("ecs", "DELETE", "/v2.1/{project}/servers/{id}/metadata/{key}", func(req *mockRequest) *mockResponse {
+		server, ok := c.get("servers", req.Params["id"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+
+		delete(server["metadata"].(map[string]interface{}), req.Params["key"])
+		return mockStatus(http.StatusNoContent)
+	})
+	c.handle("ecs", "DELETE", "/v2.1/{project}/servers/{id}", func(req *mockRequest) *mockResponse {
+		server, ok := c.get("servers", req.Params["id"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+		// the yearly/monthly servers can only be unsubscribed
+		if server["charging_mode"] == "1" {
+			return mockError(req.Service, http.StatusForbidden)
+		}
+
+		releaseServer(req.Params["id"])
+		return mockStatus(http.StatusNoContent)
+	})
+	c.handle("ecs", "POST", "/v2.1/{project}/servers/{id}/action", func(req *mockRequest) *mockResponse {
+		server, ok := c.get("servers", req.Params["id"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+
+		secgroups, _ := server["security_groups"].([]interface{})
+		switch {
+		case req.Body["addSecurityGroup"] != nil:
+			name := req.Body["addSecurityGroup"].(map[string]interface{})["name"]
+			server["security_groups"] = append(secgroups, map[string]interface{}{"name": name, "id": name})
+		case req.Body["removeSecurityGroup"] != nil:
+			name := req.Body["removeSecurityGroup"].(map[string]interface{})["name"]
+			remaining := []interface{}{}
+			for _, raw := range secgroups {
+				if raw.(map[string]interface{})["name"] != name {
+					remaining = append(remaining, raw)
+				}
+			}
+			if len(remaining) == len(secgroups) {
+				return mockError(req.Service, http.StatusNotFound)
+			}
+			server["security_groups"] = remaining
+		default:
+			return mockError(req.Service, http.StatusBadRequest)
+		}
+		return mockStatus(http.StatusAccepted)
+	})
+	c.handle("ecs", "GET", "/v2.1/{project}/servers/{id}/os-volume_attachments", func(req *mockRequest) *mockResponse {
+		if _, ok := c.get("servers", req.Params["id"]); !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+
+		result := []interface{}{}
+		for _, volume := range c.store["volumes"] {
+			attachments, _ := volume["attachments"].([]interface{})
+			for _, raw := range attachments {
+				attachment := raw.(map[string]interface{})
+				if attachment["server_id"] == req.Params["id"] {
+					result = append(result, map[string]interface{}{
+						"id":       attachment["id"],
+						"volumeId": attachment["volume_id"],
+						"serverId": attachment["server_id"],
+						"device":   attachment["device"],
+					})
+				}
+			}
+		}
+		return mockJSON(http.StatusOK, map[string]interface{}{"volumeAttachments": result})
+	})
+	c.handle("ecs", "DELETE", "/v2.1/{project}/servers/{id}/os-volume_attachments/{attachment}", func(req *mockRequest) *mockResponse {
+		for _, volume := range c.store["volumes"] {
+			attachments, _ := volume["attachments"].([]interface{})
+			for i, raw := range attachments {
+				attachment := raw.(map[string]interface{})
+				if attachment["id"] != req.Params["attachment"] || attachment["server_id"] != req.Params["id"] {
+					continue
+				}
+				volume["attachments"] = append(attachments[:i:i], attachments[i+1:]...)
+				if len(volume["attachments"].([]interface{})) == 0 {
+					volume["status"] = "available"
+				}
+				return mockStatus(http.StatusAccepted)
+			}
+		}
+		return mockError(req.Service, http.StatusNotFound)
+	})
+
+	c.handle("ecs", "GET", "/v1/{project}/cloudservers/{id}", func(req *mockRequest) *mockResponse {
+		server, ok := c.get("servers", req.Params["id"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+
+		var addresses []interface{}
+		for _, raw := range server["ports"].([]interface{}) {
+			port, _ := c.get("ports", raw.(string))
+			fixedIP := port["fixed_ips"].([]interface{})[0].(map[string]interface{})
+			addresses = append(addresses, map[string]interface{}{
+				"version":                 "4",
+				"addr":                    fixedIP["ip_address"],
+				"OS-EXT-IPS-MAC:mac_addr": port["mac_address"],
+				"OS-EXT-IPS:port_id":      port["id"],
+				"OS-EXT-IPS:type":         "fixed",
+			})
+		}
+
+		volumesAttached := []interface{}{}
+		for _, volume := range server["volumes"].([]interface{}) {
+			volumesAttached = append(volumesAttached, map[string]interface{}{"id": volume})
+		}
+
+		return mockJSON(http.StatusOK, map[string]interface{}{
+			"server": map[string]interface{}{
+				"id":                                   server["id"],
+				"name":                                 server["name"],
+				"status":                               server["status"],
+				"key_name":                             server["key_name"],
+				"tenant_id":                            mockProjectID,
+				"enterprise_project_id":                server["enterprise_project_id"],
+				"OS-EXT-AZ:availability_zone":          server["availability_zone"],
+				"flavor":                               map[string]interface{}{"id": server["flavor_id"], "name": server["flavor_id"]},
+				"image":                                map[string]interface{}{"id": server["image_id"]},
+				"security_groups":                      server["security_groups"],
+				"metadata":                             map[string]interface{}{"charging_mode": server["charging_mode"]},
+				"addresses":                            map[string]interface{}{"mock-vpc": addresses},
+				"os-extended-volumes:volumes_attached": volumesAttached,
+				"os:scheduler_hints":                   map[string]interface{}{"dedicated_host_id": server["deh_ids"]},
+			},
+		})
+	})
+	c.handle("ecs", "GET", "/v1/{project}/cloudservers/{id}/block_device/{volume}", func(req *mockRequest) *mockResponse {
+		volume, ok := c.get("volumes", req.Params["volume"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+		return mockJSON(http.StatusOK, map[string]interface{}{
+			"volumeAttachment": map[string]interface{}{
+				"id":         volume["id"],
+				"volumeId":   volume["id"],
+				"serverId":   req.Params["id"],
+				"size":       volume["size"],
+				"bootIndex":  volume["boot_index"],
+				"pciAddress": "0000:02:01.0",
+			},
+		})
+	})
+	c.handle("ecs", "POST", "/v1/{project}/cloudservers/action", func(req *mockRequest) *mockResponse {
+		for option, raw := range req.Body {
+			opts, _ := raw.(map[string]interface{})
+			serverList, _ := opts["servers"].([]interface{})
+			for _, item := range serverList {
+				server, ok := c.get("servers", fmt.Sprint(item.(map[string]interface{})["id"]))
+				if !ok {
+					return mockError(req.Service, http.StatusNotFound)
+				}
+
+				powerType, _ := opts["type"].(string)
+				switch {
+				case option == "os-start" && server["status"] == "SHUTOFF":
+					server["status"] = "ACTIVE"
+				case option == "os-stop" && server["status"] == "ACTIVE":
+					server["status"] = "SHUTOFF"
+				case option == "reboot" && server["status"] == "ACTIVE":
+					server["status"] = "REBOOT"
+					if powerType == "HARD" {
+						server["status"] = "HARD_REBOOT"
+					}
+				default:
+					return mockError(req.Service, http.StatusConflict)
+				}
+				server["power_type"] = powerType
+			}
+		}
+		return mockJSON(http.StatusOK, map[string]interface{}{"job_id": c.newID()})
+	})
+	c.handle("ecs", "GET", "/v1/{project}/cloudservers/{id}/autorecovery", func(req *mockRequest) *mockResponse {
+		server, ok := c.get("servers", req.Params["id"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+		return mockJSON(http.StatusOK, map[string]interface{}{"support_auto_recovery": server["auto_recovery"]})
+	})
+	c.handle("ecs", "PUT", "/v1/{project}/cloudservers/{id}/autorecovery", func(req *mockRequest) *mockResponse {
+		server, ok := c.get("servers", req.Params["id"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+
+		server["auto_recovery"] = req.Body["support_auto_recovery"]
+		return mockStatus(http.StatusNoContent)
+	})
+	c.handleTags("ecs", "/v1/{project}/servers")
+}
+
+func TestMockComputeInstanceV2_basic(t *testing.T) {
+	t.Parallel()
+
+	c := newMockCloud(t)
+	resourceName := "flexibleengine_compute_instance_v2.test"
+
+	c.resourceTest(t, resource.TestCase{
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			c.checkDestroyed("servers"),
+			c.checkDestroyed("ports"),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockComputeInstanceV2_basic("mock-ecs", "bar"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "name", "mock-ecs"),
+					resource.TestCheckResourceAttr(resourceName, "status", "ACTIVE"),
+					resource.TestCheckResourceAttr(resourceName, "image_name", "OBS Ubuntu 20.04"),
+					resource.TestCheckResourceAttr(resourceName, "flavor_name", "s3.small.1"),
+					resource.TestCheckResourceAttr(resourceName, "availability_zone", "eu-west-0a"),
+					resource.TestCheckResourceAttr(resourceName, "network.0.uuid", mockNetworkID),
+					resource.TestCheckResourceAttrSet(resourceName, "network.0.fixed_ip_v4"),
+					resource.TestCheckResourceAttrSet(resourceName, "access_ip_v4"),
+					resource.TestCheckResourceAttr(resourceName, "metadata.foo", "bar"),
+					resource.TestCheckResourceAttr(resourceName, "auto_recovery", "true"),
+					resource.TestCheckResourceAttr(resourceName, "tags.owner", "mock"),
+				),
+			},
+			{
+				Config: testMockComputeInstanceV2_basic("mock-ecs-update", "baz"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "name", "mock-ecs-update"),
+					resource.TestCheckResourceAttr(resourceName, "metadata.foo", "baz"),
+					c.checkStored("servers", resourceName, "name", "mock-ecs-update"),
+				),
+			},
+		},
+	})
+}
+
+func TestMockComputeInstanceV2_powerState(t *testing.T) {
+	t.Parallel()
+
+	c := newMockCloud(t)
+	resourceName := "flexibleengine_compute_instance_v2.test"
+
+	c.resourceTest(t, resource.TestCase{
+		CheckDestroy: c.checkDestroyed("servers"),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockComputeInstanceV2_power(`power_state = "SHUTOFF"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "power_state", "SHUTOFF"),
+					resource.TestCheckResourceAttr(resourceName, "status", "SHUTOFF"),
+					c.checkStored("servers", resourceName, "power_type", "SOFT"),
+				),
+			},
+			{
+				Config: testMockComputeInstanceV2_power(`power_state = "ACTIVE"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "power_state", "ACTIVE"),
+					c.checkStored("servers", resourceName, "status", "ACTIVE"),
+				),
+			},
+			{
+				Config: testMockComputeInstanceV2_power(`power_action = "FORCE-REBOOT"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "power_state", "ACTIVE"),
+					c.checkStored("servers", resourceName, "power_type", "HARD"),
+				),
+			},
+			{
+				Config:      testMockComputeInstanceV2_power(`power_action = "FORCE-OFF"`),
+				ExpectError: regexp.MustCompile(`power_state must be SHUTOFF when power_action is FORCE-OFF`),
+			},
+			{
+				Config: testMockComputeInstanceV2_power(`power_state = "ACTIVE"
+  power_action = "FORCE-OFF"`),
+				ExpectError: regexp.MustCompile(`power_state must be SHUTOFF when power_action is FORCE-OFF`),
+			},
+			{
+				Config: testMockComputeInstanceV2_power(`power_state = "SHUTOFF"
+  power_action = "FORCE-OFF"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "power_state", "SHUTOFF"),
+					c.checkStored("servers", resourceName, "status", "SHUTOFF"),
+					c.checkStored("servers", resourceName, "power_type", "HARD"),
+				),
+			},
+			{
+				Config: testMockComputeInstanceV2_power(`power_state = "SHUTOFF"
+  power_action = "REBOOT"`),
+				ExpectError: regexp.MustCompile(`power_action REBOOT can not be used when power_state is SHUTOFF`),
+			},
+		},
+	})
+}
+
+func TestMockComputeInstanceV2_systemDisk(t *testing.T) {
+	t.Parallel()
+
+	c := newMockCloud(t)
+	resourceName := "flexibleengine_compute_instance_v2.test"
+
+	c.resourceTest(t, resource.TestCase{
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			c.checkDestroyed("servers"),
+			c.checkDestroyed("volumes"),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockComputeInstanceV2_systemDisk(50),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "system_disk_size", "50"),
+					resource.TestCheckResourceAttr(resourceName, "system_disk_type", "SSD"),
+					resource.TestCheckResourceAttr(resourceName, "system_disk_kms_key_id", "mock-kms-key"),
+					resource.TestCheckResourceAttrSet(resourceName, "system_disk_id"),
+					resource.TestCheckResourceAttr(resourceName, "volume_attached.0.boot_index", "0"),
+					resource.TestCheckResourceAttr(resourceName, "metadata.%", "1"),
+				),
+			},
+			{
+				Config: testMockComputeInstanceV2_systemDisk(80),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "system_disk_size", "80"),
+					resource.TestCheckResourceAttr(resourceName, "volume_attached.0.size", "80"),
+					resource.TestCheckResourceAttr(resourceName, "status", "ACTIVE"),
+				),
+			},
+			{
+				Config:      testMockComputeInstanceV2_systemDisk(60),
+				ExpectError: regexp.MustCompile("system_disk_size can not be shrunk"),
+			},
+		},
+	})
+}
+
+func TestMockComputeInstanceV2_prePaid(t *testing.T) {
+	t.Parallel()
+
+	c := newMockCloud(t)
+	resourceName := "flexibleengine_compute_instance_v2.test"
+
+	c.resourceTest(t, resource.TestCase{
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			c.checkDestroyed("servers"),
+			c.checkDestroyed("volumes"),
+			c.checkDestroyed("subscriptions"),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockComputeInstanceV2_prePaid("false"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "charging_mode", "prePaid"),
+					resource.TestCheckResourceAttr(resourceName, "system_disk_size", "50"),
+					resource.TestCheckResourceAttr(resourceName, "metadata.foo", "bar"),
+					c.checkStored("subscriptions", resourceName, "auto_renew", "false"),
+				),
+			},
+			{
+				Config: testMockComputeInstanceV2_prePaid("true"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "auto_renew", "true"),
+					c.checkStored("subscriptions", resourceName, "auto_renew", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestMockComputeInstanceV2_changeToPrePaid(t *testing.T) {
+	t.Parallel()
+
+	c := newMockCloud(t)
+	resourceName := "flexibleengine_compute_instance_v2.test"
+
+	c.resourceTest(t, resource.TestCase{
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			c.checkDestroyed("servers"),
+			c.checkDestroyed("subscriptions"),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockComputeInstanceV2_power(""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "charging_mode", "postPaid"),
+				),
+			},
+			{
+				Config: testMockComputeInstanceV2_power(`
+  charging_mode = "prePaid"
+  period_unit   = "month"
+  period        = 1`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "charging_mode", "prePaid"),
+					c.checkStored("servers", resourceName, "charging_mode", "1"),
+					c.checkStored("subscriptions", resourceName, "auto_renew", "false"),
+				),
+			},
+			{
+				Config: testMockComputeInstanceV2_power(`charging_mode = "postPaid"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "charging_mode", "postPaid"),
+					c.checkStored("subscriptions", resourceName, "expire_policy", "on_demand"),
+				),
+			},
+			{
+				// the switch to postPaid is pending until the current period expires
+				Config: testMockComputeInstanceV2_power(`
+  charging_mode = "prePaid"
+  period_unit   = "month"
+  period        = 1`),
+				ExpectError: regexp.MustCompile("can not be changed back before that"),
+			},
+		},
+	})
+}
+
+func TestMockComputeInstanceV2_prePaidPeriod(t *testing.T) {
+	t.Parallel()
+
+	c := newMockCloud(t)
+
+	c.resourceTest(t, resource.TestCase{
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			c.checkDestroyed("servers"),
+			c.checkDestroyed("subscriptions"),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockComputeInstanceV2_power(`
+  charging_mode = "prePaid"
+  period_unit   = "month"
+  period        = 1`),
+			},
+			{
+				Config: testMockComputeInstanceV2_power(`
+  charging_mode = "prePaid"
+  period_unit   = "month"
+  period        = 2`),
+				ExpectError: regexp.MustCompile("period_unit and period can not be changed in prePaid charging mode"),
+			},
+		},
+	})
+}
+
+func TestMockComputeInstanceV2_prePaidConfigDrive(t *testing.T) {
+	t.Parallel()
+
+	c := newMockCloud(t)
+
+	c.resourceTest(t, resource.TestCase{
+		CheckDestroy: c.checkDestroyed("servers"),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockComputeInstanceV2_power(`
+  charging_mode = "prePaid"
+  period_unit   = "month"
+  period        = 1
+  config_drive  = true`),
+				ExpectError: regexp.MustCompile("config_drive is not supported in prePaid charging mode"),
+			},
+		},
+	})
+}
+
+func testMockComputeInstanceV2_basic(name, metadata string) string {
+	return fmt.Sprintf(`
+resource "flexibleengine_compute_instance_v2" "test" {
+  name              = "%s"
+  image_id          = "%s"
+  flavor_id         = "s3.small.1"
+  security_groups   = ["default"]
+  availability_zone = "eu-west-0a"
+  auto_recovery     = true
+
+  network {
+    uuid = "%s"
+  }
+
+  metadata = {
+    foo = "%s"
+  }
+
+  tags = {
+    owner = "mock"
+  }
+}
+`, name, mockImageID, mockNetworkID, metadata)
+}
+
+func testMockComputeInstanceV2_power(power string) string {
+	return fmt.Sprintf(`
+resource "flexibleengine_compute_instance_v2" "test" {
+  name      = "mock-ecs"
+  image_id  = "%s"
+  flavor_id = "s3.large.2"
+
+  network {
+    uuid = "%s"
+  }
+
+  %s
+}
+`, mockImageID, mockNetworkID, power)
+}
+
+func testMockComputeInstanceV2_systemDisk(size int) string {
+	return fmt.Sprintf(`
+resource "flexibleengine_compute_instance_v2" "test" {
+  name                   = "mock-ecs"
+  image_id               = "%s"
+  flavor_id              = "s3.large.2"
+  system_disk_size       = %d
+  system_disk_type       = "SSD"
+  system_disk_kms_key_id = "mock-kms-key"
+
+  network {
+    uuid = "%s"
+  }
+
+  metadata = {
+    foo = "bar"
+  }
+}
+`, mockImageID, size, mockNetworkID)
+}
+
+func testMockComputeInstanceV2_prePaid(autoRenew string) string {
+	return fmt.Sprintf(`
+resource "flexibleengine_compute_instance_v2" "test" {
+  name              = "mock-ecs"
+  image_id          = "%s"
+  flavor_id         = "s3.large.2"
+  availability_zone = "eu-west-0a"
+  system_disk_size  = 50
+  charging_mode     = "prePaid"
+  period_unit       = "month"
+  period            = 1
+  auto_renew        = "%s"
+
+  network {
+    uuid = "%s"
+  }
+
+  metadata = {
+    foo = "bar"
+  }
+}
+`, mockImageID, autoRenew, mockNetworkID)
+}
@@ -0,0 +1,153 @@
+package flexibleengine
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+const mockSubnetID = "d9a4b2c6-5e3f-4a0b-9c8d-7f6e5d4c3b20"
+
+// registerELB registers the load balancer APIs of the ELB v2 service, they are
+// served under both the elb and the lbaas paths.
+func (c *mockCloud) registerELB() {
+	for _, prefix := range []string{"/v2.0/elb", "/v2.0/lbaas"} {
+		c.registerLoadBalancers(prefix)
+	}
+	c.handleTags("elb", "/v2.0/{project}/loadbalancers")
+}
+
+func (c *mockCloud) registerLoadBalancers(prefix string) {
+	c.handle("elb", "POST", prefix+"/loadbalancers", func(req *mockRequest) *mockResponse {
+		opts, _ := req.Body["loadbalancer"].(map[string]interface{})
+		if opts == nil || opts["vip_subnet_id"] == nil {
+			return mockError(req.Service, http.StatusBadRequest)
+		}
+
+		id := c.newID()
+		address, _ := opts["vip_address"].(string)
+		if address == "" {
+			address = fmt.Sprintf("192.168.10.%d", c.seq%250+2)
+		}
+		portID := c.newPort(opts["vip_subnet_id"].(string), id, address)
+		epsID, _ := opts["enterprise_project_id"].(string)
+		if epsID == "" {
+			epsID = defaultEnterpriseProjectID
+		}
+		adminStateUp, ok := opts["admin_state_up"].(bool)
+		if !ok {
+			adminStateUp = true
+		}
+
+		lb := map[string]interface{}{
+			"id":                    id,
+			"name":                  opts["name"],
+			"description":           opts["description"],
+			"vip_subnet_id":         opts["vip_subnet_id"],
+			"vip_address":           address,
+			"vip_port_id":           portID,
+			"admin_state_up":        adminStateUp,
+			"tenant_id":             mockProjectID,
+			"provider":              "vlb",
+			"provisioning_status":   "PENDING_CREATE",
+			"operating_status":      "ONLINE",
+			"enterprise_project_id": epsID,
+			"listeners":             []interface{}{},
+			"pools":                 []interface{}{},
+		}
+		c.put("loadbalancers", id, lb)
+
+		return mockJSON(http.StatusCreated, map[string]interface{}{"loadbalancer": lb})
+	})
+	c.handle("elb", "GET", prefix+"/loadbalancers/{id}", func(req *mockRequest) *mockResponse {
+		lb, ok := c.get("loadbalancers", req.Params["id"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+
+		resp := mockCopy(lb)
+		switch lb["provisioning_status"] {
+		case "PENDING_CREATE", "PENDING_UPDATE":
+			lb["provisioning_status"] = "ACTIVE"
+		case "PENDING_DELETE":
+			c.remove("ports", lb["vip_port_id"].(string))
+			c.remove("loadbalancers", req.Params["id"])
+		}
+		return mockJSON(http.StatusOK, map[string]interface{}{"loadbalancer": resp})
+	})
+	c.handle("elb", "PUT", prefix+"/loadbalancers/{id}", func(req *mockRequest) *mockResponse {
+		lb, ok := c.get("loadbalancers", req.Params["id"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+		if lb["provisioning_status"] != "ACTIVE" {
+			return mockError(req.Service, http.StatusConflict)
+		}
+
+		mockMerge(lb, req.Body["loadbalancer"])
+		lb["provisioning_status"] = "PENDING_UPDATE"
+		return mockJSON(http.StatusOK, map[string]interface{}{"loadbalancer": lb})
+	})
+	c.handle("elb", "DELETE", prefix+"/loadbalancers/{id}", func(req *mockRequest) *mockResponse {
+		lb, ok := c.get("loadbalancers", req.Params["id"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+
+		lb["provisioning_status"] = "PENDING_DELETE"
+		return mockStatus(http.StatusNoContent)
+	})
+}
+
+func TestMockLBLoadBalancerV2_basic(t *testing.T) {
+	t.Parallel()
+
+	c := newMockCloud(t)
+	resourceName := "flexibleengine_lb_loadbalancer_v2.test"
+
+	c.resourceTest(t, resource.TestCase{
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			c.checkDestroyed("loadbalancers"),
+			c.checkDestroyed("ports"),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockLBLoadBalancerV2_basic("mock-lb", "sg-1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "name", "mock-lb"),
+					resource.TestCheckResourceAttr(resourceName, "vip_subnet_id", mockSubnetID),
+					resource.TestCheckResourceAttr(resourceName, "admin_state_up", "true"),
+					resource.TestCheckResourceAttr(resourceName, "security_group_ids.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "tags.key", "value"),
+					resource.TestCheckResourceAttrSet(resourceName, "vip_address"),
+					resource.TestCheckResourceAttrSet(resourceName, "vip_port_id"),
+				),
+			},
+			{
+				Config: testMockLBLoadBalancerV2_basic("mock-lb-update", "sg-2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "name", "mock-lb-update"),
+					resource.TestCheckResourceAttr(resourceName, "security_group_ids.#", "1"),
+					c.checkStored("loadbalancers", resourceName, "name", "mock-lb-update"),
+				),
+			},
+		},
+	})
+}
+
+func testMockLBLoadBalancerV2_basic(name, secgroup string) string {
+	return fmt.Sprintf(`
+resource "flexibleengine_lb_loadbalancer_v2" "test" {
+  name               = "%s"
+  description        = "created by the mock cloud"
+  vip_subnet_id      = "%s"
+  security_group_ids = ["%s"]
+
+  tags = {
+    key = "value"
+  }
+}
+`, name, mockSubnetID, secgroup)
+}
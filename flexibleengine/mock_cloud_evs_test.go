@@ -0,0 +1,764 @@
+package flexibleengine
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// registerEVS registers the volume APIs of the EVS service
+func (c *mockCloud) registerEVS() {
+	createVolume := func(req *mockRequest) *mockResponse {
+		opts, _ := req.Body["volume"].(map[string]interface{})
+		if opts == nil {
+			return mockError(req.Service, http.StatusBadRequest)
+		}
+
+		volumeType, _ := opts["volume_type"].(string)
+		if volumeType == "" {
+			volumeType = "SATA"
+		}
+		metadata, _ := opts["metadata"].(map[string]interface{})
+		if metadata == nil {
+			metadata = make(map[string]interface{})
+		}
+		multiattach, _ := opts["multiattach"].(bool)
+
+		id := c.newID()
+		volume := map[string]interface{}{
+			"id":                    id,
+			"status":                "creating",
+			"name":                  opts["name"],
+			"description":           opts["description"],
+			"size":                  opts["size"],
+			"availability_zone":     opts["availability_zone"],
+			"volume_type":           volumeType,
+			"metadata":              metadata,
+			"multiattach":           multiattach,
+			"attachments":           []interface{}{},
+			"enterprise_project_id": defaultEnterpriseProjectID,
+		}
+		c.put("volumes", id, volume)
+
+		return mockJSON(http.StatusAccepted, map[string]interface{}{"volume": volume})
+	}
+	c.handle("evs", "POST", "/v2/{project}/volumes", createVolume)
+	c.handle("evs", "GET", "/v2/{project}/volumes/{id}", func(req *mockRequest) *mockResponse {
+		volume, ok := c.get("volumes", req.Params["id"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+
+		resp := mockCopy(volume)
+		delete(resp, "enterprise_project_id")
+		switch volume["status"] {
+		case "creating", "extending", "retyping":
+			volume["status"] = "available"
+			if len(volume["attachments"].([]interface{})) > 0 {
+				volume["status"] = "in-use"
+			}
+		case "deleting":
+			c.remove("volumes", req.Params["id"])
+		}
+		return mockJSON(http.StatusOK, map[string]interface{}{"volume": resp})
+	})
+	c.handle("evs", "PUT", "/v2/{project}/volumes/{id}", func(req *mockRequest) *mockResponse {
+		volume, ok := c.get("volumes", req.Params["id"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+
+		oldMetadata := volume["metadata"].(map[string]interface{})
+		opts, _ := req.Body["volume"].(map[string]interface{})
+		for _, key := range []string{"name", "description", "metadata"} {
+			if v, ok := opts[key]; ok {
+				volume[key] = v
+			}
+		}
+		// the order of the yearly/monthly volume is kept in the metadata
+		if orderID, ok := oldMetadata[volumeMetadataOrderID]; ok {
+			volume["metadata"].(map[string]interface{})[volumeMetadataOrderID] = orderID
+		}
+		return mockJSON(http.StatusOK, map[string]interface{}{"volume": volume})
+	})
+	c.handle("evs", "POST", "/v2/{project}/volumes/{id}/action", func(req *mockRequest) *mockResponse {
+		volume, ok := c.get("volumes", req.Params["id"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+
+		// the in-use volumes can not be extended by the v2 API
+		if volume["status"] != "available" {
+			return mockError(req.Service, http.StatusBadRequest)
+		}
+		extend, ok := req.Body["os-extend"].(map[string]interface{})
+		if !ok {
+			return mockError(req.Service, http.StatusBadRequest)
+		}
+		if extend["new_size"].(float64) <= volume["size"].(float64) {
+			return mockError(req.Service, http.StatusBadRequest)
+		}
+		volume["size"] = extend["new_size"]
+		volume["status"] = "extending"
+		return mockStatus(http.StatusAccepted)
+	})
+	c.handle("evs", "DELETE", "/v2/{project}/volumes/{id}", func(req *mockRequest) *mockResponse {
+		volume, ok := c.get("volumes", req.Params["id"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+		// the yearly/monthly volumes can only be unsubscribed
+		if _, ok := volume["metadata"].(map[string]interface{})[volumeMetadataOrderID]; ok {
+			return mockError(req.Service, http.StatusForbidden)
+		}
+
+		volume["status"] = "deleting"
+		return mockStatus(http.StatusAccepted)
+	})
+
+	// the in-use volumes are extended by the v2.1 API
+	c.handle("evs", "POST", "/v2.1/{project}/cloudvolumes/{id}/action", func(req *mockRequest) *mockResponse {
+		volume, ok := c.get("volumes", req.Params["id"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+
+		extend, ok := req.Body["os-extend"].(map[string]interface{})
+		if !ok {
+			return mockError(req.Service, http.StatusBadRequest)
+		}
+		if extend["new_size"].(float64) <= volume["size"].(float64) {
+			return mockError(req.Service, http.StatusBadRequest)
+		}
+		volume["size"] = extend["new_size"]
+		volume["status"] = "extending"
+
+		// the extension of the yearly/monthly volume is paid by an order
+		if bssParam, ok := req.Body["bssParam"].(map[string]interface{}); ok {
+			if bssParam["isAutoPay"] != "true" {
+				return mockError(req.Service, http.StatusBadRequest)
+			}
+			orderID := c.newID()
+			c.put("orders", orderID, map[string]interface{}{"id": orderID, "status": 3, "resource_id": req.Params["id"]})
+			return mockJSON(http.StatusAccepted, map[string]interface{}{"job_id": c.newID(), "order_id": orderID})
+		}
+		return mockJSON(http.StatusAccepted, map[string]interface{}{"job_id": c.newID()})
+	})
+
+	c.handle("evs", "POST", "/v2.1/{project}/cloudvolumes/{id}/retype", func(req *mockRequest) *mockResponse {
+		volume, ok := c.get("volumes", req.Params["id"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+
+		retype, _ := req.Body["os-retype"].(map[string]interface{})
+		newType, _ := retype["new_type"].(string)
+		if newType == "" || newType == volume["volume_type"] {
+			return mockError(req.Service, http.StatusBadRequest)
+		}
+		volume["volume_type"] = newType
+		volume["status"] = "retyping"
+
+		// the retype of the yearly/monthly volume is paid by an order
+		if bssParam, ok := req.Body["bssParam"].(map[string]interface{}); ok {
+			if bssParam["isAutoPay"] != "true" {
+				return mockError(req.Service, http.StatusBadRequest)
+			}
+			orderID := c.newID()
+			c.put("orders", orderID, map[string]interface{}{"id": orderID, "status": 3, "resource_id": req.Params["id"]})
+			return mockJSON(http.StatusAccepted, map[string]interface{}{"job_id": c.newID(), "order_id": orderID})
+		}
+		return mockJSON(http.StatusAccepted, map[string]interface{}{"job_id": c.newID()})
+	})
+
+	// the yearly/monthly volumes are ordered by the v2.1 API
+	c.handle("evs", "POST", "/v2.1/{project}/cloudvolumes", func(req *mockRequest) *mockResponse {
+		bssParam, _ := req.Body["bssParam"].(map[string]interface{})
+		if bssParam["chargingMode"] != "prePaid" || bssParam["isAutoPay"] != "true" {
+			return mockError(req.Service, http.StatusBadRequest)
+		}
+
+		resp := createVolume(req)
+		if resp.Status != http.StatusAccepted {
+			return resp
+		}
+
+		volume := resp.Body.(map[string]interface{})["volume"].(map[string]interface{})
+		id := volume["id"].(string)
+		orderID := c.newOrder(id, bssParam["isAutoRenew"] == "true", func() { c.remove("volumes", id) })
+		volume["metadata"].(map[string]interface{})[volumeMetadataOrderID] = orderID
+		if epsID, ok := req.Body["volume"].(map[string]interface{})["enterprise_project_id"]; ok {
+			volume["enterprise_project_id"] = epsID
+		}
+		return mockJSON(http.StatusOK, map[string]interface{}{"order_id": orderID, "job_id": c.newID()})
+	})
+	c.handle("evs", "POST", "/v2/{project}/cloudvolumes/change-charge-mode", func(req *mockRequest) *mockResponse {
+		bssParam, _ := req.Body["bss_param"].(map[string]interface{})
+		volumeIDs, _ := req.Body["volume_ids"].([]interface{})
+		if bssParam["is_auto_pay"] != true || len(volumeIDs) != 1 {
+			return mockError(req.Service, http.StatusBadRequest)
+		}
+		id := volumeIDs[0].(string)
+		volume, ok := c.get("volumes", id)
+		if !ok {
+			return mockError(req.Service, http.StatusBadRequest)
+		}
+		metadata := volume["metadata"].(map[string]interface{})
+		if _, ok := metadata[volumeMetadataOrderID]; ok {
+			return mockError(req.Service, http.StatusBadRequest)
+		}
+
+		orderID := c.newOrder(id, bssParam["is_auto_renew"] == true, func() { c.remove("volumes", id) })
+		metadata[volumeMetadataOrderID] = orderID
+		return mockJSON(http.StatusOK, map[string]interface{}{"order_id": orderID})
+	})
+	c.handle("evs", "GET", "/v2/{project}/cloudvolumes/detail", func(req *mockRequest) *mockResponse {
+		query := req.URL.Query()
+		ids := make([]string, 0, len(c.store["volumes"]))
+		for id, volume := range c.store["volumes"] {
+			matched := true
+			for _, key := range []string{"name", "status", "availability_zone"} {
+				if v := query.Get(key); v != "" && v != fmt.Sprint(volume[key]) {
+					matched = false
+				}
+			}
+			if matched {
+				ids = append(ids, id)
+			}
+		}
+		sort.Strings(ids)
+
+		result := []interface{}{}
+		if offset, _ := strconv.Atoi(query.Get("offset")); offset < len(ids) {
+			for _, id := range ids[offset:] {
+				volume := mockCopy(c.store["volumes"][id])
+				volume["tags"] = c.tags[id]
+				result = append(result, volume)
+			}
+		}
+		return mockJSON(http.StatusOK, map[string]interface{}{"volumes": result, "count": len(ids)})
+	})
+	c.handle("evs", "GET", "/v2/{project}/cloudvolumes/{id}", func(req *mockRequest) *mockResponse {
+		volume, ok := c.get("volumes", req.Params["id"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+		return mockJSON(http.StatusOK, map[string]interface{}{"volume": volume})
+	})
+	c.handleTags("evs", "/v2/{project}/os-vendor-volumes")
+
+	c.handle("evs", "POST", "/v2/{project}/snapshots", func(req *mockRequest) *mockResponse {
+		opts, _ := req.Body["snapshot"].(map[string]interface{})
+		volume, ok := c.get("volumes", fmt.Sprint(opts["volume_id"]))
+		if !ok {
+			return mockError(req.Service, http.StatusBadRequest)
+		}
+		// the snapshot of the in-use volume must be forced
+		if force, _ := opts["force"].(bool); volume["status"] == "in-use" && !force {
+			return mockError(req.Service, http.StatusBadRequest)
+		}
+
+		metadata, _ := opts["metadata"].(map[string]interface{})
+		if metadata == nil {
+			metadata = make(map[string]interface{})
+		}
+		id := c.newID()
+		snapshot := map[string]interface{}{
+			"id":          id,
+			"status":      "creating",
+			"name":        opts["name"],
+			"description": opts["description"],
+			"volume_id":   opts["volume_id"],
+			"size":        volume["size"],
+			"metadata":    metadata,
+			// the snapshots are created one second apart to keep their order
+			"created_at": time.Date(2023, 1, 1, 0, 0, c.seq, 0, time.UTC).Format("2006-01-02T15:04:05.000000"),
+		}
+		c.put("snapshots", id, snapshot)
+
+		return mockJSON(http.StatusAccepted, map[string]interface{}{"snapshot": snapshot})
+	})
+	c.handle("evs", "GET", "/v2/{project}/snapshots", func(req *mockRequest) *mockResponse {
+		query := req.URL.Query()
+		result := []interface{}{}
+		for _, snapshot := range c.store["snapshots"] {
+			matched := true
+			for _, key := range []string{"name", "status", "volume_id"} {
+				if v := query.Get(key); v != "" && v != fmt.Sprint(snapshot[key]) {
+					matched = false
+				}
+			}
+			if matched {
+				result = append(result, snapshot)
+			}
+		}
+		return mockJSON(http.StatusOK, map[string]interface{}{"snapshots": result})
+	})
+	c.handle("evs", "GET", "/v2/{project}/snapshots/{id}", func(req *mockRequest) *mockResponse {
+		snapshot, ok := c.get("snapshots", req.Params["id"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+
+		resp := mockCopy(snapshot)
+		switch snapshot["status"] {
+		case "creating":
+			snapshot["status"] = "available"
+		case "deleting":
+			c.remove("snapshots", req.Params["id"])
+		}
+		return mockJSON(http.StatusOK, map[string]interface{}{"snapshot": resp})
+	})
+	c.handle("evs", "PUT", "/v2/{project}/cloudsnapshots/{id}", func(req *mockRequest) *mockResponse {
+		snapshot, ok := c.get("snapshots", req.Params["id"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+
+		mockMerge(snapshot, req.Body["snapshot"])
+		return mockJSON(http.StatusOK, map[string]interface{}{"snapshot": snapshot})
+	})
+	c.handle("evs", "PUT", "/v2/{project}/snapshots/{id}/metadata", func(req *mockRequest) *mockResponse {
+		snapshot, ok := c.get("snapshots", req.Params["id"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+
+		metadata, _ := req.Body["metadata"].(map[string]interface{})
+		if metadata == nil {
+			metadata = make(map[string]interface{})
+		}
+		snapshot["metadata"] = metadata
+		return mockJSON(http.StatusOK, map[string]interface{}{"metadata": metadata})
+	})
+	c.handle("evs", "DELETE", "/v2/{project}/snapshots/{id}", func(req *mockRequest) *mockResponse {
+		snapshot, ok := c.get("snapshots", req.Params["id"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+
+		snapshot["status"] = "deleting"
+		return mockStatus(http.StatusAccepted)
+	})
+}
+
+func TestMockBlockStorageVolumeV2_basic(t *testing.T) {
+	t.Parallel()
+
+	c := newMockCloud(t)
+	resourceName := "flexibleengine_blockstorage_volume_v2.test"
+
+	c.resourceTest(t, resource.TestCase{
+		CheckDestroy: c.checkDestroyed("volumes"),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockBlockStorageVolumeV2_basic("mock-volume", 10),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "name", "mock-volume"),
+					resource.TestCheckResourceAttr(resourceName, "size", "10"),
+					resource.TestCheckResourceAttr(resourceName, "volume_type", "SSD"),
+					resource.TestCheckResourceAttr(resourceName, "metadata.foo", "bar"),
+					resource.TestCheckResourceAttr(resourceName, "tags.key", "value"),
+					resource.TestCheckResourceAttr(resourceName, "enterprise_project_id", "0"),
+				),
+			},
+			{
+				Config: testMockBlockStorageVolumeV2_basic("mock-volume-update", 20),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "name", "mock-volume-update"),
+					resource.TestCheckResourceAttr(resourceName, "size", "20"),
+					c.checkStored("volumes", resourceName, "size", "20"),
+				),
+			},
+		},
+	})
+}
+
+func TestMockBlockStorageVolumeV2_prePaid(t *testing.T) {
+	t.Parallel()
+
+	c := newMockCloud(t)
+	resourceName := "flexibleengine_blockstorage_volume_v2.test"
+
+	c.resourceTest(t, resource.TestCase{
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			c.checkDestroyed("volumes"),
+			c.checkDestroyed("subscriptions"),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockBlockStorageVolumeV2_prePaid(10, "false"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "charging_mode", "prePaid"),
+					resource.TestCheckResourceAttr(resourceName, "metadata.%", "1"),
+					c.checkStored("subscriptions", resourceName, "auto_renew", "false"),
+				),
+			},
+			{
+				Config: testMockBlockStorageVolumeV2_prePaid(20, "true"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "size", "20"),
+					c.checkStored("volumes", resourceName, "size", "20"),
+					c.checkStored("subscriptions", resourceName, "auto_renew", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestMockBlockStorageVolumeV2_changeToPrePaid(t *testing.T) {
+	t.Parallel()
+
+	c := newMockCloud(t)
+	resourceName := "flexibleengine_blockstorage_volume_v2.test"
+
+	c.resourceTest(t, resource.TestCase{
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			c.checkDestroyed("volumes"),
+			c.checkDestroyed("subscriptions"),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockBlockStorageVolumeV2_basic("mock-volume", 10),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "charging_mode", "postPaid"),
+				),
+			},
+			{
+				Config: testMockBlockStorageVolumeV2_prePaid(10, "false"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "charging_mode", "prePaid"),
+					resource.TestCheckResourceAttr(resourceName, "metadata.%", "1"),
+					c.checkStored("subscriptions", resourceName, "auto_renew", "false"),
+				),
+			},
+		},
+	})
+}
+
+func TestMockBlockStorageVolumeV2_retype(t *testing.T) {
+	t.Parallel()
+
+	c := newMockCloud(t)
+	resourceName := "flexibleengine_blockstorage_volume_v2.test"
+
+	c.resourceTest(t, resource.TestCase{
+		CheckDestroy: c.checkDestroyed("volumes"),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockBlockStorageVolumeV2_update("SSD", 10, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "volume_type", "SSD"),
+					c.attachVolume(resourceName),
+				),
+			},
+			{
+				Config: testMockBlockStorageVolumeV2_update("SAS", 20, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "volume_type", "SAS"),
+					resource.TestCheckResourceAttr(resourceName, "size", "20"),
+					resource.TestCheckResourceAttr(resourceName, "attachment.#", "1"),
+					c.checkStored("volumes", resourceName, "volume_type", "SAS"),
+					c.checkStored("volumes", resourceName, "status", "in-use"),
+				),
+			},
+		},
+	})
+}
+
+func TestMockBlockStorageVolumeV2_shrink(t *testing.T) {
+	t.Parallel()
+
+	c := newMockCloud(t)
+	resourceName := "flexibleengine_blockstorage_volume_v2.test"
+	var volumeID string
+
+	c.resourceTest(t, resource.TestCase{
+		CheckDestroy: c.checkDestroyed("volumes"),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockBlockStorageVolumeV2_update("SSD", 20, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "size", "20"),
+					func(s *terraform.State) error {
+						volumeID = s.RootModule().Resources[resourceName].Primary.ID
+						return nil
+					},
+				),
+			},
+			{
+				Config:      testMockBlockStorageVolumeV2_update("SSD", 10, false),
+				ExpectError: regexp.MustCompile("size can not be shrunk from 20 to 10 GB"),
+			},
+			{
+				Config: testMockBlockStorageVolumeV2_update("SSD", 10, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "size", "10"),
+					c.checkStored("volumes", resourceName, "size", "10"),
+					func(s *terraform.State) error {
+						if s.RootModule().Resources[resourceName].Primary.ID == volumeID {
+							return fmt.Errorf("the volume %s was not replaced", volumeID)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestMockBlockStorageVolumesDataSource(t *testing.T) {
+	t.Parallel()
+
+	c := newMockCloud(t)
+	dataSourceName := "data.flexibleengine_blockstorage_volumes.test"
+
+	c.resourceTest(t, resource.TestCase{
+		CheckDestroy: c.checkDestroyed("volumes"),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockBlockStorageVolumesDataSource,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "ids.#", "1"),
+					resource.TestCheckResourceAttrPair(dataSourceName, "ids.0",
+						"flexibleengine_blockstorage_volume_v2.second", "id"),
+					resource.TestCheckResourceAttr(dataSourceName, "volumes.0.name", "mock-volume-2"),
+					resource.TestCheckResourceAttr(dataSourceName, "volumes.0.size", "20"),
+					resource.TestCheckResourceAttr(dataSourceName, "volumes.0.volume_type", "SAS"),
+					resource.TestCheckResourceAttr(dataSourceName, "volumes.0.status", "available"),
+					resource.TestCheckResourceAttr(dataSourceName, "volumes.0.tags.foo", "bar"),
+				),
+			},
+		},
+	})
+}
+
+// attachVolume attaches the volume to an instance in the mock cloud, which makes it in-use
+func (c *mockCloud) attachVolume(resourceName string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("Not found: %s", resourceName)
+		}
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		volume, ok := c.get("volumes", rs.Primary.ID)
+		if !ok {
+			return fmt.Errorf("volume %s not found in the mock cloud", rs.Primary.ID)
+		}
+		volume["attachments"] = []interface{}{
+			map[string]interface{}{
+				"id":        rs.Primary.ID,
+				"server_id": c.newID(),
+				"volume_id": rs.Primary.ID,
+				"device":    "/dev/vdb",
+			},
+		}
+		volume["status"] = "in-use"
+		return nil
+	}
+}
+
+func testMockBlockStorageVolumeV2_update(volumeType string, size int, replaceOnShrink bool) string {
+	return fmt.Sprintf(`
+resource "flexibleengine_blockstorage_volume_v2" "test" {
+  name              = "mock-volume"
+  size              = %d
+  volume_type       = "%s"
+  availability_zone = "eu-west-0a"
+  replace_on_shrink = %t
+}
+`, size, volumeType, replaceOnShrink)
+}
+
+const testMockBlockStorageVolumesDataSource = `
+resource "flexibleengine_blockstorage_volume_v2" "first" {
+  name              = "mock-volume-1"
+  size              = 10
+  availability_zone = "eu-west-0a"
+
+  tags = {
+    foo = "baz"
+  }
+}
+
+resource "flexibleengine_blockstorage_volume_v2" "second" {
+  name              = "mock-volume-2"
+  size              = 20
+  volume_type       = "SAS"
+  availability_zone = flexibleengine_blockstorage_volume_v2.first.availability_zone
+
+  tags = {
+    foo = "bar"
+  }
+}
+
+data "flexibleengine_blockstorage_volumes" "test" {
+  availability_zone = flexibleengine_blockstorage_volume_v2.second.availability_zone
+  status            = "available"
+
+  tags = {
+    foo = "bar"
+  }
+}
+`
+
+func testMockBlockStorageVolumeV2_basic(name string, size int) string {
+	return fmt.Sprintf(`
+resource "flexibleengine_blockstorage_volume_v2" "test" {
+  name              = "%s"
+  description       = "created by the mock cloud"
+  size              = %d
+  volume_type       = "SSD"
+  availability_zone = "eu-west-0a"
+
+  metadata = {
+    foo = "bar"
+  }
+
+  tags = {
+    key = "value"
+  }
+}
+`, name, size)
+}
+
+func testMockBlockStorageVolumeV2_prePaid(size int, autoRenew string) string {
+	return fmt.Sprintf(`
+resource "flexibleengine_blockstorage_volume_v2" "test" {
+  name              = "mock-volume"
+  description       = "created by the mock cloud"
+  size              = %d
+  volume_type       = "SSD"
+  availability_zone = "eu-west-0a"
+  charging_mode     = "prePaid"
+  period_unit       = "month"
+  period            = 1
+  auto_renew        = "%s"
+
+  metadata = {
+    foo = "bar"
+  }
+}
+`, size, autoRenew)
+}
+
+func TestMockBlockStorageSnapshotV2_basic(t *testing.T) {
+	t.Parallel()
+
+	c := newMockCloud(t)
+	resourceName := "flexibleengine_blockstorage_snapshot_v2.test"
+
+	c.resourceTest(t, resource.TestCase{
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			c.checkDestroyed("snapshots"),
+			c.checkDestroyed("volumes"),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockBlockStorageSnapshotV2_basic("mock-snapshot", "bar"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "name", "mock-snapshot"),
+					resource.TestCheckResourceAttr(resourceName, "status", "available"),
+					resource.TestCheckResourceAttr(resourceName, "size", "10"),
+					resource.TestCheckResourceAttr(resourceName, "metadata.foo", "bar"),
+					resource.TestCheckResourceAttrPair(resourceName, "volume_id",
+						"flexibleengine_blockstorage_volume_v2.test", "id"),
+				),
+			},
+			{
+				Config: testMockBlockStorageSnapshotV2_basic("mock-snapshot-update", "baz"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "name", "mock-snapshot-update"),
+					resource.TestCheckResourceAttr(resourceName, "metadata.foo", "baz"),
+					c.checkStored("snapshots", resourceName, "name", "mock-snapshot-update"),
+				),
+			},
+		},
+	})
+}
+
+func TestMockBlockStorageSnapshotsDataSource(t *testing.T) {
+	t.Parallel()
+
+	c := newMockCloud(t)
+	dataSourceName := "data.flexibleengine_blockstorage_snapshots.test"
+
+	c.resourceTest(t, resource.TestCase{
+		CheckDestroy: c.checkDestroyed("snapshots"),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockBlockStorageSnapshotsDataSource(false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "ids.#", "2"),
+					resource.TestCheckResourceAttrPair(dataSourceName, "ids.0",
+						"flexibleengine_blockstorage_snapshot_v2.second", "id"),
+				),
+			},
+			{
+				Config: testMockBlockStorageSnapshotsDataSource(true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "snapshots.#", "1"),
+					resource.TestCheckResourceAttr(dataSourceName, "snapshots.0.name", "mock-snapshot-2"),
+					resource.TestCheckResourceAttr(dataSourceName, "snapshots.0.status", "available"),
+					resource.TestCheckResourceAttrPair(dataSourceName, "snapshots.0.id",
+						"flexibleengine_blockstorage_snapshot_v2.second", "id"),
+				),
+			},
+		},
+	})
+}
+
+func testMockBlockStorageSnapshotV2_basic(name, metadata string) string {
+	return fmt.Sprintf(`
+resource "flexibleengine_blockstorage_volume_v2" "test" {
+  name              = "mock-volume"
+  size              = 10
+  availability_zone = "eu-west-0a"
+}
+
+resource "flexibleengine_blockstorage_snapshot_v2" "test" {
+  volume_id   = flexibleengine_blockstorage_volume_v2.test.id
+  name        = "%s"
+  description = "created by the mock cloud"
+
+  metadata = {
+    foo = "%s"
+  }
+}
+`, name, metadata)
+}
+
+func testMockBlockStorageSnapshotsDataSource(mostRecent bool) string {
+	return fmt.Sprintf(`
+resource "flexibleengine_blockstorage_volume_v2" "test" {
+  name              = "mock-volume"
+  size              = 10
+  availability_zone = "eu-west-0a"
+}
+
+resource "flexibleengine_blockstorage_snapshot_v2" "first" {
+  volume_id = flexibleengine_blockstorage_volume_v2.test.id
+  name      = "mock-snapshot-1"
+}
+
+resource "flexibleengine_blockstorage_snapshot_v2" "second" {
+  volume_id = flexibleengine_blockstorage_snapshot_v2.first.volume_id
+  name      = "mock-snapshot-2"
+}
+
+data "flexibleengine_blockstorage_snapshots" "test" {
+  volume_id   = flexibleengine_blockstorage_snapshot_v2.second.volume_id
+  status      = "available"
+  most_recent = %t
+}
+`, mostRecent)
+}
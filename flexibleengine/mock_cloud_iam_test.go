@@ -0,0 +1,61 @@
+package flexibleengine
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestMockCallerIdentity_basic(t *testing.T) {
+	t.Parallel()
+
+	c := newMockCloud(t)
+	dataSourceName := "data.flexibleengine_caller_identity.current"
+
+	c.resourceTest(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{
+				Config: testMockCallerIdentity_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "id", mockDomainID),
+					resource.TestCheckResourceAttr(dataSourceName, "domain_id", mockDomainID),
+					resource.TestCheckResourceAttr(dataSourceName, "domain_name", "mock-domain"),
+					resource.TestCheckResourceAttr(dataSourceName, "project_id", mockProjectID),
+					resource.TestCheckResourceAttr(dataSourceName, "project_name", mockRegion),
+					resource.TestCheckResourceAttr(dataSourceName, "user_id", "mock-user-id"),
+					resource.TestCheckResourceAttr(dataSourceName, "user_name", "mock-user"),
+					resource.TestCheckResourceAttr(dataSourceName, "region", mockRegion),
+					resource.TestCheckResourceAttr(dataSourceName, "auth_method", "aksk"),
+				),
+			},
+		},
+	})
+}
+
+func TestMockCallerIdentity_noIAMPermission(t *testing.T) {
+	t.Parallel()
+
+	c := newMockCloud(t)
+	c.injectFault("iam", "GET", "/v3.0/OS-CREDENTIAL/credentials/{ak}", http.StatusForbidden, -1)
+	dataSourceName := "data.flexibleengine_caller_identity.current"
+
+	// the user is optional as the credentials may not be allowed to query IAM
+	c.resourceTest(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{
+				Config: testMockCallerIdentity_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "domain_id", mockDomainID),
+					resource.TestCheckResourceAttr(dataSourceName, "project_id", mockProjectID),
+					resource.TestCheckResourceAttr(dataSourceName, "user_id", ""),
+					resource.TestCheckResourceAttr(dataSourceName, "auth_method", "aksk"),
+				),
+			},
+		},
+	})
+}
+
+const testMockCallerIdentity_basic = `
+data "flexibleengine_caller_identity" "current" {}
+`
@@ -0,0 +1,391 @@
+package flexibleengine
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+// registerIMS registers the private image APIs of the IMS service, the public
+// images are registered with the ECS APIs.
+func (c *mockCloud) registerIMS() {
+	c.handle("ims", "POST", "/v2/cloudimages/action", func(req *mockRequest) *mockResponse {
+		name, _ := req.Body["name"].(string)
+		if name == "" {
+			return mockError(req.Service, http.StatusBadRequest)
+		}
+
+		image := map[string]interface{}{
+			"name":                  name,
+			"__description":         req.Body["description"],
+			"status":                "active",
+			"visibility":            "private",
+			"__imagetype":           "private",
+			"disk_format":           "zvhd2",
+			"__image_size":          "0",
+			"min_ram":               req.Body["min_ram"],
+			"max_ram":               fmt.Sprint(req.Body["max_ram"]),
+			"enterprise_project_id": req.Body["enterprise_project_id"],
+		}
+		if image["enterprise_project_id"] == nil {
+			image["enterprise_project_id"] = defaultEnterpriseProjectID
+		}
+		if image["min_ram"] == nil {
+			image["min_ram"] = 0
+		}
+		if image["max_ram"] == "<nil>" {
+			image["max_ram"] = ""
+		}
+
+		switch {
+		case req.Body["instance_id"] != nil:
+			server, ok := c.get("servers", fmt.Sprint(req.Body["instance_id"]))
+			if !ok {
+				return mockError(req.Service, http.StatusBadRequest)
+			}
+			image["__data_origin"] = fmt.Sprintf("instance,%s", server["id"])
+			image["min_disk"] = 40
+			image["__os_version"] = "Ubuntu 20.04 server 64bit"
+		case req.Body["volume_id"] != nil:
+			volume, ok := c.get("volumes", fmt.Sprint(req.Body["volume_id"]))
+			if !ok || req.Body["type"] != "DataImage" {
+				return mockError(req.Service, http.StatusBadRequest)
+			}
+			image["__data_origin"] = fmt.Sprintf("volume,%s", volume["id"])
+			image["__imagetype"] = "private"
+			image["min_disk"] = volume["size"]
+		case req.Body["image_url"] != nil:
+			// the image file is in the format of bucket:object
+			if !strings.Contains(fmt.Sprint(req.Body["image_url"]), ":") || req.Body["min_disk"] == nil {
+				return mockError(req.Service, http.StatusBadRequest)
+			}
+			image["__data_origin"] = fmt.Sprintf("file,%s", req.Body["image_url"])
+			image["min_disk"] = req.Body["min_disk"]
+			image["__os_version"] = req.Body["os_version"]
+			image["__system__cmkid"] = req.Body["cmk_id"]
+		default:
+			return mockError(req.Service, http.StatusBadRequest)
+		}
+
+		id := c.newID()
+		image["id"] = id
+		c.put("cloudimages", id, image)
+
+		if tagList, ok := req.Body["image_tags"].([]interface{}); ok {
+			c.tags[id] = make(map[string]string)
+			for _, raw := range tagList {
+				tag := raw.(map[string]interface{})
+				value, _ := tag["value"].(string)
+				c.tags[id][tag["key"].(string)] = value
+			}
+		}
+
+		jobID := c.newID()
+		c.put("ims_jobs", jobID, map[string]interface{}{
+			"job_id":   jobID,
+			"job_type": "createImageByInstance",
+			"status":   "SUCCESS",
+			"entities": map[string]interface{}{"image_id": id},
+		})
+		return mockJSON(http.StatusOK, map[string]interface{}{"job_id": jobID})
+	})
+	c.handle("ims", "GET", "/v1/{project}/jobs/{id}", func(req *mockRequest) *mockResponse {
+		job, ok := c.get("ims_jobs", req.Params["id"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+		return mockJSON(http.StatusOK, job)
+	})
+	c.handle("ims", "GET", "/v2/cloudimages", func(req *mockRequest) *mockResponse {
+		result := []interface{}{}
+		if image, ok := c.get("cloudimages", req.URL.Query().Get("id")); ok {
+			result = append(result, image)
+		}
+		return mockJSON(http.StatusOK, map[string]interface{}{"images": result})
+	})
+
+	c.handle("ims", "GET", "/v2/images/{id}", func(req *mockRequest) *mockResponse {
+		image, ok := c.get("images", req.Params["id"])
+		if !ok {
+			image, ok = c.get("cloudimages", req.Params["id"])
+		}
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+		return mockJSON(http.StatusOK, image)
+	})
+	c.handle("ims", "PATCH", "/v2/images/{id}", func(req *mockRequest) *mockResponse {
+		image, ok := c.get("cloudimages", req.Params["id"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+
+		// the body is a JSON patch
+		var patches []map[string]interface{}
+		if err := json.Unmarshal(req.Raw, &patches); err != nil {
+			return mockError(req.Service, http.StatusBadRequest)
+		}
+		for _, patch := range patches {
+			if patch["op"] != "replace" || patch["path"] != "/name" {
+				return mockError(req.Service, http.StatusBadRequest)
+			}
+			image["name"] = patch["value"]
+		}
+		return mockJSON(http.StatusOK, image)
+	})
+	c.handle("ims", "DELETE", "/v2/images/{id}", func(req *mockRequest) *mockResponse {
+		if _, ok := c.get("cloudimages", req.Params["id"]); !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+
+		c.remove("cloudimages", req.Params["id"])
+		return mockStatus(http.StatusNoContent)
+	})
+	c.handleTags("ims", "/v2/{project}/images")
+
+	// the members are keyed by image/member
+	c.handle("ims", "POST", "/v2/images/{id}/members", func(req *mockRequest) *mockResponse {
+		memberID, _ := req.Body["member"].(string)
+		if _, ok := c.get("cloudimages", req.Params["id"]); !ok || memberID == "" {
+			return mockError(req.Service, http.StatusBadRequest)
+		}
+
+		key := req.Params["id"] + "/" + memberID
+		if _, ok := c.get("image_members", key); ok {
+			return mockError(req.Service, http.StatusConflict)
+		}
+		member := map[string]interface{}{
+			"image_id":   req.Params["id"],
+			"member_id":  memberID,
+			"status":     "pending",
+			"schema":     "/v2/schemas/member",
+			"created_at": "2023-01-01T00:00:00Z",
+			"updated_at": "2023-01-01T00:00:00Z",
+		}
+		c.put("image_members", key, member)
+		return mockJSON(http.StatusOK, member)
+	})
+	c.handle("ims", "GET", "/v2/images/{id}/members", func(req *mockRequest) *mockResponse {
+		if _, ok := c.get("cloudimages", req.Params["id"]); !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+
+		keys := make([]string, 0, len(c.store["image_members"]))
+		for key, member := range c.store["image_members"] {
+			if member["image_id"] == req.Params["id"] {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+
+		result := []interface{}{}
+		for _, key := range keys {
+			result = append(result, c.store["image_members"][key])
+		}
+		return mockJSON(http.StatusOK, map[string]interface{}{"members": result})
+	})
+	c.handle("ims", "GET", "/v2/images/{id}/members/{member}", func(req *mockRequest) *mockResponse {
+		member, ok := c.get("image_members", req.Params["id"]+"/"+req.Params["member"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+		return mockJSON(http.StatusOK, member)
+	})
+	c.handle("ims", "PUT", "/v2/images/{id}/members/{member}", func(req *mockRequest) *mockResponse {
+		member, ok := c.get("image_members", req.Params["id"]+"/"+req.Params["member"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+
+		switch req.Body["status"] {
+		case "accepted", "rejected", "pending":
+			member["status"] = req.Body["status"]
+		default:
+			return mockError(req.Service, http.StatusBadRequest)
+		}
+		return mockJSON(http.StatusOK, member)
+	})
+	c.handle("ims", "DELETE", "/v2/images/{id}/members/{member}", func(req *mockRequest) *mockResponse {
+		key := req.Params["id"] + "/" + req.Params["member"]
+		if _, ok := c.get("image_members", key); !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+
+		c.remove("image_members", key)
+		return mockStatus(http.StatusNoContent)
+	})
+}
+
+func TestMockImagesImage_basic(t *testing.T) {
+	t.Parallel()
+
+	c := newMockCloud(t)
+	resourceName := "flexibleengine_images_image.test"
+
+	c.resourceTest(t, resource.TestCase{
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			c.checkDestroyed("cloudimages"),
+			c.checkDestroyed("servers"),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockImagesImage_basic("mock-image", "bar"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "name", "mock-image"),
+					resource.TestCheckResourceAttr(resourceName, "status", "active"),
+					resource.TestCheckResourceAttr(resourceName, "visibility", "private"),
+					resource.TestCheckResourceAttr(resourceName, "max_ram", "4096"),
+					resource.TestCheckResourceAttr(resourceName, "min_ram", "1024"),
+					resource.TestCheckResourceAttr(resourceName, "tags.foo", "bar"),
+					resource.TestCheckResourceAttrPair(resourceName, "instance_id",
+						"flexibleengine_compute_instance_v2.test", "id"),
+				),
+			},
+			{
+				Config: testMockImagesImage_basic("mock-image-update", "baz"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "name", "mock-image-update"),
+					resource.TestCheckResourceAttr(resourceName, "tags.foo", "baz"),
+					c.checkStored("cloudimages", resourceName, "name", "mock-image-update"),
+				),
+			},
+		},
+	})
+}
+
+func TestMockImagesImage_volumeAndOBS(t *testing.T) {
+	t.Parallel()
+
+	c := newMockCloud(t)
+
+	c.resourceTest(t, resource.TestCase{
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			c.checkDestroyed("cloudimages"),
+			c.checkDestroyed("volumes"),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockImagesImage_volumeAndOBS,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("flexibleengine_images_image.data", "volume_id",
+						"flexibleengine_blockstorage_volume_v2.test", "id"),
+					resource.TestCheckResourceAttr("flexibleengine_images_image.data", "min_disk", "20"),
+					resource.TestCheckResourceAttr("flexibleengine_images_image.obs", "min_disk", "40"),
+					resource.TestCheckResourceAttr("flexibleengine_images_image.obs", "os_version", "CentOS 7.6 64bit"),
+					resource.TestCheckResourceAttr("flexibleengine_images_image.obs", "data_origin",
+						"file,mock-bucket:centos.qcow2"),
+				),
+			},
+		},
+	})
+}
+
+func TestMockImagesImageShare_basic(t *testing.T) {
+	t.Parallel()
+
+	c := newMockCloud(t)
+	resourceName := "flexibleengine_images_image_share.test"
+	accepterName := "flexibleengine_images_image_share_accepter.test"
+	otherProjectID := "1d3a2f6a3a7c4c1b8c1f2e2b1b4f6a7d"
+
+	c.resourceTest(t, resource.TestCase{
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			c.checkDestroyed("image_members"),
+			c.checkDestroyed("cloudimages"),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockImagesImageShare_basic(fmt.Sprintf("%q, %q", mockProjectID, otherProjectID), "accepted"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(resourceName, "source_image_id",
+						"flexibleengine_images_image.test", "id"),
+					resource.TestCheckResourceAttr(resourceName, "target_project_ids.#", "2"),
+					resource.TestCheckResourceAttr(resourceName, "members.#", "2"),
+					resource.TestCheckResourceAttr(accepterName, "member_id", mockProjectID),
+					resource.TestCheckResourceAttr(accepterName, "status", "accepted"),
+				),
+			},
+			{
+				Config: testMockImagesImageShare_basic(fmt.Sprintf("%q", mockProjectID), "rejected"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "target_project_ids.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "members.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "members.0.member_id", mockProjectID),
+					resource.TestCheckResourceAttr(accepterName, "status", "rejected"),
+				),
+			},
+		},
+	})
+}
+
+func testMockImagesImage_basic(name, tag string) string {
+	return fmt.Sprintf(`
+resource "flexibleengine_compute_instance_v2" "test" {
+  name      = "mock-ecs"
+  image_id  = "%s"
+  flavor_id = "s3.small.1"
+
+  network {
+    uuid = "%s"
+  }
+}
+
+resource "flexibleengine_images_image" "test" {
+  name        = "%s"
+  instance_id = flexibleengine_compute_instance_v2.test.id
+  description = "created by the mock cloud"
+  max_ram     = 4096
+  min_ram     = 1024
+
+  tags = {
+    foo = "%s"
+  }
+}
+`, mockImageID, mockNetworkID, name, tag)
+}
+
+const testMockImagesImage_volumeAndOBS = `
+resource "flexibleengine_blockstorage_volume_v2" "test" {
+  name              = "mock-volume"
+  size              = 20
+  availability_zone = "eu-west-0a"
+}
+
+resource "flexibleengine_images_image" "data" {
+  name      = "mock-data-image"
+  volume_id = flexibleengine_blockstorage_volume_v2.test.id
+}
+
+resource "flexibleengine_images_image" "obs" {
+  name       = "mock-obs-image"
+  image_url  = "mock-bucket:centos.qcow2"
+  min_disk   = 40
+  os_version = "CentOS 7.6 64bit"
+  is_config  = true
+}
+`
+
+func testMockImagesImageShare_basic(projectIDs, status string) string {
+	return fmt.Sprintf(`
+resource "flexibleengine_images_image" "test" {
+  name      = "mock-share-image"
+  image_url = "mock-bucket:centos.qcow2"
+  min_disk  = 40
+}
+
+resource "flexibleengine_images_image_share" "test" {
+  source_image_id    = flexibleengine_images_image.test.id
+  target_project_ids = [%s]
+}
+
+resource "flexibleengine_images_image_share_accepter" "test" {
+  image_id = flexibleengine_images_image_share.test.id
+  status   = "%s"
+}
+`, projectIDs, status)
+}
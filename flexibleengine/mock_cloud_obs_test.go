@@ -0,0 +1,208 @@
+package flexibleengine
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+// mockOBSSubresources lists the sub-resources of a bucket which are served by
+// the OBS backend, they are passed as query keys like ?versioning.
+var mockOBSSubresources = []string{
+	"storagePolicy", "storageClass", "versioning", "encryption", "logging", "lifecycle", "website", "cors",
+}
+
+var mockOBSVersioningStatus = regexp.MustCompile(`<Status>(\w+)</Status>`)
+
+// registerOBS registers the bucket APIs of the OBS service, the bucket is
+// addressed in the virtual-host style and the sub-resource in the query.
+func (c *mockCloud) registerOBS() {
+	c.handle("obs", "PUT", "/", func(req *mockRequest) *mockResponse {
+		if req.Bucket == "" {
+			return mockError(req.Service, http.StatusBadRequest)
+		}
+
+		switch mockOBSSubresource(req) {
+		case "":
+			if _, ok := c.get("buckets", req.Bucket); ok {
+				return mockOBSError(http.StatusConflict, "BucketAlreadyOwnedByYou")
+			}
+
+			storageClass := mockOBSHeader(req, "storage-class")
+			if storageClass == "" {
+				storageClass = req.Header.Get("x-default-storage-class")
+			}
+			if storageClass == "" {
+				storageClass = "STANDARD"
+			}
+			epsID := mockOBSHeader(req, "epid")
+			if epsID == "" {
+				epsID = defaultEnterpriseProjectID
+			}
+			c.put("buckets", req.Bucket, map[string]interface{}{
+				"id":                    req.Bucket,
+				"storage_class":         storageClass,
+				"versioning":            "",
+				"az_redundancy":         mockOBSHeader(req, "az-redundancy"),
+				"enterprise_project_id": epsID,
+			})
+			return mockStatus(http.StatusOK)
+		case "versioning":
+			bucket, ok := c.get("buckets", req.Bucket)
+			if !ok {
+				return mockOBSError(http.StatusNotFound, "NoSuchBucket")
+			}
+
+			match := mockOBSVersioningStatus.FindSubmatch(req.Raw)
+			if match == nil {
+				return mockOBSError(http.StatusBadRequest, "MalformedXML")
+			}
+			bucket["versioning"] = string(match[1])
+			return mockStatus(http.StatusOK)
+		}
+		return mockError(req.Service, http.StatusNotImplemented)
+	})
+	c.handle("obs", "HEAD", "/", func(req *mockRequest) *mockResponse {
+		bucket, ok := c.get("buckets", req.Bucket)
+		if !ok {
+			return mockStatus(http.StatusNotFound)
+		}
+
+		header := map[string]string{}
+		for _, prefix := range []string{"x-amz-", "x-obs-"} {
+			header[prefix+"epid"] = bucket["enterprise_project_id"].(string)
+			if az := bucket["az_redundancy"].(string); az != "" {
+				header[prefix+"az-redundancy"] = az
+			}
+		}
+		return &mockResponse{Status: http.StatusOK, Header: header}
+	})
+	c.handle("obs", "GET", "/", func(req *mockRequest) *mockResponse {
+		bucket, ok := c.get("buckets", req.Bucket)
+		if !ok {
+			return mockOBSError(http.StatusNotFound, "NoSuchBucket")
+		}
+
+		switch mockOBSSubresource(req) {
+		case "storagePolicy":
+			return mockOBSXML(fmt.Sprintf("<StoragePolicy><DefaultStorageClass>%s</DefaultStorageClass></StoragePolicy>",
+				bucket["storage_class"]))
+		case "storageClass":
+			return mockOBSXML(fmt.Sprintf("<StorageClass>%s</StorageClass>", bucket["storage_class"]))
+		case "versioning":
+			var status string
+			if v := bucket["versioning"].(string); v != "" {
+				status = fmt.Sprintf("<Status>%s</Status>", v)
+			}
+			return mockOBSXML(fmt.Sprintf("<VersioningConfiguration>%s</VersioningConfiguration>", status))
+		case "logging":
+			return mockOBSXML("<BucketLoggingStatus></BucketLoggingStatus>")
+		case "encryption":
+			return mockOBSError(http.StatusNotFound, "NoSuchEncryptionConfiguration")
+		case "lifecycle":
+			return mockOBSError(http.StatusNotFound, "NoSuchLifecycleConfiguration")
+		case "website":
+			return mockOBSError(http.StatusNotFound, "NoSuchWebsiteConfiguration")
+		case "cors":
+			return mockOBSError(http.StatusNotFound, "NoSuchCORSConfiguration")
+		}
+		return mockError(req.Service, http.StatusNotImplemented)
+	})
+	c.handle("obs", "DELETE", "/", func(req *mockRequest) *mockResponse {
+		if _, ok := c.get("buckets", req.Bucket); !ok {
+			return mockOBSError(http.StatusNotFound, "NoSuchBucket")
+		}
+
+		c.remove("buckets", req.Bucket)
+		return mockStatus(http.StatusNoContent)
+	})
+}
+
+// mockOBSSubresource returns the sub-resource of the bucket requested in the query
+func mockOBSSubresource(req *mockRequest) string {
+	query := req.URL.Query()
+	for _, name := range mockOBSSubresources {
+		if _, ok := query[name]; ok {
+			return name
+		}
+	}
+	return ""
+}
+
+// mockOBSHeader returns the value of the header with either the S3 or the OBS prefix
+func mockOBSHeader(req *mockRequest, name string) string {
+	if v := req.Header.Get("x-obs-" + name); v != "" {
+		return v
+	}
+	return req.Header.Get("x-amz-" + name)
+}
+
+func mockOBSXML(body string) *mockResponse {
+	return &mockResponse{
+		Status: http.StatusOK,
+		Body:   `<?xml version="1.0" encoding="UTF-8"?>` + body,
+		Header: map[string]string{"Content-Type": "application/xml"},
+	}
+}
+
+func TestMockObsBucket_basic(t *testing.T) {
+	t.Parallel()
+
+	c := newMockCloud(t)
+	resourceName := "flexibleengine_obs_bucket.test"
+
+	c.resourceTest(t, resource.TestCase{
+		CheckDestroy: c.checkDestroyed("buckets"),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockObsBucket_basic(true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "bucket", "mock-bucket"),
+					resource.TestCheckResourceAttr(resourceName, "storage_class", "STANDARD"),
+					resource.TestCheckResourceAttr(resourceName, "versioning", "true"),
+					resource.TestCheckResourceAttr(resourceName, "encryption", "false"),
+					resource.TestCheckResourceAttr(resourceName, "multi_az", "false"),
+					resource.TestCheckResourceAttr(resourceName, "region", mockRegion),
+					resource.TestCheckResourceAttr(resourceName, "enterprise_project_id", "0"),
+					c.checkStored("buckets", resourceName, "versioning", "Enabled"),
+				),
+			},
+			{
+				Config: testMockObsBucket_basic(false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "versioning", "false"),
+					c.checkStored("buckets", resourceName, "versioning", "Suspended"),
+				),
+			},
+		},
+	})
+}
+
+func TestMockObsBucket_readError(t *testing.T) {
+	t.Parallel()
+
+	c := newMockCloud(t)
+	c.injectFault("obs", "HEAD", "/", http.StatusForbidden, -1)
+
+	c.resourceTest(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{
+				Config:      testMockObsBucket_basic(false),
+				ExpectError: regexp.MustCompile(`error reading OBS bucket mock-bucket`),
+			},
+		},
+	})
+}
+
+func testMockObsBucket_basic(versioning bool) string {
+	return fmt.Sprintf(`
+resource "flexibleengine_obs_bucket" "test" {
+  bucket     = "mock-bucket"
+  acl        = "private"
+  versioning = %t
+}
+`, versioning)
+}
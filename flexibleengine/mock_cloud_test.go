@@ -0,0 +1,1314 @@
+package flexibleengine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chnsz/golangsdk"
+	hcty "github.com/hashicorp/go-cty/cty"
+	hconvert "github.com/hashicorp/go-cty/cty/convert"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/zclconf/go-cty/cty"
+
+	huaweiconfig "github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+)
+
+// The mock cloud serves every FlexibleEngine endpoint used by the unit tests
+// from one in-process HTTP server. IAM is reached through the server address
+// directly, the other services are addressed by the host names below and are
+// dialed to the same server.
+const (
+	mockRegion    = "eu-west-0"
+	mockCloudName = "mock.cloud"
+	mockProjectID = "0970dd7a1300f5672ff2c003c60ae115"
+	mockDomainID  = "0970d7b7d400f2470fbec00316a03560"
+	mockAccessKey = "MOCKACCESSKEY0000000"
+	mockSecretKey = "mocksecretkey0000000000000000000000000000"
+)
+
+// mockServices lists the services with a custom endpoint in the provider
+// configuration of the mock cloud.
+var mockServices = []string{"bss", "cce", "dcs", "ecs", "elb", "eps", "evs", "iam", "ims", "kms", "vpc"}
+
+type mockHandler func(req *mockRequest) *mockResponse
+
+type mockRoute struct {
+	service string
+	method  string
+	pattern *regexp.Regexp
+	handler mockHandler
+}
+
+type mockFault struct {
+	service   string
+	method    string
+	pattern   *regexp.Regexp
+	status    int
+	remaining int
+}
+
+// mockRequest is the request passed to the handlers of the mock cloud, the
+// JSON body is decoded into Body unless the service is OBS.
+type mockRequest struct {
+	*http.Request
+	Service string
+	Bucket  string
+	Params  map[string]string
+	Raw     []byte
+	Body    map[string]interface{}
+}
+
+// mockResponse is returned by the handlers of the mock cloud. The Body is
+// written as it is if it is a string or a byte slice, otherwise it is
+// encoded as JSON.
+type mockResponse struct {
+	Status int
+	Body   interface{}
+	Header map[string]string
+}
+
+// mockCloud is an in-process fake of the FlexibleEngine endpoints. It keeps a
+// stateful in-memory backend per service and can inject errors into any API.
+type mockCloud struct {
+	t      *testing.T
+	server *httptest.Server
+
+	// mu serializes the handlers, they can access the fields below freely
+	mu     sync.Mutex
+	routes []*mockRoute
+	faults []*mockFault
+	seq    int
+
+	// store keeps the resources of all backends by kind and ID
+	store map[string]map[string]map[string]interface{}
+	// tags keeps the tags of all backends by resource ID
+	tags map[string]map[string]string
+}
+
+func newMockCloud(t *testing.T) *mockCloud {
+	c := &mockCloud{
+		t:     t,
+		store: make(map[string]map[string]map[string]interface{}),
+		tags:  make(map[string]map[string]string),
+	}
+
+	c.registerIAM()
+	c.registerEPS()
+	c.registerBSS()
+	c.registerVPC()
+	c.registerECS()
+	c.registerBMS()
+	c.registerDeH()
+	c.registerCCE()
+	c.registerEVS()
+	c.registerIMS()
+	c.registerKPS()
+	c.registerELB()
+	c.registerDCS()
+	c.registerOBS()
+
+	c.server = httptest.NewServer(http.HandlerFunc(c.serveHTTP))
+	t.Cleanup(c.server.Close)
+	return c
+}
+
+// handle registers a handler of the service, the path can contain
+// placeholders like {id} which are passed to the handler in req.Params.
+func (c *mockCloud) handle(service, method, path string, handler mockHandler) {
+	c.routes = append(c.routes, &mockRoute{
+		service: service,
+		method:  method,
+		pattern: mockPathPattern(path),
+		handler: handler,
+	})
+}
+
+// injectFault makes the next requests to the service which match the method
+// and path fail with the status code. The fault is used up after the given
+// times, a negative value means that it never expires.
+func (c *mockCloud) injectFault(service, method, path string, status, times int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.faults = append(c.faults, &mockFault{
+		service:   service,
+		method:    method,
+		pattern:   mockPathPattern(path),
+		status:    status,
+		remaining: times,
+	})
+}
+
+func (c *mockCloud) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	service, bucket := mockServiceFromHost(r.Host)
+	raw, _ := io.ReadAll(r.Body)
+	req := &mockRequest{
+		Request: r,
+		Service: service,
+		Bucket:  bucket,
+		Params:  make(map[string]string),
+		Raw:     raw,
+	}
+	if len(raw) > 0 && service != "obs" {
+		// the body of some APIs is not a JSON object, the handlers use req.Raw then
+		_ = json.Unmarshal(raw, &req.Body)
+	}
+
+	for _, f := range c.faults {
+		if f.remaining == 0 || f.service != service || f.method != r.Method || !f.pattern.MatchString(r.URL.Path) {
+			continue
+		}
+		f.remaining--
+		c.write(w, r, mockError(service, f.status))
+		return
+	}
+
+	for _, route := range c.routes {
+		if route.service != service || route.method != r.Method {
+			continue
+		}
+		match := route.pattern.FindStringSubmatch(r.URL.Path)
+		if match == nil {
+			continue
+		}
+		for i, name := range route.pattern.SubexpNames() {
+			if name != "" {
+				req.Params[name] = match[i]
+			}
+		}
+		c.write(w, r, route.handler(req))
+		return
+	}
+
+	c.t.Logf("[WARN] mock cloud: unhandled request %s %s%s", r.Method, r.Host, r.URL)
+	c.write(w, r, mockError(service, http.StatusNotFound))
+}
+
+func (c *mockCloud) write(w http.ResponseWriter, r *http.Request, resp *mockResponse) {
+	for k, v := range resp.Header {
+		w.Header().Set(k, v)
+	}
+
+	var data []byte
+	switch body := resp.Body.(type) {
+	case nil:
+	case string:
+		data = []byte(body)
+	case []byte:
+		data = body
+	default:
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			c.t.Errorf("Error marshalling the mock response of %s %s: %s", r.Method, r.URL, err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+	}
+
+	w.WriteHeader(resp.Status)
+	if len(data) > 0 {
+		_, _ = w.Write(data)
+	}
+}
+
+// newID returns a unique ID in the UUID format
+func (c *mockCloud) newID() string {
+	c.seq++
+	return fmt.Sprintf("%08x-7a3c-4d2e-9b1f-%012x", c.seq, c.seq)
+}
+
+func (c *mockCloud) put(kind, id string, object map[string]interface{}) {
+	if c.store[kind] == nil {
+		c.store[kind] = make(map[string]map[string]interface{})
+	}
+	c.store[kind][id] = object
+}
+
+func (c *mockCloud) get(kind, id string) (map[string]interface{}, bool) {
+	object, ok := c.store[kind][id]
+	return object, ok
+}
+
+func (c *mockCloud) remove(kind, id string) {
+	delete(c.store[kind], id)
+	delete(c.tags, id)
+}
+
+// count returns the number of resources of the kind in the backend, it can
+// be called by the tests.
+func (c *mockCloud) count(kind string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.store[kind])
+}
+
+// checkDestroyed returns a CheckDestroy function which verifies that no
+// resource of the kind remains in the backend.
+func (c *mockCloud) checkDestroyed(kind string) resource.TestCheckFunc {
+	return func(_ *terraform.State) error {
+		if n := c.count(kind); n != 0 {
+			return fmt.Errorf("%d %s still exist in the mock cloud", n, kind)
+		}
+		return nil
+	}
+}
+
+// checkStored returns a check function which compares the field of the resource
+// in the backend with the expected value.
+func (c *mockCloud) checkStored(kind, resourceName, field, expected string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("Not found: %s", resourceName)
+		}
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		object, ok := c.get(kind, rs.Primary.ID)
+		if !ok {
+			return fmt.Errorf("%s %s not found in the mock cloud", kind, rs.Primary.ID)
+		}
+		if actual := fmt.Sprint(object[field]); actual != expected {
+			return fmt.Errorf("expect %s of %s to be %q, but got %q", field, resourceName, expected, actual)
+		}
+		return nil
+	}
+}
+
+// handleTags registers the common tag APIs of the resources under the path prefix
+func (c *mockCloud) handleTags(service, prefix string) {
+	c.handle(service, "GET", prefix+"/{id}/tags", func(req *mockRequest) *mockResponse {
+		tagList := make([]map[string]string, 0)
+		for k, v := range c.tags[req.Params["id"]] {
+			tagList = append(tagList, map[string]string{"key": k, "value": v})
+		}
+		sort.Slice(tagList, func(i, j int) bool { return tagList[i]["key"] < tagList[j]["key"] })
+		return mockJSON(http.StatusOK, map[string]interface{}{"tags": tagList})
+	})
+	c.handle(service, "POST", prefix+"/{id}/tags/action", func(req *mockRequest) *mockResponse {
+		id := req.Params["id"]
+		if c.tags[id] == nil {
+			c.tags[id] = make(map[string]string)
+		}
+
+		tagList, _ := req.Body["tags"].([]interface{})
+		for _, raw := range tagList {
+			tag := raw.(map[string]interface{})
+			key := tag["key"].(string)
+			switch req.Body["action"] {
+			case "create":
+				value, _ := tag["value"].(string)
+				c.tags[id][key] = value
+			case "delete":
+				delete(c.tags[id], key)
+			default:
+				return mockError(service, http.StatusBadRequest)
+			}
+		}
+		return mockStatus(http.StatusNoContent)
+	})
+}
+
+func (c *mockCloud) registerIAM() {
+	// the clients which are not created from the endpoint templates locate
+	// their endpoints in the service catalog
+	catalog := []interface{}{
+		map[string]interface{}{
+			"id":   "mock-deh",
+			"name": "deh",
+			"type": "deh",
+			"endpoints": []interface{}{
+				map[string]interface{}{
+					"id":        "mock-deh-public",
+					"interface": "public",
+					"region":    mockRegion,
+					"region_id": mockRegion,
+					"url":       fmt.Sprintf("http://deh.%s.%s/v1.0/%s", mockRegion, mockCloudName, mockProjectID),
+				},
+			},
+		},
+	}
+
+	token := func(req *mockRequest) *mockResponse {
+		body := map[string]interface{}{
+			"token": map[string]interface{}{
+				"expires_at": "2099-12-31T00:00:00.000000Z",
+				"issued_at":  "2020-01-01T00:00:00.000000Z",
+				"methods":    []string{"password"},
+				"catalog":    catalog,
+				"roles":      []interface{}{},
+				"user": map[string]interface{}{
+					"id":     "mock-user-id",
+					"name":   "mock-user",
+					"domain": map[string]string{"id": mockDomainID, "name": "mock-domain"},
+				},
+				"project": map[string]interface{}{
+					"id":     mockProjectID,
+					"name":   mockRegion,
+					"domain": map[string]string{"id": mockDomainID, "name": "mock-domain"},
+				},
+			},
+		}
+		resp := mockJSON(http.StatusCreated, body)
+		resp.Header = map[string]string{"X-Subject-Token": "mock-token"}
+		return resp
+	}
+
+	c.handle("iam", "POST", "/v3/auth/tokens", token)
+	c.handle("iam", "GET", "/v3/projects", func(req *mockRequest) *mockResponse {
+		return mockJSON(http.StatusOK, map[string]interface{}{
+			"projects": []interface{}{
+				map[string]interface{}{
+					"id":        mockProjectID,
+					"name":      mockRegion,
+					"domain_id": mockDomainID,
+					"enabled":   true,
+				},
+			},
+			"links": map[string]interface{}{},
+		})
+	})
+	c.handle("iam", "GET", "/v3/auth/catalog", func(req *mockRequest) *mockResponse {
+		return mockJSON(http.StatusOK, map[string]interface{}{
+			"catalog": catalog,
+			"links":   map[string]interface{}{},
+		})
+	})
+	c.handle("iam", "GET", "/v3/auth/domains", func(req *mockRequest) *mockResponse {
+		return mockJSON(http.StatusOK, map[string]interface{}{
+			"domains": []interface{}{
+				map[string]interface{}{"id": mockDomainID, "name": "mock-domain", "enabled": true},
+			},
+			"links": map[string]interface{}{},
+		})
+	})
+	c.handle("iam", "GET", "/v3.0/OS-CREDENTIAL/credentials/{ak}", func(req *mockRequest) *mockResponse {
+		if req.Params["ak"] != mockAccessKey {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+		return mockJSON(http.StatusOK, map[string]interface{}{
+			"credential": map[string]interface{}{
+				"user_id": "mock-user-id",
+				"access":  mockAccessKey,
+				"status":  "active",
+			},
+		})
+	})
+	c.handle("iam", "GET", "/v3.0/OS-USER/users/{id}", func(req *mockRequest) *mockResponse {
+		if req.Params["id"] != "mock-user-id" {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+		return mockJSON(http.StatusOK, map[string]interface{}{
+			"user": map[string]interface{}{
+				"id":        "mock-user-id",
+				"name":      "mock-user",
+				"domain_id": mockDomainID,
+				"enabled":   true,
+			},
+		})
+	})
+}
+
+// registerEPS registers the enterprise project migration API, the resource
+// is looked up by ID in all backends.
+func (c *mockCloud) registerEPS() {
+	c.handle("eps", "POST", "/v1.0/enterprise-projects/{eps}/resources-migrate", func(req *mockRequest) *mockResponse {
+		id, _ := req.Body["resource_id"].(string)
+		for _, objects := range c.store {
+			if object, ok := objects[id]; ok {
+				object["enterprise_project_id"] = req.Params["eps"]
+				return mockStatus(http.StatusNoContent)
+			}
+		}
+		return mockError(req.Service, http.StatusNotFound)
+	})
+}
+
+// providerConfig returns the provider block which points all endpoints to the mock cloud,
+// the arguments are added to the block
+func (c *mockCloud) providerConfig(args ...string) string {
+	var endpoints strings.Builder
+	for _, service := range mockServices {
+		fmt.Fprintf(&endpoints, "    %-3s = \"http://%s.%s/\"\n", service, service, mockCloudName)
+	}
+	fmt.Fprintf(&endpoints, "    obs = \"http://obs.%s.%s/\"\n", mockRegion, mockCloudName)
+
+	var extra strings.Builder
+	for _, arg := range args {
+		fmt.Fprintf(&extra, "  %s\n", arg)
+	}
+
+	return fmt.Sprintf(`
+provider "flexibleengine" {
+  region      = "%s"
+  auth_url    = "%s/v3"
+  domain_id   = "%s"
+  access_key  = "%s"
+  secret_key  = "%s"
+  max_retries = 0
+%s
+  endpoints = {
+%s  }
+}
+`, mockRegion, c.server.URL, mockDomainID, mockAccessKey, mockSecretKey, extra.String(), endpoints.String())
+}
+
+// provider returns a provider whose clients send all requests to the mock cloud
+func (c *mockCloud) provider() *schema.Provider {
+	p := Provider()
+
+	configure := p.ConfigureContextFunc
+	p.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+		meta, diags := configure(ctx, d)
+		if diags.HasError() {
+			return meta, diags
+		}
+
+		config := meta.(*Config)
+		for _, client := range []*golangsdk.ProviderClient{config.HwClient, config.DomainClient} {
+			if err := c.hijackTransport(client); err != nil {
+				return nil, diag.FromErr(err)
+			}
+		}
+		return meta, diags
+	}
+
+	return p
+}
+
+// hijackTransport dials all connections of the client to the mock cloud
+func (c *mockCloud) hijackTransport(client *golangsdk.ProviderClient) error {
+	if client == nil {
+		return nil
+	}
+
+	var transport *http.Transport
+	for rt := client.HTTPClient.Transport; transport == nil; {
+		switch v := rt.(type) {
+		case *huaweiconfig.LogRoundTripper:
+			rt = v.Rt
+		case *requestThrottle:
+			rt = v.next
+		case *auditLogger:
+			rt = v.next
+		case *auditResourceTagger:
+			rt = v.next
+		case *credentialSigner:
+			rt = v.next
+		case *http.Transport:
+			transport = v
+		default:
+			return fmt.Errorf("unexpected round tripper of the mock cloud client: %T", rt)
+		}
+	}
+
+	addr := c.server.Listener.Addr().String()
+	transport.Proxy = nil
+	transport.DialContext = func(ctx context.Context, network, _ string) (net.Conn, error) {
+		var dialer net.Dialer
+		return dialer.DialContext(ctx, network, addr)
+	}
+	return nil
+}
+
+func TestMain(m *testing.M) {
+	// the mock cloud completes the changes at once, the acceptance tests keep
+	// the delays of the real cloud
+	if os.Getenv(resource.EnvTfAcc) == "" {
+		stateRefreshDelay = func(time.Duration) time.Duration {
+			return 10 * time.Millisecond
+		}
+		stateRefreshInterval = func(time.Duration) time.Duration {
+			return 10 * time.Millisecond
+		}
+	}
+	os.Exit(m.Run())
+}
+
+// resourceTest runs the test case against the mock cloud. The steps are driven in-process
+// through the provider schema, so no terraform binary is required. The case is passed to
+// resource.UnitTest instead when TF_ACC_TERRAFORM_PATH is set.
+// The provider block is added to the steps which do not include one from providerConfig.
+func (c *mockCloud) resourceTest(t *testing.T, tc resource.TestCase) {
+	t.Helper()
+
+	for i := range tc.Steps {
+		if !strings.Contains(tc.Steps[i].Config, `provider "flexibleengine"`) {
+			tc.Steps[i].Config = c.providerConfig() + tc.Steps[i].Config
+		}
+	}
+
+	if os.Getenv("TF_ACC_TERRAFORM_PATH") != "" {
+		tc.ProviderFactories = map[string]func() (*schema.Provider, error){
+			"flexibleengine": func() (*schema.Provider, error) {
+				return c.provider(), nil
+			},
+		}
+		resource.UnitTest(t, tc)
+		return
+	}
+	driver := &mockDriver{
+		t:        t,
+		provider: c.provider(),
+		states:   make(map[string]*terraform.ResourceState),
+	}
+	driver.run(tc)
+}
+
+func mockJSON(status int, body interface{}) *mockResponse {
+	return &mockResponse{Status: status, Body: body}
+}
+
+func mockStatus(status int) *mockResponse {
+	return &mockResponse{Status: status}
+}
+
+func mockError(service string, status int) *mockResponse {
+	if service == "obs" {
+		return mockOBSError(status, strings.ReplaceAll(http.StatusText(status), " ", ""))
+	}
+
+	return mockJSON(status, map[string]string{
+		"error_code": fmt.Sprintf("MOCK.%04d", status),
+		"error_msg":  fmt.Sprintf("mock error: %s", http.StatusText(status)),
+	})
+}
+
+func mockOBSError(status int, code string) *mockResponse {
+	body := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>`+
+		`<Error><Code>%s</Code><Message>mock error: %s</Message><RequestId>mock</RequestId></Error>`,
+		code, http.StatusText(status))
+	return &mockResponse{
+		Status: status,
+		Body:   body,
+		Header: map[string]string{"Content-Type": "application/xml"},
+	}
+}
+
+// mockCopy returns a shallow copy of the object so that it can be modified
+// without changing the backend.
+func mockCopy(object map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(object))
+	for k, v := range object {
+		result[k] = v
+	}
+	return result
+}
+
+// mockMerge copies the keys in the request object to the stored object
+func mockMerge(object map[string]interface{}, changes interface{}) {
+	if m, ok := changes.(map[string]interface{}); ok {
+		for k, v := range m {
+			object[k] = v
+		}
+	}
+}
+
+var mockParamRegexp = regexp.MustCompile(`\{(\w+)\}`)
+
+func mockPathPattern(path string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("^")
+
+	last := 0
+	for _, loc := range mockParamRegexp.FindAllStringSubmatchIndex(path, -1) {
+		b.WriteString(regexp.QuoteMeta(path[last:loc[0]]))
+		fmt.Fprintf(&b, "(?P<%s>[^/]+)", path[loc[2]:loc[3]])
+		last = loc[1]
+	}
+	b.WriteString(regexp.QuoteMeta(path[last:]))
+	b.WriteString("/?$")
+
+	return regexp.MustCompile(b.String())
+}
+
+// mockServiceFromHost returns the service and the OBS bucket addressed by the host
+func mockServiceFromHost(host string) (service, bucket string) {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if net.ParseIP(host) != nil {
+		return "iam", ""
+	}
+
+	obsHost := fmt.Sprintf("obs.%s.%s", mockRegion, mockCloudName)
+	if host == obsHost {
+		return "obs", ""
+	}
+	if strings.HasSuffix(host, "."+obsHost) {
+		return "obs", strings.TrimSuffix(host, "."+obsHost)
+	}
+
+	return strings.SplitN(host, ".", 2)[0], ""
+}
+
+// mockDriver runs the steps of a test case without a terraform binary. The
+// resources are planned and applied in the order of the configuration, the
+// references between them must point to the top-level attributes.
+type mockDriver struct {
+	t        *testing.T
+	provider *schema.Provider
+	// providerConfig keeps the raw config of the configured provider, the provider
+	// is configured again when the config is changed like terraform does in each run
+	providerConfig map[string]interface{}
+
+	// states keeps the resources by address, order keeps the creation order
+	states map[string]*terraform.ResourceState
+	order  []string
+}
+
+type mockBlock struct {
+	address string
+	kind    string
+	block   *hclsyntax.Block
+}
+
+func (d *mockDriver) run(tc resource.TestCase) {
+	t := d.t
+	t.Helper()
+
+	if tc.PreCheck != nil {
+		tc.PreCheck()
+	}
+
+	defer d.destroyAll(tc.CheckDestroy)
+
+	for i, step := range tc.Steps {
+		if step.PlanOnly {
+			t.Fatalf("step %d: plan-only steps need a terraform binary", i+1)
+		}
+		if step.ImportState {
+			if err := d.importState(step); err != nil {
+				t.Fatalf("step %d error: %s", i+1, err)
+			}
+			continue
+		}
+
+		err := d.apply(step.Config)
+		if step.ExpectError != nil {
+			if err == nil {
+				t.Fatalf("step %d: expected an error but got none", i+1)
+			}
+			if !step.ExpectError.MatchString(err.Error()) {
+				t.Fatalf("step %d: expected an error with pattern, no match on: %s", i+1, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("step %d error: %s", i+1, err)
+		}
+
+		if step.Check != nil {
+			if err := step.Check(d.state()); err != nil {
+				t.Fatalf("step %d: Check failed: %s", i+1, err)
+			}
+		}
+
+		if !step.ExpectNonEmptyPlan {
+			if err := d.checkEmptyPlan(step.Config); err != nil {
+				t.Fatalf("step %d error: %s", i+1, err)
+			}
+		}
+	}
+}
+
+func (d *mockDriver) parse(src string) (map[string]interface{}, []mockBlock, error) {
+	file, diags := hclsyntax.ParseConfig([]byte(src), "main.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, nil, diags
+	}
+
+	var provider map[string]interface{}
+	var blocks []mockBlock
+	for _, block := range file.Body.(*hclsyntax.Body).Blocks {
+		switch block.Type {
+		case "provider":
+			raw, err := d.decode(block.Body)
+			if err != nil {
+				return nil, nil, err
+			}
+			provider = raw
+		case "resource":
+			blocks = append(blocks, mockBlock{
+				address: strings.Join(block.Labels, "."),
+				kind:    block.Labels[0],
+				block:   block,
+			})
+		case "data":
+			blocks = append(blocks, mockBlock{
+				address: "data." + strings.Join(block.Labels, "."),
+				kind:    block.Labels[0],
+				block:   block,
+			})
+		default:
+			return nil, nil, fmt.Errorf("unsupported block %q in the mock configuration", block.Type)
+		}
+	}
+
+	return provider, blocks, nil
+}
+
+// decode converts the body to the raw configuration of terraform.ResourceConfig,
+// the references are resolved against the current states.
+func (d *mockDriver) decode(body *hclsyntax.Body) (map[string]interface{}, error) {
+	ctx := d.evalContext()
+	raw := make(map[string]interface{})
+
+	for name, attr := range body.Attributes {
+		if name == "depends_on" {
+			continue
+		}
+		val, diags := attr.Expr.Value(ctx)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		if !val.IsNull() {
+			raw[name] = mockCtyToRaw(val)
+		}
+	}
+
+	for _, block := range body.Blocks {
+		if block.Type == "lifecycle" {
+			continue
+		}
+		nested, err := d.decode(block.Body)
+		if err != nil {
+			return nil, err
+		}
+		list, _ := raw[block.Type].([]interface{})
+		raw[block.Type] = append(list, nested)
+	}
+
+	return raw, nil
+}
+
+func (d *mockDriver) evalContext() *hcl.EvalContext {
+	resources := make(map[string]map[string]cty.Value)
+	dataSources := make(map[string]map[string]cty.Value)
+
+	for address, rs := range d.states {
+		attrs := map[string]cty.Value{"id": cty.StringVal(rs.Primary.ID)}
+		for k, v := range rs.Primary.Attributes {
+			if !strings.Contains(k, ".") {
+				attrs[k] = cty.StringVal(v)
+			}
+		}
+
+		target := resources
+		if strings.HasPrefix(address, "data.") {
+			target = dataSources
+			address = strings.TrimPrefix(address, "data.")
+		}
+		name := strings.TrimPrefix(address, rs.Type+".")
+		if target[rs.Type] == nil {
+			target[rs.Type] = make(map[string]cty.Value)
+		}
+		target[rs.Type][name] = cty.ObjectVal(attrs)
+	}
+
+	variables := make(map[string]cty.Value)
+	for kind, objects := range resources {
+		variables[kind] = cty.ObjectVal(objects)
+	}
+	data := make(map[string]cty.Value)
+	for kind, objects := range dataSources {
+		data[kind] = cty.ObjectVal(objects)
+	}
+	variables["data"] = cty.ObjectVal(data)
+
+	return &hcl.EvalContext{Variables: variables}
+}
+
+func (d *mockDriver) meta() interface{} {
+	return d.provider.Meta()
+}
+
+func (d *mockDriver) apply(src string) error {
+	provider, blocks, err := d.parse(src)
+	if err != nil {
+		return err
+	}
+
+	if d.providerConfig == nil || !reflect.DeepEqual(d.providerConfig, provider) {
+		diags := d.provider.Configure(context.Background(), terraform.NewResourceConfigRaw(provider))
+		if err := mockDiagsError(diags); err != nil {
+			return fmt.Errorf("error configuring the provider: %s", err)
+		}
+		d.providerConfig = provider
+	}
+
+	// destroy the resources which were removed from the configuration
+	inConfig := make(map[string]bool, len(blocks))
+	for _, b := range blocks {
+		inConfig[b.address] = true
+	}
+	for i := len(d.order) - 1; i >= 0; i-- {
+		if address := d.order[i]; !inConfig[address] {
+			if err := d.destroy(address); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, b := range blocks {
+		if err := d.applyBlock(b); err != nil {
+			return fmt.Errorf("%s: %s", b.address, err)
+		}
+	}
+	return nil
+}
+
+func (d *mockDriver) applyBlock(b mockBlock) error {
+	ctx := context.Background()
+	isData := strings.HasPrefix(b.address, "data.")
+
+	resourcesMap := d.provider.ResourcesMap
+	if isData {
+		resourcesMap = d.provider.DataSourcesMap
+	}
+	res, ok := resourcesMap[b.kind]
+	if !ok {
+		return fmt.Errorf("unknown type %s", b.kind)
+	}
+
+	raw, err := d.decode(b.block.Body)
+	if err != nil {
+		return err
+	}
+	rc := terraform.NewResourceConfigRaw(raw)
+	if err := mockDiagsError(res.Validate(rc)); err != nil {
+		return err
+	}
+
+	if isData {
+		diff, err := res.Diff(ctx, nil, rc, d.meta())
+		if err != nil {
+			return err
+		}
+		if diff == nil {
+			diff = terraform.NewInstanceDiff()
+		}
+		state, diags := res.ReadDataApply(ctx, diff, d.meta())
+		if err := mockDiagsError(diags); err != nil {
+			return err
+		}
+		d.setState(b.address, b.kind, state)
+		return nil
+	}
+
+	current, err := d.refresh(b.address)
+	if err != nil {
+		return err
+	}
+	diff, err := d.plan(res, current, raw)
+	if err != nil {
+		return err
+	}
+	if diff.Empty() {
+		return nil
+	}
+
+	state, diags := res.Apply(ctx, current, diff, d.meta())
+	d.setState(b.address, b.kind, state)
+	return mockDiagsError(diags)
+}
+
+func (d *mockDriver) setState(address, kind string, state *terraform.InstanceState) {
+	if state == nil || state.ID == "" {
+		delete(d.states, address)
+		for i, v := range d.order {
+			if v == address {
+				d.order = append(d.order[:i], d.order[i+1:]...)
+				break
+			}
+		}
+		return
+	}
+
+	if _, ok := d.states[address]; !ok && !strings.HasPrefix(address, "data.") {
+		d.order = append(d.order, address)
+	}
+	d.states[address] = &terraform.ResourceState{
+		Type:     kind,
+		Primary:  state,
+		Provider: "provider.flexibleengine",
+	}
+}
+
+// plan returns the diff of the resource against the configuration. Like terraform,
+// the configuration is also sent as the raw config of the prior state, so that it
+// is available to CustomizeDiff through GetRawConfig.
+func (d *mockDriver) plan(res *schema.Resource, current *terraform.InstanceState,
+	raw map[string]interface{}) (*terraform.InstanceDiff, error) {
+	rawConfig, err := mockRawToCty(raw, res.CoreConfigSchema().ImpliedType())
+	if err != nil {
+		return nil, fmt.Errorf("error converting the raw config: %s", err)
+	}
+
+	prior := &terraform.InstanceState{}
+	if current != nil {
+		prior = current.DeepCopy()
+	}
+	prior.RawConfig = rawConfig
+
+	return res.Diff(context.Background(), prior, terraform.NewResourceConfigRaw(raw), d.meta())
+}
+
+func (d *mockDriver) refresh(address string) (*terraform.InstanceState, error) {
+	rs, ok := d.states[address]
+	if !ok {
+		return nil, nil
+	}
+
+	res := d.provider.ResourcesMap[rs.Type]
+	state, diags := res.RefreshWithoutUpgrade(context.Background(), rs.Primary, d.meta())
+	if err := mockDiagsError(diags); err != nil {
+		return nil, err
+	}
+	d.setState(address, rs.Type, state)
+	return state, nil
+}
+
+func (d *mockDriver) destroy(address string) error {
+	rs, ok := d.states[address]
+	if !ok {
+		return nil
+	}
+
+	res := d.provider.ResourcesMap[rs.Type]
+	diff := &terraform.InstanceDiff{Destroy: true}
+	_, diags := res.Apply(context.Background(), rs.Primary, diff, d.meta())
+	if err := mockDiagsError(diags); err != nil {
+		return fmt.Errorf("error destroying %s: %s", address, err)
+	}
+
+	d.setState(address, rs.Type, nil)
+	return nil
+}
+
+func (d *mockDriver) destroyAll(checkDestroy resource.TestCheckFunc) {
+	if d.providerConfig == nil {
+		return
+	}
+
+	state := d.state()
+	for i := len(d.order) - 1; i >= 0; i-- {
+		if err := d.destroy(d.order[i]); err != nil {
+			d.t.Error(err)
+			return
+		}
+	}
+
+	if checkDestroy != nil {
+		if err := checkDestroy(state); err != nil {
+			d.t.Errorf("Check destroy failed: %s", err)
+		}
+	}
+}
+
+// checkEmptyPlan refreshes the resources and verifies that the configuration
+// has no changes to apply.
+func (d *mockDriver) checkEmptyPlan(src string) error {
+	_, blocks, err := d.parse(src)
+	if err != nil {
+		return err
+	}
+
+	for _, b := range blocks {
+		if strings.HasPrefix(b.address, "data.") {
+			continue
+		}
+
+		current, err := d.refresh(b.address)
+		if err != nil {
+			return err
+		}
+		raw, err := d.decode(b.block.Body)
+		if err != nil {
+			return err
+		}
+
+		diff, err := d.plan(d.provider.ResourcesMap[b.kind], current, raw)
+		if err != nil {
+			return err
+		}
+		if diff != nil && !diff.Empty() {
+			return fmt.Errorf("after applying this step, the plan of %s was not empty:\n%s", b.address, diff.GoString())
+		}
+	}
+	return nil
+}
+
+// importState imports the resource of the step by its ID, and compares the
+// imported attributes with the current state when ImportStateVerify is set.
+// The imported resources are not kept in the state.
+func (d *mockDriver) importState(step resource.TestStep) error {
+	rs, ok := d.states[step.ResourceName]
+	if !ok {
+		return fmt.Errorf("%s not found in the state", step.ResourceName)
+	}
+
+	id := rs.Primary.ID
+	if step.ImportStateId != "" {
+		id = step.ImportStateId
+	}
+	if step.ImportStateIdFunc != nil {
+		var err error
+		if id, err = step.ImportStateIdFunc(d.state()); err != nil {
+			return err
+		}
+	}
+
+	ctx := context.Background()
+	imported, err := d.provider.ImportState(ctx, &terraform.InstanceInfo{Type: rs.Type}, id)
+	if err != nil {
+		return fmt.Errorf("error importing %s: %s", step.ResourceName, err)
+	}
+
+	res := d.provider.ResourcesMap[rs.Type]
+	states := make([]*terraform.InstanceState, 0, len(imported))
+	for _, is := range imported {
+		state, diags := res.RefreshWithoutUpgrade(ctx, is, d.meta())
+		if err := mockDiagsError(diags); err != nil {
+			return fmt.Errorf("error refreshing the imported %s: %s", step.ResourceName, err)
+		}
+		if state == nil || state.ID == "" {
+			return fmt.Errorf("the imported %s does not exist", step.ResourceName)
+		}
+		states = append(states, state)
+	}
+
+	if step.ImportStateCheck != nil {
+		if err := step.ImportStateCheck(states); err != nil {
+			return err
+		}
+	}
+	if !step.ImportStateVerify {
+		return nil
+	}
+
+	for _, state := range states {
+		if state.ID != rs.Primary.ID {
+			continue
+		}
+
+		actual := mockVerifiedAttributes(state.Attributes, step.ImportStateVerifyIgnore)
+		expected := mockVerifiedAttributes(rs.Primary.Attributes, step.ImportStateVerifyIgnore)
+		var diffs []string
+		for k, v := range expected {
+			if actual[k] != v {
+				diffs = append(diffs, fmt.Sprintf("%s: %q (imported) != %q (state)", k, actual[k], v))
+			}
+		}
+		for k, v := range actual {
+			if _, ok := expected[k]; !ok {
+				diffs = append(diffs, fmt.Sprintf("%s: %q (imported) is not in the state", k, v))
+			}
+		}
+		if len(diffs) > 0 {
+			sort.Strings(diffs)
+			return fmt.Errorf("the imported attributes of %s are not equivalent:\n%s",
+				step.ResourceName, strings.Join(diffs, "\n"))
+		}
+		return nil
+	}
+	return fmt.Errorf("%s with ID %s was not imported", step.ResourceName, rs.Primary.ID)
+}
+
+// mockVerifiedAttributes returns the attributes to be verified after importing,
+// which skips the empty containers, the timeouts and the ignored prefixes like
+// the acceptance test framework.
+func mockVerifiedAttributes(attributes map[string]string, ignore []string) map[string]string {
+	result := make(map[string]string)
+	for k, v := range attributes {
+		if (strings.HasSuffix(k, ".#") || strings.HasSuffix(k, ".%")) && v == "0" {
+			continue
+		}
+		if k == "timeouts" || strings.HasPrefix(k, "timeouts.") {
+			continue
+		}
+		ignored := false
+		for _, prefix := range ignore {
+			if strings.HasPrefix(k, prefix) {
+				ignored = true
+			}
+		}
+		if !ignored {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+func (d *mockDriver) state() *terraform.State {
+	s := terraform.NewState()
+	for address, rs := range d.states {
+		s.RootModule().Resources[address] = &terraform.ResourceState{
+			Type:     rs.Type,
+			Primary:  rs.Primary.DeepCopy(),
+			Provider: rs.Provider,
+		}
+	}
+	return s
+}
+
+func mockDiagsError(diags diag.Diagnostics) error {
+	var msgs []string
+	for _, d := range diags {
+		if d.Severity != diag.Error {
+			continue
+		}
+		msg := d.Summary
+		if d.Detail != "" {
+			msg = fmt.Sprintf("%s: %s", msg, d.Detail)
+		}
+		msgs = append(msgs, msg)
+	}
+
+	if len(msgs) == 0 {
+		return nil
+	}
+	return errors.New(strings.Join(msgs, "; "))
+}
+
+// mockCtyToRaw converts the value to the types used in the raw configuration
+func mockCtyToRaw(val cty.Value) interface{} {
+	if val.IsNull() || !val.IsKnown() {
+		return nil
+	}
+
+	ty := val.Type()
+	switch {
+	case ty == cty.String:
+		return val.AsString()
+	case ty == cty.Bool:
+		return val.True()
+	case ty == cty.Number:
+		bf := val.AsBigFloat()
+		if i, accuracy := bf.Int64(); accuracy == big.Exact {
+			return int(i)
+		}
+		f, _ := bf.Float64()
+		return f
+	case ty.IsListType() || ty.IsTupleType() || ty.IsSetType():
+		list := make([]interface{}, 0, val.LengthInt())
+		for it := val.ElementIterator(); it.Next(); {
+			_, v := it.Element()
+			list = append(list, mockCtyToRaw(v))
+		}
+		return list
+	case ty.IsMapType() || ty.IsObjectType():
+		m := make(map[string]interface{})
+		for it := val.ElementIterator(); it.Next(); {
+			k, v := it.Element()
+			m[k.AsString()] = mockCtyToRaw(v)
+		}
+		return m
+	}
+	return nil
+}
+
+// mockRawToCty converts the raw configuration to the value of the type, the blocks
+// are decoded as lists and a single nested block is taken as the object itself.
+func mockRawToCty(raw interface{}, ty hcty.Type) (hcty.Value, error) {
+	if raw == nil {
+		return hcty.NullVal(ty), nil
+	}
+
+	switch {
+	case ty.IsObjectType():
+		if list, ok := raw.([]interface{}); ok && len(list) == 1 {
+			raw = list[0]
+		}
+		m, ok := raw.(map[string]interface{})
+		if !ok {
+			return hcty.NilVal, fmt.Errorf("expected an object, got %T", raw)
+		}
+		attrs := make(map[string]hcty.Value)
+		for name, aty := range ty.AttributeTypes() {
+			v, err := mockRawToCty(m[name], aty)
+			if err != nil {
+				return hcty.NilVal, fmt.Errorf("%s: %s", name, err)
+			}
+			attrs[name] = v
+		}
+		return hcty.ObjectVal(attrs), nil
+	case ty.IsListType() || ty.IsSetType():
+		list, ok := raw.([]interface{})
+		if !ok {
+			return hcty.NilVal, fmt.Errorf("expected a list, got %T", raw)
+		}
+		if len(list) == 0 {
+			if ty.IsSetType() {
+				return hcty.SetValEmpty(ty.ElementType()), nil
+			}
+			return hcty.ListValEmpty(ty.ElementType()), nil
+		}
+		elems := make([]hcty.Value, len(list))
+		for i, item := range list {
+			v, err := mockRawToCty(item, ty.ElementType())
+			if err != nil {
+				return hcty.NilVal, err
+			}
+			elems[i] = v
+		}
+		if ty.IsSetType() {
+			return hcty.SetVal(elems), nil
+		}
+		return hcty.ListVal(elems), nil
+	case ty.IsMapType():
+		m, ok := raw.(map[string]interface{})
+		if !ok {
+			return hcty.NilVal, fmt.Errorf("expected a map, got %T", raw)
+		}
+		if len(m) == 0 {
+			return hcty.MapValEmpty(ty.ElementType()), nil
+		}
+		elems := make(map[string]hcty.Value)
+		for k, item := range m {
+			v, err := mockRawToCty(item, ty.ElementType())
+			if err != nil {
+				return hcty.NilVal, err
+			}
+			elems[k] = v
+		}
+		return hcty.MapVal(elems), nil
+	}
+
+	var val hcty.Value
+	switch v := raw.(type) {
+	case string:
+		val = hcty.StringVal(v)
+	case bool:
+		val = hcty.BoolVal(v)
+	case int:
+		val = hcty.NumberIntVal(int64(v))
+	case float64:
+		val = hcty.NumberFloatVal(v)
+	default:
+		return hcty.NilVal, fmt.Errorf("unsupported value %T", raw)
+	}
+	return hconvert.Convert(val, ty)
+}
@@ -0,0 +1,439 @@
+package flexibleengine
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+// registerVPC registers the EIP, bandwidth, subnet, network and port APIs of the VPC service
+func (c *mockCloud) registerVPC() {
+	createEIP := func(req *mockRequest) *mockResponse {
+		publicIP, _ := req.Body["publicip"].(map[string]interface{})
+		bandwidth, _ := req.Body["bandwidth"].(map[string]interface{})
+		if publicIP == nil || bandwidth == nil {
+			return mockError(req.Service, http.StatusBadRequest)
+		}
+
+		chargeMode, _ := bandwidth["charge_mode"].(string)
+		if chargeMode == "" {
+			chargeMode = "traffic"
+		}
+		bandwidthID := c.newID()
+		c.put("bandwidths", bandwidthID, map[string]interface{}{
+			"id":          bandwidthID,
+			"name":        bandwidth["name"],
+			"size":        bandwidth["size"],
+			"share_type":  bandwidth["share_type"],
+			"charge_mode": chargeMode,
+		})
+
+		epsID, _ := req.Body["enterprise_project_id"].(string)
+		if epsID == "" {
+			epsID = defaultEnterpriseProjectID
+		}
+		id := c.newID()
+		eip := map[string]interface{}{
+			"id":                    id,
+			"status":                "PENDING_CREATE",
+			"type":                  publicIP["type"],
+			"public_ip_address":     fmt.Sprintf("100.64.0.%d", c.seq),
+			"bandwidth_id":          bandwidthID,
+			"bandwidth_size":        bandwidth["size"],
+			"bandwidth_share_type":  bandwidth["share_type"],
+			"enterprise_project_id": epsID,
+			"tenant_id":             mockProjectID,
+		}
+		if address, ok := publicIP["ip_address"].(string); ok && address != "" {
+			eip["public_ip_address"] = address
+		}
+		c.put("publicips", id, eip)
+
+		return mockJSON(http.StatusOK, map[string]interface{}{"publicip": eip})
+	}
+	c.handle("vpc", "POST", "/v1/{project}/publicips", createEIP)
+
+	releaseEIP := func(id string) {
+		eip, _ := c.get("publicips", id)
+		c.remove("bandwidths", eip["bandwidth_id"].(string))
+		c.remove("publicips", id)
+	}
+	// the yearly/monthly EIPs are ordered by the v2.0 API
+	c.handle("vpc", "POST", "/v2.0/{project}/publicips", func(req *mockRequest) *mockResponse {
+		extendParam, _ := req.Body["extendParam"].(map[string]interface{})
+		if extendParam["charge_mode"] != "prePaid" || extendParam["is_auto_pay"] != "true" {
+			return mockError(req.Service, http.StatusBadRequest)
+		}
+
+		resp := createEIP(req)
+		if resp.Status != http.StatusOK {
+			return resp
+		}
+
+		eip := resp.Body.(map[string]interface{})["publicip"].(map[string]interface{})
+		id := eip["id"].(string)
+		eip["order_id"] = c.newOrder(id, extendParam["is_auto_renew"] == "true", func() { releaseEIP(id) })
+		return mockJSON(http.StatusOK, map[string]interface{}{"order_id": eip["order_id"], "publicip_id": id})
+	})
+	c.handle("vpc", "POST", "/v2.0/{project}/publicips/change-to-period", func(req *mockRequest) *mockResponse {
+		extendParam, _ := req.Body["extendParam"].(map[string]interface{})
+		publicIPIDs, _ := req.Body["publicip_ids"].([]interface{})
+		if extendParam["is_auto_pay"] != true || len(publicIPIDs) != 1 {
+			return mockError(req.Service, http.StatusBadRequest)
+		}
+		id := publicIPIDs[0].(string)
+		eip, ok := c.get("publicips", id)
+		if !ok || eip["order_id"] != nil {
+			return mockError(req.Service, http.StatusBadRequest)
+		}
+
+		eip["order_id"] = c.newOrder(id, extendParam["is_auto_renew"] == true, func() { releaseEIP(id) })
+		return mockJSON(http.StatusOK, map[string]interface{}{"order_id": eip["order_id"]})
+	})
+	c.handle("vpc", "GET", "/v1/{project}/publicips/{id}", func(req *mockRequest) *mockResponse {
+		eip, ok := c.get("publicips", req.Params["id"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+
+		resp := mockCopy(eip)
+		if eip["status"] == "PENDING_CREATE" {
+			eip["status"] = "DOWN"
+		}
+		if bandwidth, ok := c.get("bandwidths", eip["bandwidth_id"].(string)); ok {
+			resp["bandwidth_size"] = bandwidth["size"]
+			resp["bandwidth_name"] = bandwidth["name"]
+		}
+		return mockJSON(http.StatusOK, map[string]interface{}{"publicip": resp})
+	})
+	c.handle("vpc", "PUT", "/v1/{project}/publicips/{id}", func(req *mockRequest) *mockResponse {
+		eip, ok := c.get("publicips", req.Params["id"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+
+		publicIP, _ := req.Body["publicip"].(map[string]interface{})
+		portID, _ := publicIP["port_id"].(string)
+		if portID != "" {
+			if _, ok := c.get("ports", portID); !ok {
+				return mockError(req.Service, http.StatusBadRequest)
+			}
+			eip["status"] = "ACTIVE"
+		} else {
+			eip["status"] = "DOWN"
+		}
+		eip["port_id"] = portID
+
+		return mockJSON(http.StatusOK, map[string]interface{}{"publicip": eip})
+	})
+	c.handle("vpc", "DELETE", "/v1/{project}/publicips/{id}", func(req *mockRequest) *mockResponse {
+		eip, ok := c.get("publicips", req.Params["id"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+		// the yearly/monthly EIPs can only be unsubscribed
+		if eip["order_id"] != nil {
+			return mockError(req.Service, http.StatusForbidden)
+		}
+
+		releaseEIP(req.Params["id"])
+		return mockStatus(http.StatusNoContent)
+	})
+
+	c.handle("vpc", "GET", "/v1/{project}/bandwidths/{id}", func(req *mockRequest) *mockResponse {
+		bandwidth, ok := c.get("bandwidths", req.Params["id"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+		return mockJSON(http.StatusOK, map[string]interface{}{"bandwidth": bandwidth})
+	})
+	c.handle("vpc", "PUT", "/v1/{project}/bandwidths/{id}", func(req *mockRequest) *mockResponse {
+		bandwidth, ok := c.get("bandwidths", req.Params["id"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+
+		mockMerge(bandwidth, req.Body["bandwidth"])
+		for _, eip := range c.store["publicips"] {
+			if eip["bandwidth_id"] == bandwidth["id"] {
+				eip["bandwidth_size"] = bandwidth["size"]
+			}
+		}
+		return mockJSON(http.StatusOK, map[string]interface{}{"bandwidth": bandwidth})
+	})
+
+	c.handle("vpc", "GET", "/v1/{project}/subnets/{id}", func(req *mockRequest) *mockResponse {
+		if req.Params["id"] != mockNetworkID {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+		return mockJSON(http.StatusOK, map[string]interface{}{
+			"subnet": map[string]interface{}{"id": mockNetworkID, "vpc_id": mockVpcID, "status": "ACTIVE"},
+		})
+	})
+
+	c.handle("vpc", "GET", "/v2.0/networks", func(req *mockRequest) *mockResponse {
+		query := req.URL.Query()
+		result := []interface{}{}
+		if (query.Get("id") == "" || query.Get("id") == mockNetworkID) &&
+			(query.Get("name") == "" || query.Get("name") == mockNetworkName) {
+			result = append(result, map[string]interface{}{
+				"id":     mockNetworkID,
+				"name":   mockNetworkName,
+				"status": "ACTIVE",
+			})
+		}
+		return mockJSON(http.StatusOK, map[string]interface{}{"networks": result})
+	})
+
+	// the ports are created by the backends of ECS and ELB
+	c.handle("vpc", "GET", "/v2.0/ports/{id}", func(req *mockRequest) *mockResponse {
+		port, ok := c.get("ports", req.Params["id"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+		return mockJSON(http.StatusOK, map[string]interface{}{"port": port})
+	})
+	c.handle("vpc", "PUT", "/v2.0/ports/{id}", func(req *mockRequest) *mockResponse {
+		port, ok := c.get("ports", req.Params["id"])
+		if !ok {
+			return mockError(req.Service, http.StatusNotFound)
+		}
+
+		mockMerge(port, req.Body["port"])
+		return mockJSON(http.StatusOK, map[string]interface{}{"port": port})
+	})
+
+	c.handleTags("vpc", "/v2.0/{project}/publicips")
+}
+
+// newPort creates a port in the backend and returns its ID
+func (c *mockCloud) newPort(networkID, deviceID, address string) string {
+	id := c.newID()
+	c.put("ports", id, map[string]interface{}{
+		"id":              id,
+		"name":            "",
+		"network_id":      networkID,
+		"device_id":       deviceID,
+		"admin_state_up":  true,
+		"status":          "ACTIVE",
+		"mac_address":     fmt.Sprintf("fa:16:3e:00:00:%02x", c.seq%256),
+		"security_groups": []interface{}{},
+		"fixed_ips": []interface{}{
+			map[string]interface{}{"subnet_id": networkID, "ip_address": address},
+		},
+	})
+	return id
+}
+
+func TestMockVpcEIP_basic(t *testing.T) {
+	t.Parallel()
+
+	c := newMockCloud(t)
+	resourceName := "flexibleengine_vpc_eip.test"
+
+	c.resourceTest(t, resource.TestCase{
+		CheckDestroy: c.checkDestroyed("publicips"),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockVpcEIP_basic(5),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "status", "UNBOUND"),
+					resource.TestCheckResourceAttr(resourceName, "publicip.0.type", "5_bgp"),
+					resource.TestCheckResourceAttr(resourceName, "bandwidth.0.name", "mock-bandwidth"),
+					resource.TestCheckResourceAttr(resourceName, "bandwidth.0.size", "5"),
+					resource.TestCheckResourceAttr(resourceName, "bandwidth.0.charge_mode", "traffic"),
+					resource.TestCheckResourceAttr(resourceName, "enterprise_project_id", "0"),
+					resource.TestCheckResourceAttr(resourceName, "tags.foo", "bar"),
+					resource.TestCheckResourceAttrSet(resourceName, "address"),
+				),
+			},
+			{
+				Config: testMockVpcEIP_update(10),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "bandwidth.0.size", "10"),
+					resource.TestCheckResourceAttr(resourceName, "enterprise_project_id", "mock-eps-id"),
+					resource.TestCheckResourceAttr(resourceName, "tags.%", "1"),
+					resource.TestCheckResourceAttr(resourceName, "tags.key", "value"),
+					c.checkStored("publicips", resourceName, "bandwidth_size", "10"),
+				),
+			},
+		},
+	})
+}
+
+func TestMockVpcEIP_defaultEnterpriseProject(t *testing.T) {
+	t.Parallel()
+
+	c := newMockCloud(t)
+	resourceName := "flexibleengine_vpc_eip.test"
+
+	c.resourceTest(t, resource.TestCase{
+		CheckDestroy: c.checkDestroyed("publicips"),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockVpcEIP_basic(5),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "enterprise_project_id", "0"),
+				),
+			},
+			{
+				// the existing EIP is migrated to the new provider default
+				Config: c.providerConfig(`enterprise_project_id = "mock-eps-id"`) + testMockVpcEIP_basic(5),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "enterprise_project_id", "mock-eps-id"),
+					c.checkStored("publicips", resourceName, "enterprise_project_id", "mock-eps-id"),
+				),
+			},
+			{
+				Config: testMockVpcEIP_basic(5),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "enterprise_project_id", "0"),
+					c.checkStored("publicips", resourceName, "enterprise_project_id", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestMockVpcEIP_createError(t *testing.T) {
+	t.Parallel()
+
+	c := newMockCloud(t)
+	c.injectFault("vpc", "POST", "/v1/{project}/publicips", http.StatusBadRequest, 1)
+
+	c.resourceTest(t, resource.TestCase{
+		CheckDestroy: c.checkDestroyed("publicips"),
+		Steps: []resource.TestStep{
+			{
+				Config:      testMockVpcEIP_basic(5),
+				ExpectError: regexp.MustCompile(`Error allocating EIP`),
+			},
+		},
+	})
+}
+
+func TestMockVpcEIP_prePaid(t *testing.T) {
+	t.Parallel()
+
+	c := newMockCloud(t)
+	resourceName := "flexibleengine_vpc_eip.test"
+
+	c.resourceTest(t, resource.TestCase{
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			c.checkDestroyed("publicips"),
+			c.checkDestroyed("bandwidths"),
+			c.checkDestroyed("subscriptions"),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockVpcEIP_prePaid(`charging_mode = "prePaid"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "charging_mode", "prePaid"),
+					resource.TestCheckResourceAttr(resourceName, "bandwidth.0.charge_mode", "bandwidth"),
+					c.checkStored("subscriptions", resourceName, "auto_renew", "false"),
+				),
+			},
+			{
+				Config: testMockVpcEIP_prePaid(`charging_mode = "postPaid"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "charging_mode", "postPaid"),
+					c.checkStored("subscriptions", resourceName, "expire_policy", "on_demand"),
+				),
+			},
+		},
+	})
+}
+
+func TestMockVpcEIP_prePaidTraffic(t *testing.T) {
+	t.Parallel()
+
+	c := newMockCloud(t)
+
+	c.resourceTest(t, resource.TestCase{
+		CheckDestroy: c.checkDestroyed("publicips"),
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "flexibleengine_vpc_eip" "test" {
+  charging_mode = "prePaid"
+  period_unit   = "month"
+  period        = 1
+
+  publicip {
+    type = "5_bgp"
+  }
+  bandwidth {
+    name       = "mock-bandwidth"
+    size       = 5
+    share_type = "PER"
+  }
+}
+`,
+				ExpectError: regexp.MustCompile(`billed by bandwidth`),
+			},
+		},
+	})
+}
+
+func testMockVpcEIP_basic(size int) string {
+	return fmt.Sprintf(`
+resource "flexibleengine_vpc_eip" "test" {
+  publicip {
+    type = "5_bgp"
+  }
+  bandwidth {
+    name       = "mock-bandwidth"
+    size       = %d
+    share_type = "PER"
+  }
+
+  tags = {
+    foo = "bar"
+  }
+}
+`, size)
+}
+
+func testMockVpcEIP_update(size int) string {
+	return fmt.Sprintf(`
+resource "flexibleengine_vpc_eip" "test" {
+  enterprise_project_id = "mock-eps-id"
+
+  publicip {
+    type = "5_bgp"
+  }
+  bandwidth {
+    name       = "mock-bandwidth"
+    size       = %d
+    share_type = "PER"
+  }
+
+  tags = {
+    key = "value"
+  }
+}
+`, size)
+}
+
+func testMockVpcEIP_prePaid(chargingMode string) string {
+	return fmt.Sprintf(`
+resource "flexibleengine_vpc_eip" "test" {
+  %s
+  period_unit = "month"
+  period      = 1
+
+  publicip {
+    type = "5_bgp"
+  }
+  bandwidth {
+    name        = "mock-bandwidth"
+    size        = 5
+    share_type  = "PER"
+    charge_mode = "bandwidth"
+  }
+}
+`, chargingMode)
+}
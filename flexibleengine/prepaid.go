@@ -0,0 +1,314 @@
+package flexibleengine
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/chnsz/golangsdk"
+	"github.com/chnsz/golangsdk/openstack/bss/v2/orders"
+	"github.com/chnsz/golangsdk/openstack/bss/v2/resources"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// the billing modes of the resources
+const (
+	chargingModePrePaid  = "prePaid"
+	chargingModePostPaid = "postPaid"
+)
+
+// expirePolicyToOnDemand is the expire policy of the yearly/monthly resources which are switched
+// to pay-per-use billing when the current period expires
+const expirePolicyToOnDemand = 1
+
+// the status of the BSS orders
+const (
+	orderStatusProcessing     = "3"
+	orderStatusCompleted      = "5"
+	orderStatusPendingPayment = "6"
+)
+
+// chargingModeSchema returns the schema to use for charging_mode.
+// The resource can be switched between pay-per-use and yearly/monthly billing in place.
+func chargingModeSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		Computed:     true,
+		ValidateFunc: validation.StringInSlice([]string{chargingModePrePaid, chargingModePostPaid}, false),
+	}
+}
+
+func periodUnitSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		RequiredWith: []string{"period"},
+		ValidateFunc: validation.StringInSlice([]string{"month", "year"}, false),
+	}
+}
+
+func periodSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeInt,
+		Optional:     true,
+		RequiredWith: []string{"period_unit"},
+		ValidateFunc: validation.IntBetween(1, 9),
+	}
+}
+
+func autoRenewSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		ValidateFunc: validation.StringInSlice([]string{"true", "false"}, false),
+	}
+}
+
+func isPrePaid(d *schema.ResourceData) bool {
+	return d.Get("charging_mode").(string) == chargingModePrePaid
+}
+
+// setChargingMode sets charging_mode with the billing mode reported by the service. The switch to
+// pay-per-use takes effect when the current period expires, so the pending switch is kept.
+func setChargingMode(d *schema.ResourceData, prePaid bool) {
+	if !prePaid {
+		d.Set("charging_mode", chargingModePostPaid)
+	} else if d.Get("charging_mode").(string) != chargingModePostPaid {
+		d.Set("charging_mode", chargingModePrePaid)
+	}
+}
+
+// validatePeriodDiff rejects changing period_unit and period of the yearly/monthly resource, the period
+// only takes effect when the resource is created or switched to yearly/monthly billing.
+func validatePeriodDiff(d *schema.ResourceDiff) error {
+	if d.Id() == "" || !d.HasChanges("period_unit", "period") {
+		return nil
+	}
+
+	oldMode, newMode := d.GetChange("charging_mode")
+	if oldMode.(string) == chargingModePrePaid && newMode.(string) == chargingModePrePaid {
+		return fmt.Errorf("period_unit and period can not be changed in %s charging mode, "+
+			"the period can only be specified when the resource is created or switched to %s charging mode",
+			chargingModePrePaid, chargingModePrePaid)
+	}
+	return nil
+}
+
+// prePaidOpts holds the period of the yearly/monthly billing, the orders are always paid automatically
+type prePaidOpts struct {
+	PeriodUnit string
+	Period     int
+	AutoRenew  string
+}
+
+// expandPrePaidOpts returns the period of the yearly/monthly billing, both period_unit
+// and period must be specified in this billing mode.
+func expandPrePaidOpts(d *schema.ResourceData) (*prePaidOpts, error) {
+	periodUnit := d.Get("period_unit").(string)
+	period := d.Get("period").(int)
+	if periodUnit == "" || period == 0 {
+		return nil, fmt.Errorf("both period_unit and period must be specified in %s charging mode", chargingModePrePaid)
+	}
+
+	autoRenew := d.Get("auto_renew").(string)
+	if autoRenew == "" {
+		autoRenew = "false"
+	}
+	return &prePaidOpts{
+		PeriodUnit: periodUnit,
+		Period:     period,
+		AutoRenew:  autoRenew,
+	}, nil
+}
+
+// waitForOrderComplete waits for the order to be paid and the resources to be delivered
+func waitForOrderComplete(bssClient *golangsdk.ServiceClient, orderID string, timeout time.Duration) error {
+	stateConf := &resource.StateChangeConf{
+		Pending:    []string{orderStatusProcessing, orderStatusPendingPayment},
+		Target:     []string{orderStatusCompleted},
+		Refresh:    orderStateRefreshFunc(bssClient, orderID),
+		Timeout:    timeout,
+		Delay:      stateRefreshDelay(10 * time.Second),
+		MinTimeout: stateRefreshInterval(3 * time.Second),
+	}
+
+	if _, err := stateConf.WaitForState(); err != nil {
+		return fmt.Errorf("Error waiting for the order (%s) to complete: %s", orderID, err)
+	}
+	return nil
+}
+
+func orderStateRefreshFunc(bssClient *golangsdk.ServiceClient, orderID string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		order, err := orders.Get(bssClient, orderID).Extract()
+		if err != nil {
+			return nil, "", err
+		}
+		return order, strconv.Itoa(order.OrderInfo.Status), nil
+	}
+}
+
+// getOrderResourceID returns the ID of the main resource which is delivered by the order
+func getOrderResourceID(bssClient *golangsdk.ServiceClient, orderID string, timeout time.Duration) (string, error) {
+	var resourceID string
+	err := resource.Retry(timeout, func() *resource.RetryError {
+		listOpts := resources.ListOpts{
+			OrderId:          orderID,
+			OnlyMainResource: 1,
+		}
+		resp, err := resources.List(bssClient, listOpts)
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+		if len(resp.Resources) == 0 {
+			return resource.RetryableError(fmt.Errorf("the resource of order %s is not found", orderID))
+		}
+		resourceID = resp.Resources[0].ResourceId
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("Error retrieving the resource of order %s: %s", orderID, err)
+	}
+	return resourceID, nil
+}
+
+// waitForOrderResource waits for the order to complete and returns the ID of the resource
+func waitForOrderResource(d *schema.ResourceData, config *Config, orderID string) (string, error) {
+	bssClient, err := config.BssV2Client(GetRegion(d, config))
+	if err != nil {
+		return "", fmt.Errorf("Error creating FlexibleEngine BSS client: %s", err)
+	}
+
+	timeout := d.Timeout(schema.TimeoutCreate)
+	if err := waitForOrderComplete(bssClient, orderID, timeout); err != nil {
+		return "", err
+	}
+	return getOrderResourceID(bssClient, orderID, timeout)
+}
+
+// unsubscribePrePaidResource unsubscribes the yearly/monthly resources, the resources
+// are deleted asynchronously after the unsubscription.
+func unsubscribePrePaidResource(d *schema.ResourceData, config *Config, resourceIDs []string) error {
+	bssClient, err := config.BssV2Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating FlexibleEngine BSS client: %s", err)
+	}
+
+	unsubscribeOpts := orders.UnsubscribeOpts{
+		ResourceIds:     resourceIDs,
+		UnsubscribeType: 1,
+	}
+	log.Printf("[DEBUG] Unsubscribing the resources %v", resourceIDs)
+	if _, err := orders.Unsubscribe(bssClient, unsubscribeOpts).Extract(); err != nil {
+		return fmt.Errorf("Error unsubscribing the resources %v: %s", resourceIDs, err)
+	}
+	return nil
+}
+
+type changeToOnDemandOpts struct {
+	Operation   string   `json:"operation" required:"true"`
+	ResourceIDs []string `json:"resource_ids" required:"true"`
+}
+
+// changeToOnDemand switches the yearly/monthly resources to pay-per-use billing,
+// which takes effect when the current period expires.
+func changeToOnDemand(bssClient *golangsdk.ServiceClient, resourceIDs []string) error {
+	opts := changeToOnDemandOpts{
+		Operation:   "SET_UP",
+		ResourceIDs: resourceIDs,
+	}
+	reqBody, err := golangsdk.BuildRequestBody(opts, "")
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[DEBUG] Changing the resources %v to pay-per-use billing", resourceIDs)
+	url := bssClient.ServiceURL("orders", "subscriptions", "resources", "to-on-demand")
+	_, err = bssClient.Post(url, reqBody, nil, &golangsdk.RequestOpts{
+		OkCodes: []int{200, 204},
+	})
+	if err != nil {
+		return fmt.Errorf("Error changing the resources %v to pay-per-use billing: %s", resourceIDs, err)
+	}
+	return nil
+}
+
+// isPendingOnDemand checks whether the yearly/monthly resource is switched to pay-per-use billing
+// when the current period expires, see setChargingMode.
+func isPendingOnDemand(bssClient *golangsdk.ServiceClient, resourceID string) (bool, error) {
+	listOpts := resources.ListOpts{
+		ResourceIds:      []string{resourceID},
+		OnlyMainResource: 1,
+	}
+	resp, err := resources.List(bssClient, listOpts)
+	if err != nil {
+		return false, fmt.Errorf("Error retrieving the subscription of %s: %s", resourceID, err)
+	}
+
+	for _, item := range resp.Resources {
+		if item.ResourceId == resourceID && item.ExpirePolicy == expirePolicyToOnDemand {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// changeToPeriodFunc places the order to change the pay-per-use resource to yearly/monthly billing
+type changeToPeriodFunc func(opts *prePaidOpts) (string, error)
+
+// updateChargingMode switches the billing mode of the resource when charging_mode was changed,
+// and enables or disables the auto-renew of the yearly/monthly resource when auto_renew was changed.
+func updateChargingMode(d *schema.ResourceData, config *Config, resourceID string, toPeriod changeToPeriodFunc) error {
+	if !d.HasChanges("charging_mode", "auto_renew") {
+		return nil
+	}
+
+	bssClient, err := config.BssV2Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating FlexibleEngine BSS client: %s", err)
+	}
+
+	oldMode, newMode := d.GetChange("charging_mode")
+	switch {
+	case oldMode.(string) != chargingModePrePaid && newMode.(string) == chargingModePrePaid:
+		pending, err := isPendingOnDemand(bssClient, resourceID)
+		if err != nil {
+			return err
+		}
+		if pending {
+			return fmt.Errorf("Error changing %s to %s charging mode: the switch to %s charging mode takes effect "+
+				"when the current period expires, it can not be changed back before that", resourceID,
+				chargingModePrePaid, chargingModePostPaid)
+		}
+
+		opts, err := expandPrePaidOpts(d)
+		if err != nil {
+			return err
+		}
+		orderID, err := toPeriod(opts)
+		if err != nil {
+			return fmt.Errorf("Error changing %s to %s charging mode: %s", resourceID, chargingModePrePaid, err)
+		}
+		// the auto-renew is set by the order
+		return waitForOrderComplete(bssClient, orderID, d.Timeout(schema.TimeoutUpdate))
+
+	case oldMode.(string) == chargingModePrePaid && newMode.(string) == chargingModePostPaid:
+		return changeToOnDemand(bssClient, []string{resourceID})
+	}
+
+	if d.HasChange("auto_renew") && isPrePaid(d) {
+		if d.Get("auto_renew").(string) == "true" {
+			err = resources.EnableAutoRenew(bssClient, resourceID)
+		} else {
+			err = resources.DisableAutoRenew(bssClient, resourceID)
+		}
+		if err != nil {
+			return fmt.Errorf("Error updating the auto-renew of %s: %s", resourceID, err)
+		}
+	}
+	return nil
+}
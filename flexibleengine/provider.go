@@ -9,6 +9,7 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
@@ -60,7 +61,7 @@ func Provider() *schema.Provider {
 		Schema: map[string]*schema.Schema{
 			"region": {
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				Description: descriptions["region"],
 				DefaultFunc: schema.EnvDefaultFunc("OS_REGION_NAME", nil),
 			},
@@ -162,6 +163,58 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc("OS_SECURITY_TOKEN", nil),
 			},
 
+			"credential_process": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: descriptions["credential_process"],
+				DefaultFunc: schema.EnvDefaultFunc("OS_CREDENTIAL_PROCESS", ""),
+			},
+
+			"assume_role": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"agency_name": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: descriptions["assume_role_agency_name"],
+							DefaultFunc: schema.EnvDefaultFunc("OS_ASSUME_ROLE_AGENCY_NAME", nil),
+						},
+						"domain_name": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: descriptions["assume_role_domain_name"],
+							DefaultFunc: schema.EnvDefaultFunc("OS_ASSUME_ROLE_DOMAIN_NAME", nil),
+						},
+						"duration": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      defaultAssumeRoleDuration,
+							Description:  descriptions["assume_role_duration"],
+							ValidateFunc: validation.IntBetween(900, 86400),
+						},
+					},
+				},
+			},
+
+			"default_tags": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"tags": {
+							Type:        schema.TypeMap,
+							Optional:    true,
+							Description: descriptions["default_tags"],
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+
 			"auth_url": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -173,7 +226,28 @@ func Provider() *schema.Provider {
 				Type:        schema.TypeString,
 				Optional:    true,
 				Description: descriptions["cloud"],
-				DefaultFunc: schema.EnvDefaultFunc("OS_CLOUD", defaultCloud),
+				DefaultFunc: schema.EnvDefaultFunc("OS_CLOUD", ""),
+			},
+
+			"shared_config_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: descriptions["shared_config_file"],
+				DefaultFunc: schema.EnvDefaultFunc("OS_SHARED_CONFIG_FILE", ""),
+			},
+
+			"profile": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: descriptions["profile"],
+				DefaultFunc: schema.EnvDefaultFunc("OS_PROFILE", ""),
+			},
+
+			"enterprise_project_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: descriptions["enterprise_project_id"],
+				DefaultFunc: schema.EnvDefaultFunc("OS_ENTERPRISE_PROJECT_ID", ""),
 			},
 
 			"endpoints": {
@@ -197,6 +271,29 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc("OS_MAX_RETRIES", 5),
 			},
 
+			"max_requests_per_second": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  descriptions["max_requests_per_second"],
+				DefaultFunc:  schema.EnvDefaultFunc("OS_MAX_REQUESTS_PER_SECOND", 0),
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+
+			"max_concurrent_requests": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  descriptions["max_concurrent_requests"],
+				DefaultFunc:  schema.EnvDefaultFunc("OS_MAX_CONCURRENT_REQUESTS", 0),
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+
+			"http_audit_log": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: descriptions["http_audit_log"],
+				DefaultFunc: schema.EnvDefaultFunc("OS_HTTP_AUDIT_LOG", ""),
+			},
+
 			"cacert_file": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -222,6 +319,8 @@ func Provider() *schema.Provider {
 		DataSourcesMap: map[string]*schema.Resource{
 			"flexibleengine_availability_zones":        dataSourceAvailabilityZones(),
 			"flexibleengine_blockstorage_volume_v2":    dataSourceBlockStorageVolumeV2(),
+			"flexibleengine_blockstorage_snapshots":    dataSourceBlockStorageSnapshots(),
+			"flexibleengine_blockstorage_volumes":      dataSourceBlockStorageVolumes(),
 			"flexibleengine_compute_instance_v2":       dataSourceComputeInstance(),
 			"flexibleengine_compute_instances":         dataSourceComputeInstances(),
 			"flexibleengine_compute_flavors_v2":        dataSourceEcsFlavors(),
@@ -251,6 +350,8 @@ func Provider() *schema.Provider {
 			"flexibleengine_cts_tracker_v1":            dataSourceCTSTrackerV1(),
 			"flexibleengine_dcs_maintainwindow_v1":     dataSourceDcsMaintainWindowV1(),
 			"flexibleengine_dcs_product_v1":            dataSourceDcsProductV1(),
+			"flexibleengine_deh_host_types":            dataSourceDeHHostTypes(),
+			"flexibleengine_deh_instance":              dataSourceDeHInstance(),
 			"flexibleengine_dms_product":               dataSourceDmsProduct(),
 			"flexibleengine_cce_node_v3":               dataSourceCceNodesV3(),
 			"flexibleengine_cce_node_ids_v3":           dataSourceCceNodeIdsV3(),
@@ -261,6 +362,7 @@ func Provider() *schema.Provider {
 			"flexibleengine_lb_certificate_v2":         dataSourceCertificateV2(),
 			"flexibleengine_lb_loadbalancer_v2":        dataSourceELBV2Loadbalancer(),
 			"flexibleengine_sdrs_domain_v1":            dataSourceSdrsDomainV1(),
+			"flexibleengine_caller_identity":           dataSourceCallerIdentity(),
 			"flexibleengine_identity_project_v3":       dataSourceIdentityProjectV3(),
 			"flexibleengine_identity_role_v3":          dataSourceIdentityRoleV3(),
 			"flexibleengine_identity_custom_role_v3":   dataSourceIdentityCustomRoleV3(),
@@ -269,33 +371,33 @@ func Provider() *schema.Provider {
 			"flexibleengine_elb_flavors":               dataSourceElbFlavorsV3(),
 
 			// importing new data source
-			"flexibleengine_apig_environments":  apig.DataSourceEnvironments(),
-			"flexibleengine_enterprise_project": eps.DataSourceEnterpriseProject(),
-			"flexibleengine_cbr_vaults":         cbr.DataSourceCbrVaultsV3(),
-			"flexibleengine_cce_clusters":       cce.DataSourceCCEClusters(),
+			"flexibleengine_apig_environments":  huaweiResource(apig.DataSourceEnvironments()),
+			"flexibleengine_enterprise_project": huaweiResource(eps.DataSourceEnterpriseProject()),
+			"flexibleengine_cbr_vaults":         huaweiResource(cbr.DataSourceCbrVaultsV3()),
+			"flexibleengine_cce_clusters":       huaweiResource(cce.DataSourceCCEClusters()),
 
-			"flexibleengine_dms_rocketmq_broker":    dms.DataSourceDmsRocketMQBroker(),
-			"flexibleengine_dms_rocketmq_instances": dms.DataSourceDmsRocketMQInstances(),
+			"flexibleengine_dms_rocketmq_broker":    huaweiResource(dms.DataSourceDmsRocketMQBroker()),
+			"flexibleengine_dms_rocketmq_instances": huaweiResource(dms.DataSourceDmsRocketMQInstances()),
 
-			"flexibleengine_dws_flavors":      dws.DataSourceDwsFlavlors(),
-			"flexibleengine_elb_certificate":  elb.DataSourceELBCertificateV3(),
-			"flexibleengine_fgs_dependencies": fgs.DataSourceFunctionGraphDependencies(),
+			"flexibleengine_dws_flavors":      huaweiResource(dws.DataSourceDwsFlavlors()),
+			"flexibleengine_elb_certificate":  huaweiResource(elb.DataSourceELBCertificateV3()),
+			"flexibleengine_fgs_dependencies": huaweiResource(fgs.DataSourceFunctionGraphDependencies()),
 
-			"flexibleengine_networking_port":    vpc.DataSourceNetworkingPortV2(),
-			"flexibleengine_identity_group":     iam.DataSourceIdentityGroup(),
-			"flexibleengine_identity_users":     iam.DataSourceIdentityUsers(),
-			"flexibleengine_sfs_turbos":         sfs.DataSourceTurbos(),
-			"flexibleengine_smn_topics":         smn.DataSourceTopics(),
-			"flexibleengine_sms_source_servers": sms.DataSourceServers(),
-			"flexibleengine_vpc_route_table":    vpc.DataSourceVPCRouteTable(),
+			"flexibleengine_networking_port":    huaweiResource(vpc.DataSourceNetworkingPortV2()),
+			"flexibleengine_identity_group":     huaweiResource(iam.DataSourceIdentityGroup()),
+			"flexibleengine_identity_users":     huaweiResource(iam.DataSourceIdentityUsers()),
+			"flexibleengine_sfs_turbos":         huaweiResource(sfs.DataSourceTurbos()),
+			"flexibleengine_smn_topics":         huaweiResource(smn.DataSourceTopics()),
+			"flexibleengine_sms_source_servers": huaweiResource(sms.DataSourceServers()),
+			"flexibleengine_vpc_route_table":    huaweiResource(vpc.DataSourceVPCRouteTable()),
 
-			"flexibleengine_waf_dedicated_instances": waf.DataSourceWafDedicatedInstancesV1(),
+			"flexibleengine_waf_dedicated_instances": huaweiResource(waf.DataSourceWafDedicatedInstancesV1()),
 
-			"flexibleengine_modelarts_datasets":         modelarts.DataSourceDatasets(),
-			"flexibleengine_modelarts_dataset_versions": modelarts.DataSourceDatasetVerions(),
+			"flexibleengine_modelarts_datasets":         huaweiResource(modelarts.DataSourceDatasets()),
+			"flexibleengine_modelarts_dataset_versions": huaweiResource(modelarts.DataSourceDatasetVerions()),
 
 			// importing existing data source
-			"flexibleengine_rds_flavors_v3": rds.DataSourceRdsFlavor(), // v1.34.0
+			"flexibleengine_rds_flavors_v3": huaweiResource(rds.DataSourceRdsFlavor()), // v1.34.0
 
 			// Deprecated data source
 			"flexibleengine_compute_availability_zones_v2":      dataSourceAvailabilityZones(),
@@ -313,6 +415,7 @@ func Provider() *schema.Provider {
 
 		ResourcesMap: map[string]*schema.Resource{
 			"flexibleengine_blockstorage_volume_v2":             resourceBlockStorageVolumeV2(),
+			"flexibleengine_blockstorage_snapshot_v2":           resourceBlockStorageSnapshotV2(),
 			"flexibleengine_compute_instance_v2":                resourceComputeInstanceV2(),
 			"flexibleengine_compute_interface_attach_v2":        resourceComputeInterfaceAttachV2(),
 			"flexibleengine_compute_keypair_v2":                 resourceComputeKeypairV2(),
@@ -324,14 +427,20 @@ func Provider() *schema.Provider {
 			"flexibleengine_dns_recordset_v2":                   resourceDNSRecordSetV2(),
 			"flexibleengine_dns_zone_v2":                        resourceDNSZoneV2(),
 			"flexibleengine_dcs_instance_v1":                    resourceDcsInstanceV1(),
+			"flexibleengine_deh_instance":                       resourceDeHInstance(),
 			"flexibleengine_dms_kafka_instance":                 resourceDmsKafkaInstances(),
 			"flexibleengine_dms_kafka_topic":                    resourceDmsKafkaTopic(),
 			"flexibleengine_dis_stream":                         resourceDisStreamV2(),
 			"flexibleengine_fw_firewall_group_v2":               resourceFWFirewallGroupV2(),
 			"flexibleengine_fw_policy_v2":                       resourceFWPolicyV2(),
 			"flexibleengine_fw_rule_v2":                         resourceFWRuleV2(),
+			"flexibleengine_images_image":                       resourceImagesImage(),
+			"flexibleengine_images_image_share":                 resourceImagesImageShare(),
+			"flexibleengine_images_image_share_accepter":        resourceImagesImageShareAccepter(),
 			"flexibleengine_images_image_v2":                    resourceImagesImageV2(),
 			"flexibleengine_kms_key_v1":                         resourceKmsKeyV1(),
+			"flexibleengine_kps_keypair":                        resourceKpsKeypair(),
+			"flexibleengine_kps_keypair_association":            resourceKpsKeypairAssociation(),
 			"flexibleengine_lb_loadbalancer_v2":                 resourceLoadBalancerV2(),
 			"flexibleengine_lb_listener_v2":                     resourceListenerV2(),
 			"flexibleengine_lb_member_v2":                       resourceMemberV2(),
@@ -404,6 +513,7 @@ func Provider() *schema.Provider {
 			"flexibleengine_cts_tracker_v1":                     resourceCTSTrackerV1(),
 			"flexibleengine_cce_cluster_v3":                     resourceCCEClusterV3(),
 			"flexibleengine_cce_node_v3":                        resourceCCENodeV3(),
+			"flexibleengine_cce_node_attach":                    resourceCCENodeAttach(),
 			"flexibleengine_cce_node_pool_v3":                   resourceCCENodePool(),
 			"flexibleengine_cce_addon_v3":                       resourceCCEAddon(),
 			"flexibleengine_dds_instance_v3":                    resourceDdsInstanceV3(),
@@ -427,85 +537,85 @@ func Provider() *schema.Provider {
 			"flexibleengine_dli_queue":                          ResourceDliQueueV1(),
 
 			// importing new resource
-			"flexibleengine_apig_api":                         apig.ResourceApigAPIV2(),
-			"flexibleengine_apig_api_publishment":             apig.ResourceApigApiPublishment(),
-			"flexibleengine_apig_instance":                    apig.ResourceApigInstanceV2(),
-			"flexibleengine_apig_application":                 apig.ResourceApigApplicationV2(),
-			"flexibleengine_apig_custom_authorizer":           apig.ResourceApigCustomAuthorizerV2(),
-			"flexibleengine_apig_environment":                 apig.ResourceApigEnvironmentV2(),
-			"flexibleengine_apig_group":                       apig.ResourceApigGroupV2(),
-			"flexibleengine_apig_response":                    apig.ResourceApigResponseV2(),
-			"flexibleengine_apig_throttling_policy_associate": apig.ResourceThrottlingPolicyAssociate(),
-			"flexibleengine_apig_throttling_policy":           apig.ResourceApigThrottlingPolicyV2(),
-			"flexibleengine_apig_vpc_channel":                 apig.ResourceApigVpcChannelV2(),
-
-			"flexibleengine_api_gateway_api":   huaweicloud.ResourceAPIGatewayAPI(),
-			"flexibleengine_api_gateway_group": huaweicloud.ResourceAPIGatewayGroup(),
-
-			"flexibleengine_enterprise_project":        eps.ResourceEnterpriseProject(),
-			"flexibleengine_cbr_policy":                cbr.ResourceCBRPolicyV3(),
-			"flexibleengine_cbr_vault":                 cbr.ResourceVault(),
-			"flexibleengine_cce_namespace":             cce.ResourceCCENamespaceV1(),
-			"flexibleengine_cce_pvc":                   cce.ResourceCcePersistentVolumeClaimsV1(),
-			"flexibleengine_cse_microservice":          cse.ResourceMicroservice(),
-			"flexibleengine_cse_microservice_engine":   cse.ResourceMicroserviceEngine(),
-			"flexibleengine_cse_microservice_instance": cse.ResourceMicroserviceInstance(),
-			"flexibleengine_dds_database_role":         dds.ResourceDatabaseRole(),
-			"flexibleengine_dds_database_user":         dds.ResourceDatabaseUser(),
-
-			"flexibleengine_dms_kafka_user":              dms.ResourceDmsKafkaUser(),
-			"flexibleengine_dms_rocketmq_instance":       dms.ResourceDmsRocketMQInstance(),
-			"flexibleengine_dms_rocketmq_consumer_group": dms.ResourceDmsRocketMQConsumerGroup(),
-			"flexibleengine_dms_rocketmq_topic":          dms.ResourceDmsRocketMQTopic(),
-			"flexibleengine_dms_rocketmq_user":           dms.ResourceDmsRocketMQUser(),
-
-			"flexibleengine_dli_database":           dli.ResourceDliSqlDatabaseV1(),
-			"flexibleengine_dli_package":            dli.ResourceDliPackageV2(),
-			"flexibleengine_dli_spark_job":          dli.ResourceDliSparkJobV2(),
-			"flexibleengine_dli_table":              dli.ResourceDliTable(),
-			"flexibleengine_dli_flinksql_job":       dli.ResourceFlinkSqlJob(),
-			"flexibleengine_drs_job":                drs.ResourceDrsJob(),
-			"flexibleengine_fgs_dependency":         fgs.ResourceFgsDependency(),
-			"flexibleengine_fgs_function":           fgs.ResourceFgsFunctionV2(),
-			"flexibleengine_fgs_trigger":            fgs.ResourceFunctionGraphTrigger(),
-			"flexibleengine_identity_acl":           iam.ResourceIdentityACL(),
-			"flexibleengine_rds_account":            rds.ResourceRdsAccount(),
-			"flexibleengine_rds_database":           rds.ResourceRdsDatabase(),
-			"flexibleengine_rds_database_privilege": rds.ResourceRdsDatabasePrivilege(),
-			"flexibleengine_sms_server_template":    sms.ResourceServerTemplate(),
-			"flexibleengine_sms_task":               sms.ResourceMigrateTask(),
-			"flexibleengine_swr_organization":       swr.ResourceSWROrganization(),
-			"flexibleengine_swr_organization_users": swr.ResourceSWROrganizationPermissions(),
-			"flexibleengine_swr_repository":         swr.ResourceSWRRepository(),
-			"flexibleengine_swr_repository_sharing": swr.ResourceSWRRepositorySharing(),
-
-			"flexibleengine_tms_tags": tms.ResourceTmsTag(),
-
-			"flexibleengine_vpc_eip_associate": eip.ResourceEIPAssociate(),
-			"flexibleengine_vpc_route_table":   vpc.ResourceVPCRouteTable(),
-			"flexibleengine_vpc_route":         vpc.ResourceVPCRouteTableRoute(),
+			"flexibleengine_apig_api":                         huaweiResource(apig.ResourceApigAPIV2()),
+			"flexibleengine_apig_api_publishment":             huaweiResource(apig.ResourceApigApiPublishment()),
+			"flexibleengine_apig_instance":                    huaweiResource(apig.ResourceApigInstanceV2()),
+			"flexibleengine_apig_application":                 huaweiResource(apig.ResourceApigApplicationV2()),
+			"flexibleengine_apig_custom_authorizer":           huaweiResource(apig.ResourceApigCustomAuthorizerV2()),
+			"flexibleengine_apig_environment":                 huaweiResource(apig.ResourceApigEnvironmentV2()),
+			"flexibleengine_apig_group":                       huaweiResource(apig.ResourceApigGroupV2()),
+			"flexibleengine_apig_response":                    huaweiResource(apig.ResourceApigResponseV2()),
+			"flexibleengine_apig_throttling_policy_associate": huaweiResource(apig.ResourceThrottlingPolicyAssociate()),
+			"flexibleengine_apig_throttling_policy":           huaweiResource(apig.ResourceApigThrottlingPolicyV2()),
+			"flexibleengine_apig_vpc_channel":                 huaweiResource(apig.ResourceApigVpcChannelV2()),
+
+			"flexibleengine_api_gateway_api":   huaweiResource(huaweicloud.ResourceAPIGatewayAPI()),
+			"flexibleengine_api_gateway_group": huaweiResource(huaweicloud.ResourceAPIGatewayGroup()),
+
+			"flexibleengine_enterprise_project":        huaweiResource(eps.ResourceEnterpriseProject()),
+			"flexibleengine_cbr_policy":                huaweiResource(cbr.ResourceCBRPolicyV3()),
+			"flexibleengine_cbr_vault":                 huaweiResource(cbr.ResourceVault()),
+			"flexibleengine_cce_namespace":             huaweiResource(cce.ResourceCCENamespaceV1()),
+			"flexibleengine_cce_pvc":                   huaweiResource(cce.ResourceCcePersistentVolumeClaimsV1()),
+			"flexibleengine_cse_microservice":          huaweiResource(cse.ResourceMicroservice()),
+			"flexibleengine_cse_microservice_engine":   huaweiResource(cse.ResourceMicroserviceEngine()),
+			"flexibleengine_cse_microservice_instance": huaweiResource(cse.ResourceMicroserviceInstance()),
+			"flexibleengine_dds_database_role":         huaweiResource(dds.ResourceDatabaseRole()),
+			"flexibleengine_dds_database_user":         huaweiResource(dds.ResourceDatabaseUser()),
+
+			"flexibleengine_dms_kafka_user":              huaweiResource(dms.ResourceDmsKafkaUser()),
+			"flexibleengine_dms_rocketmq_instance":       huaweiResource(dms.ResourceDmsRocketMQInstance()),
+			"flexibleengine_dms_rocketmq_consumer_group": huaweiResource(dms.ResourceDmsRocketMQConsumerGroup()),
+			"flexibleengine_dms_rocketmq_topic":          huaweiResource(dms.ResourceDmsRocketMQTopic()),
+			"flexibleengine_dms_rocketmq_user":           huaweiResource(dms.ResourceDmsRocketMQUser()),
+
+			"flexibleengine_dli_database":           huaweiResource(dli.ResourceDliSqlDatabaseV1()),
+			"flexibleengine_dli_package":            huaweiResource(dli.ResourceDliPackageV2()),
+			"flexibleengine_dli_spark_job":          huaweiResource(dli.ResourceDliSparkJobV2()),
+			"flexibleengine_dli_table":              huaweiResource(dli.ResourceDliTable()),
+			"flexibleengine_dli_flinksql_job":       huaweiResource(dli.ResourceFlinkSqlJob()),
+			"flexibleengine_drs_job":                huaweiResource(drs.ResourceDrsJob()),
+			"flexibleengine_fgs_dependency":         huaweiResource(fgs.ResourceFgsDependency()),
+			"flexibleengine_fgs_function":           huaweiResource(fgs.ResourceFgsFunctionV2()),
+			"flexibleengine_fgs_trigger":            huaweiResource(fgs.ResourceFunctionGraphTrigger()),
+			"flexibleengine_identity_acl":           huaweiResource(iam.ResourceIdentityACL()),
+			"flexibleengine_rds_account":            huaweiResource(rds.ResourceRdsAccount()),
+			"flexibleengine_rds_database":           huaweiResource(rds.ResourceRdsDatabase()),
+			"flexibleengine_rds_database_privilege": huaweiResource(rds.ResourceRdsDatabasePrivilege()),
+			"flexibleengine_sms_server_template":    huaweiResource(sms.ResourceServerTemplate()),
+			"flexibleengine_sms_task":               huaweiResource(sms.ResourceMigrateTask()),
+			"flexibleengine_swr_organization":       huaweiResource(swr.ResourceSWROrganization()),
+			"flexibleengine_swr_organization_users": huaweiResource(swr.ResourceSWROrganizationPermissions()),
+			"flexibleengine_swr_repository":         huaweiResource(swr.ResourceSWRRepository()),
+			"flexibleengine_swr_repository_sharing": huaweiResource(swr.ResourceSWRRepositorySharing()),
+
+			"flexibleengine_tms_tags": huaweiResource(tms.ResourceTmsTag()),
+
+			"flexibleengine_vpc_eip_associate": huaweiResource(eip.ResourceEIPAssociate()),
+			"flexibleengine_vpc_route_table":   huaweiResource(vpc.ResourceVPCRouteTable()),
+			"flexibleengine_vpc_route":         huaweiResource(vpc.ResourceVPCRouteTableRoute()),
 
 			"flexibleengine_waf_dedicated_instance":    ResourceWafDedicatedInstance(),
 			"flexibleengine_waf_dedicated_policy":      ResourceWafDedicatedPolicyV1(),
 			"flexibleengine_waf_dedicated_certificate": ResourceWafDedicatedCertificateV1(),
-			"flexibleengine_waf_dedicated_domain":      waf.ResourceWafDedicatedDomainV1(),
+			"flexibleengine_waf_dedicated_domain":      huaweiResource(waf.ResourceWafDedicatedDomainV1()),
 
-			"flexibleengine_lb_loadbalancer_v3": elb.ResourceLoadBalancerV3(),
-			"flexibleengine_lb_listener_v3":     elb.ResourceListenerV3(),
-			"flexibleengine_elb_certificate":    elb.ResourceCertificateV3(),
-			"flexibleengine_elb_ipgroup":        elb.ResourceIpGroupV3(),
+			"flexibleengine_lb_loadbalancer_v3": huaweiResource(elb.ResourceLoadBalancerV3()),
+			"flexibleengine_lb_listener_v3":     huaweiResource(elb.ResourceListenerV3()),
+			"flexibleengine_elb_certificate":    huaweiResource(elb.ResourceCertificateV3()),
+			"flexibleengine_elb_ipgroup":        huaweiResource(elb.ResourceIpGroupV3()),
 
-			"flexibleengine_modelarts_dataset":         modelarts.ResourceDataset(),
-			"flexibleengine_modelarts_dataset_version": modelarts.ResourceDatasetVersion(),
+			"flexibleengine_modelarts_dataset":         huaweiResource(modelarts.ResourceDataset()),
+			"flexibleengine_modelarts_dataset_version": huaweiResource(modelarts.ResourceDatasetVersion()),
 
 			// importing existing resource
-			"flexibleengine_rds_instance_v3": rds.ResourceRdsInstance(),           // v1.29.0
-			"flexibleengine_vpc_v1":          vpc.ResourceVirtualPrivateCloudV1(), // v1.29.0
-			"flexibleengine_vpc_subnet_v1":   vpc.ResourceVpcSubnetV1(),           // v1.31.0
-			"flexibleengine_lb_pool_v2":      lb.ResourcePoolV2(),                 // v1.35.0
-			"flexibleengine_lb_pool_v3":      elb.ResourcePoolV3(),                // v1.35.0
-			"flexibleengine_lb_monitor_v3":   elb.ResourceMonitorV3(),             // v1.35.0
-			"flexibleengine_lb_member_v3":    elb.ResourceMemberV3(),              // v1.35.0
+			"flexibleengine_rds_instance_v3": huaweiResource(rds.ResourceRdsInstance()),           // v1.29.0
+			"flexibleengine_vpc_v1":          huaweiResource(vpc.ResourceVirtualPrivateCloudV1()), // v1.29.0
+			"flexibleengine_vpc_subnet_v1":   huaweiResource(vpc.ResourceVpcSubnetV1()),           // v1.31.0
+			"flexibleengine_lb_pool_v2":      huaweiResource(lb.ResourcePoolV2()),                 // v1.35.0
+			"flexibleengine_lb_pool_v3":      huaweiResource(elb.ResourcePoolV3()),                // v1.35.0
+			"flexibleengine_lb_monitor_v3":   huaweiResource(elb.ResourceMonitorV3()),             // v1.35.0
+			"flexibleengine_lb_member_v3":    huaweiResource(elb.ResourceMemberV3()),              // v1.35.0
 
 			// Deprecated resource
 			"flexibleengine_elb_loadbalancer":  resourceELoadBalancer(),
@@ -529,6 +639,13 @@ func Provider() *schema.Provider {
 		ConfigureContextFunc: configureProvider,
 	}
 
+	for name, r := range provider.ResourcesMap {
+		wrapAuditResource(name, r)
+	}
+	for name, r := range provider.DataSourcesMap {
+		wrapAuditResource("data."+name, r)
+	}
+
 	return provider
 }
 
@@ -560,6 +677,14 @@ func init() {
 
 		"security_token": "Security token to use for OBS federated authentication.",
 
+		"credential_process": "The command which outputs the temporary access key, secret key and security token in JSON.",
+
+		"assume_role_agency_name": "The name of agency for assume role.",
+
+		"assume_role_domain_name": "The name of domain for assume role.",
+
+		"assume_role_duration": "The validity period (in seconds) of the temporary credentials for assume role.",
+
 		"domain_id": "The ID of the Domain to scope to (Identity v3).",
 
 		"domain_name": "The name of the Domain to scope to (Identity v3).",
@@ -568,6 +693,12 @@ func init() {
 
 		"max_retries": "How many times HTTP connection should be retried until giving up.",
 
+		"max_requests_per_second": "The maximum number of API requests per second to each service, 0 means unlimited.",
+
+		"max_concurrent_requests": "The maximum number of concurrent API requests to each service, 0 means unlimited.",
+
+		"http_audit_log": "The path of the file which a JSON line is appended to for each API request.",
+
 		"cacert_file": "A Custom CA certificate.",
 
 		"cert": "A client certificate to authenticate with.",
@@ -575,29 +706,25 @@ func init() {
 		"key": "A client private key to authenticate with.",
 
 		"cloud": "The endpoint of cloud provider, defaults to prod-cloud-ocb.orange-business.com",
+
+		"default_tags": "The default tags which will be added to every taggable resource.",
+
+		"shared_config_file": "The path to the shared config file. If not set, the default is ~/.flexibleengine/config.",
+
+		"profile": "The profile name as set in the shared config file.",
+
+		"enterprise_project_id": "The default enterprise project ID of the resources which support enterprise projects.",
 	}
 }
 
 func configureProvider(_ context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 	config := Config{}
 
-	region := d.Get("region").(string)
-	cloud := d.Get("cloud").(string)
-	config.Region = region
-
+	config.Region = d.Get("region").(string)
+	config.Cloud = d.Get("cloud").(string)
+	config.IdentityEndpoint = d.Get("auth_url").(string)
 	config.TenantID = d.Get("tenant_id").(string)
 	config.TenantName = d.Get("tenant_name").(string)
-	// set tenant_name to region when neither `tenant_name` nor `tenant_id` was specified
-	if config.TenantID == "" && config.TenantName == "" {
-		config.TenantName = region
-	}
-
-	if v, ok := d.GetOk("auth_url"); ok {
-		config.IdentityEndpoint = v.(string)
-	} else {
-		config.IdentityEndpoint = fmt.Sprintf("https://iam.%s.%s/v3", mainRegion, cloud)
-	}
-
 	config.DomainID = d.Get("domain_id").(string)
 	config.DomainName = d.Get("domain_name").(string)
 	config.UserID = d.Get("user_id").(string)
@@ -607,20 +734,76 @@ func configureProvider(_ context.Context, d *schema.ResourceData) (interface{},
 	config.SecretKey = d.Get("secret_key").(string)
 	config.SecurityToken = d.Get("security_token").(string)
 	config.Token = d.Get("token").(string)
+	config.SharedConfigFile = d.Get("shared_config_file").(string)
+	config.Profile = d.Get("profile").(string)
+	config.EnterpriseProjectID = d.Get("enterprise_project_id").(string)
+
+	// the settings in the shared config file have a lower priority than
+	// the provider arguments and environment variables
+	rawEndpoints := d.Get("endpoints").(map[string]interface{})
+	if config.SharedConfigFile != "" || config.Profile != "" {
+		profile, err := loadSharedProfile(config.SharedConfigFile, config.Profile)
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
+
+		mergeSharedProfile(&config, profile)
+		for key, val := range profile.Endpoints {
+			if _, ok := rawEndpoints[key]; !ok {
+				rawEndpoints[key] = val
+			}
+		}
+	}
+
+	if config.Region == "" {
+		return nil, diag.Errorf("region should be provided")
+	}
+	if config.Cloud == "" {
+		config.Cloud = defaultCloud
+	}
+	region := config.Region
+
+	// set tenant_name to region when neither `tenant_name` nor `tenant_id` was specified
+	if config.TenantID == "" && config.TenantName == "" {
+		config.TenantName = region
+	}
+
+	if config.IdentityEndpoint == "" {
+		config.IdentityEndpoint = fmt.Sprintf("https://iam.%s.%s/v3", mainRegion, config.Cloud)
+	}
+
+	if v, ok := d.GetOk("assume_role"); ok {
+		assumeRole := v.([]interface{})[0].(map[string]interface{})
+		config.AssumeRoleAgency = assumeRole["agency_name"].(string)
+		config.AssumeRoleDomain = assumeRole["domain_name"].(string)
+		config.AssumeRoleDuration = assumeRole["duration"].(int)
+	}
+
+	if v, ok := d.GetOk("default_tags"); ok && v.([]interface{})[0] != nil {
+		rawTags := v.([]interface{})[0].(map[string]interface{})["tags"].(map[string]interface{})
+		defaultTags := make(map[string]string, len(rawTags))
+		for k, v := range rawTags {
+			defaultTags[k] = v.(string)
+		}
+		config.DefaultTags = defaultTags
+	}
 
 	config.MaxRetries = d.Get("max_retries").(int)
+	config.RequestsPerSecond = d.Get("max_requests_per_second").(int)
+	config.MaxConcurrentRequests = d.Get("max_concurrent_requests").(int)
+	config.AuditLogPath = d.Get("http_audit_log").(string)
+	config.CredentialProcess = d.Get("credential_process").(string)
 	config.Insecure = d.Get("insecure").(bool)
 	config.CACertFile = d.Get("cacert_file").(string)
 	config.ClientCertFile = d.Get("cert").(string)
 	config.ClientKeyFile = d.Get("key").(string)
 	config.TerraformVersion = terraformVersion
-	config.Cloud = cloud
 	config.RegionClient = true
 	config.RegionProjectIDMap = make(map[string]string)
 	config.RPLock = new(sync.Mutex)
 
 	// get custom endpoints
-	endpoints, err := flattenProviderEndpoints(d)
+	endpoints, err := flattenProviderEndpoints(rawEndpoints)
 	if err != nil {
 		return nil, diag.FromErr(err)
 	}
@@ -655,8 +838,7 @@ func configureProvider(_ context.Context, d *schema.ResourceData) (interface{},
 	return &config, nil
 }
 
-func flattenProviderEndpoints(d *schema.ResourceData) (map[string]string, error) {
-	endpoints := d.Get("endpoints").(map[string]interface{})
+func flattenProviderEndpoints(endpoints map[string]interface{}) (map[string]string, error) {
 	epMap := make(map[string]string)
 
 	for key, val := range endpoints {
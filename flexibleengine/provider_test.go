@@ -34,6 +34,9 @@ var (
 	OS_DELEGATED_DOMAIN_NAME  = os.Getenv("OS_DELEGATED_DOMAIN_NAME")
 	OS_DESTINATION_BUCKET     = os.Getenv("OS_DESTINATION_BUCKET")
 	OS_FGS_BUCKET             = os.Getenv("OS_FGS_BUCKET")
+	OS_CHARGING_MODE          = os.Getenv("OS_CHARGING_MODE")
+	OS_SHARE_PROJECT_ID       = os.Getenv("OS_SHARE_PROJECT_ID")
+	OS_SHARED_IMAGE_ID        = os.Getenv("OS_SHARED_IMAGE_ID")
 	OS_TENANT_NAME            = getTenantName()
 )
 
@@ -140,6 +143,30 @@ func testAccPreCheckSdrs(t *testing.T) {
 	}
 }
 
+func testAccPreCheckChargingMode(t *testing.T) {
+	testAccPreCheckRequiredEnvVars(t)
+
+	if OS_CHARGING_MODE != "prePaid" {
+		t.Skip("OS_CHARGING_MODE must be set to prePaid for yearly/monthly billing tests")
+	}
+}
+
+func testAccPreCheckImageShare(t *testing.T) {
+	testAccPreCheckRequiredEnvVars(t)
+
+	if OS_SHARE_PROJECT_ID == "" {
+		t.Skip("OS_SHARE_PROJECT_ID must be set for image share acceptance tests")
+	}
+}
+
+func testAccPreCheckImageShareAccepter(t *testing.T) {
+	testAccPreCheckRequiredEnvVars(t)
+
+	if OS_SHARED_IMAGE_ID == "" {
+		t.Skip("OS_SHARED_IMAGE_ID must be set for image share accepter acceptance tests")
+	}
+}
+
 func testAccPreCheckAdminOnly(t *testing.T) {
 	v := os.Getenv("OS_ADMIN")
 	if v != "admin" {
@@ -27,6 +27,8 @@ func resourceASGroup() *schema.Resource {
 			Delete: schema.DefaultTimeout(10 * time.Minute),
 		},
 
+		CustomizeDiff: setTagsAllDiff,
+
 		Schema: map[string]*schema.Schema{
 			"region": {
 				Type:     schema.TypeString,
@@ -198,7 +200,8 @@ func resourceASGroup() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
-			"tags": tagsSchema(),
+			"tags":     tagsSchema(),
+			"tags_all": tagsAllSchema(),
 		},
 	}
 }
@@ -492,7 +495,7 @@ func resourceASGroupCreate(d *schema.ResourceData, meta interface{}) error {
 	d.SetId(asgId)
 
 	// set tags
-	tagRaw := d.Get("tags").(map[string]interface{})
+	tagRaw := getResourceTags(d, meta)
 	if len(tagRaw) > 0 {
 		tagList := expandResourceTags(tagRaw)
 		err := tags.Create(asClient, "scaling_group_tag", asgId, tagList).ExtractErr()
@@ -578,7 +581,7 @@ func resourceASGroupRead(d *schema.ResourceData, meta interface{}) error {
 	resourceTags, err := tags.Get(asClient, "scaling_group_tag", d.Id()).Extract()
 	if err == nil {
 		tagmap := tagsToMap(resourceTags.Tags)
-		d.Set("tags", tagmap)
+		setResourceTags(d, meta, tagmap)
 	} else {
 		log.Printf("[WARN] fetching AS group %s tags failed: %s", d.Id(), err)
 	}
@@ -636,7 +639,7 @@ func resourceASGroupUpdate(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("Error updating ASGroup %q: %s", asgID, err)
 	}
 
-	if d.HasChange("tags") {
+	if d.HasChange("tags_all") {
 		tagErr := UpdateResourceTags(asClient, d, "scaling_group_tag", d.Id())
 		if tagErr != nil {
 			return fmt.Errorf("Error updating tags of AS group:%s, err:%s", d.Id(), tagErr)
@@ -0,0 +1,242 @@
+package flexibleengine
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/chnsz/golangsdk"
+	"github.com/chnsz/golangsdk/openstack/blockstorage/v2/snapshots"
+	evssnapshots "github.com/chnsz/golangsdk/openstack/evs/v2/snapshots"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceBlockStorageSnapshotV2() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceBlockStorageSnapshotV2Create,
+		Read:   resourceBlockStorageSnapshotV2Read,
+		Update: resourceBlockStorageSnapshotV2Update,
+		Delete: resourceBlockStorageSnapshotV2Delete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"volume_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"force": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				ForceNew: true,
+				Default:  false,
+			},
+			"metadata": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"size": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"created_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceBlockStorageSnapshotV2Create(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	blockStorageClient, err := config.BlockStorageV2Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating FlexibleEngine block storage client: %s", err)
+	}
+
+	createOpts := snapshots.CreateOpts{
+		VolumeID:    d.Get("volume_id").(string),
+		Name:        d.Get("name").(string),
+		Description: d.Get("description").(string),
+		Force:       d.Get("force").(bool),
+		Metadata:    resourceContainerMetadataV2(d),
+	}
+
+	log.Printf("[DEBUG] Create Options: %#v", createOpts)
+	s, err := snapshots.Create(blockStorageClient, createOpts).Extract()
+	if err != nil {
+		return fmt.Errorf("Error creating FlexibleEngine snapshot: %s", err)
+	}
+	log.Printf("[INFO] Snapshot ID: %s", s.ID)
+
+	// Store the ID now
+	d.SetId(s.ID)
+
+	// Wait for the snapshot to become available.
+	log.Printf("[DEBUG] Waiting for snapshot (%s) to become available", s.ID)
+
+	stateConf := &resource.StateChangeConf{
+		Pending:    []string{"creating"},
+		Target:     []string{"available"},
+		Refresh:    SnapshotV2StateRefreshFunc(blockStorageClient, s.ID),
+		Timeout:    d.Timeout(schema.TimeoutCreate),
+		Delay:      stateRefreshDelay(10 * time.Second),
+		MinTimeout: stateRefreshInterval(3 * time.Second),
+	}
+
+	_, err = stateConf.WaitForState()
+	if err != nil {
+		return fmt.Errorf(
+			"Error waiting for snapshot (%s) to become ready: %s",
+			s.ID, err)
+	}
+
+	return resourceBlockStorageSnapshotV2Read(d, meta)
+}
+
+func resourceBlockStorageSnapshotV2Read(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	blockStorageClient, err := config.BlockStorageV2Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating FlexibleEngine block storage client: %s", err)
+	}
+
+	s, err := snapshots.Get(blockStorageClient, d.Id()).Extract()
+	if err != nil {
+		return CheckDeleted(d, err, "snapshot")
+	}
+
+	log.Printf("[DEBUG] Retrieved snapshot %s: %+v", d.Id(), s)
+
+	d.Set("region", GetRegion(d, config))
+	d.Set("volume_id", s.VolumeID)
+	d.Set("name", s.Name)
+	d.Set("description", s.Description)
+	d.Set("metadata", s.Metadata)
+	d.Set("size", s.Size)
+	d.Set("status", s.Status)
+	d.Set("created_at", s.CreatedAt.Format(time.RFC3339))
+
+	return nil
+}
+
+func resourceBlockStorageSnapshotV2Update(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	blockStorageClient, err := config.BlockStorageV2Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating FlexibleEngine block storage client: %s", err)
+	}
+
+	if d.HasChanges("name", "description") {
+		// the name and description are only updated by the EVS API
+		updateOpts := evssnapshots.UpdateOpts{
+			Name:        d.Get("name").(string),
+			Description: d.Get("description").(string),
+		}
+		_, err = evssnapshots.Update(blockStorageClient, d.Id(), updateOpts).Extract()
+		if err != nil {
+			return fmt.Errorf("Error updating FlexibleEngine snapshot: %s", err)
+		}
+	}
+
+	if d.HasChange("metadata") {
+		metadata := make(map[string]interface{})
+		for key, value := range d.Get("metadata").(map[string]interface{}) {
+			metadata[key] = value
+		}
+		updateOpts := snapshots.UpdateMetadataOpts{
+			Metadata: metadata,
+		}
+		_, err = snapshots.UpdateMetadata(blockStorageClient, d.Id(), updateOpts).ExtractMetadata()
+		if err != nil {
+			return fmt.Errorf("Error updating metadata of FlexibleEngine snapshot: %s", err)
+		}
+	}
+
+	return resourceBlockStorageSnapshotV2Read(d, meta)
+}
+
+func resourceBlockStorageSnapshotV2Delete(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	blockStorageClient, err := config.BlockStorageV2Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating FlexibleEngine block storage client: %s", err)
+	}
+
+	if err := snapshots.Delete(blockStorageClient, d.Id()).ExtractErr(); err != nil {
+		return CheckDeleted(d, err, "snapshot")
+	}
+
+	// Wait for the snapshot to delete before moving on.
+	log.Printf("[DEBUG] Waiting for snapshot (%s) to delete", d.Id())
+
+	stateConf := &resource.StateChangeConf{
+		Pending:    []string{"available", "deleting"},
+		Target:     []string{"deleted"},
+		Refresh:    SnapshotV2StateRefreshFunc(blockStorageClient, d.Id()),
+		Timeout:    d.Timeout(schema.TimeoutDelete),
+		Delay:      stateRefreshDelay(10 * time.Second),
+		MinTimeout: stateRefreshInterval(3 * time.Second),
+	}
+
+	_, err = stateConf.WaitForState()
+	if err != nil {
+		return fmt.Errorf(
+			"Error waiting for snapshot (%s) to delete: %s",
+			d.Id(), err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// SnapshotV2StateRefreshFunc returns a resource.StateRefreshFunc that is used to watch
+// a FlexibleEngine snapshot.
+func SnapshotV2StateRefreshFunc(client *golangsdk.ServiceClient, snapshotID string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		s, err := snapshots.Get(client, snapshotID).Extract()
+		if err != nil {
+			if _, ok := err.(golangsdk.ErrDefault404); ok {
+				return s, "deleted", nil
+			}
+			return nil, "", err
+		}
+
+		if s.Status == "error" || s.Status == "error_deleting" {
+			return s, s.Status, fmt.Errorf("There was an error creating or deleting the snapshot. " +
+				"Please check with your cloud admin or check the Block Storage " +
+				"API logs to see why this error occurred.")
+		}
+
+		return s, s.Status, nil
+	}
+}
@@ -0,0 +1,126 @@
+package flexibleengine
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	"github.com/chnsz/golangsdk/openstack/blockstorage/v2/snapshots"
+)
+
+func TestAccBlockStorageV2Snapshot_basic(t *testing.T) {
+	var snapshot snapshots.Snapshot
+	rName := fmt.Sprintf("tf-acc-test-%s", acctest.RandString(5))
+	resourceName := "flexibleengine_blockstorage_snapshot_v2.snapshot_1"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBlockStorageV2SnapshotDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBlockStorageV2Snapshot_basic(rName, "first test snapshot"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBlockStorageV2SnapshotExists(resourceName, &snapshot),
+					resource.TestCheckResourceAttr(resourceName, "name", rName),
+					resource.TestCheckResourceAttr(resourceName, "description", "first test snapshot"),
+					resource.TestCheckResourceAttr(resourceName, "status", "available"),
+					resource.TestCheckResourceAttr(resourceName, "size", "10"),
+					resource.TestCheckResourceAttrPair(resourceName, "volume_id",
+						"flexibleengine_blockstorage_volume_v2.volume_1", "id"),
+				),
+			},
+			{
+				Config: testAccBlockStorageV2Snapshot_basic(rName+"-update", "first test snapshot updated"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBlockStorageV2SnapshotExists(resourceName, &snapshot),
+					resource.TestCheckResourceAttr(resourceName, "name", rName+"-update"),
+					resource.TestCheckResourceAttr(resourceName, "description", "first test snapshot updated"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateVerifyIgnore: []string{
+					"force",
+				},
+			},
+		},
+	})
+}
+
+func testAccCheckBlockStorageV2SnapshotDestroy(s *terraform.State) error {
+	config := testAccProvider.Meta().(*Config)
+	blockStorageClient, err := config.BlockStorageV2Client(OS_REGION_NAME)
+	if err != nil {
+		return fmt.Errorf("Error creating FlexibleEngine block storage client: %s", err)
+	}
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "flexibleengine_blockstorage_snapshot_v2" {
+			continue
+		}
+
+		_, err := snapshots.Get(blockStorageClient, rs.Primary.ID).Extract()
+		if err == nil {
+			return fmt.Errorf("Snapshot still exists")
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckBlockStorageV2SnapshotExists(n string, snapshot *snapshots.Snapshot) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		config := testAccProvider.Meta().(*Config)
+		blockStorageClient, err := config.BlockStorageV2Client(OS_REGION_NAME)
+		if err != nil {
+			return fmt.Errorf("Error creating FlexibleEngine block storage client: %s", err)
+		}
+
+		found, err := snapshots.Get(blockStorageClient, rs.Primary.ID).Extract()
+		if err != nil {
+			return err
+		}
+
+		if found.ID != rs.Primary.ID {
+			return fmt.Errorf("Snapshot not found")
+		}
+
+		*snapshot = *found
+
+		return nil
+	}
+}
+
+func testAccBlockStorageV2Snapshot_basic(rName, description string) string {
+	return fmt.Sprintf(`
+resource "flexibleengine_blockstorage_volume_v2" "volume_1" {
+  name = "%s"
+  size = 10
+}
+
+resource "flexibleengine_blockstorage_snapshot_v2" "snapshot_1" {
+  volume_id   = flexibleengine_blockstorage_volume_v2.volume_1.id
+  name        = "%s"
+  description = "%s"
+
+  metadata = {
+    foo = "bar"
+  }
+}
+`, rName, rName, description)
+}
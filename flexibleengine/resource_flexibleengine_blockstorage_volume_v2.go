@@ -2,6 +2,7 @@ package flexibleengine
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"log"
 	"time"
@@ -11,10 +12,14 @@ import (
 	"github.com/chnsz/golangsdk/openstack/blockstorage/v2/volumes"
 	"github.com/chnsz/golangsdk/openstack/common/tags"
 	"github.com/chnsz/golangsdk/openstack/compute/v2/extensions/volumeattach"
+	"github.com/chnsz/golangsdk/openstack/evs/v2/cloudvolumes"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
+// volumeMetadataOrderID is the metadata key of the order of the yearly/monthly volume
+const volumeMetadataOrderID = "orderID"
+
 func resourceBlockStorageVolumeV2() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceBlockStorageVolumeV2Create,
@@ -27,9 +32,12 @@ func resourceBlockStorageVolumeV2() *schema.Resource {
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
 			Delete: schema.DefaultTimeout(10 * time.Minute),
 		},
 
+		CustomizeDiff: resourceBlockStorageVolumeV2CustomizeDiff,
+
 		Schema: map[string]*schema.Schema{
 			"region": {
 				Type:     schema.TypeString,
@@ -61,7 +69,13 @@ func resourceBlockStorageVolumeV2() *schema.Resource {
 				Optional: true,
 				Computed: true,
 			},
-			"tags": tagsSchema(),
+			"tags":                  tagsSchema(),
+			"tags_all":              tagsAllSchema(),
+			"enterprise_project_id": enterpriseProjectSchema(),
+			"charging_mode":         chargingModeSchema(),
+			"period_unit":           periodUnitSchema(),
+			"period":                periodSchema(),
+			"auto_renew":            autoRenewSchema(),
 			"snapshot_id": {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -80,7 +94,6 @@ func resourceBlockStorageVolumeV2() *schema.Resource {
 			"volume_type": {
 				Type:     schema.TypeString,
 				Optional: true,
-				ForceNew: true,
 				Computed: true,
 			},
 			"consistency_group_id": {
@@ -124,6 +137,11 @@ func resourceBlockStorageVolumeV2() *schema.Resource {
 				Optional: true,
 				Default:  false,
 			},
+			"replace_on_shrink": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
 		},
 	}
 }
@@ -143,64 +161,133 @@ func resourceBlockStorageVolumeV2Create(d *schema.ResourceData, meta interface{}
 		return fmt.Errorf("Error creating FlexibleEngine block storage client: %s", err)
 	}
 
-	createOpts := &volumes.CreateOpts{
-		AvailabilityZone:   d.Get("availability_zone").(string),
-		ConsistencyGroupID: d.Get("consistency_group_id").(string),
-		Description:        d.Get("description").(string),
-		ImageID:            d.Get("image_id").(string),
-		Metadata:           resourceContainerMetadataV2(d),
-		Name:               d.Get("name").(string),
-		Size:               d.Get("size").(int),
-		SnapshotID:         d.Get("snapshot_id").(string),
-		SourceReplica:      d.Get("source_replica").(string),
-		SourceVolID:        d.Get("source_vol_id").(string),
-		VolumeType:         d.Get("volume_type").(string),
-		Multiattach:        d.Get("multiattach").(bool),
-	}
+	var volumeID string
+	if isPrePaid(d) {
+		volumeID, err = createPrePaidBlockStorageVolume(d, config, blockStorageClient)
+		if err != nil {
+			return err
+		}
+	} else {
+		createOpts := &volumes.CreateOpts{
+			AvailabilityZone:   d.Get("availability_zone").(string),
+			ConsistencyGroupID: d.Get("consistency_group_id").(string),
+			Description:        d.Get("description").(string),
+			ImageID:            d.Get("image_id").(string),
+			Metadata:           resourceContainerMetadataV2(d),
+			Name:               d.Get("name").(string),
+			Size:               d.Get("size").(int),
+			SnapshotID:         d.Get("snapshot_id").(string),
+			SourceReplica:      d.Get("source_replica").(string),
+			SourceVolID:        d.Get("source_vol_id").(string),
+			VolumeType:         d.Get("volume_type").(string),
+			Multiattach:        d.Get("multiattach").(bool),
+		}
 
-	log.Printf("[DEBUG] Create Options: %#v", createOpts)
-	v, err := volumes.Create(blockStorageClient, createOpts).Extract()
-	if err != nil {
-		return fmt.Errorf("Error creating FlexibleEngine volume: %s", err)
+		log.Printf("[DEBUG] Create Options: %#v", createOpts)
+		v, err := volumes.Create(blockStorageClient, createOpts).Extract()
+		if err != nil {
+			return fmt.Errorf("Error creating FlexibleEngine volume: %s", err)
+		}
+		volumeID = v.ID
 	}
-	log.Printf("[INFO] Volume ID: %s", v.ID)
+	log.Printf("[INFO] Volume ID: %s", volumeID)
 
 	// Wait for the volume to become available.
 	log.Printf(
 		"[DEBUG] Waiting for volume (%s) to become available",
-		v.ID)
+		volumeID)
 
 	stateConf := &resource.StateChangeConf{
 		Pending:    []string{"downloading", "creating"},
 		Target:     []string{"available"},
-		Refresh:    VolumeV2StateRefreshFunc(blockStorageClient, v.ID),
+		Refresh:    VolumeV2StateRefreshFunc(blockStorageClient, volumeID),
 		Timeout:    d.Timeout(schema.TimeoutCreate),
-		Delay:      10 * time.Second,
-		MinTimeout: 3 * time.Second,
+		Delay:      stateRefreshDelay(10 * time.Second),
+		MinTimeout: stateRefreshInterval(3 * time.Second),
 	}
 
 	_, err = stateConf.WaitForState()
 	if err != nil {
 		return fmt.Errorf(
 			"Error waiting for volume (%s) to become ready: %s",
-			v.ID, err)
+			volumeID, err)
 	}
 
 	// Store the ID now
-	d.SetId(v.ID)
+	d.SetId(volumeID)
 
 	//set tags
-	tagRaw := d.Get("tags").(map[string]interface{})
+	tagRaw := getResourceTags(d, meta)
 	if len(tagRaw) > 0 {
 		taglist := expandResourceTags(tagRaw)
-		if tagErr := tags.Create(blockStorageClient, "os-vendor-volumes", v.ID, taglist).ExtractErr(); tagErr != nil {
-			return fmt.Errorf("Error setting tags of volume %s: %s", v.ID, tagErr)
+		if tagErr := tags.Create(blockStorageClient, "os-vendor-volumes", volumeID, taglist).ExtractErr(); tagErr != nil {
+			return fmt.Errorf("Error setting tags of volume %s: %s", volumeID, tagErr)
+		}
+	}
+
+	// the pay-per-use volume is created in the default enterprise project by the OpenStack API
+	epsID := config.GetEnterpriseProjectID(d)
+	if !isPrePaid(d) && epsID != "" && epsID != defaultEnterpriseProjectID {
+		err = migrateEnterpriseProject(config, GetRegion(d, config), epsID, epsResourceTypeEVS, volumeID, false)
+		if err != nil {
+			return err
 		}
 	}
 
 	return resourceBlockStorageVolumeV2Read(d, meta)
 }
 
+// createPrePaidBlockStorageVolume places the order of the yearly/monthly volume with the EVS API,
+// and returns the ID of the volume once the order is completed. The order is paid automatically.
+func createPrePaidBlockStorageVolume(d *schema.ResourceData, config *Config,
+	client *golangsdk.ServiceClient) (string, error) {
+	for _, key := range []string{"consistency_group_id", "source_replica", "source_vol_id"} {
+		if _, ok := d.GetOk(key); ok {
+			return "", fmt.Errorf("%s is not supported in %s charging mode", key, chargingModePrePaid)
+		}
+	}
+	availabilityZone := d.Get("availability_zone").(string)
+	volumeType := d.Get("volume_type").(string)
+	if availabilityZone == "" || volumeType == "" {
+		return "", fmt.Errorf("both availability_zone and volume_type must be specified in %s charging mode",
+			chargingModePrePaid)
+	}
+
+	prePaid, err := expandPrePaidOpts(d)
+	if err != nil {
+		return "", err
+	}
+
+	createOpts := cloudvolumes.CreateOpts{
+		Volume: cloudvolumes.VolumeOpts{
+			AvailabilityZone:    availabilityZone,
+			VolumeType:          volumeType,
+			Name:                d.Get("name").(string),
+			Description:         d.Get("description").(string),
+			Size:                d.Get("size").(int),
+			SnapshotID:          d.Get("snapshot_id").(string),
+			ImageID:             d.Get("image_id").(string),
+			Multiattach:         d.Get("multiattach").(bool),
+			Metadata:            resourceContainerMetadataV2(d),
+			EnterpriseProjectID: config.GetEnterpriseProjectID(d),
+		},
+		ChargeInfo: &cloudvolumes.BssParam{
+			ChargingMode: chargingModePrePaid,
+			PeriodType:   prePaid.PeriodUnit,
+			PeriodNum:    prePaid.Period,
+			IsAutoRenew:  prePaid.AutoRenew,
+			IsAutoPay:    "true",
+		},
+	}
+
+	log.Printf("[DEBUG] Create Options: %#v", createOpts)
+	job, err := cloudvolumes.Create(client, createOpts).Extract()
+	if err != nil {
+		return "", fmt.Errorf("Error creating FlexibleEngine volume: %s", err)
+	}
+	return waitForOrderResource(d, config, job.OrderID)
+}
+
 func resourceBlockStorageVolumeV2Read(d *schema.ResourceData, meta interface{}) error {
 	config := meta.(*Config)
 	blockStorageClient, err := config.BlockStorageV2Client(GetRegion(d, config))
@@ -222,10 +309,12 @@ func resourceBlockStorageVolumeV2Read(d *schema.ResourceData, meta interface{})
 	d.Set("snapshot_id", v.SnapshotID)
 	d.Set("source_vol_id", v.SourceVolID)
 	d.Set("volume_type", v.VolumeType)
+	setChargingMode(d, v.Metadata[volumeMetadataOrderID] != "")
 	//flexibleengine will add metadata 'billing=1' additionally, so remove the metadata 'billing' from response
+	//and the order of the yearly/monthly volume is also added to the metadata
 	m := make(map[string]string)
 	for key, val := range v.Metadata {
-		if key == "billing" {
+		if key == "billing" || key == volumeMetadataOrderID {
 			continue
 		}
 		m[key] = val
@@ -245,10 +334,17 @@ func resourceBlockStorageVolumeV2Read(d *schema.ResourceData, meta interface{})
 	d.Set("attachment", attachments)
 	d.Set("multiattach", v.Multiattach)
 
+	// the enterprise project ID is only returned by the EVS API
+	if evsVolume, err := cloudvolumes.Get(blockStorageClient, d.Id()).Extract(); err == nil {
+		d.Set("enterprise_project_id", evsVolume.EnterpriseProjectID)
+	} else {
+		log.Printf("[WARN] fetching enterprise project ID of volume failed: %s", err)
+	}
+
 	// fetch tags
 	if resourceTags, err := tags.Get(blockStorageClient, "os-vendor-volumes", d.Id()).Extract(); err == nil {
 		tagmap := tagsToMap(resourceTags.Tags)
-		d.Set("tags", tagmap)
+		setResourceTags(d, meta, tagmap)
 	} else {
 		log.Printf("[WARN] fetching tags of volume failed: %s", err)
 	}
@@ -280,33 +376,36 @@ func resourceBlockStorageVolumeV2Update(d *schema.ResourceData, meta interface{}
 	}
 
 	if d.HasChange("size") {
-		extendOpts := volumeactions.ExtendSizeOpts{
-			NewSize: d.Get("size").(int),
-		}
-
-		err = volumeactions.ExtendSize(blockStorageClient, d.Id(), extendOpts).ExtractErr()
-		if err != nil {
+		if err := extendBlockStorageVolume(d, config, blockStorageClient); err != nil {
 			return fmt.Errorf("Error extending flexibleengine_blockstorage_volume_v2 %s size: %s", d.Id(), err)
 		}
-
-		stateConf := &resource.StateChangeConf{
-			Pending:    []string{"extending"},
-			Target:     []string{"available", "in-use"},
-			Refresh:    VolumeV2StateRefreshFunc(blockStorageClient, d.Id()),
-			Timeout:    d.Timeout(schema.TimeoutCreate),
-			Delay:      10 * time.Second,
-			MinTimeout: 3 * time.Second,
+		if err := waitForBlockStorageVolumeUpdated(d, blockStorageClient, "extending"); err != nil {
+			return err
 		}
+	}
 
-		_, err := stateConf.WaitForState()
-		if err != nil {
-			return fmt.Errorf(
-				"Error waiting for flexibleengine_blockstorage_volume_v2 %s to become ready: %s", d.Id(), err)
+	if d.HasChange("volume_type") {
+		if err := retypeBlockStorageVolume(d, config); err != nil {
+			return fmt.Errorf("Error changing flexibleengine_blockstorage_volume_v2 %s type: %s", d.Id(), err)
 		}
+		if err := waitForBlockStorageVolumeUpdated(d, blockStorageClient, "retyping"); err != nil {
+			return err
+		}
+	}
+
+	err = updateChargingMode(d, config, d.Id(), func(opts *prePaidOpts) (string, error) {
+		return changeBlockStorageVolumeToPeriod(blockStorageClient, d.Id(), opts)
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := updateEnterpriseProject(d, config, epsResourceTypeEVS, d.Id(), false); err != nil {
+		return err
 	}
 
 	// update tags
-	if d.HasChange("tags") {
+	if d.HasChange("tags_all") {
 		tagErr := UpdateResourceTags(blockStorageClient, d, "os-vendor-volumes", d.Id())
 		if tagErr != nil {
 			return fmt.Errorf("Error updating tags of volume:%s, err:%s", d.Id(), tagErr)
@@ -346,8 +445,8 @@ func resourceBlockStorageVolumeV2Delete(d *schema.ResourceData, meta interface{}
 				Target:     []string{"available"},
 				Refresh:    VolumeV2StateRefreshFunc(blockStorageClient, d.Id()),
 				Timeout:    d.Timeout(schema.TimeoutDelete),
-				Delay:      10 * time.Second,
-				MinTimeout: 3 * time.Second,
+				Delay:      stateRefreshDelay(10 * time.Second),
+				MinTimeout: stateRefreshInterval(3 * time.Second),
 			}
 
 			_, err = stateConf.WaitForState()
@@ -366,7 +465,15 @@ func resourceBlockStorageVolumeV2Delete(d *schema.ResourceData, meta interface{}
 	// It's possible that this volume was used as a boot device and is currently
 	// in a "deleting" state from when the instance was terminated.
 	// If this is true, just move on. It'll eventually delete.
-	if v.Status != "deleting" {
+	if v.Status == "deleting" {
+		log.Printf("[DEBUG] the volume %s is being deleted", d.Id())
+	} else if v.Metadata[volumeMetadataOrderID] != "" {
+		// the yearly/monthly volume can only be deleted by unsubscribing, even if
+		// it is waiting to be switched to pay-per-use billing
+		if err := unsubscribePrePaidResource(d, config, []string{d.Id()}); err != nil {
+			return err
+		}
+	} else {
 		if err := volumes.Delete(blockStorageClient, d.Id(), deleteOpts).ExtractErr(); err != nil {
 			return CheckDeleted(d, err, "volume")
 		}
@@ -380,8 +487,8 @@ func resourceBlockStorageVolumeV2Delete(d *schema.ResourceData, meta interface{}
 		Target:     []string{"deleted"},
 		Refresh:    VolumeV2StateRefreshFunc(blockStorageClient, d.Id()),
 		Timeout:    d.Timeout(schema.TimeoutDelete),
-		Delay:      10 * time.Second,
-		MinTimeout: 3 * time.Second,
+		Delay:      stateRefreshDelay(10 * time.Second),
+		MinTimeout: stateRefreshInterval(3 * time.Second),
 	}
 
 	_, err = stateConf.WaitForState()
@@ -395,6 +502,184 @@ func resourceBlockStorageVolumeV2Delete(d *schema.ResourceData, meta interface{}
 	return nil
 }
 
+// resourceBlockStorageVolumeV2CustomizeDiff rejects shrinking the volume as EVS can only extend volumes,
+// a new volume is planned instead only when replace_on_shrink is set.
+func resourceBlockStorageVolumeV2CustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if err := validatePeriodDiff(d); err != nil {
+		return err
+	}
+	if d.Id() != "" && d.HasChange("size") {
+		oldSize, newSize := d.GetChange("size")
+		if newSize.(int) < oldSize.(int) {
+			if !d.Get("replace_on_shrink").(bool) {
+				return fmt.Errorf("size can not be shrunk from %d to %d GB, set replace_on_shrink to "+
+					"replace the volume with a new one", oldSize.(int), newSize.(int))
+			}
+			if err := d.ForceNew("size"); err != nil {
+				return err
+			}
+		}
+	}
+
+	return setTagsAllAndEnterpriseProjectDiff(ctx, d, meta)
+}
+
+// isVolumeUpdatedByOrder returns whether the changes of the volume are billed by an order,
+// which is true for the yearly/monthly volumes.
+func isVolumeUpdatedByOrder(d *schema.ResourceData) bool {
+	oldMode, _ := d.GetChange("charging_mode")
+	return oldMode.(string) == chargingModePrePaid
+}
+
+// extendBlockStorageVolume extends the volume. The volumes attached to instances are extended
+// online by the cloudvolumes API, and the extension of the yearly/monthly volume is paid automatically.
+func extendBlockStorageVolume(d *schema.ResourceData, config *Config, client *golangsdk.ServiceClient) error {
+	prePaid := isVolumeUpdatedByOrder(d)
+	if !prePaid && d.Get("attachment").(*schema.Set).Len() == 0 {
+		extendOpts := volumeactions.ExtendSizeOpts{
+			NewSize: d.Get("size").(int),
+		}
+		return volumeactions.ExtendSize(client, d.Id(), extendOpts).ExtractErr()
+	}
+
+	extendOpts := cloudvolumes.ExtendOpts{
+		SizeOpts: cloudvolumes.ExtendSizeOpts{
+			NewSize: d.Get("size").(int),
+		},
+	}
+	if prePaid {
+		extendOpts.ChargeInfo = &cloudvolumes.ExtendChargeOpts{
+			IsAutoPay: "true",
+		}
+	}
+	job, err := cloudvolumes.ExtendSize(client, d.Id(), extendOpts).Extract()
+	if err != nil {
+		return err
+	}
+	return waitForVolumeOrder(d, config, job.OrderID)
+}
+
+type retypeVolumeOpts struct {
+	RetypeOpts retypeVolumeTypeOpts           `json:"os-retype" required:"true"`
+	ChargeInfo *cloudvolumes.ExtendChargeOpts `json:"bssParam,omitempty"`
+}
+
+type retypeVolumeTypeOpts struct {
+	NewType string `json:"new_type" required:"true"`
+}
+
+// retypeBlockStorageVolume changes the type of the volume in place by the EVS v2.1 API,
+// the volume can be attached to a running instance.
+func retypeBlockStorageVolume(d *schema.ResourceData, config *Config) error {
+	opts := retypeVolumeOpts{
+		RetypeOpts: retypeVolumeTypeOpts{
+			NewType: d.Get("volume_type").(string),
+		},
+	}
+	if isVolumeUpdatedByOrder(d) {
+		opts.ChargeInfo = &cloudvolumes.ExtendChargeOpts{
+			IsAutoPay: "true",
+		}
+	}
+	reqBody, err := golangsdk.BuildRequestBody(opts, "")
+	if err != nil {
+		return err
+	}
+
+	// the version of retype API is v2.1
+	client, err := config.BlockStorageV21Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating FlexibleEngine block storage v2.1 client: %s", err)
+	}
+
+	var job cloudvolumes.JobResponse
+	_, err = client.Post(client.ServiceURL("cloudvolumes", d.Id(), "retype"), reqBody, &job, &golangsdk.RequestOpts{
+		OkCodes: []int{200, 202},
+	})
+	if err != nil {
+		return err
+	}
+	return waitForVolumeOrder(d, config, job.OrderID)
+}
+
+// waitForVolumeOrder waits for the order of the yearly/monthly volume changes to complete
+func waitForVolumeOrder(d *schema.ResourceData, config *Config, orderID string) error {
+	if orderID == "" {
+		return nil
+	}
+
+	bssClient, err := config.BssV2Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating FlexibleEngine BSS client: %s", err)
+	}
+	return waitForOrderComplete(bssClient, orderID, d.Timeout(schema.TimeoutUpdate))
+}
+
+// waitForBlockStorageVolumeUpdated waits for the volume to leave the pending status of the change
+func waitForBlockStorageVolumeUpdated(d *schema.ResourceData, client *golangsdk.ServiceClient, pending string) error {
+	stateConf := &resource.StateChangeConf{
+		Pending:    []string{pending},
+		Target:     []string{"available", "in-use"},
+		Refresh:    VolumeV2StateRefreshFunc(client, d.Id()),
+		Timeout:    d.Timeout(schema.TimeoutUpdate),
+		Delay:      stateRefreshDelay(10 * time.Second),
+		MinTimeout: stateRefreshInterval(3 * time.Second),
+	}
+
+	_, err := stateConf.WaitForState()
+	if err != nil {
+		return fmt.Errorf(
+			"Error waiting for flexibleengine_blockstorage_volume_v2 %s to become ready: %s", d.Id(), err)
+	}
+	return nil
+}
+
+type changeVolumeChargeModeOpts struct {
+	VolumeIDs []string               `json:"volume_ids" required:"true"`
+	BssParam  volumeChargeModeParams `json:"bss_param" required:"true"`
+}
+
+type volumeChargeModeParams struct {
+	// the period type is 2 for month and 3 for year
+	PeriodType  int  `json:"period_type" required:"true"`
+	PeriodNum   int  `json:"period_num" required:"true"`
+	IsAutoRenew bool `json:"is_auto_renew"`
+	IsAutoPay   bool `json:"is_auto_pay"`
+}
+
+// changeBlockStorageVolumeToPeriod places the order to change the pay-per-use volume to
+// yearly/monthly billing, and returns the ID of the order.
+func changeBlockStorageVolumeToPeriod(client *golangsdk.ServiceClient, volumeID string, opts *prePaidOpts) (string, error) {
+	periodType := 2
+	if opts.PeriodUnit == "year" {
+		periodType = 3
+	}
+	changeOpts := changeVolumeChargeModeOpts{
+		VolumeIDs: []string{volumeID},
+		BssParam: volumeChargeModeParams{
+			PeriodType:  periodType,
+			PeriodNum:   opts.Period,
+			IsAutoRenew: opts.AutoRenew == "true",
+			IsAutoPay:   true,
+		},
+	}
+	reqBody, err := golangsdk.BuildRequestBody(changeOpts, "")
+	if err != nil {
+		return "", err
+	}
+
+	var rst struct {
+		OrderID string `json:"order_id"`
+	}
+	log.Printf("[DEBUG] Changing the charging mode of volume %s: %#v", volumeID, changeOpts)
+	_, err = client.Post(client.ServiceURL("cloudvolumes", "change-charge-mode"), reqBody, &rst,
+		&golangsdk.RequestOpts{OkCodes: []int{200}})
+	if err != nil {
+		return "", err
+	}
+	return rst.OrderID, nil
+}
+
 func resourceVolumeMetadataV2(d *schema.ResourceData) map[string]string {
 	m := make(map[string]string)
 	for key, val := range d.Get("metadata").(map[string]interface{}) {
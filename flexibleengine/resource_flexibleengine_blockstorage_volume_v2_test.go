@@ -2,6 +2,7 @@ package flexibleengine
 
 import (
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -78,6 +79,45 @@ func TestAccBlockStorageV2Volume_online_resize(t *testing.T) {
 	})
 }
 
+func TestAccBlockStorageV2Volume_retype(t *testing.T) {
+	var volume volumes.Volume
+	resourceName := "flexibleengine_blockstorage_volume_v2.volume_1"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBlockStorageV2VolumeDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBlockStorageV2Volume_retype("SATA", 10, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBlockStorageV2VolumeExists(resourceName, &volume),
+					resource.TestCheckResourceAttr(resourceName, "volume_type", "SATA"),
+				),
+			},
+			{
+				Config: testAccBlockStorageV2Volume_retype("SAS", 20, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBlockStorageV2VolumeExists(resourceName, &volume),
+					resource.TestCheckResourceAttr(resourceName, "volume_type", "SAS"),
+					resource.TestCheckResourceAttr(resourceName, "size", "20"),
+				),
+			},
+			{
+				Config:      testAccBlockStorageV2Volume_retype("SAS", 10, false),
+				ExpectError: regexp.MustCompile("size can not be shrunk"),
+			},
+			{
+				Config: testAccBlockStorageV2Volume_retype("SAS", 10, true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBlockStorageV2VolumeExists(resourceName, &volume),
+					resource.TestCheckResourceAttr(resourceName, "size", "10"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccBlockStorageV2Volume_image(t *testing.T) {
 	var volume volumes.Volume
 
@@ -98,6 +138,34 @@ func TestAccBlockStorageV2Volume_image(t *testing.T) {
 	})
 }
 
+func TestAccBlockStorageV2Volume_prePaid(t *testing.T) {
+	var volume volumes.Volume
+	resourceName := "flexibleengine_blockstorage_volume_v2.volume_1"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheckChargingMode(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBlockStorageV2VolumeDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBlockStorageV2Volume_prePaid(10),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBlockStorageV2VolumeExists(resourceName, &volume),
+					resource.TestCheckResourceAttr(resourceName, "charging_mode", "prePaid"),
+					resource.TestCheckResourceAttr(resourceName, "size", "10"),
+				),
+			},
+			{
+				Config: testAccBlockStorageV2Volume_prePaid(20),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBlockStorageV2VolumeExists(resourceName, &volume),
+					resource.TestCheckResourceAttr(resourceName, "size", "20"),
+				),
+			},
+		},
+	})
+}
+
 func testAccCheckBlockStorageV2VolumeDestroy(s *terraform.State) error {
 	config := testAccProvider.Meta().(*Config)
 	blockStorageClient, err := config.BlockStorageV2Client(OS_REGION_NAME)
@@ -246,3 +314,29 @@ resource "flexibleengine_blockstorage_volume_v2" "volume_1" {
   image_id = "%s"
 }
 `, OS_IMAGE_ID)
+
+func testAccBlockStorageV2Volume_prePaid(size int) string {
+	return fmt.Sprintf(`
+resource "flexibleengine_blockstorage_volume_v2" "volume_1" {
+  name              = "volume_1"
+  size              = %d
+  volume_type       = "SATA"
+  availability_zone = "%s"
+  charging_mode     = "prePaid"
+  period_unit       = "month"
+  period            = 1
+}
+`, size, OS_AVAILABILITY_ZONE)
+}
+
+func testAccBlockStorageV2Volume_retype(volumeType string, size int, replaceOnShrink bool) string {
+	return fmt.Sprintf(`
+resource "flexibleengine_blockstorage_volume_v2" "volume_1" {
+  name              = "volume_1"
+  size              = %d
+  volume_type       = "%s"
+  availability_zone = "%s"
+  replace_on_shrink = %t
+}
+`, size, volumeType, OS_AVAILABILITY_ZONE, replaceOnShrink)
+}
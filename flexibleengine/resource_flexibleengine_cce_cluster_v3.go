@@ -1,16 +1,21 @@
 package flexibleengine
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/chnsz/golangsdk"
 	"github.com/chnsz/golangsdk/openstack/cce/v3/clusters"
+	"github.com/chnsz/golangsdk/openstack/cce/v3/nodepools"
 	"github.com/chnsz/golangsdk/openstack/networking/v1/eips"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"gopkg.in/yaml.v2"
 )
 
 func resourceCCEClusterV3() *schema.Resource {
@@ -25,9 +30,12 @@ func resourceCCEClusterV3() *schema.Resource {
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(60 * time.Minute),
 			Delete: schema.DefaultTimeout(30 * time.Minute),
 		},
 
+		CustomizeDiff: resourceCCEClusterV3CustomizeDiff,
+
 		Schema: map[string]*schema.Schema{
 			"region": {
 				Type:     schema.TypeString,
@@ -59,7 +67,26 @@ func resourceCCEClusterV3() *schema.Resource {
 				Type:     schema.TypeString,
 				Optional: true,
 				Computed: true,
-				ForceNew: true,
+			},
+			"upgrade_policy": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"upgrade_node_pools": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  false,
+						},
+						"node_batch_size": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      20,
+							ValidateFunc: validation.IntBetween(1, 40),
+						},
+					},
+				},
 			},
 			"cluster_type": {
 				Type:     schema.TypeString,
@@ -153,6 +180,26 @@ func resourceCCEClusterV3() *schema.Resource {
 				Optional: true,
 				ForceNew: true,
 			},
+			"hibernate": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"kube_config_duration": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  -1,
+				ValidateFunc: validation.Any(
+					validation.IntInSlice([]int{-1}),
+					validation.IntBetween(1, 1827),
+				),
+			},
+			"kube_config_context": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "internal",
+				ValidateFunc: validation.StringInSlice([]string{"internal", "external"}, false),
+			},
+			"enterprise_project_id": enterpriseProjectSchema(),
 			"status": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -173,6 +220,15 @@ func resourceCCEClusterV3() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"upgrade_status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"kube_config_raw": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
 			"certificate_clusters": {
 				Type:     schema.TypeList,
 				Computed: true,
@@ -231,7 +287,7 @@ func resourceClusterAnnotationsV3(d *schema.ResourceData) map[string]string {
 	}
 	return m
 }
-func resourceClusterExtendParamV3(d *schema.ResourceData) map[string]interface{} {
+func resourceClusterExtendParamV3(d *schema.ResourceData, config *Config) map[string]interface{} {
 	m := make(map[string]interface{})
 	for key, val := range d.Get("extend_param").(map[string]interface{}) {
 		m[key] = val.(string)
@@ -242,6 +298,9 @@ func resourceClusterExtendParamV3(d *schema.ResourceData) map[string]interface{}
 	if eip, ok := d.GetOk("eip"); ok {
 		m["clusterExternalIP"] = eip.(string)
 	}
+	if epsID := config.GetEnterpriseProjectID(d); epsID != "" {
+		m["enterpriseProjectId"] = epsID
+	}
 	return m
 }
 
@@ -302,7 +361,7 @@ func resourceCCEClusterV3Create(d *schema.ResourceData, meta interface{}) error
 			AuthenticatingProxy: authenticatingProxy,
 		},
 		BillingMode: d.Get("billing_mode").(int),
-		ExtendParam: resourceClusterExtendParamV3(d),
+		ExtendParam: resourceClusterExtendParamV3(d, config),
 	}
 
 	masters, err := resourceClusterMastersV3(d)
@@ -333,13 +392,23 @@ func resourceCCEClusterV3Create(d *schema.ResourceData, meta interface{}) error
 		Target:       []string{"Available"},
 		Refresh:      waitForCCEClusterActive(cceClient, create.Metadata.Id),
 		Timeout:      d.Timeout(schema.TimeoutCreate),
-		Delay:        120 * time.Second,
-		PollInterval: 10 * time.Second,
+		Delay:        stateRefreshDelay(120 * time.Second),
+		PollInterval: stateRefreshInterval(10 * time.Second),
 	}
 
 	_, err = stateConf.WaitForState()
 	d.SetId(create.Metadata.Id)
 
+	if err := setCCEClusterKubeConfig(d, cceClient); err != nil {
+		log.Printf("[WARN] Error issuing the kubeconfig of flexibleengine CCE cluster %s: %s", d.Id(), err)
+	}
+
+	if d.Get("hibernate").(bool) {
+		if err := resourceCCEClusterV3Hibernate(cceClient, d.Id(), d.Timeout(schema.TimeoutCreate)); err != nil {
+			return err
+		}
+	}
+
 	return resourceCCEClusterV3Read(d, meta)
 
 }
@@ -366,6 +435,7 @@ func resourceCCEClusterV3Read(d *schema.ResourceData, meta interface{}) error {
 	d.Set("region", region)
 	d.Set("name", n.Metadata.Name)
 	d.Set("status", n.Status.Phase)
+	d.Set("hibernate", n.Status.Phase == "Hibernating" || n.Status.Phase == "Hibernation")
 	d.Set("flavor_id", n.Spec.Flavor)
 	d.Set("cluster_type", n.Spec.Type)
 	d.Set("cluster_version", n.Spec.Version)
@@ -379,33 +449,57 @@ func resourceCCEClusterV3Read(d *schema.ResourceData, meta interface{}) error {
 	d.Set("service_network_cidr", n.Spec.KubernetesSvcIPRange)
 	d.Set("authentication_mode", n.Spec.Authentication.Mode)
 	d.Set("security_group_id", n.Spec.HostNetwork.SecurityGroup)
+	if epsID, ok := n.Spec.ExtendParam["enterpriseProjectId"]; ok {
+		d.Set("enterprise_project_id", epsID)
+	}
+
+	// the arguments of the kubeconfig are not set when the cluster is being imported
+	if d.Get("kube_config_duration").(int) == 0 {
+		d.Set("kube_config_duration", -1)
+	}
+	contextName := d.Get("kube_config_context").(string)
+	if contextName == "" {
+		contextName = "internal"
+		d.Set("kube_config_context", contextName)
+	}
 
-	cert, err := clusters.GetCert(cceClient, d.Id()).Extract()
+	certResult := clusters.GetCert(cceClient, d.Id())
+	cert, err := certResult.Extract()
 	if err != nil {
 		log.Printf("Error retrieving flexibleengine CCE cluster cert: %s", err)
-	}
+	} else {
+		// the kubeconfig is issued on creation and kept in the state, it is only
+		// rendered from the current certificate when the cluster is imported
+		if d.Get("kube_config_raw").(string) == "" {
+			if kubeConfig, err := flattenCCEClusterKubeConfig(certResult.Result, d.Id(), contextName); err == nil {
+				d.Set("kube_config_raw", kubeConfig)
+			} else {
+				log.Printf("[WARN] Error rendering the kubeconfig of flexibleengine CCE cluster %s: %s", d.Id(), err)
+			}
+		}
 
-	//Set Certificate Clusters
-	var clusterList []map[string]interface{}
-	for _, clusterObj := range cert.Clusters {
-		clusterCert := make(map[string]interface{})
-		clusterCert["name"] = clusterObj.Name
-		clusterCert["server"] = clusterObj.Cluster.Server
-		clusterCert["certificate_authority_data"] = clusterObj.Cluster.CertAuthorityData
-		clusterList = append(clusterList, clusterCert)
-	}
-	d.Set("certificate_clusters", clusterList)
+		//Set Certificate Clusters
+		var clusterList []map[string]interface{}
+		for _, clusterObj := range cert.Clusters {
+			clusterCert := make(map[string]interface{})
+			clusterCert["name"] = clusterObj.Name
+			clusterCert["server"] = clusterObj.Cluster.Server
+			clusterCert["certificate_authority_data"] = clusterObj.Cluster.CertAuthorityData
+			clusterList = append(clusterList, clusterCert)
+		}
+		d.Set("certificate_clusters", clusterList)
 
-	// Set Certificate Users
-	var userList []map[string]interface{}
-	for _, userObj := range cert.Users {
-		userCert := make(map[string]interface{})
-		userCert["name"] = userObj.Name
-		userCert["client_certificate_data"] = userObj.User.ClientCertData
-		userCert["client_key_data"] = userObj.User.ClientKeyData
-		userList = append(userList, userCert)
+		// Set Certificate Users
+		var userList []map[string]interface{}
+		for _, userObj := range cert.Users {
+			userCert := make(map[string]interface{})
+			userCert["name"] = userObj.Name
+			userCert["client_certificate_data"] = userObj.User.ClientCertData
+			userCert["client_key_data"] = userObj.User.ClientKeyData
+			userList = append(userList, userCert)
+		}
+		d.Set("certificate_users", userList)
 	}
-	d.Set("certificate_users", userList)
 
 	// Set masters
 	var masterList []map[string]interface{}
@@ -430,6 +524,13 @@ func resourceCCEClusterV3Read(d *schema.ResourceData, meta interface{}) error {
 	// the value is always empty, keep compatibility
 	d.Set("external_apig_endpoint", n.Status.Endpoints[0].ExternalOTC)
 
+	// Set the status of the latest upgrade task
+	if status, err := getCCEClusterUpgradeStatus(cceClient, d.Id()); err == nil {
+		d.Set("upgrade_status", status)
+	} else {
+		log.Printf("[WARN] Error retrieving the upgrade tasks of flexibleengine CCE cluster %s: %s", d.Id(), err)
+	}
+
 	return nil
 }
 
@@ -440,6 +541,19 @@ func resourceCCEClusterV3Update(d *schema.ResourceData, meta interface{}) error
 		return fmt.Errorf("Error creating flexibleengine CCE Client: %s", err)
 	}
 
+	// the cluster must be awake before the other changes are applied
+	if d.HasChange("hibernate") && !d.Get("hibernate").(bool) {
+		if err := resourceCCEClusterV3Awake(cceClient, d.Id(), d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange("cluster_version") {
+		if err := upgradeCCEClusterV3(d, cceClient); err != nil {
+			return err
+		}
+	}
+
 	if d.HasChange("description") {
 		var updateOpts clusters.UpdateOpts
 		updateOpts.Spec.Description = d.Get("description").(string)
@@ -471,9 +585,376 @@ func resourceCCEClusterV3Update(d *schema.ResourceData, meta interface{}) error
 		}
 	}
 
+	if err := updateEnterpriseProject(d, config, epsResourceTypeCCE, d.Id(), false); err != nil {
+		return err
+	}
+
+	// the external context of the kubeconfig depends on the EIP bound to the cluster
+	if d.HasChanges("kube_config_duration", "kube_config_context", "eip") {
+		if err := setCCEClusterKubeConfig(d, cceClient); err != nil {
+			return fmt.Errorf("Error issuing the kubeconfig of flexibleengine CCE cluster %s: %s", d.Id(), err)
+		}
+	}
+
+	if d.HasChange("hibernate") && d.Get("hibernate").(bool) {
+		if err := resourceCCEClusterV3Hibernate(cceClient, d.Id(), d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return err
+		}
+	}
+
 	return resourceCCEClusterV3Read(d, meta)
 }
 
+func resourceCCEClusterV3CustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+
+	if err := setEnterpriseProjectDiff(ctx, d, meta); err != nil {
+		return err
+	}
+
+	// a new certificate is issued when the arguments of the kubeconfig are changed
+	if d.HasChanges("kube_config_duration", "kube_config_context", "eip") {
+		if err := d.SetNewComputed("kube_config_raw"); err != nil {
+			return err
+		}
+	}
+
+	if !d.HasChange("cluster_version") {
+		return nil
+	}
+
+	oldVersion, newVersion := d.GetChange("cluster_version")
+	if oldVersion.(string) == "" || newVersion.(string) == "" {
+		return nil
+	}
+	if compareCCEClusterVersion(newVersion.(string), oldVersion.(string)) < 0 {
+		return fmt.Errorf("cluster_version can not be downgraded from %s to %s",
+			oldVersion.(string), newVersion.(string))
+	}
+	return nil
+}
+
+// parseCCEClusterVersion splits a cluster version like v1.23.5-r0 into the numbers
+// of major, minor, patch and revision, the parts which are not specified are omitted.
+func parseCCEClusterVersion(version string) []int {
+	version = strings.TrimPrefix(version, "v")
+	parts := strings.Split(version, "-")
+	fields := strings.Split(parts[0], ".")
+	if len(parts) > 1 {
+		fields = append(fields, strings.TrimPrefix(parts[1], "r"))
+	}
+
+	numbers := make([]int, 0, len(fields))
+	for _, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil {
+			break
+		}
+		numbers = append(numbers, n)
+	}
+	return numbers
+}
+
+// compareCCEClusterVersion returns a negative number if version a is lower than b, a positive
+// number if it is higher and 0 otherwise. Only the parts specified in both versions are compared,
+// so v1.23 equals to v1.23.5-r0.
+func compareCCEClusterVersion(a, b string) int {
+	numbersA := parseCCEClusterVersion(a)
+	numbersB := parseCCEClusterVersion(b)
+	for i := 0; i < len(numbersA) && i < len(numbersB); i++ {
+		if numbersA[i] != numbersB[i] {
+			return numbersA[i] - numbersB[i]
+		}
+	}
+	return 0
+}
+
+// cceClusterTask is the pre-upgrade check task or the upgrade task of a cluster
+type cceClusterTask struct {
+	Metadata struct {
+		ID                string `json:"uid"`
+		CreationTimestamp string `json:"creationTimestamp"`
+	} `json:"metadata"`
+	Status struct {
+		Phase   string `json:"phase"`
+		Message string `json:"message"`
+	} `json:"status"`
+}
+
+func createCCEClusterTask(client *golangsdk.ServiceClient, url string, opts map[string]interface{}) (string, error) {
+	var r golangsdk.Result
+	_, r.Err = client.Post(url, opts, &r.Body, &golangsdk.RequestOpts{
+		OkCodes:     []int{200, 201},
+		MoreHeaders: clusters.RequestOpts.MoreHeaders,
+	})
+	var task cceClusterTask
+	if r.Err == nil {
+		r.Err = r.ExtractInto(&task)
+	}
+	return task.Metadata.ID, r.Err
+}
+
+// waitForCCEClusterTask polls the task until it completes and returns the last phase of the task.
+// The upgrade task may be paused to be confirmed in the console, it is regarded as pending.
+func waitForCCEClusterTask(client *golangsdk.ServiceClient, url string, timeout time.Duration) (string, error) {
+	stateConf := &resource.StateChangeConf{
+		Pending: []string{"Init", "Queuing", "Running", "Pause"},
+		Target:  []string{"Success", "Failed"},
+		Refresh: func() (interface{}, string, error) {
+			var r golangsdk.Result
+			_, r.Err = client.Get(url, &r.Body, &golangsdk.RequestOpts{
+				OkCodes:     []int{200},
+				MoreHeaders: clusters.RequestOpts.MoreHeaders,
+			})
+			if r.Err != nil {
+				return nil, "", r.Err
+			}
+			var task cceClusterTask
+			if err := r.ExtractInto(&task); err != nil {
+				return nil, "", err
+			}
+			return task, task.Status.Phase, nil
+		},
+		Timeout:    timeout,
+		Delay:      stateRefreshDelay(10 * time.Second),
+		MinTimeout: stateRefreshInterval(3 * time.Second),
+	}
+
+	result, err := stateConf.WaitForState()
+	if err != nil {
+		return "", err
+	}
+	task := result.(cceClusterTask)
+	if task.Status.Phase == "Failed" {
+		return task.Status.Phase, fmt.Errorf("the task failed: %s", task.Status.Message)
+	}
+	return task.Status.Phase, nil
+}
+
+// getCCEClusterUpgradeStatus returns the phase of the latest upgrade task of the cluster,
+// it is empty if the cluster has never been upgraded.
+func getCCEClusterUpgradeStatus(client *golangsdk.ServiceClient, clusterID string) (string, error) {
+	var r golangsdk.Result
+	_, r.Err = client.Get(client.ServiceURL("clusters", clusterID, "operation", "upgrade", "tasks"), &r.Body,
+		&golangsdk.RequestOpts{
+			OkCodes:     []int{200},
+			MoreHeaders: clusters.RequestOpts.MoreHeaders,
+		})
+	if r.Err != nil {
+		return "", r.Err
+	}
+
+	var tasks []cceClusterTask
+	if err := r.ExtractIntoSlicePtr(&tasks, "items"); err != nil {
+		return "", err
+	}
+
+	var latest *cceClusterTask
+	for i := range tasks {
+		if latest == nil || tasks[i].Metadata.CreationTimestamp >= latest.Metadata.CreationTimestamp {
+			latest = &tasks[i]
+		}
+	}
+	if latest == nil {
+		return "", nil
+	}
+	return latest.Status.Phase, nil
+}
+
+// upgradeCCEClusterV3 upgrades the cluster in place: the pre-upgrade check is executed first,
+// then the master is upgraded, and the nodes of the node pools are rolled in batches
+// if upgrade_node_pools is enabled.
+func upgradeCCEClusterV3(d *schema.ResourceData, client *golangsdk.ServiceClient) error {
+	clusterID := d.Id()
+	oldVersion, newVersion := d.GetChange("cluster_version")
+	targetVersion := newVersion.(string)
+	timeout := d.Timeout(schema.TimeoutUpdate)
+
+	precheckOpts := map[string]interface{}{
+		"kind":       "PreCheckTask",
+		"apiVersion": "v3",
+		"spec": map[string]interface{}{
+			"clusterId":      clusterID,
+			"clusterVersion": oldVersion.(string),
+			"targetVersion":  targetVersion,
+		},
+	}
+	log.Printf("[DEBUG] Pre-upgrade check options of CCE cluster %s: %#v", clusterID, precheckOpts)
+	precheckURL := client.ServiceURL("clusters", clusterID, "operation", "precheck")
+	taskID, err := createCCEClusterTask(client, precheckURL, precheckOpts)
+	if err != nil {
+		return fmt.Errorf("Error checking flexibleengine CCE cluster %s before upgrading: %s", clusterID, err)
+	}
+	if _, err := waitForCCEClusterTask(client, precheckURL+"/tasks/"+taskID, timeout); err != nil {
+		return fmt.Errorf("Error checking flexibleengine CCE cluster %s before upgrading to %s: %s",
+			clusterID, targetVersion, err)
+	}
+
+	batchSize := 20
+	upgradeNodePools := false
+	if v, ok := d.GetOk("upgrade_policy"); ok {
+		policy := v.([]interface{})[0].(map[string]interface{})
+		batchSize = policy["node_batch_size"].(int)
+		upgradeNodePools = policy["upgrade_node_pools"].(bool)
+	}
+
+	action := map[string]interface{}{
+		"targetVersion": targetVersion,
+		"strategy": map[string]interface{}{
+			"type": "inPlaceRollingUpdate",
+			"inPlaceRollingUpdate": map[string]interface{}{
+				"userDefinedStep": batchSize,
+			},
+		},
+	}
+	// the node pools are upgraded after the master in the order of the priorities
+	if upgradeNodePools {
+		pools, err := nodepools.List(client, clusterID, nodepools.ListOpts{})
+		if err != nil {
+			return fmt.Errorf("Error listing the node pools of flexibleengine CCE cluster %s: %s", clusterID, err)
+		}
+		nodePoolOrder := make(map[string]int, len(pools))
+		for i, pool := range pools {
+			nodePoolOrder[pool.Metadata.Id] = len(pools) - i
+		}
+		action["nodePoolOrder"] = nodePoolOrder
+	}
+
+	upgradeOpts := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"kind":       "UpgradeTask",
+			"apiVersion": "v3",
+		},
+		"spec": map[string]interface{}{
+			"clusterUpgradeAction": action,
+		},
+	}
+	log.Printf("[DEBUG] Upgrade options of CCE cluster %s: %#v", clusterID, upgradeOpts)
+	upgradeURL := client.ServiceURL("clusters", clusterID, "operation", "upgrade")
+	taskID, err = createCCEClusterTask(client, upgradeURL, upgradeOpts)
+	if err != nil {
+		return fmt.Errorf("Error upgrading flexibleengine CCE cluster %s: %s", clusterID, err)
+	}
+
+	status, err := waitForCCEClusterTask(client, upgradeURL+"/tasks/"+taskID, timeout)
+	if status != "" {
+		d.Set("upgrade_status", status)
+	}
+	if err != nil {
+		// keep the current version in the state so that the upgrade can be retried
+		d.Set("cluster_version", oldVersion)
+		return fmt.Errorf("Error upgrading flexibleengine CCE cluster %s to %s: %s", clusterID, targetVersion, err)
+	}
+
+	stateConf := &resource.StateChangeConf{
+		Pending:      []string{"Upgrading"},
+		Target:       []string{"Available"},
+		Refresh:      waitForCCEClusterActive(client, clusterID),
+		Timeout:      timeout,
+		Delay:        stateRefreshDelay(10 * time.Second),
+		PollInterval: stateRefreshInterval(10 * time.Second),
+	}
+	if _, err := stateConf.WaitForState(); err != nil {
+		return fmt.Errorf("Error waiting for flexibleengine CCE cluster %s to become available: %s", clusterID, err)
+	}
+	return nil
+}
+
+func resourceCCEClusterV3Hibernate(cceClient *golangsdk.ServiceClient, clusterID string, timeout time.Duration) error {
+	if err := clusters.Operation(cceClient, clusterID, "hibernate").ExtractErr(); err != nil {
+		return fmt.Errorf("Error hibernating flexibleengine CCE cluster %s: %s", clusterID, err)
+	}
+
+	log.Printf("[DEBUG] Waiting for flexibleengine CCE cluster (%s) to be hibernated", clusterID)
+	stateConf := &resource.StateChangeConf{
+		Pending:      []string{"Available", "Hibernating"},
+		Target:       []string{"Hibernation"},
+		Refresh:      waitForCCEClusterActive(cceClient, clusterID),
+		Timeout:      timeout,
+		Delay:        stateRefreshDelay(10 * time.Second),
+		PollInterval: stateRefreshInterval(10 * time.Second),
+	}
+	if _, err := stateConf.WaitForState(); err != nil {
+		return fmt.Errorf("Error waiting for flexibleengine CCE cluster %s to be hibernated: %s", clusterID, err)
+	}
+	return nil
+}
+
+func resourceCCEClusterV3Awake(cceClient *golangsdk.ServiceClient, clusterID string, timeout time.Duration) error {
+	if err := clusters.Operation(cceClient, clusterID, "awake").ExtractErr(); err != nil {
+		return fmt.Errorf("Error waking up flexibleengine CCE cluster %s: %s", clusterID, err)
+	}
+
+	log.Printf("[DEBUG] Waiting for flexibleengine CCE cluster (%s) to become available", clusterID)
+	stateConf := &resource.StateChangeConf{
+		Pending:      []string{"Hibernation", "Awaking"},
+		Target:       []string{"Available"},
+		Refresh:      waitForCCEClusterActive(cceClient, clusterID),
+		Timeout:      timeout,
+		Delay:        stateRefreshDelay(10 * time.Second),
+		PollInterval: stateRefreshInterval(10 * time.Second),
+	}
+	if _, err := stateConf.WaitForState(); err != nil {
+		return fmt.Errorf("Error waiting for flexibleengine CCE cluster %s to become available: %s", clusterID, err)
+	}
+	return nil
+}
+
+// setCCEClusterKubeConfig issues a new certificate of the cluster which is valid for the
+// duration in days, -1 means the maximum, and saves the kubeconfig in YAML to kube_config_raw.
+func setCCEClusterKubeConfig(d *schema.ResourceData, client *golangsdk.ServiceClient) error {
+	var r golangsdk.Result
+	_, r.Err = client.Post(client.ServiceURL("clusters", d.Id(), "clustercert"),
+		map[string]interface{}{"duration": d.Get("kube_config_duration").(int)}, &r.Body, &golangsdk.RequestOpts{
+			OkCodes:     []int{200, 201},
+			MoreHeaders: clusters.RequestOpts.MoreHeaders,
+		})
+	if r.Err != nil {
+		return r.Err
+	}
+
+	kubeConfig, err := flattenCCEClusterKubeConfig(r, d.Id(), d.Get("kube_config_context").(string))
+	if err != nil {
+		return err
+	}
+	return d.Set("kube_config_raw", kubeConfig)
+}
+
+// flattenCCEClusterKubeConfig returns the kubeconfig in YAML with the current context
+// switched to the internal or external one.
+func flattenCCEClusterKubeConfig(r golangsdk.Result, clusterID, contextName string) (string, error) {
+	var cert clusters.Certificate
+	if err := r.ExtractInto(&cert); err != nil {
+		return "", err
+	}
+	kubeConfig, ok := r.Body.(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("the kubeconfig is not an object")
+	}
+
+	found := false
+	for _, c := range cert.Contexts {
+		if c.Name == contextName {
+			found = true
+		}
+	}
+	if found {
+		kubeConfig["current-context"] = contextName
+	} else {
+		// the external context only exists when an EIP is bound to the cluster
+		log.Printf("[WARN] the %s context is not found in the kubeconfig of CCE cluster %s, keep %v as the current context",
+			contextName, clusterID, kubeConfig["current-context"])
+	}
+
+	raw, err := yaml.Marshal(kubeConfig)
+	if err != nil {
+		return "", err
+	}
+	return string(raw), nil
+}
+
 func resourceCCEClusterV3Delete(d *schema.ResourceData, meta interface{}) error {
 	config := meta.(*Config)
 	cceClient, err := config.CceV3Client(GetRegion(d, config))
@@ -489,8 +970,8 @@ func resourceCCEClusterV3Delete(d *schema.ResourceData, meta interface{}) error
 		Target:       []string{"Deleted"},
 		Refresh:      waitForCCEClusterDelete(cceClient, d.Id()),
 		Timeout:      d.Timeout(schema.TimeoutDelete),
-		Delay:        60 * time.Second,
-		PollInterval: 10 * time.Second,
+		Delay:        stateRefreshDelay(60 * time.Second),
+		PollInterval: stateRefreshInterval(10 * time.Second),
 	}
 
 	_, err = stateConf.WaitForState()
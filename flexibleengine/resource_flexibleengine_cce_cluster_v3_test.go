@@ -41,7 +41,7 @@ func TestAccCCEClusterV3_basic(t *testing.T) {
 				ImportState:       true,
 				ImportStateVerify: true,
 				ImportStateVerifyIgnore: []string{
-					"eip",
+					"eip", "kube_config_raw",
 				},
 			},
 			{
@@ -54,6 +54,66 @@ func TestAccCCEClusterV3_basic(t *testing.T) {
 	})
 }
 
+func TestAccCCEClusterV3_upgrade(t *testing.T) {
+	var cluster clusters.Clusters
+	var cceName = fmt.Sprintf("terra-test-%s", acctest.RandString(5))
+	resourceName := "flexibleengine_cce_cluster_v3.cluster_1"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCCEClusterV3Destroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCCEClusterV3_version(cceName, "v1.21"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCCEClusterV3Exists(resourceName, &cluster),
+					resource.TestCheckResourceAttr(resourceName, "cluster_version", "v1.21"),
+				),
+			},
+			{
+				Config: testAccCCEClusterV3_version(cceName, "v1.23"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCCEClusterV3Exists(resourceName, &cluster),
+					resource.TestCheckResourceAttr(resourceName, "cluster_version", "v1.23"),
+					resource.TestCheckResourceAttr(resourceName, "upgrade_status", "Success"),
+					resource.TestCheckResourceAttr(resourceName, "status", "Available"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCCEClusterV3_hibernate(t *testing.T) {
+	var cluster clusters.Clusters
+	var cceName = fmt.Sprintf("terra-test-%s", acctest.RandString(5))
+	resourceName := "flexibleengine_cce_cluster_v3.cluster_1"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCCEClusterV3Destroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCCEClusterV3_hibernate(cceName, true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCCEClusterV3Exists(resourceName, &cluster),
+					resource.TestCheckResourceAttr(resourceName, "hibernate", "true"),
+					resource.TestCheckResourceAttr(resourceName, "status", "Hibernation"),
+					resource.TestCheckResourceAttrSet(resourceName, "kube_config_raw"),
+				),
+			},
+			{
+				Config: testAccCCEClusterV3_hibernate(cceName, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "hibernate", "false"),
+					resource.TestCheckResourceAttr(resourceName, "status", "Available"),
+				),
+			},
+		},
+	})
+}
+
 func testAccCheckCCEClusterV3Destroy(s *terraform.State) error {
 	config := testAccProvider.Meta().(*Config)
 	cceClient, err := config.CceV3Client(OS_REGION_NAME)
@@ -193,3 +253,38 @@ resource "flexibleengine_cce_cluster_v3" "cluster_1" {
   eip                    = flexibleengine_vpc_eip.update.address
 }`, testAccCCEClusterV3_Base(cceName), cceName)
 }
+
+func testAccCCEClusterV3_version(cceName, version string) string {
+	return fmt.Sprintf(`
+%s
+
+resource "flexibleengine_cce_cluster_v3" "cluster_1" {
+  name                   = "%s"
+  cluster_type           = "VirtualMachine"
+  cluster_version        = "%s"
+  flavor_id              = "cce.s1.small"
+  vpc_id                 = flexibleengine_vpc_v1.test.id
+  subnet_id              = flexibleengine_vpc_subnet_v1.test.id
+  container_network_type = "overlay_l2"
+
+  upgrade_policy {
+    upgrade_node_pools = true
+  }
+}`, testAccCCEClusterV3_Base(cceName), cceName, version)
+}
+
+func testAccCCEClusterV3_hibernate(cceName string, hibernate bool) string {
+	return fmt.Sprintf(`
+%s
+
+resource "flexibleengine_cce_cluster_v3" "cluster_1" {
+  name                   = "%s"
+  cluster_type           = "VirtualMachine"
+  flavor_id              = "cce.s1.small"
+  vpc_id                 = flexibleengine_vpc_v1.test.id
+  subnet_id              = flexibleengine_vpc_subnet_v1.test.id
+  container_network_type = "overlay_l2"
+  hibernate              = %t
+  kube_config_duration   = 30
+}`, testAccCCEClusterV3_Base(cceName), cceName, hibernate)
+}
@@ -0,0 +1,378 @@
+package flexibleengine
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/chnsz/golangsdk"
+	"github.com/chnsz/golangsdk/openstack/cce/v3/nodes"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceCCENodeAttach() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceCCENodeAttachCreate,
+		Read:   resourceCCENodeAttachRead,
+		Update: resourceCCENodeAttachUpdate,
+		Delete: resourceCCENodeAttachDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceCCENodeV3Import,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"cluster_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"server_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"os": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"key_pair": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"password", "key_pair"},
+			},
+			"password": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				ExactlyOneOf: []string{"password", "key_pair"},
+			},
+			"labels": { //(k8s_tags)
+				Type:     schema.TypeMap,
+				Optional: true,
+				ForceNew: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"taints": {
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"key": {
+							Type:     schema.TypeString,
+							Required: true,
+							ForceNew: true,
+						},
+						"value": {
+							Type:     schema.TypeString,
+							Required: true,
+							ForceNew: true,
+						},
+						"effect": {
+							Type:     schema.TypeString,
+							Required: true,
+							ForceNew: true,
+						},
+					}},
+			},
+			"max_pods": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				ForceNew: true,
+			},
+			"preinstall": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				StateFunc: func(v interface{}) string {
+					switch v.(type) {
+					case string:
+						return installScriptHashSum(v.(string))
+					default:
+						return ""
+					}
+				},
+			},
+			"postinstall": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				StateFunc: func(v interface{}) string {
+					switch v.(type) {
+					case string:
+						return installScriptHashSum(v.(string))
+					default:
+						return ""
+					}
+				},
+			},
+
+			"flavor_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"availability_zone": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"private_ip": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"public_ip": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func buildCCENodeAttachSpec(d *schema.ResourceData) nodes.AddNodeSpec {
+	spec := nodes.AddNodeSpec{
+		Os:   d.Get("os").(string),
+		Name: d.Get("name").(string),
+	}
+
+	labels := resourceCCENodeK8sTags(d)
+	taints := resourceCCETaint(d)
+	maxPods := d.Get("max_pods").(int)
+	if len(labels) > 0 || len(taints) > 0 || maxPods > 0 {
+		spec.K8sOptions = &nodes.K8sOptions{
+			Labels:  labels,
+			Taints:  taints,
+			MaxPods: maxPods,
+		}
+	}
+
+	preinstall := d.Get("preinstall").(string)
+	postinstall := d.Get("postinstall").(string)
+	if preinstall != "" || postinstall != "" {
+		spec.Lifecycle = &nodes.Lifecycle{}
+		if preinstall != "" {
+			spec.Lifecycle.Preinstall = installScriptEncode(preinstall)
+		}
+		if postinstall != "" {
+			spec.Lifecycle.PostInstall = installScriptEncode(postinstall)
+		}
+	}
+
+	return spec
+}
+
+// waitForCCENodeAttached waits for the job which installs the node and returns the node ID
+func waitForCCENodeAttached(client *golangsdk.ServiceClient, clusterID, jobID string, timeout time.Duration) (string, error) {
+	nodeID, err := getResourceIDFromJob(client, jobID, "CreateNode", "InstallNode", timeout)
+	if err != nil {
+		return "", err
+	}
+
+	stateConf := &resource.StateChangeConf{
+		Pending:      []string{"Build", "Installing"},
+		Target:       []string{"Active"},
+		Refresh:      waitForCceNodeActive(client, clusterID, nodeID),
+		Timeout:      timeout,
+		Delay:        stateRefreshDelay(5 * time.Second),
+		PollInterval: stateRefreshInterval(5 * time.Second),
+	}
+	if _, err := stateConf.WaitForState(); err != nil {
+		return "", err
+	}
+	return nodeID, nil
+}
+
+func resourceCCENodeAttachCreate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	nodeClient, err := config.CceV3Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating flexibleengine CCE Node client: %s", err)
+	}
+
+	// wait for the cce cluster to become available
+	clusterid := d.Get("cluster_id").(string)
+	stateCluster := &resource.StateChangeConf{
+		Target:       []string{"Available"},
+		Refresh:      waitForClusterAvailable(nodeClient, clusterid),
+		Timeout:      d.Timeout(schema.TimeoutCreate),
+		Delay:        stateRefreshDelay(5 * time.Second),
+		PollInterval: stateRefreshInterval(5 * time.Second),
+	}
+	if _, err = stateCluster.WaitForState(); err != nil {
+		return fmt.Errorf("CCE Cluster %s is inactive: %s", clusterid, err)
+	}
+
+	addOpts := nodes.AddOpts{
+		Kind:       "List",
+		ApiVersion: "v3",
+		NodeList: []nodes.AddNode{
+			{
+				ServerID: d.Get("server_id").(string),
+				Spec:     buildCCENodeAttachSpec(d),
+			},
+		},
+	}
+
+	log.Printf("[DEBUG] Attach node options: %#v", addOpts)
+	// Add loginSpec here so it wouldn't go in the above log entry
+	addOpts.NodeList[0].Spec.Login = buildCCENodePoolLoginSpec(d)
+
+	s, err := nodes.Add(nodeClient, clusterid, addOpts).ExtractAddNode()
+	if err != nil {
+		return fmt.Errorf("Error attaching server %s to flexibleengine CCE cluster %s: %s",
+			d.Get("server_id").(string), clusterid, err)
+	}
+
+	nodeID, err := waitForCCENodeAttached(nodeClient, clusterid, s.JobID, d.Timeout(schema.TimeoutCreate))
+	if err != nil {
+		return fmt.Errorf("Error waiting for flexibleengine CCE Node to be attached: %s", err)
+	}
+	d.SetId(nodeID)
+
+	return resourceCCENodeAttachRead(d, meta)
+}
+
+func resourceCCENodeAttachRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	region := GetRegion(d, config)
+	nodeClient, err := config.CceV3Client(region)
+	if err != nil {
+		return fmt.Errorf("Error creating flexibleengine CCE Node client: %s", err)
+	}
+
+	clusterid := d.Get("cluster_id").(string)
+	s, err := nodes.Get(nodeClient, clusterid, d.Id()).Extract()
+	if err != nil {
+		return CheckDeleted(d, err, "CCE Node")
+	}
+
+	mErr := multierror.Append(
+		d.Set("region", region),
+		d.Set("name", s.Metadata.Name),
+		d.Set("server_id", s.Status.ServerID),
+		d.Set("os", s.Spec.Os),
+		d.Set("flavor_id", s.Spec.Flavor),
+		d.Set("availability_zone", s.Spec.Az),
+		d.Set("max_pods", s.Spec.ExtendParam["maxPods"]),
+		d.Set("private_ip", s.Status.PrivateIP),
+		d.Set("public_ip", s.Status.PublicIP),
+		d.Set("status", s.Status.Phase),
+		d.Set("taints", expandResourceCCETaints(s.Spec)),
+		d.Set("labels", expandResourceCCEK8sTags(s.Spec)),
+	)
+	// the password is not returned
+	if s.Spec.Login.SshKey != "" {
+		mErr = multierror.Append(mErr, d.Set("key_pair", s.Spec.Login.SshKey))
+	}
+	if err := mErr.ErrorOrNil(); err != nil {
+		return fmt.Errorf("Error setting attributes of CCE Node %s: %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+func resourceCCENodeAttachUpdate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	nodeClient, err := config.CceV3Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating flexibleengine CCE client: %s", err)
+	}
+	clusterid := d.Get("cluster_id").(string)
+
+	// the server is reinstalled when the OS or the login mode is changed
+	if d.HasChanges("os", "key_pair", "password") {
+		resetOpts := nodes.ResetOpts{
+			Kind:       "List",
+			ApiVersion: "v3",
+			NodeList: []nodes.ResetNode{
+				{
+					NodeID: d.Id(),
+					Spec:   buildCCENodeAttachSpec(d),
+				},
+			},
+		}
+
+		log.Printf("[DEBUG] Reset node options: %#v", resetOpts)
+		// Add loginSpec here so it wouldn't go in the above log entry
+		resetOpts.NodeList[0].Spec.Login = buildCCENodePoolLoginSpec(d)
+
+		s, err := nodes.Reset(nodeClient, clusterid, resetOpts).ExtractAddNode()
+		if err != nil {
+			return fmt.Errorf("Error resetting flexibleengine CCE Node %s: %s", d.Id(), err)
+		}
+
+		nodeID, err := waitForCCENodeAttached(nodeClient, clusterid, s.JobID, d.Timeout(schema.TimeoutUpdate))
+		if err != nil {
+			return fmt.Errorf("Error waiting for flexibleengine CCE Node %s to be reset: %s", d.Id(), err)
+		}
+		d.SetId(nodeID)
+	} else if d.HasChange("name") {
+		var updateOpts nodes.UpdateOpts
+		updateOpts.Metadata.Name = d.Get("name").(string)
+		if _, err := nodes.Update(nodeClient, clusterid, d.Id(), updateOpts).Extract(); err != nil {
+			return fmt.Errorf("Error updating flexibleengine CCE Node %s: %s", d.Id(), err)
+		}
+	}
+
+	return resourceCCENodeAttachRead(d, meta)
+}
+
+func resourceCCENodeAttachDelete(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	nodeClient, err := config.CceV3Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating flexibleengine CCE client: %s", err)
+	}
+	clusterid := d.Get("cluster_id").(string)
+
+	// removing the node keeps the server, the OS of the server is reinstalled with the login mode
+	removeOpts := nodes.RemoveOpts{
+		Kind:       "RemoveNodesTask",
+		Apiversion: "v3",
+		Spec: nodes.RemoveNodeSpec{
+			Login: buildCCENodePoolLoginSpec(d),
+			Nodes: []nodes.NodeItem{
+				{Uid: d.Id()},
+			},
+		},
+	}
+	if err := nodes.Remove(nodeClient, clusterid, removeOpts).ExtractErr(); err != nil {
+		return CheckDeleted(d, err, "CCE Node")
+	}
+
+	stateConf := &resource.StateChangeConf{
+		Pending:      []string{"Active", "Deleting"},
+		Target:       []string{"Deleted"},
+		Refresh:      waitForCceNodeDelete(nodeClient, clusterid, d.Id()),
+		Timeout:      d.Timeout(schema.TimeoutDelete),
+		Delay:        stateRefreshDelay(10 * time.Second),
+		PollInterval: stateRefreshInterval(10 * time.Second),
+	}
+	if _, err := stateConf.WaitForState(); err != nil {
+		return fmt.Errorf("Error removing flexibleengine CCE Node %s: %s", d.Id(), err)
+	}
+
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,112 @@
+package flexibleengine
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	"github.com/chnsz/golangsdk/openstack/cce/v3/nodes"
+)
+
+func TestAccCCENodeAttach_basic(t *testing.T) {
+	rName := fmt.Sprintf("tf-acc-test-%s", acctest.RandString(5))
+	resourceName := "flexibleengine_cce_node_attach.test"
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccCCEKeyPairPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCCENodeAttachDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCCENodeAttach_basic(rName, "EulerOS 2.9"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(resourceName, "server_id",
+						"flexibleengine_compute_instance_v2.test", "id"),
+					resource.TestCheckResourceAttr(resourceName, "os", "EulerOS 2.9"),
+					resource.TestCheckResourceAttr(resourceName, "status", "Active"),
+					resource.TestCheckResourceAttr(resourceName, "labels.foo", "bar"),
+				),
+			},
+			{
+				Config: testAccCCENodeAttach_basic(rName, "CentOS 7.6"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "os", "CentOS 7.6"),
+					resource.TestCheckResourceAttr(resourceName, "status", "Active"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateIdFunc: nodePoolImportStateIdFunc(resourceName),
+			},
+		},
+	})
+}
+
+func testAccCheckCCENodeAttachDestroy(s *terraform.State) error {
+	config := testAccProvider.Meta().(*Config)
+	cceClient, err := config.CceV3Client(OS_REGION_NAME)
+	if err != nil {
+		return fmt.Errorf("Error creating flexibleengine CCE client: %s", err)
+	}
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "flexibleengine_cce_node_attach" {
+			continue
+		}
+
+		_, err := nodes.Get(cceClient, rs.Primary.Attributes["cluster_id"], rs.Primary.ID).Extract()
+		if err == nil {
+			return fmt.Errorf("Node still exists")
+		}
+	}
+
+	return nil
+}
+
+func testAccCCENodeAttach_basic(rName, osName string) string {
+	return fmt.Sprintf(`
+data "flexibleengine_availability_zones" "test" {}
+
+resource "flexibleengine_cce_cluster_v3" "test" {
+  name                   = "%[1]s"
+  cluster_type           = "VirtualMachine"
+  flavor_id              = "cce.s1.small"
+  vpc_id                 = "%[2]s"
+  subnet_id              = "%[3]s"
+  container_network_type = "overlay_l2"
+}
+
+resource "flexibleengine_compute_instance_v2" "test" {
+  name              = "%[1]s"
+  image_id          = "%[4]s"
+  flavor_id         = "s3.large.2"
+  key_pair          = "%[5]s"
+  security_groups   = ["default"]
+  availability_zone = data.flexibleengine_availability_zones.test.names[0]
+
+  network {
+    uuid = "%[3]s"
+  }
+
+  lifecycle {
+    ignore_changes = [image_id, image_name]
+  }
+}
+
+resource "flexibleengine_cce_node_attach" "test" {
+  cluster_id = flexibleengine_cce_cluster_v3.test.id
+  server_id  = flexibleengine_compute_instance_v2.test.id
+  os         = "%[6]s"
+  key_pair   = "%[5]s"
+
+  labels = {
+    foo = "bar"
+  }
+}
+`, rName, OS_VPC_ID, OS_NETWORK_ID, OS_IMAGE_ID, OS_KEYPAIR_NAME, osName)
+}
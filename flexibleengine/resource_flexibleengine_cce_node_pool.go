@@ -1,6 +1,7 @@
 package flexibleengine
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strings"
@@ -12,6 +13,7 @@ import (
 	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceCCENodePool() *schema.Resource {
@@ -39,9 +41,12 @@ func resourceCCENodePool() *schema.Resource {
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(20 * time.Minute),
+			Update: schema.DefaultTimeout(60 * time.Minute),
 			Delete: schema.DefaultTimeout(20 * time.Minute),
 		},
 
+		CustomizeDiff: resourceCCENodePoolCustomizeDiff,
+
 		Schema: map[string]*schema.Schema{
 			"region": {
 				Type:     schema.TypeString,
@@ -65,7 +70,6 @@ func resourceCCENodePool() *schema.Resource {
 			"flavor_id": {
 				Type:     schema.TypeString,
 				Required: true,
-				ForceNew: true,
 			},
 			"type": {
 				Type:     schema.TypeString,
@@ -75,24 +79,20 @@ func resourceCCENodePool() *schema.Resource {
 			"root_volume": {
 				Type:     schema.TypeList,
 				Required: true,
-				ForceNew: true,
 				MaxItems: 1,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"size": {
 							Type:     schema.TypeInt,
 							Required: true,
-							ForceNew: true,
 						},
 						"volumetype": {
 							Type:     schema.TypeString,
 							Required: true,
-							ForceNew: true,
 						},
 						"extend_params": {
 							Type:     schema.TypeMap,
 							Optional: true,
-							ForceNew: true,
 							Elem:     &schema.Schema{Type: schema.TypeString},
 						},
 					}},
@@ -100,28 +100,23 @@ func resourceCCENodePool() *schema.Resource {
 			"data_volumes": {
 				Type:     schema.TypeList,
 				Required: true,
-				ForceNew: true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"size": {
 							Type:     schema.TypeInt,
 							Required: true,
-							ForceNew: true,
 						},
 						"volumetype": {
 							Type:     schema.TypeString,
 							Required: true,
-							ForceNew: true,
 						},
 						"kms_key_id": {
 							Type:     schema.TypeString,
 							Optional: true,
-							ForceNew: true,
 						},
 						"extend_params": {
 							Type:     schema.TypeMap,
 							Optional: true,
-							ForceNew: true,
 							Elem:     &schema.Schema{Type: schema.TypeString},
 						},
 					}},
@@ -135,7 +130,6 @@ func resourceCCENodePool() *schema.Resource {
 			"os": {
 				Type:     schema.TypeString,
 				Optional: true,
-				ForceNew: true,
 				Computed: true,
 			},
 			"key_pair": {
@@ -175,7 +169,8 @@ func resourceCCENodePool() *schema.Resource {
 						},
 					}},
 			},
-			"tags": tagsSchema(),
+			"tags":     tagsSchema(),
+			"tags_all": tagsAllSchema(),
 			"max_pods": {
 				Type:     schema.TypeInt,
 				Optional: true,
@@ -213,6 +208,10 @@ func resourceCCENodePool() *schema.Resource {
 				ForceNew: true,
 				Elem:     &schema.Schema{Type: schema.TypeString},
 			},
+			"runtime":          resourceCCENodeRuntimeSchema(false),
+			"storage":          resourceCCENodeStorageSchema(false),
+			"kubelet":          resourceCCENodeKubeletSchema(false),
+			"docker_base_size": resourceCCENodeDockerBaseSizeSchema(false),
 			"subnet_id": {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -249,6 +248,27 @@ func resourceCCENodePool() *schema.Resource {
 				Optional: true,
 				ForceNew: true,
 			},
+			"rolling_update": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"max_surge": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      1,
+							ValidateFunc: validation.IntAtLeast(0),
+						},
+						"max_unavailable": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      0,
+							ValidateFunc: validation.IntAtLeast(0),
+						},
+					},
+				},
+			},
 
 			"billing_mode": {
 				Type:     schema.TypeInt,
@@ -290,12 +310,15 @@ func resourceCCENodePoolCreate(d *schema.ResourceData, meta interface{}) error {
 		Target:     []string{"Available"},
 		Refresh:    waitForClusterAvailable(nodePoolClient, clusterid),
 		Timeout:    d.Timeout(schema.TimeoutCreate),
-		Delay:      5 * time.Second,
-		MinTimeout: 5 * time.Second,
+		Delay:      stateRefreshDelay(5 * time.Second),
+		MinTimeout: stateRefreshInterval(5 * time.Second),
 	}
 	if _, err = stateCluster.WaitForState(); err != nil {
 		return fmt.Errorf("CCE Cluster %s is inactive: %s", clusterid, err)
 	}
+	if err := checkCCENodeOptions(config, GetRegion(d, config), clusterid, d); err != nil {
+		return err
+	}
 
 	initialNodeCount := d.Get("initial_node_count").(int)
 	createOpts := nodepools.CreateOpts{
@@ -322,7 +345,9 @@ func resourceCCENodePoolCreate(d *schema.ResourceData, meta interface{}) error {
 				},
 				ExtendParam: resourceCCEExtendParam(d),
 				Taints:      resourceCCETaint(d),
-				UserTags:    resourceCCENodeUserTags(d),
+				UserTags:    resourceCCENodeUserTags(d, meta),
+				RunTime:     buildCCENodeRuntime(d.Get("runtime").([]interface{})),
+				Storage:     buildCCENodeStorage(d.Get("storage").([]interface{})),
 			},
 			Autoscaling: nodepools.AutoscalingSpec{
 				Enable:                d.Get("scale_enable").(bool) || d.Get("scall_enable").(bool),
@@ -364,8 +389,8 @@ func resourceCCENodePoolCreate(d *schema.ResourceData, meta interface{}) error {
 		Target:       []string{""},
 		Refresh:      waitForCceNodePoolActive(nodePoolClient, clusterid, s.Metadata.Id),
 		Timeout:      d.Timeout(schema.TimeoutCreate),
-		Delay:        120 * time.Second,
-		PollInterval: 20 * time.Second,
+		Delay:        stateRefreshDelay(120 * time.Second),
+		PollInterval: stateRefreshInterval(20 * time.Second),
 	}
 	_, err = stateConf.WaitForState()
 	if err != nil {
@@ -439,32 +464,29 @@ func resourceCCENodePoolRead(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("Error saving labels/k8stags of cce node pool %s: %s", d.Id(), err)
 	}
 
+	if err := setCCENodeOptions(d, s.Spec.NodeTemplate); err != nil {
+		return fmt.Errorf("Error saving options of cce node pool %s: %s", d.Id(), err)
+	}
+
 	tagmap := tagsToMap(s.Spec.NodeTemplate.UserTags)
 	// ignore "CCE-Dynamic-Provisioning-Node"
 	delete(tagmap, "CCE-Dynamic-Provisioning-Node")
-	if err := d.Set("tags", tagmap); err != nil {
+	if err := setResourceTags(d, meta, tagmap); err != nil {
 		return fmt.Errorf("Error saving tags to state for CCE Node Pool(%s): %s", d.Id(), err)
 	}
 
 	return nil
 }
 
-func resourceCCENodePoolUpdate(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*Config)
-	nodePoolClient, err := config.CceV3Client(GetRegion(d, config))
-	if err != nil {
-		return fmt.Errorf("Error creating Flexibleengine CCE client: %s", err)
-	}
-
-	initialNodeCount := d.Get("initial_node_count").(int)
-	updateOpts := nodepools.UpdateOpts{
+func buildCCENodePoolUpdateOpts(d *schema.ResourceData, meta interface{}, nodeCount int) nodepools.UpdateOpts {
+	return nodepools.UpdateOpts{
 		Kind:       "NodePool",
 		ApiVersion: "v3",
 		Metadata: nodepools.UpdateMetaData{
 			Name: d.Get("name").(string),
 		},
 		Spec: nodepools.UpdateSpec{
-			InitialNodeCount: &initialNodeCount,
+			InitialNodeCount: &nodeCount,
 			Autoscaling: nodepools.AutoscalingSpec{
 				Enable:                d.Get("scale_enable").(bool) || d.Get("scall_enable").(bool),
 				MinNodeCount:          d.Get("min_node_count").(int),
@@ -475,20 +497,42 @@ func resourceCCENodePoolUpdate(d *schema.ResourceData, meta interface{}) error {
 			NodeTemplate: nodes.Spec{
 				Flavor:      d.Get("flavor_id").(string),
 				Az:          d.Get("availability_zone").(string),
+				Os:          d.Get("os").(string),
 				Login:       buildCCENodePoolLoginSpec(d),
 				RootVolume:  resourceCCERootVolume(d),
 				DataVolumes: resourceCCEDataVolume(d),
 				Count:       1,
 				K8sTags:     resourceCCENodeK8sTags(d),
-				UserTags:    resourceCCENodeUserTags(d),
+				UserTags:    resourceCCENodeUserTags(d, meta),
 				Taints:      resourceCCETaint(d),
 				ExtendParam: resourceCCEExtendParam(d),
+				RunTime:     buildCCENodeRuntime(d.Get("runtime").([]interface{})),
+				Storage:     buildCCENodeStorage(d.Get("storage").([]interface{})),
 			},
 			Type: d.Get("type").(string),
 		},
 	}
+}
+
+func resourceCCENodePoolUpdate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	nodePoolClient, err := config.CceV3Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating Flexibleengine CCE client: %s", err)
+	}
 
 	clusterid := d.Get("cluster_id").(string)
+	// the template changes are only applied to new nodes, so the nodes created
+	// with the old template are replaced after the node pool is updated
+	var oldNodes []string
+	rollingUpdate := d.HasChanges(cceNodePoolTemplateKeys...)
+	if rollingUpdate {
+		if oldNodes, err = prepareCCENodePoolRollingUpdate(d, nodePoolClient); err != nil {
+			return err
+		}
+	}
+
+	updateOpts := buildCCENodePoolUpdateOpts(d, meta, d.Get("initial_node_count").(int))
 	_, err = nodepools.Update(nodePoolClient, clusterid, d.Id(), updateOpts).Extract()
 	if err != nil {
 		return fmt.Errorf("Error updating Flexibleengine Node Node Pool: %s", err)
@@ -498,15 +542,21 @@ func resourceCCENodePoolUpdate(d *schema.ResourceData, meta interface{}) error {
 		Pending:    []string{"Synchronizing", "Synchronized"},
 		Target:     []string{""},
 		Refresh:    waitForCceNodePoolActive(nodePoolClient, clusterid, d.Id()),
-		Timeout:    d.Timeout(schema.TimeoutCreate),
-		Delay:      60 * time.Second,
-		MinTimeout: 10 * time.Second,
+		Timeout:    d.Timeout(schema.TimeoutUpdate),
+		Delay:      stateRefreshDelay(60 * time.Second),
+		MinTimeout: stateRefreshInterval(10 * time.Second),
 	}
 	_, err = stateConf.WaitForState()
 	if err != nil {
 		return fmt.Errorf("Error creating Flexibleengine CCE Node Pool: %s", err)
 	}
 
+	if rollingUpdate {
+		if err := rollingUpdateCCENodePool(d, meta, nodePoolClient, oldNodes); err != nil {
+			return fmt.Errorf("Error replacing the nodes of Flexibleengine CCE Node Pool %s: %s", d.Id(), err)
+		}
+	}
+
 	return resourceCCENodePoolRead(d, meta)
 }
 
@@ -526,8 +576,8 @@ func resourceCCENodePoolDelete(d *schema.ResourceData, meta interface{}) error {
 		Target:       []string{"Deleted"},
 		Refresh:      waitForCceNodePoolDelete(nodePoolClient, clusterid, d.Id()),
 		Timeout:      d.Timeout(schema.TimeoutDelete),
-		Delay:        60 * time.Second,
-		PollInterval: 20 * time.Second,
+		Delay:        stateRefreshDelay(60 * time.Second),
+		PollInterval: stateRefreshInterval(20 * time.Second),
 	}
 
 	_, err = stateConf.WaitForState()
@@ -539,6 +589,350 @@ func resourceCCENodePoolDelete(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
+// cceNodePoolAnnotation is the annotation of a node which records its node pool
+const cceNodePoolAnnotation = "kubernetes.io/node-pool.id"
+
+// cceNodePoolTemplateKeys are the arguments of the node template which only apply to
+// the new nodes, the existing nodes are replaced when rolling_update is set.
+var cceNodePoolTemplateKeys = []string{"flavor_id", "os", "root_volume", "data_volumes",
+	"runtime", "storage", "kubelet", "docker_base_size"}
+
+// errCCENodePoolNoRoom is returned when the bounds of the node pool leave no room to replace a node
+var errCCENodePoolNoRoom = fmt.Errorf("no node can be replaced within the bounds of the node pool, " +
+	"please increase max_surge, max_unavailable or max_node_count")
+
+func resourceCCENodePoolCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() != "" {
+		if v, ok := d.GetOk("rolling_update"); !ok {
+			if err := forceNewCCENodePoolTemplate(d); err != nil {
+				return err
+			}
+		} else if maxSurge, maxUnavailable := getCCENodePoolRollingUpdate(v.([]interface{})); maxSurge+maxUnavailable == 0 {
+			return fmt.Errorf("max_surge and max_unavailable of rolling_update can not both be 0")
+		}
+	}
+	if err := customizeDiffCCENodeOptions(ctx, d, meta); err != nil {
+		return err
+	}
+
+	return setTagsAllDiff(ctx, d, meta)
+}
+
+// forceNewCCENodePoolTemplate replaces the node pool on the changes of the node template
+// when rolling_update is not set. The changed nested arguments are marked as well because
+// the ForceNew of a list only covers the changes of its length.
+func forceNewCCENodePoolTemplate(d *schema.ResourceDiff) error {
+	nodePoolSchema := resourceCCENodePool().Schema
+	for _, key := range cceNodePoolTemplateKeys {
+		if !d.HasChange(key) {
+			continue
+		}
+		if err := d.ForceNew(key); err != nil {
+			return err
+		}
+
+		if err := forceNewCCENodePoolNested(d, key, nodePoolSchema[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// forceNewCCENodePoolNested marks the changed nested arguments of the list at key, the
+// nested lists such as the groups of storage are walked through recursively.
+func forceNewCCENodePoolNested(d *schema.ResourceDiff, key string, s *schema.Schema) error {
+	elem, ok := s.Elem.(*schema.Resource)
+	if !ok {
+		return nil
+	}
+	o, n := d.GetChange(key)
+	count := len(o.([]interface{}))
+	if l := len(n.([]interface{})); l > count {
+		count = l
+	}
+	for i := 0; i < count; i++ {
+		for field, fieldSchema := range elem.Schema {
+			nestedKey := fmt.Sprintf("%s.%d.%s", key, i, field)
+			if !d.HasChange(nestedKey) {
+				continue
+			}
+			if err := d.ForceNew(nestedKey); err != nil {
+				return err
+			}
+			if err := forceNewCCENodePoolNested(d, nestedKey, fieldSchema); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func getCCENodePoolRollingUpdate(raw []interface{}) (maxSurge, maxUnavailable int) {
+	if len(raw) == 0 || raw[0] == nil {
+		return 1, 0
+	}
+	policy := raw[0].(map[string]interface{})
+	return policy["max_surge"].(int), policy["max_unavailable"].(int)
+}
+
+// cceNodePoolReplaceBatch returns the number of nodes to add and the number of old nodes
+// to replace in a round of the rolling update. No more than max_unavailable nodes are missing
+// from initial_node_count during the round, and the node count stays within min_node_count
+// and max_node_count when auto scaling is enabled.
+func cceNodePoolReplaceBatch(d *schema.ResourceData, current, remaining int) (surge, batch int) {
+	maxSurge, maxUnavailable := getCCENodePoolRollingUpdate(d.Get("rolling_update").([]interface{}))
+	minCount := d.Get("initial_node_count").(int) - maxUnavailable
+	surge = maxSurge
+	if d.Get("scale_enable").(bool) || d.Get("scall_enable").(bool) {
+		if scaleMin := d.Get("min_node_count").(int); scaleMin > minCount {
+			minCount = scaleMin
+		}
+		if maxCount := d.Get("max_node_count").(int); maxCount > 0 && current+surge > maxCount {
+			surge = maxCount - current
+		}
+	}
+	if surge < 0 {
+		surge = 0
+	}
+	if minCount < 0 {
+		minCount = 0
+	}
+
+	batch = surge + maxUnavailable
+	if batch > remaining {
+		batch = remaining
+	}
+	if current+surge-batch < minCount {
+		batch = current + surge - minCount
+	}
+	if batch < 0 {
+		batch = 0
+	}
+	return surge, batch
+}
+
+func listCCENodePoolNodes(client *golangsdk.ServiceClient, clusterID, nodePoolID string) ([]nodes.Nodes, error) {
+	allNodes, err := nodes.List(client, clusterID, nodes.ListOpts{})
+	if err != nil {
+		return nil, err
+	}
+
+	poolNodes := make([]nodes.Nodes, 0)
+	for _, node := range allNodes {
+		// the value may be prefixed with the availability zone, like "eu-west-0a#{id}"
+		if v := node.Metadata.Annotations[cceNodePoolAnnotation]; v == nodePoolID || strings.HasSuffix(v, "#"+nodePoolID) {
+			poolNodes = append(poolNodes, node)
+		}
+	}
+	return poolNodes, nil
+}
+
+// prepareCCENodePoolRollingUpdate returns the nodes to be replaced by the rolling update. It fails
+// before the node pool is changed if no node can be replaced within the bounds of the node pool.
+func prepareCCENodePoolRollingUpdate(d *schema.ResourceData, client *golangsdk.ServiceClient) ([]string, error) {
+	poolNodes, err := listCCENodePoolNodes(client, d.Get("cluster_id").(string), d.Id())
+	if err != nil {
+		return nil, fmt.Errorf("Error listing the nodes of Flexibleengine CCE Node Pool %s: %s", d.Id(), err)
+	}
+
+	nodeIDs := make([]string, len(poolNodes))
+	for i, node := range poolNodes {
+		nodeIDs[i] = node.Metadata.Id
+	}
+	if len(nodeIDs) > 0 {
+		if _, batch := cceNodePoolReplaceBatch(d, d.Get("initial_node_count").(int), len(nodeIDs)); batch == 0 {
+			return nil, fmt.Errorf("Error updating Flexibleengine CCE Node Pool %s: %s", d.Id(), errCCENodePoolNoRoom)
+		}
+	}
+	return nodeIDs, nil
+}
+
+// rollingUpdateCCENodePool replaces the old nodes of the node pool in rounds: the node pool is
+// scaled up with the new template first, then the old nodes are cordoned, drained and deleted,
+// and the node pool is scaled back to initial_node_count before the next round.
+func rollingUpdateCCENodePool(d *schema.ResourceData, meta interface{}, client *golangsdk.ServiceClient,
+	oldNodes []string) error {
+	clusterID := d.Get("cluster_id").(string)
+	timeout := d.Timeout(schema.TimeoutUpdate)
+
+	pending := make(map[string]bool, len(oldNodes))
+	for _, id := range oldNodes {
+		pending[id] = true
+	}
+
+	for {
+		poolNodes, err := listCCENodePoolNodes(client, clusterID, d.Id())
+		if err != nil {
+			return err
+		}
+		// the old nodes may have been deleted out of terraform
+		remaining := make([]string, 0, len(pending))
+		for _, node := range poolNodes {
+			if pending[node.Metadata.Id] {
+				remaining = append(remaining, node.Metadata.Id)
+			}
+		}
+		if len(remaining) == 0 {
+			break
+		}
+
+		surge, batch := cceNodePoolReplaceBatch(d, len(poolNodes), len(remaining))
+		if batch == 0 {
+			return errCCENodePoolNoRoom
+		}
+		if surge > 0 {
+			log.Printf("[DEBUG] Scaling up CCE Node Pool %s to %d nodes", d.Id(), len(poolNodes)+surge)
+			if err := resizeCCENodePool(d, meta, client, len(poolNodes)+surge, timeout); err != nil {
+				return err
+			}
+		}
+
+		log.Printf("[DEBUG] Replacing the nodes of CCE Node Pool %s: %v", d.Id(), remaining[:batch])
+		if err := removeCCENodePoolNodes(client, clusterID, remaining[:batch], timeout); err != nil {
+			return err
+		}
+		for _, id := range remaining[:batch] {
+			delete(pending, id)
+		}
+
+		// replace the deleted nodes with the new template when more old nodes than new ones
+		// have been deleted in this round
+		if nodeCount := d.Get("initial_node_count").(int); len(poolNodes)+surge-batch < nodeCount {
+			log.Printf("[DEBUG] Scaling CCE Node Pool %s back to %d nodes", d.Id(), nodeCount)
+			if err := resizeCCENodePool(d, meta, client, nodeCount, timeout); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// resizeCCENodePool sets the node count of the node pool and waits for the new nodes to become active
+func resizeCCENodePool(d *schema.ResourceData, meta interface{}, client *golangsdk.ServiceClient,
+	nodeCount int, timeout time.Duration) error {
+	clusterID := d.Get("cluster_id").(string)
+	updateOpts := buildCCENodePoolUpdateOpts(d, meta, nodeCount)
+	if _, err := nodepools.Update(client, clusterID, d.Id(), updateOpts).Extract(); err != nil {
+		return fmt.Errorf("Error scaling the node pool to %d nodes: %s", nodeCount, err)
+	}
+
+	stateConf := &resource.StateChangeConf{
+		Pending:    []string{"Synchronizing", "Synchronized"},
+		Target:     []string{""},
+		Refresh:    waitForCceNodePoolActive(client, clusterID, d.Id()),
+		Timeout:    timeout,
+		Delay:      stateRefreshDelay(5 * time.Second),
+		MinTimeout: stateRefreshInterval(3 * time.Second),
+	}
+	if _, err := stateConf.WaitForState(); err != nil {
+		return fmt.Errorf("Error waiting for the node pool to be scaled to %d nodes: %s", nodeCount, err)
+	}
+
+	poolNodes, err := listCCENodePoolNodes(client, clusterID, d.Id())
+	if err != nil {
+		return err
+	}
+	for _, node := range poolNodes {
+		if node.Status.Phase == "Active" || node.Status.Phase == "Deleting" {
+			continue
+		}
+		nodeConf := &resource.StateChangeConf{
+			Pending:    []string{"Build", "Installing"},
+			Target:     []string{"Active"},
+			Refresh:    waitForCceNodeActive(client, clusterID, node.Metadata.Id),
+			Timeout:    timeout,
+			Delay:      stateRefreshDelay(5 * time.Second),
+			MinTimeout: stateRefreshInterval(3 * time.Second),
+		}
+		if _, err := nodeConf.WaitForState(); err != nil {
+			return fmt.Errorf("Error waiting for node %s to become active: %s", node.Metadata.Id, err)
+		}
+	}
+	return nil
+}
+
+// removeCCENodePoolNodes marks the nodes unschedulable, evicts their pods and deletes them
+func removeCCENodePoolNodes(client *golangsdk.ServiceClient, clusterID string, nodeIDs []string,
+	timeout time.Duration) error {
+	items := make([]map[string]interface{}, len(nodeIDs))
+	for i, id := range nodeIDs {
+		items[i] = map[string]interface{}{"uid": id}
+	}
+	reqOpts := &golangsdk.RequestOpts{
+		OkCodes:     []int{200},
+		MoreHeaders: nodes.RequestOpts.MoreHeaders,
+	}
+
+	cordonOpts := map[string]interface{}{
+		"kind":       "Cordon",
+		"apiVersion": "v3",
+		"spec": map[string]interface{}{
+			"nodes": items,
+		},
+	}
+	_, err := client.Post(client.ServiceURL("clusters", clusterID, "nodes/operation/cordon"), cordonOpts, nil, reqOpts)
+	if err != nil {
+		return fmt.Errorf("Error cordoning nodes %v: %s", nodeIDs, err)
+	}
+
+	drainOpts := map[string]interface{}{
+		"kind":       "Drain",
+		"apiVersion": "v3",
+		"spec": map[string]interface{}{
+			"nodes":              items,
+			"ignoreDaemonSets":   true,
+			"deleteEmptyDirPods": true,
+			"force":              false,
+		},
+	}
+	var r golangsdk.Result
+	_, r.Err = client.Post(client.ServiceURL("clusters", clusterID, "nodes/operation/drain"), drainOpts, &r.Body, reqOpts)
+	var drain struct {
+		Status struct {
+			JobID string `json:"jobID"`
+		} `json:"status"`
+	}
+	if r.Err == nil {
+		r.Err = r.ExtractInto(&drain)
+	}
+	if r.Err != nil {
+		return fmt.Errorf("Error draining nodes %v: %s", nodeIDs, r.Err)
+	}
+
+	stateJob := &resource.StateChangeConf{
+		Pending:    []string{"Initializing", "Running"},
+		Target:     []string{"Success"},
+		Refresh:    waitForJobStatus(client, drain.Status.JobID),
+		Timeout:    timeout,
+		Delay:      stateRefreshDelay(5 * time.Second),
+		MinTimeout: stateRefreshInterval(3 * time.Second),
+	}
+	if _, err := stateJob.WaitForState(); err != nil {
+		return fmt.Errorf("Error waiting for nodes %v to be drained: %s", nodeIDs, err)
+	}
+
+	for _, id := range nodeIDs {
+		if err := nodes.Delete(client, clusterID, id).ExtractErr(); err != nil {
+			return fmt.Errorf("Error deleting node %s: %s", id, err)
+		}
+	}
+	for _, id := range nodeIDs {
+		stateConf := &resource.StateChangeConf{
+			Pending:    []string{"Active", "Deleting"},
+			Target:     []string{"Deleted"},
+			Refresh:    waitForCceNodeDelete(client, clusterID, id),
+			Timeout:    timeout,
+			Delay:      stateRefreshDelay(5 * time.Second),
+			MinTimeout: stateRefreshInterval(3 * time.Second),
+		}
+		if _, err := stateConf.WaitForState(); err != nil {
+			return fmt.Errorf("Error waiting for node %s to be deleted: %s", id, err)
+		}
+	}
+	return nil
+}
+
 func waitForCceNodePoolActive(cceClient *golangsdk.ServiceClient, clusterId, nodePoolId string) resource.StateRefreshFunc {
 	return func() (interface{}, string, error) {
 		n, err := nodepools.Get(cceClient, clusterId, nodePoolId).Extract()
@@ -574,8 +968,8 @@ func recursiveNodePoolCreate(cceClient *golangsdk.ServiceClient, opts nodepools.
 			Target:     []string{"Available"},
 			Refresh:    waitForClusterAvailable(cceClient, ClusterID),
 			Timeout:    15 * time.Minute,
-			Delay:      15 * time.Second,
-			MinTimeout: 3 * time.Second,
+			Delay:      stateRefreshDelay(15 * time.Second),
+			MinTimeout: stateRefreshInterval(3 * time.Second),
 		}
 		_, stateErr := stateCluster.WaitForState()
 		if stateErr != nil {
@@ -100,6 +100,71 @@ func TestAccCCENodePool_serverGroup(t *testing.T) {
 	})
 }
 
+func TestAccCCENodePool_rollingUpdate(t *testing.T) {
+	var nodePool nodepools.NodePool
+
+	rName := fmt.Sprintf("tf-acc-test-%s", acctest.RandString(5))
+	resourceName := "flexibleengine_cce_node_pool_v3.test"
+	clusterName := "flexibleengine_cce_cluster_v3.test"
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: TestAccProviderFactories,
+		CheckDestroy:      testAccCheckCCENodePoolDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCCENodePool_rollingUpdate(rName, "s3.large.2", 40),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCCENodePoolExists(resourceName, clusterName, &nodePool),
+					resource.TestCheckResourceAttr(resourceName, "flavor_id", "s3.large.2"),
+					resource.TestCheckResourceAttr(resourceName, "root_volume.0.size", "40"),
+				),
+			},
+			{
+				Config: testAccCCENodePool_rollingUpdate(rName, "s3.xlarge.2", 50),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPtr(resourceName, "id", &nodePool.Metadata.Id),
+					resource.TestCheckResourceAttr(resourceName, "flavor_id", "s3.xlarge.2"),
+					resource.TestCheckResourceAttr(resourceName, "root_volume.0.size", "50"),
+					resource.TestCheckResourceAttr(resourceName, "initial_node_count", "2"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCCENodePool_options(t *testing.T) {
+	var nodePool nodepools.NodePool
+
+	rName := fmt.Sprintf("tf-acc-test-%s", acctest.RandString(5))
+	resourceName := "flexibleengine_cce_node_pool_v3.test"
+	clusterName := "flexibleengine_cce_cluster_v3.test"
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: TestAccProviderFactories,
+		CheckDestroy:      testAccCheckCCENodePoolDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCCENodePool_options(rName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCCENodePoolExists(resourceName, clusterName, &nodePool),
+					resource.TestCheckResourceAttr(resourceName, "runtime.0.name", "containerd"),
+					resource.TestCheckResourceAttr(resourceName, "storage.0.groups.0.cce_managed", "true"),
+					resource.TestCheckResourceAttr(resourceName, "kubelet.0.cpu_manager_policy", "static"),
+					resource.TestCheckResourceAttr(resourceName, "kubelet.0.image_gc_high_threshold", "80"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateIdFunc: nodePoolImportStateIdFunc(resourceName),
+			},
+		},
+	})
+}
+
 func nodePoolImportStateIdFunc(resourceName string) resource.ImportStateIdFunc {
 	return func(s *terraform.State) (string, error) {
 		rs, ok := s.RootModule().Resources[resourceName]
@@ -297,6 +362,36 @@ resource "flexibleengine_cce_node_pool_v3" "test" {
 `, testAccCCENodePool_Base(rName), updateName)
 }
 
+func testAccCCENodePool_rollingUpdate(rName, flavor string, rootVolumeSize int) string {
+	return fmt.Sprintf(`
+%s
+
+resource "flexibleengine_cce_node_pool_v3" "test" {
+  cluster_id         = flexibleengine_cce_cluster_v3.test.id
+  name               = "%s"
+  os                 = "EulerOS 2.5"
+  flavor_id          = "%s"
+  availability_zone  = data.flexibleengine_availability_zones.test.names[0]
+  key_pair           = flexibleengine_compute_keypair_v2.test.name
+  initial_node_count = 2
+
+  root_volume {
+    size       = %d
+    volumetype = "SSD"
+  }
+  data_volumes {
+    size       = 100
+    volumetype = "SSD"
+  }
+
+  rolling_update {
+    max_surge       = 1
+    max_unavailable = 0
+  }
+}
+`, testAccCCENodePool_Base(rName), rName, flavor, rootVolumeSize)
+}
+
 func testAccCCENodePool_serverGroup(rName string) string {
 	return fmt.Sprintf(`
 %s
@@ -333,3 +428,77 @@ resource "flexibleengine_cce_node_pool_v3" "test" {
 }
 `, testAccCCENodePool_Base(rName), rName)
 }
+
+func testAccCCENodePool_options(rName string) string {
+	return fmt.Sprintf(`
+%[1]s
+
+data "flexibleengine_availability_zones" "test" {}
+
+resource "flexibleengine_cce_cluster_v3" "test" {
+  name                   = "%[2]s"
+  cluster_type           = "VirtualMachine"
+  cluster_version        = "v1.23"
+  flavor_id              = "cce.s1.small"
+  vpc_id                 = flexibleengine_vpc_v1.test.id
+  subnet_id              = flexibleengine_vpc_subnet_v1.test.id
+  container_network_type = "overlay_l2"
+}
+
+resource "flexibleengine_cce_node_pool_v3" "test" {
+  cluster_id         = flexibleengine_cce_cluster_v3.test.id
+  name               = "%[2]s"
+  os                 = "EulerOS 2.9"
+  flavor_id          = "s3.large.2"
+  availability_zone  = data.flexibleengine_availability_zones.test.names[0]
+  password           = "Test@123"
+  initial_node_count = 1
+
+  root_volume {
+    size       = 40
+    volumetype = "SSD"
+  }
+  data_volumes {
+    size       = 100
+    volumetype = "SSD"
+  }
+
+  runtime {
+    name = "containerd"
+  }
+
+  storage {
+    selectors {
+      name             = "cceUse"
+      match_label_size = "100"
+    }
+    groups {
+      name           = "vgpaas"
+      cce_managed    = true
+      selector_names = ["cceUse"]
+
+      virtual_spaces {
+        name        = "kubernetes"
+        size        = "10%%"
+        lvm_lv_type = "linear"
+      }
+      virtual_spaces {
+        name            = "runtime"
+        size            = "90%%"
+        runtime_lv_type = "linear"
+      }
+    }
+  }
+
+  kubelet {
+    cpu_manager_policy      = "static"
+    image_gc_high_threshold = 80
+    image_gc_low_threshold  = 70
+
+    eviction_hard = {
+      "memory.available" = "100Mi"
+    }
+  }
+}
+`, testAccCCEClusterV3_Base(rName), rName)
+}
@@ -1,6 +1,7 @@
 package flexibleengine
 
 import (
+	"context"
 	"crypto/sha1"
 	"encoding/base64"
 	"encoding/hex"
@@ -34,6 +35,8 @@ func resourceCCENodeV3() *schema.Resource {
 			Delete: schema.DefaultTimeout(20 * time.Minute),
 		},
 
+		CustomizeDiff: resourceCCENodeV3CustomizeDiff,
+
 		Schema: map[string]*schema.Schema{
 			"region": {
 				Type:     schema.TypeString,
@@ -61,7 +64,8 @@ func resourceCCENodeV3() *schema.Resource {
 				Optional: true,
 				ForceNew: true,
 			},
-			"tags": tagsSchema(),
+			"tags":     tagsSchema(),
+			"tags_all": tagsAllSchema(),
 
 			"flavor_id": {
 				Type:     schema.TypeString,
@@ -267,6 +271,10 @@ func resourceCCENodeV3() *schema.Resource {
 				ForceNew: true,
 				Elem:     &schema.Schema{Type: schema.TypeString},
 			},
+			"runtime":          resourceCCENodeRuntimeSchema(true),
+			"storage":          resourceCCENodeStorageSchema(true),
+			"kubelet":          resourceCCENodeKubeletSchema(true),
+			"docker_base_size": resourceCCENodeDockerBaseSizeSchema(true),
 			"private_ip": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -307,6 +315,13 @@ func resourceCCENodeV3() *schema.Resource {
 	}
 }
 
+func resourceCCENodeV3CustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if err := customizeDiffCCENodeOptions(ctx, d, meta); err != nil {
+		return err
+	}
+	return setTagsAllDiff(ctx, d, meta)
+}
+
 func resourceCCENodeAnnotationsV2(d *schema.ResourceData) map[string]string {
 	m := make(map[string]string)
 	for key, val := range d.Get("annotations").(map[string]interface{}) {
@@ -386,6 +401,12 @@ func resourceCCEExtendParam(d *schema.ResourceData) map[string]interface{} {
 	if v, ok := d.GetOk("postinstall"); ok {
 		extendParam["alpha.cce/postInstall"] = installScriptEncode(v.(string))
 	}
+	if v, ok := d.GetOk("docker_base_size"); ok {
+		extendParam["dockerBaseSize"] = v.(int)
+	}
+	for key, val := range buildCCENodeKubeletParams(d.Get("kubelet").([]interface{})) {
+		extendParam[key] = val
+	}
 
 	return extendParam
 }
@@ -421,8 +442,8 @@ func resourceCCENodeK8sTags(d *schema.ResourceData) map[string]string {
 	return m
 }
 
-func resourceCCENodeUserTags(d *schema.ResourceData) []tags.ResourceTag {
-	tagRaw := d.Get("tags").(map[string]interface{})
+func resourceCCENodeUserTags(d *schema.ResourceData, meta interface{}) []tags.ResourceTag {
+	tagRaw := getResourceTags(d, meta)
 	return expandResourceTags(tagRaw)
 }
 
@@ -448,9 +469,11 @@ func resourceCCENodeV3Create(d *schema.ResourceData, meta interface{}) error {
 			RootVolume:  resourceCCERootVolume(d),
 			DataVolumes: resourceCCEDataVolume(d),
 			ExtendParam: resourceCCEExtendParam(d),
-			UserTags:    resourceCCENodeUserTags(d),
+			UserTags:    resourceCCENodeUserTags(d, meta),
 			K8sTags:     resourceCCENodeK8sTags(d),
 			Taints:      resourceCCETaint(d),
+			RunTime:     buildCCENodeRuntime(d.Get("runtime").([]interface{})),
+			Storage:     buildCCENodeStorage(d.Get("storage").([]interface{})),
 			PublicIP: nodes.PublicIPSpec{
 				Ids:   resourceCCEEipIDs(d),
 				Count: d.Get("eip_count").(int),
@@ -479,11 +502,15 @@ func resourceCCENodeV3Create(d *schema.ResourceData, meta interface{}) error {
 		Target:       []string{"Available"},
 		Refresh:      waitForClusterAvailable(nodeClient, clusterid),
 		Timeout:      d.Timeout(schema.TimeoutCreate),
-		Delay:        5 * time.Second,
-		PollInterval: 5 * time.Second,
+		Delay:        stateRefreshDelay(5 * time.Second),
+		PollInterval: stateRefreshInterval(5 * time.Second),
 	}
 	_, err = stateCluster.WaitForState()
 
+	if err := checkCCENodeOptions(config, GetRegion(d, config), clusterid, d); err != nil {
+		return err
+	}
+
 	log.Printf("[DEBUG] Create Options: %#v", createOpts)
 	s, err := nodes.Create(nodeClient, clusterid, createOpts).Extract()
 	if err != nil {
@@ -511,8 +538,8 @@ func resourceCCENodeV3Create(d *schema.ResourceData, meta interface{}) error {
 		Target:       []string{"Active"},
 		Refresh:      waitForCceNodeActive(nodeClient, clusterid, nodeID),
 		Timeout:      d.Timeout(schema.TimeoutCreate),
-		Delay:        5 * time.Second,
-		PollInterval: 5 * time.Second,
+		Delay:        stateRefreshDelay(5 * time.Second),
+		PollInterval: stateRefreshInterval(5 * time.Second),
 	}
 	_, err = stateConf.WaitForState()
 	if err != nil {
@@ -594,9 +621,13 @@ func resourceCCENodeV3Read(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("Error saving labels/k8stags of cce node %s: %s", d.Id(), err)
 	}
 
+	if err := setCCENodeOptions(d, s.Spec); err != nil {
+		return fmt.Errorf("Error saving options of cce node %s: %s", d.Id(), err)
+	}
+
 	// fetch tags from ECS instance as Spec.UserTags is empty
 	if tagmap, err := expandResourceCCETagsByServer(computeClient, s.Status.ServerID); err == nil {
-		d.Set("tags", tagmap)
+		setResourceTags(d, meta, tagmap)
 	} else {
 		return err
 	}
@@ -624,7 +655,7 @@ func resourceCCENodeV3Update(d *schema.ResourceData, meta interface{}) error {
 	}
 
 	// update tags
-	if d.HasChange("tags") {
+	if d.HasChange("tags_all") {
 		computeClient, err := config.ComputeV1Client(GetRegion(d, config))
 		if err != nil {
 			return fmt.Errorf("Error creating Flexibleengine compute client: %s", err)
@@ -655,8 +686,8 @@ func resourceCCENodeV3Delete(d *schema.ResourceData, meta interface{}) error {
 		Target:       []string{"Deleted"},
 		Refresh:      waitForCceNodeDelete(nodeClient, clusterid, d.Id()),
 		Timeout:      d.Timeout(schema.TimeoutDelete),
-		Delay:        60 * time.Second,
-		PollInterval: 15 * time.Second,
+		Delay:        stateRefreshDelay(60 * time.Second),
+		PollInterval: stateRefreshInterval(15 * time.Second),
 	}
 
 	_, err = stateConf.WaitForState()
@@ -717,8 +748,8 @@ func recursiveCreate(cceClient *golangsdk.ServiceClient, opts nodes.CreateOptsBu
 			Target:       []string{"Available"},
 			Refresh:      waitForClusterAvailable(cceClient, ClusterID),
 			Timeout:      15 * time.Minute,
-			Delay:        5 * time.Second,
-			PollInterval: 5 * time.Second,
+			Delay:        stateRefreshDelay(5 * time.Second),
+			PollInterval: stateRefreshInterval(5 * time.Second),
 		}
 		_, stateErr := stateCluster.WaitForState()
 		if stateErr != nil {
@@ -857,8 +888,8 @@ func getResourceIDFromJob(client *golangsdk.ServiceClient, jobID, jobType, subJo
 		Target:       []string{"Success"},
 		Refresh:      waitForJobStatus(client, jobID),
 		Timeout:      timeout,
-		Delay:        120 * time.Second,
-		PollInterval: 20 * time.Second,
+		Delay:        stateRefreshDelay(120 * time.Second),
+		PollInterval: stateRefreshInterval(20 * time.Second),
 	}
 
 	v, err := stateJob.WaitForState()
@@ -84,6 +84,31 @@ func TestAccCCENodeV3_data_volume_encryption(t *testing.T) {
 	})
 }
 
+func TestAccCCENodeV3_options(t *testing.T) {
+	var node nodes.Nodes
+
+	rName := fmt.Sprintf("tf-acc-test-%s", acctest.RandString(5))
+	resourceName := "flexibleengine_cce_node_v3.node_1"
+	clusterName := "flexibleengine_cce_cluster_v3.cluster_1"
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccCCEKeyPairPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCCENodeV3Destroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCCENodeV3_options(rName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCCENodeV3Exists(resourceName, clusterName, &node),
+					resource.TestCheckResourceAttr(resourceName, "runtime.0.name", "docker"),
+					resource.TestCheckResourceAttr(resourceName, "docker_base_size", "20"),
+					resource.TestCheckResourceAttr(resourceName, "kubelet.0.image_gc_high_threshold", "85"),
+				),
+			},
+		},
+	})
+}
+
 func testAccCCENodeImportStateIdFunc() resource.ImportStateIdFunc {
 	return func(s *terraform.State) (string, error) {
 		cluster, ok := s.RootModule().Resources["flexibleengine_cce_cluster_v3.cluster_1"]
@@ -273,3 +298,45 @@ resource "flexibleengine_cce_node_v3" "node_1" {
 }
 `, testAccCCENodeV3_base(rName), rName, rName, OS_KEYPAIR_NAME)
 }
+
+func testAccCCENodeV3_options(rName string) string {
+	return fmt.Sprintf(`
+data "flexibleengine_availability_zones" "test" {}
+
+resource "flexibleengine_cce_cluster_v3" "cluster_1" {
+  name                   = "%s"
+  cluster_type           = "VirtualMachine"
+  cluster_version        = "v1.21"
+  flavor_id              = "cce.s1.small"
+  vpc_id                 = "%s"
+  subnet_id              = "%s"
+  container_network_type = "overlay_l2"
+}
+
+resource "flexibleengine_cce_node_v3" "node_1" {
+  cluster_id        = flexibleengine_cce_cluster_v3.cluster_1.id
+  name              = "%s"
+  flavor_id         = "s3.large.2"
+  availability_zone = data.flexibleengine_availability_zones.test.names[0]
+  key_pair          = "%s"
+  docker_base_size  = 20
+
+  root_volume {
+    size       = 40
+    volumetype = "SATA"
+  }
+  data_volumes {
+    size       = 100
+    volumetype = "SATA"
+  }
+
+  runtime {
+    name = "docker"
+  }
+
+  kubelet {
+    image_gc_high_threshold = 85
+    image_gc_low_threshold  = 80
+  }
+}`, rName, OS_VPC_ID, OS_NETWORK_ID, rName, OS_KEYPAIR_NAME)
+}
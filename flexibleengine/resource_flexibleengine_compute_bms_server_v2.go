@@ -5,19 +5,24 @@ import (
 	"encoding/hex"
 	"fmt"
 	"log"
+	"sort"
 	"time"
 
 	"github.com/chnsz/golangsdk"
 	bms "github.com/chnsz/golangsdk/openstack/bms/v2/servers"
-	"github.com/chnsz/golangsdk/openstack/bms/v2/tags"
+	bmstags "github.com/chnsz/golangsdk/openstack/bms/v2/tags"
+	"github.com/chnsz/golangsdk/openstack/common/tags"
 	"github.com/chnsz/golangsdk/openstack/compute/v2/extensions/bootfromvolume"
 	"github.com/chnsz/golangsdk/openstack/compute/v2/extensions/keypairs"
 	"github.com/chnsz/golangsdk/openstack/compute/v2/extensions/secgroups"
 	"github.com/chnsz/golangsdk/openstack/compute/v2/extensions/startstop"
+	"github.com/chnsz/golangsdk/openstack/compute/v2/extensions/volumeattach"
 	"github.com/chnsz/golangsdk/openstack/compute/v2/flavors"
 	"github.com/chnsz/golangsdk/openstack/compute/v2/servers"
+	"github.com/chnsz/golangsdk/openstack/evs/v2/cloudvolumes"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceComputeBMSInstanceV2() *schema.Resource {
@@ -26,6 +31,11 @@ func resourceComputeBMSInstanceV2() *schema.Resource {
 		Read:   resourceComputeBMSInstanceV2Read,
 		Update: resourceComputeBMSInstanceV2Update,
 		Delete: resourceComputeBMSInstanceV2Delete,
+		Importer: &schema.ResourceImporter{
+			State: resourceComputeBMSInstanceV2ImportState,
+		},
+
+		CustomizeDiff: setTagsAllDiff,
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(30 * time.Minute),
@@ -179,6 +189,8 @@ func resourceComputeBMSInstanceV2() *schema.Resource {
 				ForceNew: true,
 				Computed: true,
 			},
+			"tags":     tagsSchema(),
+			"tags_all": tagsAllSchema(),
 			"stop_before_destroy": {
 				Type:     schema.TypeBool,
 				Optional: true,
@@ -245,6 +257,19 @@ func resourceComputeBMSInstanceV2() *schema.Resource {
 							Optional: true,
 							ForceNew: true,
 						},
+						"disk_bus": {
+							Type:     schema.TypeString,
+							Optional: true,
+							ForceNew: true,
+							ValidateFunc: validation.StringInSlice([]string{
+								"virtio", "scsi", "ide",
+							}, false),
+						},
+						"device_type": {
+							Type:     schema.TypeString,
+							Optional: true,
+							ForceNew: true,
+						},
 						"device_name": {
 							Type:     schema.TypeString,
 							Optional: true,
@@ -276,12 +301,15 @@ func expandBmsInstanceNetworks(allInstanceNetworks []ServerNetwork) []servers.Ne
 	return networks
 }
 
+// bmsTypeTag is the tag which marks a server as a bare metal server
+const bmsTypeTag = "__type_baremetal"
+
 func bmsTagsCreate(client *golangsdk.ServiceClient, serverID string) error {
-	createOpts := tags.CreateOpts{
-		Tag: []string{"__type_baremetal"},
+	createOpts := bmstags.CreateOpts{
+		Tag: []string{bmsTypeTag},
 	}
 
-	_, err := tags.Create(client, serverID, createOpts).Extract()
+	_, err := bmstags.Create(client, serverID, createOpts).Extract()
 	if err != nil {
 		return fmt.Errorf("Error creating BMS Tags: %s", err)
 	}
@@ -387,8 +415,8 @@ func resourceComputeBMSInstanceV2Create(d *schema.ResourceData, meta interface{}
 		Target:     []string{"ACTIVE"},
 		Refresh:    computeV2StateRefreshFunc(bmsClient, server.ID),
 		Timeout:    d.Timeout(schema.TimeoutCreate),
-		Delay:      10 * time.Second,
-		MinTimeout: 3 * time.Second,
+		Delay:      stateRefreshDelay(10 * time.Second),
+		MinTimeout: stateRefreshInterval(3 * time.Second),
 	}
 
 	_, err = stateConf.WaitForState()
@@ -398,6 +426,19 @@ func resourceComputeBMSInstanceV2Create(d *schema.ResourceData, meta interface{}
 			server.ID, err)
 	}
 
+	//set tags
+	tagRaw := getResourceTags(d, meta)
+	if len(tagRaw) > 0 {
+		ecsClient, err := config.ComputeV1Client(region)
+		if err != nil {
+			return fmt.Errorf("Error creating FlexibleEngine compute v1 client: %s", err)
+		}
+		taglist := expandResourceTags(tagRaw)
+		if tagErr := tags.Create(ecsClient, "servers", d.Id(), taglist).ExtractErr(); tagErr != nil {
+			return fmt.Errorf("Error setting tags of BMS instance %s: %s", d.Id(), tagErr)
+		}
+	}
+
 	return resourceComputeBMSInstanceV2Read(d, meta)
 }
 
@@ -462,7 +503,14 @@ func resourceComputeBMSInstanceV2Read(d *schema.ResourceData, meta interface{})
 		})
 	}
 
-	d.Set("metadata", server.Metadata)
+	secGrpNames := []string{}
+	for _, sg := range server.SecurityGroups {
+		secGrpNames = append(secGrpNames, sg.Name)
+	}
+	d.Set("security_groups", secGrpNames)
+
+	d.Set("metadata", flattenComputeMetadataV2(server.Metadata))
+	d.Set("key_pair", server.KeyName)
 	d.Set("flavor_id", server.Flavor.ID)
 
 	flavor, err := flavors.Get(bmsClient, server.Flavor.ID).Extract()
@@ -484,6 +532,21 @@ func resourceComputeBMSInstanceV2Read(d *schema.ResourceData, meta interface{})
 	d.Set("user_id", server.UserID)
 	d.Set("region", region)
 
+	// save tags
+	ecsClient, err := config.ComputeV1Client(region)
+	if err != nil {
+		return fmt.Errorf("Error creating FlexibleEngine compute v1 client: %s", err)
+	}
+	resourceTags, err := tags.Get(ecsClient, "servers", d.Id()).Extract()
+	if err == nil {
+		tagmap := tagsToMap(resourceTags.Tags)
+		// the type tag is managed by the provider
+		delete(tagmap, bmsTypeTag)
+		setResourceTags(d, meta, tagmap)
+	} else {
+		log.Printf("[WARN] fetching BMS instance %s tags failed: %s", d.Id(), err)
+	}
+
 	return nil
 }
 
@@ -614,8 +677,8 @@ func resourceComputeBMSInstanceV2Update(d *schema.ResourceData, meta interface{}
 			Target:     []string{"VERIFY_RESIZE"},
 			Refresh:    computeV2StateRefreshFunc(computeClient, d.Id()),
 			Timeout:    d.Timeout(schema.TimeoutUpdate),
-			Delay:      10 * time.Second,
-			MinTimeout: 3 * time.Second,
+			Delay:      stateRefreshDelay(10 * time.Second),
+			MinTimeout: stateRefreshInterval(3 * time.Second),
 		}
 
 		_, err = stateConf.WaitForState()
@@ -635,8 +698,8 @@ func resourceComputeBMSInstanceV2Update(d *schema.ResourceData, meta interface{}
 			Target:     []string{"ACTIVE"},
 			Refresh:    computeV2StateRefreshFunc(computeClient, d.Id()),
 			Timeout:    d.Timeout(schema.TimeoutUpdate),
-			Delay:      10 * time.Second,
-			MinTimeout: 3 * time.Second,
+			Delay:      stateRefreshDelay(10 * time.Second),
+			MinTimeout: stateRefreshInterval(3 * time.Second),
 		}
 
 		_, err = stateConf.WaitForState()
@@ -645,6 +708,19 @@ func resourceComputeBMSInstanceV2Update(d *schema.ResourceData, meta interface{}
 		}
 	}
 
+	//update tags
+	if d.HasChange("tags_all") {
+		ecsClient, err := config.ComputeV1Client(GetRegion(d, config))
+		if err != nil {
+			return fmt.Errorf("Error creating FlexibleEngine compute v1 client: %s", err)
+		}
+
+		tagErr := UpdateResourceTags(ecsClient, d, "servers", d.Id())
+		if tagErr != nil {
+			return fmt.Errorf("Error updating tags of BMS instance %s: %s", d.Id(), tagErr)
+		}
+	}
+
 	return resourceComputeBMSInstanceV2Read(d, meta)
 }
 
@@ -665,8 +741,8 @@ func resourceComputeBMSInstanceV2Delete(d *schema.ResourceData, meta interface{}
 				Target:     []string{"SHUTOFF"},
 				Refresh:    computeV2StateRefreshFunc(computeClient, d.Id()),
 				Timeout:    3 * time.Minute,
-				Delay:      10 * time.Second,
-				MinTimeout: 3 * time.Second,
+				Delay:      stateRefreshDelay(10 * time.Second),
+				MinTimeout: stateRefreshInterval(3 * time.Second),
 			}
 			log.Printf("[DEBUG] Waiting for instance (%s) to stop", d.Id())
 			_, err = stopStateConf.WaitForState()
@@ -690,8 +766,8 @@ func resourceComputeBMSInstanceV2Delete(d *schema.ResourceData, meta interface{}
 		Target:     []string{"DELETED", "SOFT_DELETED"},
 		Refresh:    computeV2StateRefreshFunc(computeClient, d.Id()),
 		Timeout:    d.Timeout(schema.TimeoutDelete),
-		Delay:      10 * time.Second,
-		MinTimeout: 3 * time.Second,
+		Delay:      stateRefreshDelay(10 * time.Second),
+		MinTimeout: stateRefreshInterval(3 * time.Second),
 	}
 
 	_, err = stateConf.WaitForState()
@@ -704,3 +780,135 @@ func resourceComputeBMSInstanceV2Delete(d *schema.ResourceData, meta interface{}
 	d.SetId("")
 	return nil
 }
+
+func resourceComputeBMSInstanceV2ImportState(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	config := meta.(*Config)
+	region := GetRegion(d, config)
+	bmsClient, err := config.ComputeV2Client(region)
+	if err != nil {
+		return nil, fmt.Errorf("Error creating FlexibleEngine compute client: %s", err)
+	}
+
+	server, err := bms.Get(bmsClient, d.Id()).Extract()
+	if err != nil {
+		return nil, fmt.Errorf("Error retrieving FlexibleEngine BMS instance %s: %s", d.Id(), err)
+	}
+
+	networks, err := flattenBMSImportNetworks(d, meta, server)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[DEBUG] flatten BMS Instance Networks: %#v", networks)
+	if err := d.Set("network", networks); err != nil {
+		return nil, fmt.Errorf("Error setting network of BMS instance %s: %s", d.Id(), err)
+	}
+
+	blockDevices, bootImageID, err := flattenBMSImportBlockDevices(d, meta, server)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[DEBUG] flatten BMS Instance Block Devices: %#v", blockDevices)
+	if len(blockDevices) > 0 {
+		if err := d.Set("block_device", blockDevices); err != nil {
+			return nil, fmt.Errorf("Error setting block_device of BMS instance %s: %s", d.Id(), err)
+		}
+	}
+
+	// the image is not returned when the server boots from a volume, so take
+	// it from the image metadata of the boot volume
+	if server.Image.ID == "" && bootImageID != "" {
+		d.Set("image_id", bootImageID)
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// flattenBMSImportNetworks builds the network blocks of an imported server, the
+// network IDs are queried by the network names in the server addresses.
+func flattenBMSImportNetworks(d *schema.ResourceData, meta interface{}, server *bms.Server) ([]map[string]interface{}, error) {
+	allServerAddresses := getServerAddresses(server.Addresses)
+	sort.Slice(allServerAddresses, func(i, j int) bool {
+		return allServerAddresses[i].NetworkName < allServerAddresses[j].NetworkName
+	})
+
+	networks := []map[string]interface{}{}
+	for _, instanceAddresses := range allServerAddresses {
+		networkInfo, err := getInstanceNetworkInfo(d, meta, "name", instanceAddresses.NetworkName)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, instanceNIC := range instanceAddresses.ServerNICS {
+			networks = append(networks, map[string]interface{}{
+				"uuid":           networkInfo["uuid"],
+				"name":           instanceAddresses.NetworkName,
+				"fixed_ip_v4":    instanceNIC.IP,
+				"fixed_ip_v6":    instanceNIC.IPv6,
+				"mac":            instanceNIC.MAC,
+				"access_network": false,
+			})
+		}
+	}
+
+	return networks, nil
+}
+
+// flattenBMSImportBlockDevices builds the block_device blocks of an imported server
+// from the attached volumes, and returns the image ID of the boot volume.
+// The boot volume is described as created from its image, and the other volumes
+// are described as existing volumes.
+func flattenBMSImportBlockDevices(d *schema.ResourceData, meta interface{},
+	server *bms.Server) ([]map[string]interface{}, string, error) {
+	config := meta.(*Config)
+	region := GetRegion(d, config)
+	computeClient, err := config.ComputeV2Client(region)
+	if err != nil {
+		return nil, "", fmt.Errorf("Error creating FlexibleEngine compute client: %s", err)
+	}
+	blockStorageClient, err := config.BlockStorageV2Client(region)
+	if err != nil {
+		return nil, "", fmt.Errorf("Error creating FlexibleEngine block storage client: %s", err)
+	}
+
+	allPages, err := volumeattach.List(computeClient, server.ID).AllPages()
+	if err != nil {
+		return nil, "", fmt.Errorf("Error retrieving volume attachments of BMS instance %s: %s", server.ID, err)
+	}
+	attachments, err := volumeattach.ExtractVolumeAttachments(allPages)
+	if err != nil {
+		return nil, "", fmt.Errorf("Error extracting volume attachments of BMS instance %s: %s", server.ID, err)
+	}
+	sort.Slice(attachments, func(i, j int) bool {
+		return attachments[i].Device < attachments[j].Device
+	})
+
+	var bootImageID string
+	blockDevices := make([]map[string]interface{}, 0, len(attachments))
+	for _, attachment := range attachments {
+		volume, err := cloudvolumes.Get(blockStorageClient, attachment.VolumeID).Extract()
+		if err != nil {
+			return nil, "", fmt.Errorf("Error retrieving volume %s of BMS instance %s: %s", attachment.VolumeID, server.ID, err)
+		}
+
+		bd := map[string]interface{}{
+			"source_type":      "volume",
+			"uuid":             volume.ID,
+			"destination_type": "volume",
+			"boot_index":       -1,
+		}
+		if attachment.Device == server.RootDevicName && volume.ImageMetadata["image_id"] != "" {
+			bootImageID = volume.ImageMetadata["image_id"]
+			bd = map[string]interface{}{
+				"source_type":      "image",
+				"uuid":             bootImageID,
+				"destination_type": "volume",
+				"boot_index":       0,
+				"volume_size":      volume.Size,
+				"volume_type":      volume.VolumeType,
+			}
+		}
+		blockDevices = append(blockDevices, bd)
+	}
+
+	return blockDevices, bootImageID, nil
+}
@@ -23,6 +23,8 @@ func TestAccComputeV2BmsInstance_basic(t *testing.T) {
 					testAccCheckComputeV2BmsInstanceExists("flexibleengine_compute_bms_server_v2.instance_1", &instance),
 					resource.TestCheckResourceAttr(
 						"flexibleengine_compute_bms_server_v2.instance_1", "availability_zone", OS_AVAILABILITY_ZONE),
+					resource.TestCheckResourceAttr(
+						"flexibleengine_compute_bms_server_v2.instance_1", "tags.foo", "bar"),
 				),
 			},
 			{
@@ -31,8 +33,21 @@ func TestAccComputeV2BmsInstance_basic(t *testing.T) {
 					testAccCheckComputeV2BmsInstanceExists("flexibleengine_compute_bms_server_v2.instance_1", &instance),
 					resource.TestCheckResourceAttr(
 						"flexibleengine_compute_bms_server_v2.instance_1", "name", "instance_2"),
+					resource.TestCheckResourceAttr(
+						"flexibleengine_compute_bms_server_v2.instance_1", "metadata.foo", "baz"),
+					resource.TestCheckResourceAttr(
+						"flexibleengine_compute_bms_server_v2.instance_1", "tags.foo", "baz"),
 				),
 			},
+			{
+				ResourceName:      "flexibleengine_compute_bms_server_v2.instance_1",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateVerifyIgnore: []string{
+					"stop_before_destroy",
+					"admin_pass",
+				},
+			},
 		},
 	})
 }
@@ -102,6 +117,9 @@ resource "flexibleengine_compute_bms_server_v2" "instance_1" {
   metadata = {
     foo = "bar"
   }
+  tags = {
+    foo = "bar"
+  }
   network {
     uuid = "%s"
   }
@@ -116,7 +134,10 @@ resource "flexibleengine_compute_bms_server_v2" "instance_1" {
   security_groups   = ["default"]
   availability_zone = "%s"
   metadata = {
-    foo = "bar"
+    foo = "baz"
+  }
+  tags = {
+    foo = "baz"
   }
   network {
     uuid = "%s"
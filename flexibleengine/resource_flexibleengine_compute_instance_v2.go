@@ -2,11 +2,13 @@ package flexibleengine
 
 import (
 	"bytes"
+	"context"
 	"crypto/sha1"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/chnsz/golangsdk"
@@ -18,6 +20,10 @@ import (
 	"github.com/chnsz/golangsdk/openstack/compute/v2/flavors"
 	"github.com/chnsz/golangsdk/openstack/compute/v2/servers"
 	"github.com/chnsz/golangsdk/openstack/ecs/v1/cloudservers"
+	"github.com/chnsz/golangsdk/openstack/ecs/v1/powers"
+	"github.com/chnsz/golangsdk/openstack/evs/v2/cloudvolumes"
+	"github.com/chnsz/golangsdk/openstack/networking/v1/subnets"
+	"github.com/chnsz/golangsdk/openstack/networking/v2/extensions/security/groups"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -34,6 +40,8 @@ func resourceComputeInstanceV2() *schema.Resource {
 			State: resourceComputeInstanceV2ImportState,
 		},
 
+		CustomizeDiff: resourceComputeInstanceV2CustomizeDiff,
+
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(30 * time.Minute),
 			Update: schema.DefaultTimeout(30 * time.Minute),
@@ -180,6 +188,26 @@ func resourceComputeInstanceV2() *schema.Resource {
 				Optional: true,
 				ForceNew: true,
 			},
+			"system_disk_size": {
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"block_device"},
+			},
+			"system_disk_type": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"block_device"},
+			},
+			"system_disk_kms_key_id": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"block_device"},
+			},
 			"block_device": {
 				Type:     schema.TypeList,
 				Optional: true,
@@ -327,16 +355,29 @@ func resourceComputeInstanceV2() *schema.Resource {
 				Optional: true,
 				Default:  false,
 			},
+			"power_state": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice([]string{"ACTIVE", "SHUTOFF"}, false),
+			},
+			"power_action": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"REBOOT", "FORCE-REBOOT", "FORCE-OFF"}, false),
+			},
 			"auto_recovery": {
 				Type:     schema.TypeBool,
 				Optional: true,
 				Computed: true,
 			},
-			"tags": {
-				Type:     schema.TypeMap,
-				Optional: true,
-				Elem:     &schema.Schema{Type: schema.TypeString},
-			},
+			"tags":                  tagsSchema(),
+			"tags_all":              tagsAllSchema(),
+			"enterprise_project_id": enterpriseProjectSchema(),
+			"charging_mode":         chargingModeSchema(),
+			"period_unit":           periodUnitSchema(),
+			"period":                periodSchema(),
+			"auto_renew":            autoRenewSchema(),
 			"all_metadata": {
 				Type:     schema.TypeMap,
 				Computed: true,
@@ -405,8 +446,6 @@ func resourceComputeInstanceV2Create(d *schema.ResourceData, meta interface{}) e
 		return fmt.Errorf("Error creating FlexibleEngine image client: %s", err)
 	}
 
-	var createOpts servers.CreateOptsBuilder
-
 	imageId, err := getInstanceImageID(imsClient, d)
 	if err != nil {
 		return err
@@ -423,14 +462,102 @@ func resourceComputeInstanceV2Create(d *schema.ResourceData, meta interface{}) e
 		return err
 	}
 
+	var serverID string
+	if isPrePaid(d) {
+		serverID, err = createPrePaidComputeInstance(d, config, imsClient, imageId, flavorId)
+	} else {
+		serverID, err = createComputeInstanceV2(d, meta, computeClient, imsClient, imageId, flavorId)
+	}
+	if err != nil {
+		return err
+	}
+	log.Printf("[INFO] Instance ID: %s", serverID)
+
+	// Store the ID now
+	d.SetId(serverID)
+
+	// Wait for the instance to become running so we can get some attributes
+	// that aren't available until later.
+	log.Printf(
+		"[DEBUG] Waiting for instance (%s) to become running",
+		serverID)
+
+	stateConf := &resource.StateChangeConf{
+		Pending:    []string{"BUILD"},
+		Target:     []string{"ACTIVE"},
+		Refresh:    computeV2StateRefreshFunc(computeClient, serverID),
+		Timeout:    d.Timeout(schema.TimeoutCreate),
+		Delay:      stateRefreshDelay(10 * time.Second),
+		MinTimeout: stateRefreshInterval(3 * time.Second),
+	}
+
+	_, err = stateConf.WaitForState()
+	if err != nil {
+		return fmt.Errorf(
+			"Error waiting for instance (%s) to become ready: %s",
+			serverID, err)
+	}
+
+	if hasFilledOpt(d, "power_state") || hasFilledOpt(d, "power_action") {
+		if err := updateComputeInstancePowerState(d, config, computeClient, schema.TimeoutCreate); err != nil {
+			return err
+		}
+	}
+
+	if hasFilledOpt(d, "auto_recovery") {
+		ar := d.Get("auto_recovery").(bool)
+		log.Printf("[DEBUG] Set auto recovery of instance to %t", ar)
+		err = setAutoRecoveryForInstance(d, meta, serverID, ar)
+		if err != nil {
+			log.Printf("[WARN] Error setting auto recovery of instance:%s, err=%s", serverID, err)
+		}
+	}
+
+	if tagmap := getResourceTags(d, meta); len(tagmap) > 0 {
+		ecsClient, err := config.ComputeV1Client(GetRegion(d, config))
+		if err != nil {
+			return fmt.Errorf("Error creating FlexibleEngine compute v1 client: %s", err)
+		}
+
+		log.Printf("[DEBUG] Setting tags(key/value): %v", tagmap)
+		err = setTagsForInstance(ecsClient, serverID, tagmap)
+		if err != nil {
+			log.Printf("[WARN] Error setting tags(key/value) of instance:%s, err=%s", serverID, err)
+		}
+	}
+
+	// the pay-per-use instance is created in the default enterprise project by the nova API
+	epsID := config.GetEnterpriseProjectID(d)
+	if !isPrePaid(d) && epsID != "" && epsID != defaultEnterpriseProjectID {
+		err = migrateEnterpriseProject(config, region, epsID, epsResourceTypeECS, serverID, false)
+		if err != nil {
+			return err
+		}
+	}
+
+	return resourceComputeInstanceV2Read(d, meta)
+}
+
+// createComputeInstanceV2 creates the pay-per-use instance with the nova API
+func createComputeInstanceV2(d *schema.ResourceData, meta interface{}, computeClient, imsClient *golangsdk.ServiceClient,
+	imageId, flavorId string) (string, error) {
+	var createOpts servers.CreateOptsBuilder
+
 	// Build a []servers.Network to pass into the create options.
 	networks, err := expandInstanceNetworks(d, meta)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	configDrive := d.Get("config_drive").(bool)
 
+	metadata := resourceComputeMetadataV2(d)
+	if kmsID := d.Get("system_disk_kms_key_id").(string); kmsID != "" {
+		// the boot volume is encrypted with the system metadata
+		metadata[instanceMetadataEncrypted] = "1"
+		metadata[instanceMetadataCmkID] = kmsID
+	}
+
 	createOpts = &servers.CreateOpts{
 		Name:             d.Get("name").(string),
 		ImageRef:         imageId,
@@ -438,7 +565,7 @@ func resourceComputeInstanceV2Create(d *schema.ResourceData, meta interface{}) e
 		SecurityGroups:   resourceComputeSecGroupsV2(d),
 		AvailabilityZone: d.Get("availability_zone").(string),
 		Networks:         networks,
-		Metadata:         resourceComputeMetadataV2(d),
+		Metadata:         metadata,
 		ConfigDrive:      &configDrive,
 		AdminPass:        d.Get("admin_pass").(string),
 		UserData:         []byte(d.Get("user_data").(string)),
@@ -452,16 +579,29 @@ func resourceComputeInstanceV2Create(d *schema.ResourceData, meta interface{}) e
 		}
 	}
 
+	var bootFromVolume bool
 	if vL, ok := d.GetOk("block_device"); ok {
 		blockDevices, err := resourceInstanceBlockDevicesV2(d, vL.([]interface{}))
 		if err != nil {
-			return err
+			return "", err
 		}
 
 		createOpts = &bootfromvolume.CreateOptsExt{
 			CreateOptsBuilder: createOpts,
 			BlockDevice:       blockDevices,
 		}
+		bootFromVolume = true
+	} else if hasInstanceSystemDiskOpts(d) {
+		systemDisk, err := resourceInstanceSystemDiskV2(d, imsClient, imageId)
+		if err != nil {
+			return "", err
+		}
+
+		createOpts = &bootfromvolume.CreateOptsExt{
+			CreateOptsBuilder: createOpts,
+			BlockDevice:       systemDisk,
+		}
+		bootFromVolume = true
 	}
 
 	schedulerHintsRaw := d.Get("scheduler_hints").(*schema.Set).List()
@@ -476,69 +616,203 @@ func resourceComputeInstanceV2Create(d *schema.ResourceData, meta interface{}) e
 
 	log.Printf("[DEBUG] Create Options: %#v", createOpts)
 
-	// If a block_device is used, use the bootfromvolume.Create function as it allows an empty ImageRef.
-	// Otherwise, use the normal servers.Create function.
+	// If a block_device or the system disk is used, use the bootfromvolume.Create function as it
+	// allows an empty ImageRef. Otherwise, use the normal servers.Create function.
 	var server *servers.Server
-	if _, ok := d.GetOk("block_device"); ok {
+	if bootFromVolume {
 		server, err = bootfromvolume.Create(computeClient, createOpts).Extract()
 	} else {
 		server, err = servers.Create(computeClient, createOpts).Extract()
 	}
 
 	if err != nil {
-		return fmt.Errorf("Error creating FlexibleEngine server: %s", err)
+		return "", fmt.Errorf("Error creating FlexibleEngine server: %s", err)
 	}
-	log.Printf("[INFO] Instance ID: %s", server.ID)
+	return server.ID, nil
+}
 
-	// Store the ID now
-	d.SetId(server.ID)
+// createPrePaidComputeInstance places the order of the yearly/monthly instance with the ECS v1.1 API,
+// and returns the ID of the instance once the order is completed. The order is paid automatically.
+func createPrePaidComputeInstance(d *schema.ResourceData, config *Config, imsClient *golangsdk.ServiceClient,
+	imageId, flavorId string) (string, error) {
+	if _, ok := d.GetOk("block_device"); ok {
+		return "", fmt.Errorf("block_device is not supported in %s charging mode, use system_disk_size, "+
+			"system_disk_type and system_disk_kms_key_id instead", chargingModePrePaid)
+	}
+	if d.Get("personality").(*schema.Set).Len() > 0 {
+		return "", fmt.Errorf("personality is not supported in %s charging mode", chargingModePrePaid)
+	}
+	if d.Get("config_drive").(bool) {
+		return "", fmt.Errorf("config_drive is not supported in %s charging mode", chargingModePrePaid)
+	}
+	for _, raw := range d.Get("network").([]interface{}) {
+		if network, ok := raw.(map[string]interface{}); ok && network["access_network"].(bool) {
+			return "", fmt.Errorf("access_network is not supported in %s charging mode", chargingModePrePaid)
+		}
+	}
 
-	// Wait for the instance to become running so we can get some attributes
-	// that aren't available until later.
-	log.Printf(
-		"[DEBUG] Waiting for instance (%s) to become running",
-		server.ID)
+	region := GetRegion(d, config)
+	ecsClient, err := config.ComputeV11Client(region)
+	if err != nil {
+		return "", fmt.Errorf("Error creating FlexibleEngine compute v1.1 client: %s", err)
+	}
 
-	stateConf := &resource.StateChangeConf{
-		Pending:    []string{"BUILD"},
-		Target:     []string{"ACTIVE"},
-		Refresh:    computeV2StateRefreshFunc(computeClient, server.ID),
-		Timeout:    d.Timeout(schema.TimeoutCreate),
-		Delay:      10 * time.Second,
-		MinTimeout: 3 * time.Second,
+	prePaid, err := expandPrePaidOpts(d)
+	if err != nil {
+		return "", err
+	}
+	vpcID, nics, err := expandPrePaidInstanceNics(d, config)
+	if err != nil {
+		return "", err
+	}
+	secGroups, err := expandPrePaidInstanceSecGroups(d, config)
+	if err != nil {
+		return "", err
 	}
 
-	_, err = stateConf.WaitForState()
+	systemDiskSize, err := getInstanceSystemDiskSize(d, imsClient, imageId)
 	if err != nil {
-		return fmt.Errorf(
-			"Error waiting for instance (%s) to become ready: %s",
-			server.ID, err)
+		return "", err
+	}
+	rootVolume := cloudservers.RootVolume{
+		VolumeType: d.Get("system_disk_type").(string),
+		Size:       systemDiskSize,
+	}
+	if rootVolume.VolumeType == "" {
+		rootVolume.VolumeType = "SATA"
+	}
+	if kmsID := d.Get("system_disk_kms_key_id").(string); kmsID != "" {
+		rootVolume.Metadata = &cloudservers.VolumeMetadata{
+			SystemEncrypted: "1",
+			SystemCmkid:     kmsID,
+		}
 	}
 
-	if hasFilledOpt(d, "auto_recovery") {
-		ar := d.Get("auto_recovery").(bool)
-		log.Printf("[DEBUG] Set auto recovery of instance to %t", ar)
-		err = setAutoRecoveryForInstance(d, meta, server.ID, ar)
-		if err != nil {
-			log.Printf("[WARN] Error setting auto recovery of instance:%s, err=%s", server.ID, err)
+	createOpts := cloudservers.CreateOpts{
+		Name:             d.Get("name").(string),
+		ImageRef:         imageId,
+		FlavorRef:        flavorId,
+		AdminPass:        d.Get("admin_pass").(string),
+		KeyName:          d.Get("key_pair").(string),
+		VpcId:            vpcID,
+		Nics:             nics,
+		RootVolume:       rootVolume,
+		SecurityGroups:   secGroups,
+		AvailabilityZone: d.Get("availability_zone").(string),
+		ExtendParam: &cloudservers.ServerExtendParam{
+			ChargingMode:        chargingModePrePaid,
+			PeriodType:          prePaid.PeriodUnit,
+			PeriodNum:           prePaid.Period,
+			IsAutoRenew:         prePaid.AutoRenew,
+			IsAutoPay:           "true",
+			EnterpriseProjectId: config.GetEnterpriseProjectID(d),
+		},
+	}
+	if userData := d.Get("user_data").(string); userData != "" {
+		createOpts.UserData = []byte(userData)
+	}
+
+	schedulerHintsRaw := d.Get("scheduler_hints").(*schema.Set).List()
+	if len(schedulerHintsRaw) > 0 {
+		hints := schedulerHintsRaw[0].(map[string]interface{})
+		createOpts.SchedulerHints = &cloudservers.SchedulerHints{
+			Group:           hints["group"].(string),
+			Tenancy:         hints["tenancy"].(string),
+			DedicatedHostID: hints["deh_id"].(string),
 		}
 	}
 
-	if hasFilledOpt(d, "tags") {
-		ecsClient, err := config.ComputeV1Client(GetRegion(d, config))
+	log.Printf("[DEBUG] Create Options: %#v", createOpts)
+	order, err := cloudservers.CreatePrePaid(ecsClient, createOpts).ExtractOrderResponse()
+	if err != nil {
+		return "", fmt.Errorf("Error creating FlexibleEngine server: %s", err)
+	}
+
+	serverID, err := waitForOrderResource(d, config, order.OrderID)
+	if err != nil {
+		return "", err
+	}
+
+	// the metadata can not be specified by the ECS v1.1 API
+	if metadata := resourceComputeMetadataV2(d); len(metadata) > 0 {
+		computeClient, err := config.ComputeV2Client(region)
 		if err != nil {
-			return fmt.Errorf("Error creating FlexibleEngine compute v1 client: %s", err)
+			return "", fmt.Errorf("Error creating FlexibleEngine compute client: %s", err)
 		}
+		if _, err := servers.UpdateMetadata(computeClient, serverID, servers.MetadataOpts(metadata)).Extract(); err != nil {
+			return "", fmt.Errorf("Error setting the metadata of FlexibleEngine server (%s): %s", serverID, err)
+		}
+	}
+	return serverID, nil
+}
 
-		tagmap := d.Get("tags").(map[string]interface{})
-		log.Printf("[DEBUG] Setting tags(key/value): %v", tagmap)
-		err = setTagsForInstance(ecsClient, server.ID, tagmap)
-		if err != nil {
-			log.Printf("[WARN] Error setting tags(key/value) of instance:%s, err=%s", server.ID, err)
+// expandPrePaidInstanceNics returns the VPC and the subnets of the instance. The network.uuid is
+// the ID of the VPC subnet, and all the subnets must belong to the same VPC.
+func expandPrePaidInstanceNics(d *schema.ResourceData, config *Config) (string, []cloudservers.Nic, error) {
+	networks, err := expandInstanceNetworks(d, config)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(networks) == 0 {
+		return "", nil, fmt.Errorf("at least one network must be specified in %s charging mode", chargingModePrePaid)
+	}
+
+	vpcClient, err := config.NetworkingV1Client(GetRegion(d, config))
+	if err != nil {
+		return "", nil, fmt.Errorf("Error creating FlexibleEngine networking v1 client: %s", err)
+	}
+
+	var vpcID string
+	nics := make([]cloudservers.Nic, len(networks))
+	for i, network := range networks {
+		if network.Port != "" {
+			return "", nil, fmt.Errorf("network.port is not supported in %s charging mode", chargingModePrePaid)
+		}
+
+		if vpcID == "" {
+			subnet, err := subnets.Get(vpcClient, network.UUID).Extract()
+			if err != nil {
+				return "", nil, fmt.Errorf("Error retrieving FlexibleEngine VPC subnet %s: %s", network.UUID, err)
+			}
+			vpcID = subnet.VPC_ID
+		}
+		nics[i] = cloudservers.Nic{
+			SubnetId:  network.UUID,
+			IpAddress: network.FixedIP,
 		}
 	}
+	return vpcID, nics, nil
+}
 
-	return resourceComputeInstanceV2Read(d, meta)
+// expandPrePaidInstanceSecGroups converts the names of the security groups to the IDs
+func expandPrePaidInstanceSecGroups(d *schema.ResourceData, config *Config) ([]cloudservers.SecurityGroup, error) {
+	names := resourceComputeSecGroupsV2(d)
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	networkingClient, err := config.NetworkingV2Client(GetRegion(d, config))
+	if err != nil {
+		return nil, fmt.Errorf("Error creating FlexibleEngine networking client: %s", err)
+	}
+
+	secGroups := make([]cloudservers.SecurityGroup, len(names))
+	for i, name := range names {
+		pages, err := groups.List(networkingClient, groups.ListOpts{Name: name}).AllPages()
+		if err != nil {
+			return nil, fmt.Errorf("Error retrieving FlexibleEngine security group %s: %s", name, err)
+		}
+		allGroups, err := groups.ExtractGroups(pages)
+		if err != nil {
+			return nil, fmt.Errorf("Error extracting FlexibleEngine security group %s: %s", name, err)
+		}
+		if len(allGroups) != 1 {
+			return nil, fmt.Errorf("Error retrieving FlexibleEngine security group %s: %d security groups found",
+				name, len(allGroups))
+		}
+		secGroups[i] = cloudservers.SecurityGroup{ID: allGroups[0].ID}
+	}
+	return secGroups, nil
 }
 
 func resourceComputeInstanceV2Read(d *schema.ResourceData, meta interface{}) error {
@@ -565,6 +839,12 @@ func resourceComputeInstanceV2Read(d *schema.ResourceData, meta interface{}) err
 	d.Set("availability_zone", server.AvailabilityZone)
 	d.Set("name", server.Name)
 	d.Set("status", server.Status)
+	d.Set("enterprise_project_id", server.EnterpriseProjectID)
+	setChargingMode(d, server.Metadata.ChargingMode == "1")
+	// the transitional states are not reported as the power state
+	if server.Status == "ACTIVE" || server.Status == "SHUTOFF" {
+		d.Set("power_state", server.Status)
+	}
 
 	flavorInfo := server.Flavor
 	d.Set("flavor_id", flavorInfo.ID)
@@ -630,12 +910,17 @@ func resourceComputeInstanceV2Read(d *schema.ResourceData, meta interface{}) err
 
 	// Set volume attached
 	if len(server.VolumeAttached) > 0 {
-		volumes, rootID, err := flattenInstanceVolumeAttached(d, config, server)
+		volumes, systemDisk, err := flattenInstanceVolumeAttached(d, config, server)
 		if err != nil {
 			return nil
 		}
 		d.Set("volume_attached", volumes)
-		d.Set("system_disk_id", rootID)
+		if systemDisk != nil {
+			d.Set("system_disk_id", systemDisk.ID)
+			d.Set("system_disk_size", systemDisk.Size)
+			d.Set("system_disk_type", systemDisk.VolumeType)
+			d.Set("system_disk_kms_key_id", systemDisk.Metadata[instanceMetadataCmkID])
+		}
 	}
 
 	// set scheduler_hints
@@ -681,7 +966,7 @@ func resourceComputeInstanceV2Read(d *schema.ResourceData, meta interface{}) err
 	if err != nil {
 		return CheckDeleted(d, err, "server")
 	}
-	d.Set("metadata", novaResp.Metadata)
+	d.Set("metadata", flattenComputeMetadataV2(novaResp.Metadata))
 
 	ar, err := resourceECSAutoRecoveryV1Read(d, meta, d.Id())
 	if err != nil && !isResourceNotFound(err) {
@@ -693,7 +978,9 @@ func resourceComputeInstanceV2Read(d *schema.ResourceData, meta interface{}) err
 	if err != nil && !isResourceNotFound(err) {
 		return fmt.Errorf("Error reading tags of instance:%s, err=%s", d.Id(), err)
 	}
-	d.Set("tags", tags)
+	if err := setResourceTags(d, meta, tags); err != nil {
+		return fmt.Errorf("Error saving tags of instance:%s, err=%s", d.Id(), err)
+	}
 
 	return nil
 }
@@ -825,8 +1112,8 @@ func resourceComputeInstanceV2Update(d *schema.ResourceData, meta interface{}) e
 			Target:     []string{"VERIFY_RESIZE"},
 			Refresh:    computeV2StateRefreshFunc(computeClient, d.Id()),
 			Timeout:    d.Timeout(schema.TimeoutUpdate),
-			Delay:      10 * time.Second,
-			MinTimeout: 3 * time.Second,
+			Delay:      stateRefreshDelay(10 * time.Second),
+			MinTimeout: stateRefreshInterval(3 * time.Second),
 		}
 
 		_, err = stateConf.WaitForState()
@@ -841,13 +1128,14 @@ func resourceComputeInstanceV2Update(d *schema.ResourceData, meta interface{}) e
 			return fmt.Errorf("Error confirming resize of FlexibleEngine server: %s", err)
 		}
 
+		// the stopped instance is still stopped after resizing
 		stateConf = &resource.StateChangeConf{
 			Pending:    []string{"VERIFY_RESIZE"},
-			Target:     []string{"ACTIVE"},
+			Target:     []string{"ACTIVE", "SHUTOFF"},
 			Refresh:    computeV2StateRefreshFunc(computeClient, d.Id()),
 			Timeout:    d.Timeout(schema.TimeoutUpdate),
-			Delay:      10 * time.Second,
-			MinTimeout: 3 * time.Second,
+			Delay:      stateRefreshDelay(10 * time.Second),
+			MinTimeout: stateRefreshInterval(3 * time.Second),
 		}
 
 		_, err = stateConf.WaitForState()
@@ -856,6 +1144,18 @@ func resourceComputeInstanceV2Update(d *schema.ResourceData, meta interface{}) e
 		}
 	}
 
+	if d.HasChange("system_disk_size") {
+		if err := extendComputeInstanceSystemDisk(d, config); err != nil {
+			return err
+		}
+	}
+
+	if d.HasChanges("power_state", "power_action") {
+		if err := updateComputeInstancePowerState(d, config, computeClient, schema.TimeoutUpdate); err != nil {
+			return err
+		}
+	}
+
 	if d.HasChange("auto_recovery") {
 		ar := d.Get("auto_recovery").(bool)
 		log.Printf("[DEBUG] Update auto recovery of instance to %t", ar)
@@ -865,8 +1165,19 @@ func resourceComputeInstanceV2Update(d *schema.ResourceData, meta interface{}) e
 		}
 	}
 
-	if d.HasChange("tags") {
-		oRaw, nRaw := d.GetChange("tags")
+	if err := updateEnterpriseProject(d, config, epsResourceTypeECS, d.Id(), false); err != nil {
+		return err
+	}
+
+	err = updateChargingMode(d, config, d.Id(), func(opts *prePaidOpts) (string, error) {
+		return changeComputeInstanceToPeriod(d, config, opts)
+	})
+	if err != nil {
+		return err
+	}
+
+	if d.HasChange("tags_all") {
+		oRaw, nRaw := d.GetChange("tags_all")
 		oMap := oRaw.(map[string]interface{})
 		nMap := nRaw.(map[string]interface{})
 
@@ -912,8 +1223,8 @@ func resourceComputeInstanceV2Delete(d *schema.ResourceData, meta interface{}) e
 				Target:     []string{"SHUTOFF"},
 				Refresh:    computeV2StateRefreshFunc(computeClient, d.Id()),
 				Timeout:    d.Timeout(schema.TimeoutDelete),
-				Delay:      10 * time.Second,
-				MinTimeout: 3 * time.Second,
+				Delay:      stateRefreshDelay(10 * time.Second),
+				MinTimeout: stateRefreshInterval(3 * time.Second),
 			}
 			log.Printf("[DEBUG] Waiting for instance (%s) to stop", d.Id())
 			_, err = stopStateConf.WaitForState()
@@ -923,10 +1234,27 @@ func resourceComputeInstanceV2Delete(d *schema.ResourceData, meta interface{}) e
 		}
 	}
 
-	log.Printf("[DEBUG] Deleting FlexibleEngine Instance %s", d.Id())
-	err = servers.Delete(computeClient, d.Id()).ExtractErr()
+	ecsClient, err := config.ComputeV1Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating FlexibleEngine compute V1 client: %s", err)
+	}
+	server, err := cloudservers.Get(ecsClient, d.Id()).Extract()
 	if err != nil {
-		return fmt.Errorf("Error deleting FlexibleEngine server: %s", err)
+		return CheckDeleted(d, err, "server")
+	}
+
+	log.Printf("[DEBUG] Deleting FlexibleEngine Instance %s", d.Id())
+	if server.Metadata.ChargingMode == "1" {
+		// the yearly/monthly instance can only be deleted by unsubscribing, even if
+		// it is waiting to be switched to pay-per-use billing
+		if err := unsubscribePrePaidResource(d, config, []string{d.Id()}); err != nil {
+			return err
+		}
+	} else {
+		err = servers.Delete(computeClient, d.Id()).ExtractErr()
+		if err != nil {
+			return fmt.Errorf("Error deleting FlexibleEngine server: %s", err)
+		}
 	}
 
 	// Wait for the instance to delete before moving on.
@@ -937,8 +1265,8 @@ func resourceComputeInstanceV2Delete(d *schema.ResourceData, meta interface{}) e
 		Target:     []string{"DELETED", "SOFT_DELETED"},
 		Refresh:    computeV2StateRefreshFunc(computeClient, d.Id()),
 		Timeout:    d.Timeout(schema.TimeoutDelete),
-		Delay:      10 * time.Second,
-		MinTimeout: 3 * time.Second,
+		Delay:      stateRefreshDelay(10 * time.Second),
+		MinTimeout: stateRefreshInterval(3 * time.Second),
 	}
 
 	_, err = stateConf.WaitForState()
@@ -952,6 +1280,238 @@ func resourceComputeInstanceV2Delete(d *schema.ResourceData, meta interface{}) e
 	return nil
 }
 
+// resourceComputeInstanceV2CustomizeDiff rejects shrinking the system disk as EVS can only extend volumes,
+// and plans tags_all with the default tags of provider.
+func resourceComputeInstanceV2CustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if err := validatePeriodDiff(d); err != nil {
+		return err
+	}
+	if d.Id() != "" && d.HasChange("system_disk_size") {
+		oldSize, newSize := d.GetChange("system_disk_size")
+		if newSize.(int) < oldSize.(int) {
+			return fmt.Errorf("system_disk_size can not be shrunk from %d to %d GB", oldSize.(int), newSize.(int))
+		}
+	}
+	if err := validatePowerActionDiff(d); err != nil {
+		return err
+	}
+	return setTagsAllAndEnterpriseProjectDiff(ctx, d, meta)
+}
+
+// validatePowerActionDiff rejects the power_action which conflicts with power_state, the instance would
+// never settle otherwise: FORCE-OFF requires power_state to be SHUTOFF, and the reboots can not be used with it.
+func validatePowerActionDiff(d *schema.ResourceDiff) error {
+	action := d.Get("power_action").(string)
+	if action == "" {
+		return nil
+	}
+
+	// power_state is computed, only the configured value is checked
+	state := d.Get("power_state").(string)
+	if rawConfig := d.GetRawConfig(); !rawConfig.IsNull() {
+		v := rawConfig.GetAttr("power_state")
+		if !v.IsKnown() {
+			return nil
+		}
+		state = ""
+		if !v.IsNull() {
+			state = v.AsString()
+		}
+	}
+
+	if action == "FORCE-OFF" && state != "SHUTOFF" {
+		return fmt.Errorf("power_state must be SHUTOFF when power_action is FORCE-OFF")
+	}
+	if action != "FORCE-OFF" && state == "SHUTOFF" {
+		return fmt.Errorf("power_action %s can not be used when power_state is SHUTOFF", action)
+	}
+	return nil
+}
+
+// extendComputeInstanceSystemDisk extends the boot volume online, the instance keeps running
+func extendComputeInstanceSystemDisk(d *schema.ResourceData, config *Config) error {
+	systemDiskID := d.Get("system_disk_id").(string)
+	if systemDiskID == "" {
+		return fmt.Errorf("Error extending the system disk of FlexibleEngine server (%s): the system disk is not found", d.Id())
+	}
+
+	blockStorageClient, err := config.BlockStorageV2Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating FlexibleEngine block storage client: %s", err)
+	}
+
+	extendOpts := cloudvolumes.ExtendOpts{
+		SizeOpts: cloudvolumes.ExtendSizeOpts{
+			NewSize: d.Get("system_disk_size").(int),
+		},
+	}
+	if isPrePaid(d) {
+		// the system disk of the yearly/monthly instance is extended by an order
+		extendOpts.ChargeInfo = &cloudvolumes.ExtendChargeOpts{
+			IsAutoPay: "true",
+		}
+	}
+	log.Printf("[DEBUG] Extending system disk %s of instance %s: %#v", systemDiskID, d.Id(), extendOpts)
+	job, err := cloudvolumes.ExtendSize(blockStorageClient, systemDiskID, extendOpts).Extract()
+	if err != nil {
+		return fmt.Errorf("Error extending the system disk of FlexibleEngine server (%s): %s", d.Id(), err)
+	}
+
+	if job.OrderID != "" {
+		bssClient, err := config.BssV2Client(GetRegion(d, config))
+		if err != nil {
+			return fmt.Errorf("Error creating FlexibleEngine BSS client: %s", err)
+		}
+		if err := waitForOrderComplete(bssClient, job.OrderID, d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return err
+		}
+	}
+
+	stateConf := &resource.StateChangeConf{
+		Pending:    []string{"extending"},
+		Target:     []string{"available", "in-use"},
+		Refresh:    VolumeV2StateRefreshFunc(blockStorageClient, systemDiskID),
+		Timeout:    d.Timeout(schema.TimeoutUpdate),
+		Delay:      stateRefreshDelay(10 * time.Second),
+		MinTimeout: stateRefreshInterval(3 * time.Second),
+	}
+
+	_, err = stateConf.WaitForState()
+	if err != nil {
+		return fmt.Errorf("Error waiting for the system disk of instance (%s) to be extended: %s", d.Id(), err)
+	}
+	return nil
+}
+
+type changeComputeChargeModeOpts struct {
+	ChargingMode string `json:"charging_mode" required:"true"`
+	PeriodType   string `json:"period_type" required:"true"`
+	PeriodNum    int    `json:"period_num" required:"true"`
+	IsAutoRenew  bool   `json:"is_auto_renew"`
+	IsAutoPay    bool   `json:"is_auto_pay"`
+}
+
+// changeComputeInstanceToPeriod places the order to change the pay-per-use instance and its
+// system disk to yearly/monthly billing, and returns the ID of the order.
+func changeComputeInstanceToPeriod(d *schema.ResourceData, config *Config, opts *prePaidOpts) (string, error) {
+	ecsClient, err := config.ComputeV1Client(GetRegion(d, config))
+	if err != nil {
+		return "", fmt.Errorf("Error creating FlexibleEngine compute v1 client: %s", err)
+	}
+
+	changeOpts := changeComputeChargeModeOpts{
+		ChargingMode: chargingModePrePaid,
+		PeriodType:   opts.PeriodUnit,
+		PeriodNum:    opts.Period,
+		IsAutoRenew:  opts.AutoRenew == "true",
+		IsAutoPay:    true,
+	}
+	reqBody, err := golangsdk.BuildRequestBody(changeOpts, "")
+	if err != nil {
+		return "", err
+	}
+
+	var rst struct {
+		OrderID string `json:"order_id"`
+	}
+	log.Printf("[DEBUG] Changing the charging mode of instance %s: %#v", d.Id(), changeOpts)
+	_, err = ecsClient.Post(ecsClient.ServiceURL("cloudservers", d.Id(), "changechargemode"), reqBody, &rst,
+		&golangsdk.RequestOpts{OkCodes: []int{200}})
+	if err != nil {
+		return "", err
+	}
+	return rst.OrderID, nil
+}
+
+// updateComputeInstancePowerState starts or stops the instance if the power state is changed,
+// and then runs the power action if it is changed.
+func updateComputeInstancePowerState(d *schema.ResourceData, config *Config, computeClient *golangsdk.ServiceClient,
+	timeout string) error {
+	ecsClient, err := config.ComputeV1Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating FlexibleEngine compute v1 client: %s", err)
+	}
+
+	// FORCE-OFF requires power_state to be SHUTOFF, it replaces the graceful stop
+	forceOff := d.Get("power_action").(string) == "FORCE-OFF" && d.HasChange("power_action")
+
+	// the power state is computed, it is changed only if it is specified in the configuration
+	if state := d.Get("power_state").(string); d.HasChange("power_state") && state != "" {
+		server, err := servers.Get(computeClient, d.Id()).Extract()
+		if err != nil {
+			return fmt.Errorf("Error retrieving FlexibleEngine server %s: %s", d.Id(), err)
+		}
+
+		var action string
+		if state == "ACTIVE" && server.Status == "SHUTOFF" {
+			action = "ON"
+		} else if state == "SHUTOFF" && server.Status == "ACTIVE" {
+			action = "OFF"
+			if forceOff {
+				action = "FORCE-OFF"
+			}
+		}
+		if action != "" {
+			if err := doComputeInstancePowerAction(d, ecsClient, computeClient, action, timeout); err != nil {
+				return err
+			}
+		}
+	}
+
+	// the new instance does not need to be rebooted, and the instance is already stopped by FORCE-OFF
+	action := d.Get("power_action").(string)
+	if action == "" || action == "FORCE-OFF" || !d.HasChange("power_action") || d.IsNewResource() {
+		return nil
+	}
+	return doComputeInstancePowerAction(d, ecsClient, computeClient, action, timeout)
+}
+
+// doComputeInstancePowerAction starts, stops or reboots the instance through the ECS API,
+// which supports the forced stop and reboot, and waits for the instance to be stable.
+func doComputeInstancePowerAction(d *schema.ResourceData, ecsClient, computeClient *golangsdk.ServiceClient,
+	action, timeout string) error {
+	var option, powerType string
+	var pending, target []string
+	switch action {
+	case "ON":
+		option, pending, target = "os-start", []string{"SHUTOFF"}, []string{"ACTIVE"}
+	case "OFF", "FORCE-OFF":
+		option, pending, target = "os-stop", []string{"ACTIVE"}, []string{"SHUTOFF"}
+	case "REBOOT", "FORCE-REBOOT":
+		option, pending, target = "reboot", []string{"REBOOT", "HARD_REBOOT"}, []string{"ACTIVE"}
+	default:
+		return fmt.Errorf("unsupported power action: %s", action)
+	}
+	if option != "os-start" {
+		powerType = "SOFT"
+		if strings.HasPrefix(action, "FORCE-") {
+			powerType = "HARD"
+		}
+	}
+
+	opts := powers.PowerOpts{
+		Servers: []powers.ServerInfo{{ID: d.Id()}},
+		Type:    powerType,
+	}
+	log.Printf("[DEBUG] Running the power action %s of instance %s", action, d.Id())
+	if _, err := powers.PowerAction(ecsClient, opts, option).ExtractJobResponse(); err != nil {
+		return fmt.Errorf("Error running the power action %s of instance %s: %s", action, d.Id(), err)
+	}
+
+	stateConf := &resource.StateChangeConf{
+		Pending:    pending,
+		Target:     target,
+		Refresh:    computeV2StateRefreshFunc(computeClient, d.Id()),
+		Timeout:    d.Timeout(timeout),
+		Delay:      stateRefreshDelay(10 * time.Second),
+		MinTimeout: stateRefreshInterval(3 * time.Second),
+	}
+	if _, err := stateConf.WaitForState(); err != nil {
+		return fmt.Errorf("Error waiting for instance (%s) to be %s: %s", d.Id(), target[0], err)
+	}
+	return nil
+}
+
 func resourceComputeInstanceV2ImportState(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	config := meta.(*Config)
 	ecsClient, err := config.ComputeV1Client(GetRegion(d, config))
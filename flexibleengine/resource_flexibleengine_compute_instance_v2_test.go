@@ -31,6 +31,8 @@ func TestAccComputeV2Instance_basic(t *testing.T) {
 					resource.TestCheckResourceAttr(resourceName, "availability_zone", OS_AVAILABILITY_ZONE),
 					resource.TestCheckResourceAttr(resourceName, "tags.key1", "value1"),
 					resource.TestCheckResourceAttr(resourceName, "tags.key2", "value.key"),
+					resource.TestCheckResourceAttr(resourceName, "tags_all.key1", "value1"),
+					resource.TestCheckResourceAttr(resourceName, "tags_all.key2", "value.key"),
 				),
 			},
 			{
@@ -232,6 +234,90 @@ func TestAccComputeV2Instance_stopBeforeDestroy(t *testing.T) {
 	})
 }
 
+func TestAccComputeV2Instance_powerState(t *testing.T) {
+	var instance servers.Server
+	resourceName := "flexibleengine_compute_instance_v2.instance_1"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckComputeV2InstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccComputeV2Instance_powerState("SHUTOFF"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeV2InstanceExists(resourceName, &instance),
+					resource.TestCheckResourceAttr(resourceName, "power_state", "SHUTOFF"),
+				),
+			},
+			{
+				Config: testAccComputeV2Instance_powerState("ACTIVE"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeV2InstanceExists(resourceName, &instance),
+					resource.TestCheckResourceAttr(resourceName, "power_state", "ACTIVE"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccComputeV2Instance_systemDisk(t *testing.T) {
+	var instance servers.Server
+	resourceName := "flexibleengine_compute_instance_v2.instance_1"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckComputeV2InstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccComputeV2Instance_systemDisk(50),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeV2InstanceExists(resourceName, &instance),
+					resource.TestCheckResourceAttr(resourceName, "system_disk_size", "50"),
+					resource.TestCheckResourceAttr(resourceName, "system_disk_type", "SSD"),
+					resource.TestCheckResourceAttrSet(resourceName, "system_disk_id"),
+				),
+			},
+			{
+				Config: testAccComputeV2Instance_systemDisk(60),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeV2InstanceExists(resourceName, &instance),
+					resource.TestCheckResourceAttr(resourceName, "system_disk_size", "60"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccComputeV2Instance_prePaid(t *testing.T) {
+	var instance servers.Server
+	resourceName := "flexibleengine_compute_instance_v2.instance_1"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheckChargingMode(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckComputeV2InstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccComputeV2Instance_prePaid(false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeV2InstanceExists(resourceName, &instance),
+					resource.TestCheckResourceAttr(resourceName, "charging_mode", "prePaid"),
+					resource.TestCheckResourceAttr(resourceName, "auto_renew", "false"),
+				),
+			},
+			{
+				Config: testAccComputeV2Instance_prePaid(true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeV2InstanceExists(resourceName, &instance),
+					resource.TestCheckResourceAttr(resourceName, "auto_renew", "true"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccComputeV2Instance_metadataRemove(t *testing.T) {
 	var instance servers.Server
 
@@ -772,6 +858,50 @@ resource "flexibleengine_compute_instance_v2" "instance_1" {
 }
 `, OS_NETWORK_ID)
 
+func testAccComputeV2Instance_powerState(state string) string {
+	return fmt.Sprintf(`
+resource "flexibleengine_compute_instance_v2" "instance_1" {
+  name = "instance_1"
+  security_groups = ["default"]
+  network {
+    uuid = "%s"
+  }
+  power_state = "%s"
+}
+`, OS_NETWORK_ID, state)
+}
+
+func testAccComputeV2Instance_systemDisk(size int) string {
+	return fmt.Sprintf(`
+resource "flexibleengine_compute_instance_v2" "instance_1" {
+  name = "instance_1"
+  security_groups = ["default"]
+  system_disk_size = %d
+  system_disk_type = "SSD"
+  network {
+    uuid = "%s"
+  }
+}
+`, size, OS_NETWORK_ID)
+}
+
+func testAccComputeV2Instance_prePaid(autoRenew bool) string {
+	return fmt.Sprintf(`
+resource "flexibleengine_compute_instance_v2" "instance_1" {
+  name = "instance_1"
+  security_groups = ["default"]
+  availability_zone = "%s"
+  charging_mode = "prePaid"
+  period_unit = "month"
+  period = 1
+  auto_renew = "%t"
+  network {
+    uuid = "%s"
+  }
+}
+`, OS_AVAILABILITY_ZONE, autoRenew, OS_NETWORK_ID)
+}
+
 var testAccComputeV2Instance_metadataRemove_1 = fmt.Sprintf(`
 resource "flexibleengine_compute_instance_v2" "instance_1" {
   name = "instance_1"
@@ -38,7 +38,15 @@ func resourceCssClusterV1() *schema.Resource {
 			Update: schema.DefaultTimeout(60 * time.Minute),
 		},
 
+		CustomizeDiff: setTagsAllAndEnterpriseProjectDiff,
+
 		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
 			"name": {
 				Type:     schema.TypeString,
 				Required: true,
@@ -164,7 +172,9 @@ func resourceCssClusterV1() *schema.Resource {
 				},
 			},
 
-			"tags": tagsSchema(),
+			"tags":                  tagsSchema(),
+			"tags_all":              tagsAllSchema(),
+			"enterprise_project_id": enterpriseProjectSchema(),
 
 			"created": {
 				Type:     schema.TypeString,
@@ -220,6 +230,9 @@ func resourceCssClusterV1Create(d *schema.ResourceData, meta interface{}) error
 	}
 
 	opts := resourceCssClusterV1UserInputParams(d)
+	// the tags sent on creation should include the default tags
+	opts["tags"] = getResourceTags(d, meta)
+	opts["enterprise_project_id"] = config.GetEnterpriseProjectID(d)
 	arrayIndex := map[string]int{
 		"node_config.network_info": 0,
 		"node_config.volume":       0,
@@ -287,6 +300,7 @@ func resourceCssClusterV1Read(d *schema.ResourceData, meta interface{}) error {
 	if err := setCssClusterV1Properties(d, res); err != nil {
 		return err
 	}
+	d.Set("region", GetRegion(d, config))
 
 	// set backup strategy property
 	policy, err := snapshots.PolicyGet(client, d.Id()).Extract()
@@ -316,7 +330,7 @@ func resourceCssClusterV1Read(d *schema.ResourceData, meta interface{}) error {
 	}
 
 	tagmap := tagsToMap(resourceTags.Tags)
-	if err := d.Set("tags", tagmap); err != nil {
+	if err := setResourceTags(d, meta, tagmap); err != nil {
 		return fmt.Errorf("[DEBUG] Error saving tag to state for CSS cluster (%s): %s", d.Id(), err)
 	}
 
@@ -391,7 +405,11 @@ func resourceCssClusterV1Update(d *schema.ResourceData, meta interface{}) error
 		}
 	}
 
-	if d.HasChange("tags") {
+	if err := updateEnterpriseProject(d, config, epsResourceTypeCSS, d.Id(), false); err != nil {
+		return err
+	}
+
+	if d.HasChange("tags_all") {
 		tagErr := UpdateResourceTags(client, d, "css-cluster", d.Id())
 		if tagErr != nil {
 			return fmt.Errorf("Error updating tags of CSS cluster:%s, err:%s", d.Id(), tagErr)
@@ -497,6 +515,10 @@ func buildCssClusterV1CreateParameters(opts map[string]interface{}, arrayIndex m
 		params["tags"] = tags
 	}
 
+	if epsID, ok := opts["enterprise_project_id"].(string); ok && epsID != "" {
+		params["enterprise_project_id"] = epsID
+	}
+
 	if len(params) == 0 {
 		return params, nil
 	}
@@ -848,6 +870,10 @@ func fillCssClusterV1ReadRespBody(body interface{}) interface{} {
 		result["security_mode"] = v
 	}
 
+	if v, ok := val["enterpriseProjectId"]; ok {
+		result["enterprise_project_id"] = v
+	}
+
 	if v, ok := val["status"]; ok {
 		result["status"] = v
 	} else {
@@ -985,6 +1011,13 @@ func setCssClusterV1Properties(d *schema.ResourceData, response map[string]inter
 		}
 	}
 
+	v, err = navigateValue(response, []string{"read", "enterprise_project_id"}, nil)
+	if err == nil {
+		if err = d.Set("enterprise_project_id", v); err != nil {
+			return fmt.Errorf("Error setting Cluster:enterprise_project_id, err: %s", err)
+		}
+	}
+
 	v, _ = opts["nodes"]
 	v, err = flattenCssClusterV1Nodes(response, nil, v)
 	if err != nil {
@@ -22,7 +22,7 @@ func resourceDcsInstanceV1() *schema.Resource {
 		Update: resourceDcsInstancesV1Update,
 		Delete: resourceDcsInstancesV1Delete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: importStatePassthroughWithRegion,
 		},
 
 		Timeouts: &schema.ResourceTimeout{
@@ -30,7 +30,15 @@ func resourceDcsInstanceV1() *schema.Resource {
 			Delete: schema.DefaultTimeout(15 * time.Minute),
 		},
 
+		CustomizeDiff: setEnterpriseProjectDiff,
+
 		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
 			"name": {
 				Type:     schema.TypeString,
 				Required: true,
@@ -182,6 +190,7 @@ func resourceDcsInstanceV1() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"enterprise_project_id": enterpriseProjectSchema(),
 		},
 	}
 }
@@ -281,6 +290,8 @@ func resourceDcsInstancesV1Create(d *schema.ResourceData, meta interface{}) erro
 		MaintainBegin:    d.Get("maintain_begin").(string),
 		MaintainEnd:      d.Get("maintain_end").(string),
 		Port:             d.Get("port").(int),
+
+		EnterpriseProjectID: config.GetEnterpriseProjectID(d),
 	}
 
 	product_id, product_ok := d.GetOk("product_id")
@@ -314,8 +325,8 @@ func resourceDcsInstancesV1Create(d *schema.ResourceData, meta interface{}) erro
 		Target:     []string{"RUNNING"},
 		Refresh:    DcsInstancesV1StateRefreshFunc(dcsV1Client, v.InstanceID),
 		Timeout:    d.Timeout(schema.TimeoutCreate),
-		Delay:      10 * time.Second,
-		MinTimeout: 3 * time.Second,
+		Delay:      stateRefreshDelay(10 * time.Second),
+		MinTimeout: stateRefreshInterval(3 * time.Second),
 	}
 	_, err = stateConf.WaitForState()
 	if err != nil {
@@ -350,6 +361,7 @@ func resourceDcsInstancesV1Read(d *schema.ResourceData, meta interface{}) error
 	}
 
 	d.SetId(v.InstanceID)
+	d.Set("region", GetRegion(d, config))
 	d.Set("name", v.Name)
 	d.Set("engine", v.Engine)
 	d.Set("engine_version", v.EngineVersion)
@@ -372,6 +384,7 @@ func resourceDcsInstancesV1Read(d *schema.ResourceData, meta interface{}) error
 	d.Set("maintain_end", v.MaintainEnd)
 	d.Set("access_user", v.AccessUser)
 	d.Set("available_zones", v.AvailableZones)
+	d.Set("enterprise_project_id", v.EnterpriseProjectID)
 
 	// set capacity by Capacity and CapacityMinor
 	var capacity float64 = float64(v.Capacity)
@@ -422,6 +435,10 @@ func resourceDcsInstancesV1Update(d *schema.ResourceData, meta interface{}) erro
 		return fmt.Errorf("Error updating FlexibleEngine Dcs Instance: %s", err)
 	}
 
+	if err := updateEnterpriseProject(d, config, epsResourceTypeDCS, d.Id(), false); err != nil {
+		return err
+	}
+
 	return resourceDcsInstancesV1Read(d, meta)
 }
 
@@ -450,8 +467,8 @@ func resourceDcsInstancesV1Delete(d *schema.ResourceData, meta interface{}) erro
 		Target:     []string{"DELETED"},
 		Refresh:    DcsInstancesV1StateRefreshFunc(dcsV1Client, d.Id()),
 		Timeout:    d.Timeout(schema.TimeoutDelete),
-		Delay:      10 * time.Second,
-		MinTimeout: 3 * time.Second,
+		Delay:      stateRefreshDelay(10 * time.Second),
+		MinTimeout: stateRefreshInterval(3 * time.Second),
 	}
 
 	_, err = stateConf.WaitForState()
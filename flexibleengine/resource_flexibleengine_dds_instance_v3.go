@@ -27,6 +27,8 @@ func resourceDdsInstanceV3() *schema.Resource {
 			Delete: schema.DefaultTimeout(30 * time.Minute),
 		},
 
+		CustomizeDiff: setTagsAllAndEnterpriseProjectDiff,
+
 		Schema: map[string]*schema.Schema{
 			"region": {
 				Type:     schema.TypeString,
@@ -178,7 +180,9 @@ func resourceDdsInstanceV3() *schema.Resource {
 				Default:  true,
 				ForceNew: true,
 			},
-			"tags": tagsSchema(),
+			"tags":                  tagsSchema(),
+			"tags_all":              tagsAllSchema(),
+			"enterprise_project_id": enterpriseProjectSchema(),
 			"db_username": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -323,6 +327,8 @@ func resourceDdsInstanceV3Create(d *schema.ResourceData, meta interface{}) error
 		Mode:             d.Get("mode").(string),
 		Flavor:           resourceDdsFlavors(d),
 		BackupStrategy:   resourceDdsBackupStrategy(d),
+
+		EnterpriseProjectID: config.GetEnterpriseProjectID(d),
 	}
 	if d.Get("ssl").(bool) {
 		createOpts.Ssl = "1"
@@ -355,7 +361,7 @@ func resourceDdsInstanceV3Create(d *schema.ResourceData, meta interface{}) error
 	}
 
 	//set tags
-	tagRaw := d.Get("tags").(map[string]interface{})
+	tagRaw := getResourceTags(d, meta)
 	if len(tagRaw) > 0 {
 		taglist := expandResourceTags(tagRaw)
 		if tagErr := tags.Create(client, "instances", instance.Id, taglist).ExtractErr(); tagErr != nil {
@@ -411,6 +417,7 @@ func resourceDdsInstanceV3Read(d *schema.ResourceData, meta interface{}) error {
 		d.Set("db_username", instance.DbUserName),
 		d.Set("status", instance.Status),
 		d.Set("ssl", sslEnable),
+		d.Set("enterprise_project_id", instance.EnterpriseProjectID),
 	)
 	if err := mErr.ErrorOrNil(); err != nil {
 		return err
@@ -449,7 +456,7 @@ func resourceDdsInstanceV3Read(d *schema.ResourceData, meta interface{}) error {
 	// save tags
 	if resourceTags, err := tags.Get(client, "instances", d.Id()).Extract(); err == nil {
 		tagmap := tagsToMap(resourceTags.Tags)
-		if err := d.Set("tags", tagmap); err != nil {
+		if err := setResourceTags(d, meta, tagmap); err != nil {
 			return fmt.Errorf("Error saving tags to state for DDS instance (%s): %s", d.Id(), err)
 		}
 	} else {
@@ -466,7 +473,11 @@ func resourceDdsInstanceV3Update(d *schema.ResourceData, meta interface{}) error
 		return fmt.Errorf("Error creating FlexibleEngine DDS client: %s ", err)
 	}
 
-	if d.HasChange("tags") {
+	if err := updateEnterpriseProject(d, config, epsResourceTypeDDS, d.Id(), false); err != nil {
+		return err
+	}
+
+	if d.HasChange("tags_all") {
 		tagErr := UpdateResourceTags(client, d, "instances", d.Id())
 		if tagErr != nil {
 			return fmt.Errorf("Error updating tags of DDS instance:%s, err:%s", d.Id(), tagErr)
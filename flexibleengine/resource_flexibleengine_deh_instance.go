@@ -0,0 +1,294 @@
+package flexibleengine
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/chnsz/golangsdk"
+	"github.com/chnsz/golangsdk/openstack/common/tags"
+	"github.com/chnsz/golangsdk/openstack/deh/v1/hosts"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// dehTagType is the resource type of dedicated hosts in the tag APIs
+const dehTagType = "dedicated-host-tags"
+
+func resourceDeHInstance() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceDeHInstanceCreate,
+		Read:   resourceDeHInstanceRead,
+		Update: resourceDeHInstanceUpdate,
+		Delete: resourceDeHInstanceDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+
+		CustomizeDiff: setTagsAllDiff,
+
+		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"availability_zone": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"host_type": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"auto_placement": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "on",
+				ValidateFunc: validation.StringInSlice([]string{"on", "off"}, false),
+			},
+			"tags":     tagsSchema(),
+			"tags_all": tagsAllSchema(),
+
+			"host_type_name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"vcpus": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"cores": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"sockets": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"memory": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"available_vcpus": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"available_memory": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"available_flavors": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"instance_total": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"instance_uuids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"allocated_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceDeHInstanceCreate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	dehClient, err := dehV1Client(config, GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating FlexibleEngine DeH client: %s", err)
+	}
+
+	allocateOpts := hosts.AllocateOpts{
+		Name:          d.Get("name").(string),
+		Az:            d.Get("availability_zone").(string),
+		HostType:      d.Get("host_type").(string),
+		AutoPlacement: d.Get("auto_placement").(string),
+		Quantity:      1,
+	}
+
+	log.Printf("[DEBUG] Allocate DeH options: %#v", allocateOpts)
+	allocated, err := hosts.Allocate(dehClient, allocateOpts).ExtractHost()
+	if err != nil {
+		return fmt.Errorf("Error allocating FlexibleEngine DeH instance: %s", err)
+	}
+	if len(allocated.AllocatedHostIds) == 0 {
+		return fmt.Errorf("Error allocating FlexibleEngine DeH instance: no host ID returned")
+	}
+
+	d.SetId(allocated.AllocatedHostIds[0])
+
+	stateConf := &resource.StateChangeConf{
+		Pending:    []string{"creating"},
+		Target:     []string{"available"},
+		Refresh:    dehInstanceStateRefreshFunc(dehClient, d.Id()),
+		Timeout:    d.Timeout(schema.TimeoutCreate),
+		Delay:      stateRefreshDelay(10 * time.Second),
+		MinTimeout: stateRefreshInterval(3 * time.Second),
+	}
+	if _, err := stateConf.WaitForState(); err != nil {
+		return fmt.Errorf("Error waiting for DeH instance %s to become available: %s", d.Id(), err)
+	}
+
+	// set tags
+	tagRaw := getResourceTags(d, meta)
+	if len(tagRaw) > 0 {
+		taglist := expandResourceTags(tagRaw)
+		if tagErr := tags.Create(dehClient, dehTagType, d.Id(), taglist).ExtractErr(); tagErr != nil {
+			return fmt.Errorf("Error setting tags of DeH instance %s: %s", d.Id(), tagErr)
+		}
+	}
+
+	return resourceDeHInstanceRead(d, meta)
+}
+
+func resourceDeHInstanceRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	region := GetRegion(d, config)
+	dehClient, err := dehV1Client(config, region)
+	if err != nil {
+		return fmt.Errorf("Error creating FlexibleEngine DeH client: %s", err)
+	}
+
+	host, err := hosts.Get(dehClient, d.Id()).Extract()
+	if err != nil {
+		return CheckDeleted(d, err, "DeH instance")
+	}
+	if host.State == "released" {
+		log.Printf("[WARN] DeH instance %s has been released, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	log.Printf("[DEBUG] Retrieved DeH instance %s: %#v", d.Id(), host)
+
+	d.Set("region", region)
+	d.Set("name", host.Name)
+	d.Set("availability_zone", host.Az)
+	d.Set("auto_placement", host.AutoPlacement)
+	d.Set("status", host.State)
+	d.Set("available_vcpus", host.AvailableVcpus)
+	d.Set("available_memory", host.AvailableMemory)
+	d.Set("instance_total", host.InstanceTotal)
+	d.Set("instance_uuids", host.InstanceUuids)
+	d.Set("allocated_at", host.AllocatedAt)
+	setDeHHostProperties(d, &host.HostProperties)
+
+	// save tags
+	if resourceTags, err := tags.Get(dehClient, dehTagType, d.Id()).Extract(); err == nil {
+		tagmap := tagsToMap(resourceTags.Tags)
+		if err := setResourceTags(d, meta, tagmap); err != nil {
+			return fmt.Errorf("Error saving tags of DeH instance %s: %s", d.Id(), err)
+		}
+	} else {
+		log.Printf("[WARN] Error fetching tags of DeH instance %s: %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+func setDeHHostProperties(d *schema.ResourceData, properties *hosts.HostPropertiesOpts) {
+	flavors := make([]string, len(properties.InstanceCapacities))
+	for i, capacity := range properties.InstanceCapacities {
+		flavors[i] = capacity.Flavor
+	}
+
+	d.Set("host_type", properties.HostType)
+	d.Set("host_type_name", properties.HostTypeName)
+	d.Set("vcpus", properties.Vcpus)
+	d.Set("cores", properties.Cores)
+	d.Set("sockets", properties.Sockets)
+	d.Set("memory", properties.Memory)
+	d.Set("available_flavors", flavors)
+}
+
+func resourceDeHInstanceUpdate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	dehClient, err := dehV1Client(config, GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating FlexibleEngine DeH client: %s", err)
+	}
+
+	if d.HasChanges("name", "auto_placement") {
+		updateOpts := hosts.UpdateOpts{
+			Name:          d.Get("name").(string),
+			AutoPlacement: d.Get("auto_placement").(string),
+		}
+		if err := hosts.Update(dehClient, d.Id(), updateOpts).Err; err != nil {
+			return fmt.Errorf("Error updating FlexibleEngine DeH instance %s: %s", d.Id(), err)
+		}
+	}
+
+	if d.HasChange("tags_all") {
+		if err := UpdateResourceTags(dehClient, d, dehTagType, d.Id()); err != nil {
+			return fmt.Errorf("Error updating tags of DeH instance %s: %s", d.Id(), err)
+		}
+	}
+
+	return resourceDeHInstanceRead(d, meta)
+}
+
+func resourceDeHInstanceDelete(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	dehClient, err := dehV1Client(config, GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating FlexibleEngine DeH client: %s", err)
+	}
+
+	if err := hosts.Delete(dehClient, d.Id()).Err; err != nil {
+		return CheckDeleted(d, err, "DeH instance")
+	}
+
+	stateConf := &resource.StateChangeConf{
+		Pending:    []string{"available", "fault"},
+		Target:     []string{"released", "deleted"},
+		Refresh:    dehInstanceStateRefreshFunc(dehClient, d.Id()),
+		Timeout:    d.Timeout(schema.TimeoutDelete),
+		Delay:      stateRefreshDelay(10 * time.Second),
+		MinTimeout: stateRefreshInterval(3 * time.Second),
+	}
+	if _, err := stateConf.WaitForState(); err != nil {
+		return fmt.Errorf("Error waiting for DeH instance %s to be released: %s", d.Id(), err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func dehInstanceStateRefreshFunc(client *golangsdk.ServiceClient, id string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		host, err := hosts.Get(client, id).Extract()
+		if err != nil {
+			if isResourceNotFound(err) {
+				return host, "deleted", nil
+			}
+			return nil, "", err
+		}
+		return host, host.State, nil
+	}
+}
@@ -0,0 +1,124 @@
+package flexibleengine
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/chnsz/golangsdk/openstack/deh/v1/hosts"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccDeHInstance_basic(t *testing.T) {
+	var host hosts.Host
+	rName := fmt.Sprintf("tf-acc-test-%s", acctest.RandString(5))
+	resourceName := "flexibleengine_deh_instance.test"
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDeHInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDeHInstance_basic(rName, "on", "bar"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDeHInstanceExists(resourceName, &host),
+					resource.TestCheckResourceAttr(resourceName, "name", rName),
+					resource.TestCheckResourceAttr(resourceName, "auto_placement", "on"),
+					resource.TestCheckResourceAttr(resourceName, "status", "available"),
+					resource.TestCheckResourceAttr(resourceName, "tags.foo", "bar"),
+					resource.TestCheckResourceAttrPair(resourceName, "host_type",
+						"data.flexibleengine_deh_host_types.test", "host_types.0.host_type"),
+					resource.TestCheckResourceAttrSet(resourceName, "vcpus"),
+					resource.TestCheckResourceAttrSet(resourceName, "memory"),
+				),
+			},
+			{
+				Config: testAccDeHInstance_basic(rName+"-update", "off", "baz"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDeHInstanceExists(resourceName, &host),
+					resource.TestCheckResourceAttr(resourceName, "name", rName+"-update"),
+					resource.TestCheckResourceAttr(resourceName, "auto_placement", "off"),
+					resource.TestCheckResourceAttr(resourceName, "tags.foo", "baz"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckDeHInstanceDestroy(s *terraform.State) error {
+	config := testAccProvider.Meta().(*Config)
+	dehClient, err := dehV1Client(config, OS_REGION_NAME)
+	if err != nil {
+		return fmt.Errorf("Error creating FlexibleEngine DeH client: %s", err)
+	}
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "flexibleengine_deh_instance" {
+			continue
+		}
+
+		host, err := hosts.Get(dehClient, rs.Primary.ID).Extract()
+		if err == nil && host.State != "released" {
+			return fmt.Errorf("DeH instance still exists")
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckDeHInstanceExists(n string, host *hosts.Host) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		config := testAccProvider.Meta().(*Config)
+		dehClient, err := dehV1Client(config, OS_REGION_NAME)
+		if err != nil {
+			return fmt.Errorf("Error creating FlexibleEngine DeH client: %s", err)
+		}
+
+		found, err := hosts.Get(dehClient, rs.Primary.ID).Extract()
+		if err != nil {
+			return err
+		}
+
+		if found.ID != rs.Primary.ID {
+			return fmt.Errorf("DeH instance not found")
+		}
+
+		*host = *found
+		return nil
+	}
+}
+
+func testAccDeHInstance_basic(rName, autoPlacement, tag string) string {
+	return fmt.Sprintf(`
+data "flexibleengine_deh_host_types" "test" {
+  availability_zone = "%s"
+}
+
+resource "flexibleengine_deh_instance" "test" {
+  name              = "%s"
+  availability_zone = "%s"
+  host_type         = data.flexibleengine_deh_host_types.test.host_types[0].host_type
+  auto_placement    = "%s"
+
+  tags = {
+    foo = "%s"
+  }
+}
+`, OS_AVAILABILITY_ZONE, rName, OS_AVAILABILITY_ZONE, autoPlacement, tag)
+}
@@ -11,7 +11,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 
-	"github.com/chnsz/golangsdk/openstack/common/tags"
 	"github.com/chnsz/golangsdk/openstack/dli/v1/queues"
 )
 
@@ -37,6 +36,8 @@ func ResourceDliQueueV1() *schema.Resource {
 			State: schema.ImportStatePassthrough,
 		},
 
+		CustomizeDiff: setTagsAllDiff,
+
 		Timeouts: &schema.ResourceTimeout{
 			Default: schema.DefaultTimeout(10 * time.Minute),
 		},
@@ -86,6 +87,14 @@ func ResourceDliQueueV1() *schema.Resource {
 				ForceNew: true,
 			},
 
+			// the tags of a queue can not be updated, the queue is replaced when the default tags change
+			"tags_all": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				ForceNew: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+
 			"create_time": {
 				Type:     schema.TypeInt,
 				Computed: true,
@@ -112,7 +121,7 @@ func resourceDliQueueCreate(d *schema.ResourceData, meta interface{}) error {
 		Description:  d.Get("description").(string),
 		CuCount:      d.Get("cu_count").(int),
 		ResourceMode: d.Get("resource_mode").(int),
-		Tags:         assembleTagsFromRecource("tags", d),
+		Tags:         expandResourceTags(getResourceTags(d, meta)),
 	}
 
 	log.Printf("[DEBUG] create dli queues using paramaters: %+v", createOpts)
@@ -129,16 +138,6 @@ func resourceDliQueueCreate(d *schema.ResourceData, meta interface{}) error {
 	return resourceDliQueueRead(d, meta)
 }
 
-func assembleTagsFromRecource(key string, d *schema.ResourceData) []tags.ResourceTag {
-	if v, ok := d.GetOk(key); ok {
-		tagRaw := v.(map[string]interface{})
-		taglist := expandResourceTags(tagRaw)
-		return taglist
-	}
-	return nil
-
-}
-
 func resourceDliQueueRead(d *schema.ResourceData, meta interface{}) error {
 	config := meta.(*Config)
 	region := GetRegion(d, config)
@@ -31,6 +31,7 @@ func TestAccDliQueueV1_basic(t *testing.T) {
 					resource.TestCheckResourceAttr(resourceName, "cu_count", "16"),
 					resource.TestCheckResourceAttrSet(resourceName, "resource_mode"),
 					resource.TestCheckResourceAttrSet(resourceName, "create_time"),
+					resource.TestCheckResourceAttr(resourceName, "tags_all.%", "2"),
 				),
 			},
 			{
@@ -49,7 +50,7 @@ func TestAccDliQueueV1_basic(t *testing.T) {
 				ImportState:       true,
 				ImportStateVerify: true,
 				ImportStateVerifyIgnore: []string{
-					"tags",
+					"tags", "tags_all",
 				},
 			},
 		},
@@ -31,6 +31,8 @@ func resourceDNSPtrRecordV2() *schema.Resource {
 			Delete: schema.DefaultTimeout(10 * time.Minute),
 		},
 
+		CustomizeDiff: setTagsAllDiff,
+
 		Schema: map[string]*schema.Schema{
 			"region": {
 				Type:     schema.TypeString,
@@ -57,7 +59,8 @@ func resourceDNSPtrRecordV2() *schema.Resource {
 				Default:      300,
 				ValidateFunc: validation.IntBetween(1, 2147483647),
 			},
-			"tags": tagsSchema(),
+			"tags":     tagsSchema(),
+			"tags_all": tagsAllSchema(),
 			"address": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -74,7 +77,7 @@ func resourceDNSPtrRecordV2Create(d *schema.ResourceData, meta interface{}) erro
 		return fmt.Errorf("Error creating FlexibleEngine DNS client: %s", err)
 	}
 
-	tagmap := d.Get("tags").(map[string]interface{})
+	tagmap := getResourceTags(d, meta)
 	taglist := []ptrrecords.Tag{}
 	for k, v := range tagmap {
 		tag := ptrrecords.Tag{
@@ -151,7 +154,7 @@ func resourceDNSPtrRecordV2Read(d *schema.ResourceData, meta interface{}) error
 	resourceTags, err := tags.Get(dnsClient, "DNS-ptr_record", d.Id()).Extract()
 	if err == nil {
 		tagmap := tagsToMap(resourceTags.Tags)
-		d.Set("tags", tagmap)
+		setResourceTags(d, meta, tagmap)
 	} else {
 		log.Printf("[WARN] Error fetching FlexibleEngine DNS ptr record tags: %s", err)
 	}
@@ -32,6 +32,8 @@ func resourceDNSRecordSetV2() *schema.Resource {
 			Delete: schema.DefaultTimeout(10 * time.Minute),
 		},
 
+		CustomizeDiff: setTagsAllDiff,
+
 		Schema: map[string]*schema.Schema{
 			"region": {
 				Type:     schema.TypeString,
@@ -79,7 +81,8 @@ func resourceDNSRecordSetV2() *schema.Resource {
 				ForceNew: true,
 				Elem:     &schema.Schema{Type: schema.TypeString},
 			},
-			"tags": tagsSchema(),
+			"tags":     tagsSchema(),
+			"tags_all": tagsAllSchema(),
 		},
 	}
 }
@@ -143,7 +146,7 @@ func resourceDNSRecordSetV2Create(d *schema.ResourceData, meta interface{}) erro
 	}
 
 	// set tags
-	tagRaw := d.Get("tags").(map[string]interface{})
+	tagRaw := getResourceTags(d, meta)
 	if len(tagRaw) > 0 {
 		resourceType, err := getDNSRecordSetTagType(zoneType)
 		if err != nil {
@@ -200,7 +203,7 @@ func resourceDNSRecordSetV2Read(d *schema.ResourceData, meta interface{}) error
 		resourceTags, err := tags.Get(dnsClient, resourceType, recordsetID).Extract()
 		if err == nil {
 			tagmap := tagsToMap(resourceTags.Tags)
-			d.Set("tags", tagmap)
+			setResourceTags(d, meta, tagmap)
 		} else {
 			log.Printf("[WARN] Error fetching FlexibleEngine DNS record set tags: %s", err)
 		}
@@ -30,6 +30,8 @@ func resourceDNSZoneV2() *schema.Resource {
 			Delete: schema.DefaultTimeout(10 * time.Minute),
 		},
 
+		CustomizeDiff: setTagsAllDiff,
+
 		Schema: map[string]*schema.Schema{
 			"region": {
 				Type:     schema.TypeString,
@@ -95,7 +97,8 @@ func resourceDNSZoneV2() *schema.Resource {
 				Computed: true,
 				Elem:     &schema.Schema{Type: schema.TypeString},
 			},
-			"tags": tagsSchema(),
+			"tags":     tagsSchema(),
+			"tags_all": tagsAllSchema(),
 		},
 	}
 }
@@ -210,7 +213,7 @@ func resourceDNSZoneV2Create(d *schema.ResourceData, meta interface{}) error {
 	}
 
 	// set tags
-	tagRaw := d.Get("tags").(map[string]interface{})
+	tagRaw := getResourceTags(d, meta)
 	if len(tagRaw) > 0 {
 		resourceType, err := getDNSZoneTagType(zoneType)
 		if err != nil {
@@ -256,7 +259,7 @@ func resourceDNSZoneV2Read(d *schema.ResourceData, meta interface{}) error {
 		resourceTags, err := tags.Get(dnsClient, resourceType, d.Id()).Extract()
 		if err == nil {
 			tagmap := tagsToMap(resourceTags.Tags)
-			d.Set("tags", tagmap)
+			setResourceTags(d, meta, tagmap)
 		} else {
 			log.Printf("[WARN] Error fetching FlexibleEngine DNS zone tags: %s", err)
 		}
@@ -0,0 +1,472 @@
+package flexibleengine
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chnsz/golangsdk"
+	"github.com/chnsz/golangsdk/openstack/imageservice/v2/images"
+	"github.com/chnsz/golangsdk/openstack/ims/v2/cloudimages"
+	imstags "github.com/chnsz/golangsdk/openstack/ims/v2/tags"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceImagesImage() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceImagesImageCreate,
+		Read:   resourceImagesImageRead,
+		Update: resourceImagesImageUpdate,
+		Delete: resourceImagesImageDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(3 * time.Minute),
+		},
+
+		CustomizeDiff: setTagsAllDiff,
+
+		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			// the image is created from an ECS, an EVS volume or a file in an OBS bucket
+			"instance_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"instance_id", "volume_id", "image_url"},
+			},
+			"volume_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"image_url": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				RequiredWith: []string{"min_disk"},
+			},
+			// the following arguments are only valid for the image created from an OBS file
+			"min_disk": {
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"instance_id", "volume_id"},
+			},
+			"os_version": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"instance_id", "volume_id"},
+			},
+			"is_config": {
+				Type:          schema.TypeBool,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"instance_id", "volume_id"},
+			},
+			"cmk_id": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"instance_id", "volume_id"},
+			},
+			"type": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"instance_id", "volume_id"},
+				ValidateFunc: validation.StringInSlice([]string{
+					"ECS", "FusionCompute", "BMS", "Ironic",
+				}, false),
+			},
+			"max_ram": {
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"volume_id"},
+			},
+			"min_ram": {
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"volume_id"},
+			},
+			"tags":     tagsSchema(),
+			"tags_all": tagsAllSchema(),
+			"enterprise_project_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"visibility": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"image_type": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"data_origin": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"disk_format": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"image_size": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"checksum": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// createDataImageByVolumeOpts is used to create a data disk image from an EVS volume
+// attached to an ECS, which is not supported by the SDK yet.
+type createDataImageByVolumeOpts struct {
+	Name                string                 `json:"name" required:"true"`
+	Description         string                 `json:"description,omitempty"`
+	VolumeID            string                 `json:"volume_id" required:"true"`
+	Type                string                 `json:"type" required:"true"`
+	ImageTags           []cloudimages.ImageTag `json:"image_tags,omitempty"`
+	EnterpriseProjectID string                 `json:"enterprise_project_id,omitempty"`
+}
+
+func (opts createDataImageByVolumeOpts) ToImageCreateMap() (map[string]interface{}, error) {
+	return golangsdk.BuildRequestBody(opts, "")
+}
+
+func expandImagesImageTags(tagmap map[string]interface{}) []cloudimages.ImageTag {
+	taglist := make([]cloudimages.ImageTag, 0, len(tagmap))
+	for k, v := range tagmap {
+		taglist = append(taglist, cloudimages.ImageTag{
+			Key:   k,
+			Value: v.(string),
+		})
+	}
+	return taglist
+}
+
+func resourceImagesImageCreate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	imsClient, err := config.ImageV2Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating FlexibleEngine image client: %s", err)
+	}
+
+	var createOpts cloudimages.CreateOptsBuilder
+	imageTags := expandImagesImageTags(getResourceTags(d, meta))
+	if v, ok := d.GetOk("instance_id"); ok {
+		createOpts = cloudimages.CreateByServerOpts{
+			Name:                d.Get("name").(string),
+			Description:         d.Get("description").(string),
+			InstanceId:          v.(string),
+			MaxRam:              d.Get("max_ram").(int),
+			MinRam:              d.Get("min_ram").(int),
+			ImageTags:           imageTags,
+			EnterpriseProjectID: config.GetEnterpriseProjectID(d),
+		}
+	} else if v, ok := d.GetOk("volume_id"); ok {
+		createOpts = createDataImageByVolumeOpts{
+			Name:                d.Get("name").(string),
+			Description:         d.Get("description").(string),
+			VolumeID:            v.(string),
+			Type:                "DataImage",
+			ImageTags:           imageTags,
+			EnterpriseProjectID: config.GetEnterpriseProjectID(d),
+		}
+	} else {
+		createOpts = cloudimages.CreateByOBSOpts{
+			Name:                d.Get("name").(string),
+			Description:         d.Get("description").(string),
+			ImageUrl:            d.Get("image_url").(string),
+			MinDisk:             d.Get("min_disk").(int),
+			OsVersion:           d.Get("os_version").(string),
+			IsConfig:            d.Get("is_config").(bool),
+			CmkId:               d.Get("cmk_id").(string),
+			Type:                d.Get("type").(string),
+			MaxRam:              d.Get("max_ram").(int),
+			MinRam:              d.Get("min_ram").(int),
+			ImageTags:           imageTags,
+			EnterpriseProjectID: config.GetEnterpriseProjectID(d),
+		}
+	}
+
+	log.Printf("[DEBUG] Create Options: %#v", createOpts)
+	job, err := cloudimages.CreateImageByServer(imsClient, createOpts).ExtractJobResponse()
+	if err != nil {
+		return fmt.Errorf("Error creating FlexibleEngine image: %s", err)
+	}
+	log.Printf("[INFO] IMS Job ID: %s", job.JobID)
+
+	// Wait for the image to become available.
+	stateConf := &resource.StateChangeConf{
+		Pending:      []string{"INIT", "RUNNING"},
+		Target:       []string{"SUCCESS"},
+		Refresh:      waitForImagesImageJob(imsClient, job.JobID),
+		Timeout:      d.Timeout(schema.TimeoutCreate),
+		Delay:        stateRefreshDelay(10 * time.Second),
+		PollInterval: stateRefreshInterval(10 * time.Second),
+	}
+
+	v, err := stateConf.WaitForState()
+	if err != nil {
+		return fmt.Errorf("Error waiting for FlexibleEngine image to become available: %s", err)
+	}
+
+	id := v.(*cloudimages.JobStatus).Entities.ImageID
+	if id == "" {
+		return fmt.Errorf("Error creating FlexibleEngine image: can not get the image ID from job %s", job.JobID)
+	}
+
+	log.Printf("[INFO] Image ID: %s", id)
+	d.SetId(id)
+
+	return resourceImagesImageRead(d, meta)
+}
+
+// getCloudImage returns the IMS image by ID, a 404 error is returned if it does not exist
+func getCloudImage(client *golangsdk.ServiceClient, id string) (*cloudimages.Image, error) {
+	listOpts := &cloudimages.ListOpts{
+		ID:    id,
+		Limit: 1,
+	}
+	allPages, err := cloudimages.List(client, listOpts).AllPages()
+	if err != nil {
+		return nil, err
+	}
+
+	allImages, err := cloudimages.ExtractImages(allPages)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(allImages) < 1 || allImages[0].ID != id {
+		return nil, golangsdk.ErrDefault404{}
+	}
+	return &allImages[0], nil
+}
+
+func resourceImagesImageRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	region := GetRegion(d, config)
+	imsClient, err := config.ImageV2Client(region)
+	if err != nil {
+		return fmt.Errorf("Error creating FlexibleEngine image client: %s", err)
+	}
+
+	img, err := getCloudImage(imsClient, d.Id())
+	if err != nil {
+		return CheckDeleted(d, err, "image")
+	}
+	log.Printf("[DEBUG] Retrieved Image %s: %#v", d.Id(), img)
+
+	d.Set("region", region)
+	d.Set("name", img.Name)
+	d.Set("description", img.Description)
+	d.Set("min_disk", img.MinDisk)
+	d.Set("min_ram", img.MinRam)
+	d.Set("cmk_id", img.SystemCmkid)
+	d.Set("enterprise_project_id", img.EnterpriseProjectID)
+	d.Set("visibility", img.Visibility)
+	d.Set("image_type", img.Imagetype)
+	d.Set("data_origin", img.DataOrigin)
+	d.Set("disk_format", img.DiskFormat)
+	d.Set("image_size", img.ImageSize)
+	d.Set("checksum", img.Checksum)
+	d.Set("status", img.Status)
+
+	if img.OsVersion != "" {
+		d.Set("os_version", img.OsVersion)
+	}
+	if maxRAM, err := strconv.Atoi(img.MaxRam); err == nil {
+		d.Set("max_ram", maxRAM)
+	}
+
+	// the data origin is in the format of "instance,{id}" or "volume,{id}"
+	if origin := strings.SplitN(img.DataOrigin, ",", 2); len(origin) == 2 {
+		switch origin[0] {
+		case "instance":
+			d.Set("instance_id", origin[1])
+		case "volume":
+			d.Set("volume_id", origin[1])
+		}
+	}
+
+	// fetch tags
+	if resp, err := imstags.Get(imsClient, d.Id()).Extract(); err == nil {
+		tagmap := make(map[string]string)
+		for _, tag := range resp.Tags {
+			tagmap[tag.Key] = tag.Value
+		}
+		setResourceTags(d, meta, tagmap)
+	} else {
+		log.Printf("[WARN] fetching tags of image failed: %s", err)
+	}
+
+	return nil
+}
+
+func resourceImagesImageUpdate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	imsClient, err := config.ImageV2Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating FlexibleEngine image client: %s", err)
+	}
+
+	if d.HasChange("name") {
+		updateOpts := images.UpdateOpts{
+			images.ReplaceImageName{NewName: d.Get("name").(string)},
+		}
+		log.Printf("[DEBUG] Update Options: %#v", updateOpts)
+		if _, err := images.Update(imsClient, d.Id(), updateOpts).Extract(); err != nil {
+			return fmt.Errorf("Error updating FlexibleEngine image: %s", err)
+		}
+	}
+
+	if d.HasChange("tags_all") {
+		if err := updateImagesImageTags(imsClient, d); err != nil {
+			return fmt.Errorf("Error updating tags of image %s: %s", d.Id(), err)
+		}
+	}
+
+	return resourceImagesImageRead(d, meta)
+}
+
+func updateImagesImageTags(client *golangsdk.ServiceClient, d *schema.ResourceData) error {
+	oRaw, nRaw := d.GetChange("tags_all")
+	oMap := oRaw.(map[string]interface{})
+	nMap := nRaw.(map[string]interface{})
+
+	// remove the old tags which are deleted or changed
+	removed := make([]imstags.Tag, 0)
+	for k, v := range oMap {
+		if nv, ok := nMap[k]; !ok || nv != v {
+			removed = append(removed, imstags.Tag{Key: k, Value: v.(string)})
+		}
+	}
+	if len(removed) > 0 {
+		opts := imstags.BatchOpts{Action: imstags.ActionDelete, Tags: removed}
+		if err := imstags.BatchAction(client, d.Id(), opts).Err; err != nil {
+			return err
+		}
+	}
+
+	added := make([]imstags.Tag, 0)
+	for k, v := range nMap {
+		if ov, ok := oMap[k]; !ok || ov != v {
+			added = append(added, imstags.Tag{Key: k, Value: v.(string)})
+		}
+	}
+	if len(added) > 0 {
+		opts := imstags.BatchOpts{Action: imstags.ActionCreate, Tags: added}
+		if err := imstags.BatchAction(client, d.Id(), opts).Err; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func resourceImagesImageDelete(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	imsClient, err := config.ImageV2Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating FlexibleEngine image client: %s", err)
+	}
+
+	log.Printf("[DEBUG] Deleting Image %s", d.Id())
+	if err := images.Delete(imsClient, d.Id()).ExtractErr(); err != nil {
+		return CheckDeleted(d, err, "image")
+	}
+
+	stateConf := &resource.StateChangeConf{
+		Pending:    []string{"ACTIVE"},
+		Target:     []string{"DELETED"},
+		Refresh:    waitForImagesImageDelete(imsClient, d.Id()),
+		Timeout:    d.Timeout(schema.TimeoutDelete),
+		Delay:      stateRefreshDelay(10 * time.Second),
+		MinTimeout: stateRefreshInterval(3 * time.Second),
+	}
+
+	_, err = stateConf.WaitForState()
+	if err != nil {
+		return fmt.Errorf("Error waiting for image (%s) to delete: %s", d.Id(), err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func waitForImagesImageDelete(client *golangsdk.ServiceClient, imageID string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		img, err := images.Get(client, imageID).Extract()
+		if err != nil {
+			if _, ok := err.(golangsdk.ErrDefault404); ok {
+				log.Printf("[INFO] Successfully deleted FlexibleEngine image %s", imageID)
+				return img, "DELETED", nil
+			}
+			return img, "ACTIVE", err
+		}
+
+		return img, "ACTIVE", nil
+	}
+}
+
+// waitForImagesImageJob returns the status of an IMS job, the job API is only available in v1.
+func waitForImagesImageJob(client *golangsdk.ServiceClient, jobID string) resource.StateRefreshFunc {
+	jobClient := *client
+	// v1/{project_id}/jobs/{job_id}
+	jobClient.ResourceBase = jobClient.Endpoint + "v1/" + jobClient.ProjectID + "/"
+
+	return func() (interface{}, string, error) {
+		job := new(cloudimages.JobStatus)
+		if _, err := jobClient.Get(jobClient.ServiceURL("jobs", jobID), job, nil); err != nil {
+			return nil, "", err
+		}
+
+		if job.Status == "FAIL" {
+			return job, job.Status, fmt.Errorf("job %s failed with code %s: %s", jobID, job.ErrorCode, job.FailReason)
+		}
+		return job, job.Status, nil
+	}
+}
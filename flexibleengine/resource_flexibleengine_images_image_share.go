@@ -0,0 +1,177 @@
+package flexibleengine
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/chnsz/golangsdk"
+	"github.com/chnsz/golangsdk/openstack/imageservice/v2/members"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceImagesImageShare() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceImagesImageShareCreate,
+		Read:   resourceImagesImageShareRead,
+		Update: resourceImagesImageShareUpdate,
+		Delete: resourceImagesImageShareDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"source_image_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"target_project_ids": {
+				Type:     schema.TypeSet,
+				Required: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"members": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"member_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"status": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func addImageMembers(client *golangsdk.ServiceClient, imageID string, projectIDs []interface{}) error {
+	for _, projectID := range projectIDs {
+		log.Printf("[DEBUG] Sharing image %s with project %s", imageID, projectID)
+		if _, err := members.Create(client, imageID, projectID.(string)).Extract(); err != nil {
+			return fmt.Errorf("Error sharing image %s with project %s: %s", imageID, projectID, err)
+		}
+	}
+	return nil
+}
+
+func removeImageMembers(client *golangsdk.ServiceClient, imageID string, projectIDs []interface{}) error {
+	for _, projectID := range projectIDs {
+		log.Printf("[DEBUG] Revoking the share of image %s with project %s", imageID, projectID)
+		err := members.Delete(client, imageID, projectID.(string)).ExtractErr()
+		if err != nil && !isResourceNotFound(err) {
+			return fmt.Errorf("Error revoking the share of image %s with project %s: %s", imageID, projectID, err)
+		}
+	}
+	return nil
+}
+
+func resourceImagesImageShareCreate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	imageClient, err := config.ImageV2Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating FlexibleEngine image client: %s", err)
+	}
+
+	imageID := d.Get("source_image_id").(string)
+	if err := addImageMembers(imageClient, imageID, d.Get("target_project_ids").(*schema.Set).List()); err != nil {
+		return err
+	}
+
+	d.SetId(imageID)
+	return resourceImagesImageShareRead(d, meta)
+}
+
+func resourceImagesImageShareRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	region := GetRegion(d, config)
+	imageClient, err := config.ImageV2Client(region)
+	if err != nil {
+		return fmt.Errorf("Error creating FlexibleEngine image client: %s", err)
+	}
+
+	pages, err := members.List(imageClient, d.Id()).AllPages()
+	if err != nil {
+		return CheckDeleted(d, err, "image share")
+	}
+	allMembers, err := members.ExtractMembers(pages)
+	if err != nil {
+		return fmt.Errorf("Error extracting members of image %s: %s", d.Id(), err)
+	}
+
+	// the share is removed when all members are revoked out of terraform
+	if len(allMembers) == 0 {
+		log.Printf("[WARN] image %s is not shared with any project, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	projectIDs := make([]string, len(allMembers))
+	memberList := make([]map[string]interface{}, len(allMembers))
+	for i, m := range allMembers {
+		projectIDs[i] = m.MemberID
+		memberList[i] = map[string]interface{}{
+			"member_id": m.MemberID,
+			"status":    m.Status,
+		}
+	}
+
+	d.Set("region", region)
+	d.Set("source_image_id", d.Id())
+	d.Set("target_project_ids", projectIDs)
+	if err := d.Set("members", memberList); err != nil {
+		return fmt.Errorf("Error setting members of image %s: %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+func resourceImagesImageShareUpdate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	imageClient, err := config.ImageV2Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating FlexibleEngine image client: %s", err)
+	}
+
+	if d.HasChange("target_project_ids") {
+		oRaw, nRaw := d.GetChange("target_project_ids")
+		oldSet := oRaw.(*schema.Set)
+		newSet := nRaw.(*schema.Set)
+
+		if err := removeImageMembers(imageClient, d.Id(), oldSet.Difference(newSet).List()); err != nil {
+			return err
+		}
+		if err := addImageMembers(imageClient, d.Id(), newSet.Difference(oldSet).List()); err != nil {
+			return err
+		}
+	}
+
+	return resourceImagesImageShareRead(d, meta)
+}
+
+func resourceImagesImageShareDelete(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	imageClient, err := config.ImageV2Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating FlexibleEngine image client: %s", err)
+	}
+
+	if err := removeImageMembers(imageClient, d.Id(), d.Get("target_project_ids").(*schema.Set).List()); err != nil {
+		return err
+	}
+
+	d.SetId("")
+	return nil
+}
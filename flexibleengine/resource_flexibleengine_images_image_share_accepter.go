@@ -0,0 +1,161 @@
+package flexibleengine
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/chnsz/golangsdk/openstack/imageservice/v2/members"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceImagesImageShareAccepter() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceImagesImageShareAccepterCreate,
+		Read:   resourceImagesImageShareAccepterRead,
+		Update: resourceImagesImageShareAccepterUpdate,
+		Delete: resourceImagesImageShareAccepterDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"image_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"member_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"status": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "accepted",
+				ValidateFunc: validation.StringInSlice([]string{
+					"accepted", "rejected",
+				}, false),
+			},
+		},
+	}
+}
+
+func resourceImagesImageShareAccepterCreate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	imageClient, err := config.ImageV2Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating FlexibleEngine image client: %s", err)
+	}
+
+	imageID := d.Get("image_id").(string)
+	// the member is the project of the provider by default
+	memberID := d.Get("member_id").(string)
+	if memberID == "" {
+		memberID = imageClient.ProjectID
+	}
+
+	// the image may be shared at the same time, so wait for the membership to appear
+	updateOpts := members.UpdateOpts{
+		Status: d.Get("status").(string),
+	}
+	err = resource.Retry(d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+		_, err := members.Update(imageClient, imageID, memberID, updateOpts).Extract()
+		if isResourceNotFound(err) {
+			return resource.RetryableError(err)
+		}
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Error accepting the share of image %s: %s", imageID, err)
+	}
+
+	d.SetId(imageID)
+	d.Set("member_id", memberID)
+	return resourceImagesImageShareAccepterRead(d, meta)
+}
+
+func resourceImagesImageShareAccepterRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	region := GetRegion(d, config)
+	imageClient, err := config.ImageV2Client(region)
+	if err != nil {
+		return fmt.Errorf("Error creating FlexibleEngine image client: %s", err)
+	}
+
+	memberID := d.Get("member_id").(string)
+	if memberID == "" {
+		memberID = imageClient.ProjectID
+	}
+
+	member, err := members.Get(imageClient, d.Id(), memberID).Extract()
+	if err != nil {
+		return CheckDeleted(d, err, "image share accepter")
+	}
+	log.Printf("[DEBUG] Retrieved member %s of image %s: %#v", memberID, d.Id(), member)
+
+	d.Set("region", region)
+	d.Set("image_id", member.ImageID)
+	d.Set("member_id", member.MemberID)
+	d.Set("status", member.Status)
+
+	return nil
+}
+
+func resourceImagesImageShareAccepterUpdate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	imageClient, err := config.ImageV2Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating FlexibleEngine image client: %s", err)
+	}
+
+	if d.HasChange("status") {
+		updateOpts := members.UpdateOpts{
+			Status: d.Get("status").(string),
+		}
+		_, err := members.Update(imageClient, d.Id(), d.Get("member_id").(string), updateOpts).Extract()
+		if err != nil {
+			return fmt.Errorf("Error updating the share status of image %s: %s", d.Id(), err)
+		}
+	}
+
+	return resourceImagesImageShareAccepterRead(d, meta)
+}
+
+func resourceImagesImageShareAccepterDelete(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	imageClient, err := config.ImageV2Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating FlexibleEngine image client: %s", err)
+	}
+
+	// the shared image is removed from the project by rejecting it
+	updateOpts := members.UpdateOpts{
+		Status: "rejected",
+	}
+	_, err = members.Update(imageClient, d.Id(), d.Get("member_id").(string), updateOpts).Extract()
+	if err != nil && !isResourceNotFound(err) {
+		return fmt.Errorf("Error rejecting the share of image %s: %s", d.Id(), err)
+	}
+
+	d.SetId("")
+	return nil
+}
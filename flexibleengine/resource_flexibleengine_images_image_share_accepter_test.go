@@ -0,0 +1,42 @@
+package flexibleengine
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccImagesImageShareAccepter_basic(t *testing.T) {
+	resourceName := "flexibleengine_images_image_share_accepter.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheckImageShareAccepter(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccImagesImageShareAccepter_basic("accepted"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "image_id", OS_SHARED_IMAGE_ID),
+					resource.TestCheckResourceAttr(resourceName, "status", "accepted"),
+					resource.TestCheckResourceAttrSet(resourceName, "member_id"),
+				),
+			},
+			{
+				Config: testAccImagesImageShareAccepter_basic("rejected"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "status", "rejected"),
+				),
+			},
+		},
+	})
+}
+
+func testAccImagesImageShareAccepter_basic(status string) string {
+	return fmt.Sprintf(`
+resource "flexibleengine_images_image_share_accepter" "test" {
+  image_id = "%s"
+  status   = "%s"
+}
+`, OS_SHARED_IMAGE_ID, status)
+}
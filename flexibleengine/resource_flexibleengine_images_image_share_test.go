@@ -0,0 +1,96 @@
+package flexibleengine
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/chnsz/golangsdk/openstack/imageservice/v2/members"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccImagesImageShare_basic(t *testing.T) {
+	resourceName := "flexibleengine_images_image_share.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheckImageShare(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckImagesImageShareDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccImagesImageShare_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckImagesImageShareExists(resourceName),
+					resource.TestCheckResourceAttrPair(resourceName, "source_image_id",
+						"flexibleengine_images_image_v2.test", "id"),
+					resource.TestCheckResourceAttr(resourceName, "target_project_ids.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "members.0.member_id", OS_SHARE_PROJECT_ID),
+					resource.TestCheckResourceAttr(resourceName, "members.0.status", "pending"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckImagesImageShareDestroy(s *terraform.State) error {
+	config := testAccProvider.Meta().(*Config)
+	imageClient, err := config.ImageV2Client(OS_REGION_NAME)
+	if err != nil {
+		return fmt.Errorf("Error creating FlexibleEngine image client: %s", err)
+	}
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "flexibleengine_images_image_share" {
+			continue
+		}
+
+		_, err := members.Get(imageClient, rs.Primary.ID, OS_SHARE_PROJECT_ID).Extract()
+		if err == nil {
+			return fmt.Errorf("Image %s is still shared with project %s", rs.Primary.ID, OS_SHARE_PROJECT_ID)
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckImagesImageShareExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		config := testAccProvider.Meta().(*Config)
+		imageClient, err := config.ImageV2Client(OS_REGION_NAME)
+		if err != nil {
+			return fmt.Errorf("Error creating FlexibleEngine image client: %s", err)
+		}
+
+		_, err = members.Get(imageClient, rs.Primary.ID, OS_SHARE_PROJECT_ID).Extract()
+		return err
+	}
+}
+
+var testAccImagesImageShare_basic = fmt.Sprintf(`
+resource "flexibleengine_images_image_v2" "test" {
+  name             = "image-share-test"
+  image_source_url = "https://releases.rancher.com/os/latest/rancheros-openstack.img"
+  container_format = "bare"
+  disk_format      = "qcow2"
+}
+
+resource "flexibleengine_images_image_share" "test" {
+  source_image_id    = flexibleengine_images_image_v2.test.id
+  target_project_ids = ["%s"]
+}
+`, OS_SHARE_PROJECT_ID)
@@ -0,0 +1,176 @@
+package flexibleengine
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccImagesImage_basic(t *testing.T) {
+	rName := fmt.Sprintf("tf-acc-test-%s", acctest.RandString(5))
+	resourceName := "flexibleengine_images_image.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckImagesImageDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccImagesImage_basic(rName, "bar"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckImagesImageExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "name", rName),
+					resource.TestCheckResourceAttr(resourceName, "status", "active"),
+					resource.TestCheckResourceAttr(resourceName, "tags.foo", "bar"),
+					resource.TestCheckResourceAttrPair(resourceName, "instance_id",
+						"flexibleengine_compute_instance_v2.test", "id"),
+				),
+			},
+			{
+				Config: testAccImagesImage_basic(rName+"-update", "baz"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckImagesImageExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "name", rName+"-update"),
+					resource.TestCheckResourceAttr(resourceName, "tags.foo", "baz"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccImagesImage_volume(t *testing.T) {
+	rName := fmt.Sprintf("tf-acc-test-%s", acctest.RandString(5))
+	resourceName := "flexibleengine_images_image.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckImagesImageDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccImagesImage_volume(rName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckImagesImageExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "name", rName),
+					resource.TestCheckResourceAttrPair(resourceName, "volume_id",
+						"flexibleengine_blockstorage_volume_v2.test", "id"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckImagesImageDestroy(s *terraform.State) error {
+	config := testAccProvider.Meta().(*Config)
+	imsClient, err := config.ImageV2Client(OS_REGION_NAME)
+	if err != nil {
+		return fmt.Errorf("Error creating FlexibleEngine image client: %s", err)
+	}
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "flexibleengine_images_image" {
+			continue
+		}
+
+		if _, err := getCloudImage(imsClient, rs.Primary.ID); err == nil {
+			return fmt.Errorf("Image still exists")
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckImagesImageExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		config := testAccProvider.Meta().(*Config)
+		imsClient, err := config.ImageV2Client(OS_REGION_NAME)
+		if err != nil {
+			return fmt.Errorf("Error creating FlexibleEngine image client: %s", err)
+		}
+
+		found, err := getCloudImage(imsClient, rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if found.ID != rs.Primary.ID {
+			return fmt.Errorf("Image not found")
+		}
+
+		return nil
+	}
+}
+
+func testAccImagesImage_basic(rName, tag string) string {
+	return fmt.Sprintf(`
+resource "flexibleengine_compute_instance_v2" "test" {
+  name              = "%[1]s"
+  image_id          = "%[2]s"
+  flavor_id         = "%[3]s"
+  availability_zone = "%[4]s"
+  security_groups   = ["default"]
+
+  network {
+    uuid = "%[5]s"
+  }
+}
+
+resource "flexibleengine_images_image" "test" {
+  name        = "%[6]s"
+  instance_id = flexibleengine_compute_instance_v2.test.id
+  description = "created by terraform acceptance test"
+
+  tags = {
+    foo = "%[7]s"
+  }
+}
+`, rName, OS_IMAGE_ID, OS_FLAVOR_ID, OS_AVAILABILITY_ZONE, OS_NETWORK_ID, rName, tag)
+}
+
+func testAccImagesImage_volume(rName string) string {
+	return fmt.Sprintf(`
+resource "flexibleengine_compute_instance_v2" "test" {
+  name              = "%[1]s"
+  image_id          = "%[2]s"
+  flavor_id         = "%[3]s"
+  availability_zone = "%[4]s"
+  security_groups   = ["default"]
+
+  network {
+    uuid = "%[5]s"
+  }
+}
+
+resource "flexibleengine_blockstorage_volume_v2" "test" {
+  name              = "%[1]s"
+  size              = 10
+  availability_zone = "%[4]s"
+}
+
+resource "flexibleengine_compute_volume_attach_v2" "test" {
+  instance_id = flexibleengine_compute_instance_v2.test.id
+  volume_id   = flexibleengine_blockstorage_volume_v2.test.id
+}
+
+resource "flexibleengine_images_image" "test" {
+  name      = "%[1]s"
+  volume_id = flexibleengine_compute_volume_attach_v2.test.volume_id
+}
+`, rName, OS_IMAGE_ID, OS_FLAVOR_ID, OS_AVAILABILITY_ZONE, OS_NETWORK_ID)
+}
@@ -28,13 +28,19 @@ func resourceKmsKeyV1() *schema.Resource {
 		Update: resourceKmsKeyV1Update,
 		Delete: resourceKmsKeyV1Delete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: importStatePassthroughWithRegion,
 		},
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(5 * time.Minute),
 		},
 
 		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
 			"key_alias": {
 				Type:     schema.TypeString,
 				Required: true,
@@ -200,6 +206,7 @@ func resourceKmsKeyV1Read(d *schema.ResourceData, meta interface{}) error {
 	}
 
 	d.SetId(v.KeyID)
+	d.Set("region", GetRegion(d, config))
 	d.Set("domain_id", v.DomainID)
 	d.Set("key_alias", v.KeyAlias)
 	d.Set("realm", v.Realm)
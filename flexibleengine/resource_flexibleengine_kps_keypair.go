@@ -0,0 +1,320 @@
+package flexibleengine
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"regexp"
+	"time"
+
+	"github.com/chnsz/golangsdk"
+	"github.com/chnsz/golangsdk/openstack/kms/v1/keys"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// kpsKeypair is the key pair returned by the KPS APIs
+type kpsKeypair struct {
+	Name            string `json:"name"`
+	Type            string `json:"type"`
+	Scope           string `json:"scope"`
+	PublicKey       string `json:"public_key"`
+	PrivateKey      string `json:"private_key"`
+	Fingerprint     string `json:"fingerprint"`
+	IsKeyProtection bool   `json:"is_key_protection"`
+	Description     string `json:"description"`
+	CreateTime      int64  `json:"create_time"`
+}
+
+func resourceKpsKeypair() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceKpsKeypairCreate,
+		Read:   resourceKpsKeypairRead,
+		Update: resourceKpsKeypairUpdate,
+		Delete: resourceKpsKeypairDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				ValidateFunc: validation.All(
+					validation.StringLenBetween(1, 64),
+					validation.StringMatch(regexp.MustCompile(`^[\-_A-Za-z0-9]+$`),
+						"only letters, digits, underscores (_) and hyphens (-) are allowed"),
+				),
+			},
+			"scope": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "user",
+				ValidateFunc: validation.StringInSlice([]string{"user", "account"}, false),
+			},
+			"public_key": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"key_file"},
+			},
+			"key_file": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"encryption_type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"default", "kms"}, false),
+			},
+			"kms_key_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"description": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringLenBetween(0, 255),
+			},
+			"fingerprint": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"is_managed": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"created_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func kpsKeypairURL(client *golangsdk.ServiceClient, parts ...string) string {
+	return client.ServiceURL(append([]string{"keypairs"}, parts...)...)
+}
+
+// buildKpsKeyProtection returns the protection options which host the private key in KMS,
+// the KPS API only accepts the key alias so the alias of kms_key_id is looked up.
+func buildKpsKeyProtection(d *schema.ResourceData, config *Config) (map[string]interface{}, error) {
+	encryptionType := d.Get("encryption_type").(string)
+	kmsKeyID := d.Get("kms_key_id").(string)
+	if kmsKeyID != "" {
+		if encryptionType == "default" {
+			return nil, fmt.Errorf("kms_key_id can not be specified when encryption_type is default")
+		}
+		encryptionType = "kms"
+	}
+	if encryptionType == "" {
+		return nil, nil
+	}
+
+	encryption := map[string]interface{}{
+		"type": encryptionType,
+	}
+	if encryptionType == "kms" {
+		if kmsKeyID == "" {
+			return nil, fmt.Errorf("kms_key_id is required when encryption_type is kms")
+		}
+
+		kmsClient, err := config.KmsKeyV1Client(GetRegion(d, config))
+		if err != nil {
+			return nil, fmt.Errorf("Error creating FlexibleEngine kms key client: %s", err)
+		}
+		key, err := keys.Get(kmsClient, kmsKeyID).ExtractKeyInfo()
+		if err != nil {
+			return nil, fmt.Errorf("Error retrieving FlexibleEngine KMS key %s: %s", kmsKeyID, err)
+		}
+		encryption["kms_key_name"] = key.KeyAlias
+	}
+
+	return map[string]interface{}{"encryption": encryption}, nil
+}
+
+// writeKeyFile saves the private key to the path which is only readable and writable by the owner
+func writeKeyFile(path, privateKey string) error {
+	// the file may be left by a previous key pair with the read-only permission
+	if _, err := os.Stat(path); err == nil {
+		if err := os.Chmod(path, 0600); err != nil {
+			return err
+		}
+	}
+	if err := ioutil.WriteFile(path, []byte(privateKey), 0600); err != nil {
+		return err
+	}
+	return os.Chmod(path, 0600)
+}
+
+func resourceKpsKeypairCreate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	kpsClient, err := config.KmsV3Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating FlexibleEngine KPS client: %s", err)
+	}
+
+	name := d.Get("name").(string)
+	scope := d.Get("scope").(string)
+	if scope == "account" {
+		scope = "domain"
+	}
+	opts := map[string]interface{}{
+		"name":  name,
+		"type":  "ssh",
+		"scope": scope,
+	}
+
+	publicKey, hasPublicKey := d.GetOk("public_key")
+	if hasPublicKey {
+		opts["public_key"] = publicKey
+	}
+	protection, err := buildKpsKeyProtection(d, config)
+	if err != nil {
+		return err
+	}
+	if protection != nil {
+		opts["key_protection"] = protection
+	}
+
+	// the private key is generated by the server when the public key is not specified
+	var keyFile string
+	if !hasPublicKey {
+		keyFile = d.Get("key_file").(string)
+		if keyFile == "" {
+			keyFile = fmt.Sprintf("%s.pem", name)
+		}
+	}
+
+	log.Printf("[DEBUG] Create KPS keypair options: %#v", opts)
+	var r golangsdk.Result
+	_, r.Err = kpsClient.Post(kpsKeypairURL(kpsClient), map[string]interface{}{"keypair": opts}, &r.Body,
+		&golangsdk.RequestOpts{OkCodes: []int{200}})
+	var kp kpsKeypair
+	if r.Err == nil {
+		r.Err = r.ExtractIntoStructPtr(&kp, "keypair")
+	}
+	if r.Err != nil {
+		return fmt.Errorf("Error creating FlexibleEngine KPS keypair: %s", r.Err)
+	}
+
+	d.SetId(name)
+
+	if keyFile != "" {
+		if err := writeKeyFile(keyFile, kp.PrivateKey); err != nil {
+			return fmt.Errorf("Unable to save the private key of keypair %s: %s", name, err)
+		}
+		d.Set("key_file", keyFile)
+	}
+
+	if desc := d.Get("description").(string); desc != "" {
+		if err := updateKpsKeypairDescription(kpsClient, name, desc); err != nil {
+			return err
+		}
+	}
+
+	return resourceKpsKeypairRead(d, meta)
+}
+
+func resourceKpsKeypairRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	region := GetRegion(d, config)
+	kpsClient, err := config.KmsV3Client(region)
+	if err != nil {
+		return fmt.Errorf("Error creating FlexibleEngine KPS client: %s", err)
+	}
+
+	var r golangsdk.Result
+	_, r.Err = kpsClient.Get(kpsKeypairURL(kpsClient, d.Id()), &r.Body, nil)
+	if r.Err != nil {
+		return CheckDeleted(d, r.Err, "KPS keypair")
+	}
+	var kp kpsKeypair
+	if err := r.ExtractIntoStructPtr(&kp, "keypair"); err != nil {
+		return fmt.Errorf("Error extracting FlexibleEngine KPS keypair %s: %s", d.Id(), err)
+	}
+	log.Printf("[DEBUG] Retrieved KPS keypair %s: %#v", d.Id(), kp)
+
+	scope := kp.Scope
+	if scope == "domain" {
+		scope = "account"
+	}
+
+	d.Set("region", region)
+	d.Set("name", kp.Name)
+	d.Set("scope", scope)
+	d.Set("public_key", kp.PublicKey)
+	d.Set("fingerprint", kp.Fingerprint)
+	d.Set("is_managed", kp.IsKeyProtection)
+	d.Set("description", kp.Description)
+	d.Set("created_at", time.UnixMilli(kp.CreateTime).UTC().Format(time.RFC3339))
+
+	return nil
+}
+
+func updateKpsKeypairDescription(client *golangsdk.ServiceClient, name, description string) error {
+	body := map[string]interface{}{
+		"keypair": map[string]interface{}{
+			"description": description,
+		},
+	}
+	_, err := client.Put(kpsKeypairURL(client, name), body, nil, &golangsdk.RequestOpts{OkCodes: []int{200}})
+	if err != nil {
+		return fmt.Errorf("Error updating the description of FlexibleEngine KPS keypair %s: %s", name, err)
+	}
+	return nil
+}
+
+func resourceKpsKeypairUpdate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	kpsClient, err := config.KmsV3Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating FlexibleEngine KPS client: %s", err)
+	}
+
+	if d.HasChange("description") {
+		if err := updateKpsKeypairDescription(kpsClient, d.Id(), d.Get("description").(string)); err != nil {
+			return err
+		}
+	}
+
+	return resourceKpsKeypairRead(d, meta)
+}
+
+func resourceKpsKeypairDelete(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	kpsClient, err := config.KmsV3Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating FlexibleEngine KPS client: %s", err)
+	}
+
+	_, err = kpsClient.Delete(kpsKeypairURL(kpsClient, d.Id()), &golangsdk.RequestOpts{OkCodes: []int{204}})
+	if err != nil {
+		return CheckDeleted(d, err, "KPS keypair")
+	}
+
+	if keyFile := d.Get("key_file").(string); keyFile != "" {
+		log.Printf("[DEBUG] try to remove the private key (%s) after the keypair is deleted", keyFile)
+		if err := os.Remove(keyFile); err != nil {
+			log.Printf("[WARN] failed to remove private key %s: %s", keyFile, err)
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
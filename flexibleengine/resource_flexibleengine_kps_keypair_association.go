@@ -0,0 +1,244 @@
+package flexibleengine
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/chnsz/golangsdk"
+	"github.com/chnsz/golangsdk/openstack/ecs/v1/cloudservers"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceKpsKeypairAssociation() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceKpsKeypairAssociationCreate,
+		Read:   resourceKpsKeypairAssociationRead,
+		Update: resourceKpsKeypairAssociationUpdate,
+		Delete: resourceKpsKeypairAssociationDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"keypair_name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"server_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"auth_type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"password", "keypair"}, false),
+			},
+			"auth_key": {
+				Type:      schema.TypeString,
+				Optional:  true,
+				Sensitive: true,
+			},
+			"disable_password": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				ForceNew: true,
+			},
+		},
+	}
+}
+
+// buildKpsServerInfo returns the server options of the association APIs, the authentication
+// is the password or the private key of the current key pair which is used to log in to the server.
+func buildKpsServerInfo(d *schema.ResourceData) map[string]interface{} {
+	server := map[string]interface{}{
+		"id": d.Get("server_id").(string),
+	}
+	if authType := d.Get("auth_type").(string); authType != "" {
+		server["auth"] = map[string]interface{}{
+			"type": authType,
+			"key":  d.Get("auth_key").(string),
+		}
+	}
+	return server
+}
+
+// waitForKpsTask polls the KPS task until it succeeds, the status of the task is
+// one of READY_*, RUNNING_*, FAILED_* and SUCCESS_*.
+func waitForKpsTask(client *golangsdk.ServiceClient, taskID string, timeout time.Duration) error {
+	stateConf := &resource.StateChangeConf{
+		Pending: []string{"PENDING"},
+		Target:  []string{"SUCCESS"},
+		Refresh: func() (interface{}, string, error) {
+			var r golangsdk.Result
+			_, r.Err = client.Get(client.ServiceURL("tasks", taskID), &r.Body, nil)
+			if r.Err != nil {
+				return nil, "", r.Err
+			}
+			var task struct {
+				TaskStatus string `json:"task_status"`
+			}
+			if err := r.ExtractInto(&task); err != nil {
+				return nil, "", err
+			}
+
+			switch {
+			case strings.HasPrefix(task.TaskStatus, "SUCCESS"):
+				return task, "SUCCESS", nil
+			case strings.HasPrefix(task.TaskStatus, "FAILED"):
+				return task, task.TaskStatus, fmt.Errorf("the task status is %s", task.TaskStatus)
+			default:
+				return task, "PENDING", nil
+			}
+		},
+		Timeout:    timeout,
+		Delay:      stateRefreshDelay(10 * time.Second),
+		MinTimeout: stateRefreshInterval(3 * time.Second),
+	}
+
+	_, err := stateConf.WaitForState()
+	return err
+}
+
+func associateKpsKeypair(d *schema.ResourceData, client *golangsdk.ServiceClient, timeout time.Duration) error {
+	keypairName := d.Get("keypair_name").(string)
+	serverID := d.Get("server_id").(string)
+
+	server := buildKpsServerInfo(d)
+	if v, ok := d.GetOk("disable_password"); ok {
+		server["disable_password"] = v
+	}
+	opts := map[string]interface{}{
+		"keypair_name": keypairName,
+		"server":       server,
+	}
+
+	log.Printf("[DEBUG] Associating KPS keypair %s with server %s", keypairName, serverID)
+	var r golangsdk.Result
+	_, r.Err = client.Post(kpsKeypairURL(client, "associate"), opts, &r.Body,
+		&golangsdk.RequestOpts{OkCodes: []int{202}})
+	var task struct {
+		TaskID string `json:"task_id"`
+	}
+	if r.Err == nil {
+		r.Err = r.ExtractInto(&task)
+	}
+	if r.Err != nil {
+		return fmt.Errorf("Error associating KPS keypair %s with server %s: %s", keypairName, serverID, r.Err)
+	}
+
+	if err := waitForKpsTask(client, task.TaskID, timeout); err != nil {
+		return fmt.Errorf("Error waiting for KPS keypair %s to be associated with server %s: %s",
+			keypairName, serverID, err)
+	}
+	return nil
+}
+
+func resourceKpsKeypairAssociationCreate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	kpsClient, err := config.KmsV3Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating FlexibleEngine KPS client: %s", err)
+	}
+
+	if err := associateKpsKeypair(d, kpsClient, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return err
+	}
+
+	d.SetId(d.Get("server_id").(string))
+	return resourceKpsKeypairAssociationRead(d, meta)
+}
+
+func resourceKpsKeypairAssociationRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	region := GetRegion(d, config)
+	ecsClient, err := config.ComputeV1Client(region)
+	if err != nil {
+		return fmt.Errorf("Error creating FlexibleEngine compute V1 client: %s", err)
+	}
+
+	server, err := cloudservers.Get(ecsClient, d.Id()).Extract()
+	if err != nil {
+		return CheckDeleted(d, err, "server")
+	}
+
+	// the key pair may be disassociated out of terraform
+	if server.KeyName == "" {
+		log.Printf("[WARN] no keypair is associated with server %s, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("region", region)
+	d.Set("server_id", server.ID)
+	d.Set("keypair_name", server.KeyName)
+
+	return nil
+}
+
+func resourceKpsKeypairAssociationUpdate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	kpsClient, err := config.KmsV3Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating FlexibleEngine KPS client: %s", err)
+	}
+
+	// associating another key pair replaces the current one of the server
+	if d.HasChange("keypair_name") {
+		if err := associateKpsKeypair(d, kpsClient, d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return err
+		}
+	}
+
+	return resourceKpsKeypairAssociationRead(d, meta)
+}
+
+func resourceKpsKeypairAssociationDelete(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	kpsClient, err := config.KmsV3Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating FlexibleEngine KPS client: %s", err)
+	}
+
+	opts := map[string]interface{}{
+		"server": buildKpsServerInfo(d),
+	}
+
+	log.Printf("[DEBUG] Disassociating KPS keypair from server %s", d.Id())
+	var r golangsdk.Result
+	_, r.Err = kpsClient.Post(kpsKeypairURL(kpsClient, "disassociate"), opts, &r.Body,
+		&golangsdk.RequestOpts{OkCodes: []int{202}})
+	var task struct {
+		TaskID string `json:"task_id"`
+	}
+	if r.Err == nil {
+		r.Err = r.ExtractInto(&task)
+	}
+	if r.Err != nil {
+		return CheckDeleted(d, r.Err, "KPS keypair association")
+	}
+
+	if err := waitForKpsTask(kpsClient, task.TaskID, d.Timeout(schema.TimeoutDelete)); err != nil {
+		return fmt.Errorf("Error waiting for KPS keypair to be disassociated from server %s: %s", d.Id(), err)
+	}
+
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,135 @@
+package flexibleengine
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/chnsz/golangsdk/openstack/ecs/v1/cloudservers"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccKpsKeypairAssociation_basic(t *testing.T) {
+	rName := fmt.Sprintf("tf-acc-test-%s", acctest.RandString(5))
+	resourceName := "flexibleengine_kps_keypair_association.test"
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckKpsKeypairAssociationDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKpsKeypairAssociation_basic(rName, "first"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckKpsKeypairAssociationExists(resourceName),
+					resource.TestCheckResourceAttrPair(resourceName, "server_id",
+						"flexibleengine_compute_instance_v2.test", "id"),
+					resource.TestCheckResourceAttrPair(resourceName, "keypair_name",
+						"flexibleengine_kps_keypair.first", "name"),
+				),
+			},
+			{
+				Config: testAccKpsKeypairAssociation_basic(rName, "second"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckKpsKeypairAssociationExists(resourceName),
+					resource.TestCheckResourceAttrPair(resourceName, "keypair_name",
+						"flexibleengine_kps_keypair.second", "name"),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"auth_type", "auth_key"},
+			},
+		},
+	})
+}
+
+func testAccCheckKpsKeypairAssociationDestroy(s *terraform.State) error {
+	config := testAccProvider.Meta().(*Config)
+	ecsClient, err := config.ComputeV1Client(OS_REGION_NAME)
+	if err != nil {
+		return fmt.Errorf("Error creating FlexibleEngine compute V1 client: %s", err)
+	}
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "flexibleengine_kps_keypair_association" {
+			continue
+		}
+
+		server, err := cloudservers.Get(ecsClient, rs.Primary.ID).Extract()
+		if err == nil && server.KeyName != "" {
+			return fmt.Errorf("keypair %s is still associated with server %s", server.KeyName, rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckKpsKeypairAssociationExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		config := testAccProvider.Meta().(*Config)
+		ecsClient, err := config.ComputeV1Client(OS_REGION_NAME)
+		if err != nil {
+			return fmt.Errorf("Error creating FlexibleEngine compute V1 client: %s", err)
+		}
+
+		server, err := cloudservers.Get(ecsClient, rs.Primary.ID).Extract()
+		if err != nil {
+			return err
+		}
+		if server.KeyName != rs.Primary.Attributes["keypair_name"] {
+			return fmt.Errorf("keypair %s is not associated with server %s", rs.Primary.Attributes["keypair_name"],
+				rs.Primary.ID)
+		}
+
+		return nil
+	}
+}
+
+func testAccKpsKeypairAssociation_basic(rName, keypair string) string {
+	return fmt.Sprintf(`
+resource "flexibleengine_kps_keypair" "first" {
+  name = "%[1]s-first"
+}
+
+resource "flexibleengine_kps_keypair" "second" {
+  name = "%[1]s-second"
+}
+
+resource "flexibleengine_compute_instance_v2" "test" {
+  name              = "%[1]s"
+  image_id          = "%[2]s"
+  flavor_id         = "%[3]s"
+  availability_zone = "%[4]s"
+  admin_pass        = "Terraform@123"
+  security_groups   = ["default"]
+
+  network {
+    uuid = "%[5]s"
+  }
+
+  lifecycle {
+    ignore_changes = [key_pair]
+  }
+}
+
+resource "flexibleengine_kps_keypair_association" "test" {
+  server_id    = flexibleengine_compute_instance_v2.test.id
+  keypair_name = flexibleengine_kps_keypair.%[6]s.name
+  auth_type    = "password"
+  auth_key     = "Terraform@123"
+}
+`, rName, OS_IMAGE_ID, OS_FLAVOR_ID, OS_AVAILABILITY_ZONE, OS_NETWORK_ID, keypair)
+}
@@ -0,0 +1,167 @@
+package flexibleengine
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/chnsz/golangsdk"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccKpsKeypair_basic(t *testing.T) {
+	rName := fmt.Sprintf("tf-acc-test-%s", acctest.RandString(5))
+	resourceName := "flexibleengine_kps_keypair.test"
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckKpsKeypairDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKpsKeypair_basic(rName, "created by acc test"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckKpsKeypairExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "name", rName),
+					resource.TestCheckResourceAttr(resourceName, "scope", "user"),
+					resource.TestCheckResourceAttr(resourceName, "description", "created by acc test"),
+					resource.TestCheckResourceAttr(resourceName, "is_managed", "false"),
+					resource.TestCheckResourceAttrSet(resourceName, "key_file"),
+					resource.TestCheckResourceAttrSet(resourceName, "fingerprint"),
+					resource.TestCheckResourceAttrSet(resourceName, "public_key"),
+				),
+			},
+			{
+				Config: testAccKpsKeypair_basic(rName, "updated by acc test"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckKpsKeypairExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "description", "updated by acc test"),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"key_file"},
+			},
+		},
+	})
+}
+
+func TestAccKpsKeypair_kms(t *testing.T) {
+	rName := fmt.Sprintf("tf-acc-test-%s", acctest.RandString(5))
+	resourceName := "flexibleengine_kps_keypair.test"
+	publicKey, _, _ := acctest.RandSSHKeyPair("Generated-by-AccTest")
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckKpsKeypairDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKpsKeypair_kms(rName, publicKey),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckKpsKeypairExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "scope", "account"),
+					resource.TestCheckResourceAttr(resourceName, "public_key", publicKey),
+					resource.TestCheckResourceAttr(resourceName, "is_managed", "true"),
+					resource.TestCheckResourceAttrPair(resourceName, "kms_key_id",
+						"flexibleengine_kms_key_v1.test", "id"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckKpsKeypairDestroy(s *terraform.State) error {
+	config := testAccProvider.Meta().(*Config)
+	kpsClient, err := config.KmsV3Client(OS_REGION_NAME)
+	if err != nil {
+		return fmt.Errorf("Error creating FlexibleEngine KPS client: %s", err)
+	}
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "flexibleengine_kps_keypair" {
+			continue
+		}
+
+		var r golangsdk.Result
+		_, err := kpsClient.Get(kpsKeypairURL(kpsClient, rs.Primary.ID), &r.Body, nil)
+		if err == nil {
+			return fmt.Errorf("KPS keypair still exists")
+		}
+
+		keyFile := rs.Primary.Attributes["key_file"]
+		if keyFile != "" {
+			if _, err := os.Stat(keyFile); err == nil {
+				return fmt.Errorf("private key file still exists")
+			}
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckKpsKeypairExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		config := testAccProvider.Meta().(*Config)
+		kpsClient, err := config.KmsV3Client(OS_REGION_NAME)
+		if err != nil {
+			return fmt.Errorf("Error creating FlexibleEngine KPS client: %s", err)
+		}
+
+		var r golangsdk.Result
+		if _, err := kpsClient.Get(kpsKeypairURL(kpsClient, rs.Primary.ID), &r.Body, nil); err != nil {
+			return err
+		}
+
+		keyFile := rs.Primary.Attributes["key_file"]
+		if keyFile != "" {
+			info, err := os.Stat(keyFile)
+			if err != nil {
+				return fmt.Errorf("private key file not found: %s", err)
+			}
+			if perm := info.Mode().Perm(); perm != 0600 {
+				return fmt.Errorf("the permission of private key file is %#o, expect 0600", perm)
+			}
+		}
+
+		return nil
+	}
+}
+
+func testAccKpsKeypair_basic(rName, description string) string {
+	return fmt.Sprintf(`
+resource "flexibleengine_kps_keypair" "test" {
+  name        = "%s"
+  description = "%s"
+}
+`, rName, description)
+}
+
+func testAccKpsKeypair_kms(rName, publicKey string) string {
+	return fmt.Sprintf(`
+resource "flexibleengine_kms_key_v1" "test" {
+  key_alias    = "%[1]s"
+  pending_days = "7"
+}
+
+resource "flexibleengine_kps_keypair" "test" {
+  name       = "%[1]s"
+  scope      = "account"
+  public_key = "%[2]s"
+  kms_key_id = flexibleengine_kms_key_v1.test.id
+}
+`, rName, publicKey)
+}
@@ -30,6 +30,8 @@ func resourceListenerV2() *schema.Resource {
 			Delete: schema.DefaultTimeout(10 * time.Minute),
 		},
 
+		CustomizeDiff: setTagsAllDiff,
+
 		Schema: map[string]*schema.Schema{
 			"region": {
 				Type:     schema.TypeString,
@@ -121,7 +123,8 @@ func resourceListenerV2() *schema.Resource {
 				Computed:     true,
 				ValidateFunc: validation.IntBetween(1, 300),
 			},
-			"tags": tagsSchema(),
+			"tags":     tagsSchema(),
+			"tags_all": tagsAllSchema(),
 
 			"tenant_id": {
 				Type:       schema.TypeString,
@@ -216,7 +219,7 @@ func resourceListenerCreate(d *schema.ResourceData, meta interface{}) error {
 	d.SetId(listener.ID)
 
 	//set tags
-	tagRaw := d.Get("tags").(map[string]interface{})
+	tagRaw := getResourceTags(d, meta)
 	if len(tagRaw) > 0 {
 		taglist := expandResourceTags(tagRaw)
 		if tagErr := tags.Create(lbClient, "listeners", listener.ID, taglist).ExtractErr(); tagErr != nil {
@@ -269,7 +272,7 @@ func resourceListenerRead(d *schema.ResourceData, meta interface{}) error {
 	// fetch tags
 	if resourceTags, err := tags.Get(lbClient, "listeners", d.Id()).Extract(); err == nil {
 		tagmap := tagsToMap(resourceTags.Tags)
-		d.Set("tags", tagmap)
+		setResourceTags(d, meta, tagmap)
 	} else {
 		log.Printf("[WARN] fetching tags of elb listener failed: %s", err)
 	}
@@ -362,7 +365,7 @@ func resourceListenerUpdate(d *schema.ResourceData, meta interface{}) error {
 	}
 
 	// update tags
-	if d.HasChange("tags") {
+	if d.HasChange("tags_all") {
 		tagErr := UpdateResourceTags(lbClient, d, "listeners", d.Id())
 		if tagErr != nil {
 			return fmt.Errorf("Error updating tags of elb listener:%s, err:%s", d.Id(), tagErr)
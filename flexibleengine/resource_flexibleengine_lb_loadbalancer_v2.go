@@ -10,7 +10,7 @@ import (
 
 	"github.com/chnsz/golangsdk"
 	"github.com/chnsz/golangsdk/openstack/common/tags"
-	"github.com/chnsz/golangsdk/openstack/networking/v2/extensions/lbaas_v2/loadbalancers"
+	"github.com/chnsz/golangsdk/openstack/elb/v2/loadbalancers"
 	"github.com/chnsz/golangsdk/openstack/networking/v2/ports"
 )
 
@@ -30,6 +30,8 @@ func resourceLoadBalancerV2() *schema.Resource {
 			Delete: schema.DefaultTimeout(5 * time.Minute),
 		},
 
+		CustomizeDiff: setTagsAllAndEnterpriseProjectDiff,
+
 		Schema: map[string]*schema.Schema{
 			"region": {
 				Type:     schema.TypeString,
@@ -69,7 +71,9 @@ func resourceLoadBalancerV2() *schema.Resource {
 				Set:      schema.HashString,
 			},
 
-			"tags": tagsSchema(),
+			"tags":                  tagsSchema(),
+			"tags_all":              tagsAllSchema(),
+			"enterprise_project_id": enterpriseProjectSchema(),
 			"loadbalancer_provider": {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -126,6 +130,8 @@ func resourceLoadBalancerV2Create(d *schema.ResourceData, meta interface{}) erro
 		AdminStateUp: &adminStateUp,
 		Flavor:       d.Get("flavor").(string),
 		Provider:     lbProvider,
+
+		EnterpriseProjectID: config.GetEnterpriseProjectID(d),
 	}
 
 	log.Printf("[DEBUG] Create Options: %#v", createOpts)
@@ -158,7 +164,7 @@ func resourceLoadBalancerV2Create(d *schema.ResourceData, meta interface{}) erro
 	d.SetId(lb.ID)
 
 	//set tags
-	tagRaw := d.Get("tags").(map[string]interface{})
+	tagRaw := getResourceTags(d, meta)
 	if len(tagRaw) > 0 {
 		taglist := expandResourceTags(tagRaw)
 		if tagErr := tags.Create(lbClient, "loadbalancers", lb.ID, taglist).ExtractErr(); tagErr != nil {
@@ -193,6 +199,7 @@ func resourceLoadBalancerV2Read(d *schema.ResourceData, meta interface{}) error
 	d.Set("admin_state_up", lb.AdminStateUp)
 	d.Set("flavor", lb.Flavor)
 	d.Set("loadbalancer_provider", lb.Provider)
+	d.Set("enterprise_project_id", lb.EnterpriseProjectID)
 	d.Set("region", region)
 
 	// Get any security groups on the VIP Port
@@ -215,7 +222,7 @@ func resourceLoadBalancerV2Read(d *schema.ResourceData, meta interface{}) error
 	// fetch tags
 	if resourceTags, err := tags.Get(lbClient, "loadbalancers", d.Id()).Extract(); err == nil {
 		tagmap := tagsToMap(resourceTags.Tags)
-		d.Set("tags", tagmap)
+		setResourceTags(d, meta, tagmap)
 	} else {
 		log.Printf("[WARN] fetching tags of elb loadbalancer failed: %s", err)
 	}
@@ -282,8 +289,12 @@ func resourceLoadBalancerV2Update(d *schema.ResourceData, meta interface{}) erro
 		}
 	}
 
+	if err := updateEnterpriseProject(d, config, epsResourceTypeELB, d.Id(), false); err != nil {
+		return err
+	}
+
 	// update tags
-	if d.HasChange("tags") {
+	if d.HasChange("tags_all") {
 		tagErr := UpdateResourceTags(lbClient, d, "loadbalancers", d.Id())
 		if tagErr != nil {
 			return fmt.Errorf("Error updating tags of load balancer:%s, err:%s", d.Id(), tagErr)
@@ -207,11 +207,8 @@ func resourceMRSClusterV2() *schema.Resource {
 				Elem:     nodeGroupSchemaResource("", false, 1, 500),
 			},
 
-			"tags": {
-				Type:     schema.TypeMap,
-				Optional: true,
-				Elem:     &schema.Schema{Type: schema.TypeString},
-			},
+			"tags":     tagsSchema(),
+			"tags_all": tagsAllSchema(),
 			"total_node_number": {
 				Type:     schema.TypeInt,
 				Computed: true,
@@ -278,7 +275,7 @@ func resourceMRSClusterV2() *schema.Resource {
 					rd.SetNewComputed(changeKey)
 				}
 			}
-			return nil
+			return setTagsAllDiff(c, rd, i)
 		},
 	}
 }
@@ -508,7 +505,7 @@ func addTagsToMrsCluster(d *schema.ResourceData, config *Config) error {
 		return fmt.Errorf("Error creating FlexibleEngine MRS V1 client: %s", err)
 	}
 
-	tagRaw := d.Get("tags").(map[string]interface{})
+	tagRaw := getResourceTags(d, config)
 	if len(tagRaw) > 0 {
 		taglist := expandResourceTags(tagRaw)
 		if tagErr := tags.Create(client, "clusters", d.Id(), taglist).ExtractErr(); tagErr != nil {
@@ -764,13 +761,13 @@ func parseHostIps(groupName string, isCustomNode bool, clustHostMap map[string][
 	return rt
 }
 
-func setClsuterTags(d *schema.ResourceData, client *golangsdk.ServiceClient) error {
+func setClsuterTags(d *schema.ResourceData, meta interface{}, client *golangsdk.ServiceClient) error {
 	resourceTags, err := tags.Get(client, "clusters", d.Id()).Extract()
 	if err != nil {
 		return fmt.Errorf("Error Fetching tags of MapReduce cluster form server: %s", err)
 	}
 	tagmap := tagsToMap(resourceTags.Tags)
-	return d.Set("tags", tagmap)
+	return setResourceTags(d, meta, tagmap)
 }
 
 func getMrsClusterFromServer(d *schema.ResourceData, client *golangsdk.ServiceClient) (*cluster.Cluster, error) {
@@ -824,7 +821,7 @@ func resourceMRSClusterV2Read(d *schema.ResourceData, meta interface{}) error {
 		setMrsClsuterChargingTimestamp(d, resp),
 		setMrsClsuterCreateTimestamp(d, resp),
 		setMrsClusterNodeGroups(d, client, resp),
-		setClsuterTags(d, client),
+		setClsuterTags(d, meta, client),
 	)
 	if err := mErr.ErrorOrNil(); err != nil {
 		return fmt.Errorf("Error setting vault fields: %s", err)
@@ -1007,7 +1004,7 @@ func resourceMRSClusterV2Update(d *schema.ResourceData, meta interface{}) error
 		return fmt.Errorf("Error creating FlexibleEngine MRS client: %s", err)
 	}
 
-	if d.HasChange("tags") {
+	if d.HasChange("tags_all") {
 		tagErr := UpdateResourceTags(client, d, "clusters", d.Id())
 		if tagErr != nil {
 			return fmt.Errorf("Error updating tags of MRS cluster:%s, err:%s", d.Id(), tagErr)
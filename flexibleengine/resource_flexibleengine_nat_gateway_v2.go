@@ -29,6 +29,8 @@ func resourceNatGatewayV2() *schema.Resource {
 			Delete: schema.DefaultTimeout(10 * time.Minute),
 		},
 
+		CustomizeDiff: setEnterpriseProjectDiff,
+
 		Schema: map[string]*schema.Schema{
 			"region": {
 				Type:     schema.TypeString,
@@ -66,6 +68,7 @@ func resourceNatGatewayV2() *schema.Resource {
 				Optional: true,
 				Computed: true,
 			},
+			"enterprise_project_id": enterpriseProjectSchema(),
 
 			"status": {
 				Type:     schema.TypeString,
@@ -115,12 +118,13 @@ func resourceNatGatewayV2Create(d *schema.ResourceData, meta interface{}) error
 	}
 
 	createOpts := &natgateways.CreateOpts{
-		Name:              d.Get("name").(string),
-		Description:       d.Get("description").(string),
-		Spec:              d.Get("spec").(string),
-		TenantID:          d.Get("tenant_id").(string),
-		RouterID:          vpcID,
-		InternalNetworkID: subnetID,
+		Name:                d.Get("name").(string),
+		Description:         d.Get("description").(string),
+		Spec:                d.Get("spec").(string),
+		TenantID:            d.Get("tenant_id").(string),
+		RouterID:            vpcID,
+		InternalNetworkID:   subnetID,
+		EnterpriseProjectID: config.GetEnterpriseProjectID(d),
 	}
 
 	log.Printf("[DEBUG] Create Options: %#v", createOpts)
@@ -167,6 +171,7 @@ func resourceNatGatewayV2Read(d *schema.ResourceData, meta interface{}) error {
 	d.Set("vpc_id", natGateway.RouterID)
 	d.Set("subnet_id", natGateway.InternalNetworkID)
 	d.Set("status", natGateway.Status)
+	d.Set("enterprise_project_id", natGateway.EnterpriseProjectID)
 	d.Set("region", GetRegion(d, config))
 
 	return nil
@@ -198,6 +203,10 @@ func resourceNatGatewayV2Update(d *schema.ResourceData, meta interface{}) error
 		return fmt.Errorf("Error updating Nat Gateway: %s", err)
 	}
 
+	if err := updateEnterpriseProject(d, config, epsResourceTypeNAT, d.Id(), false); err != nil {
+		return err
+	}
+
 	return resourceNatGatewayV2Read(d, meta)
 }
 
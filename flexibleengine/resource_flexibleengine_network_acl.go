@@ -30,6 +30,12 @@ func resourceNetworkACL() *schema.Resource {
 		},
 
 		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
 			"name": {
 				Type:     schema.TypeString,
 				Required: true,
@@ -108,7 +114,7 @@ func resourceNetworkACLCreate(d *schema.ResourceData, meta interface{}) error {
 	subnetsRaw := d.Get("subnets").(*schema.Set).List()
 	if len(subnetsRaw) > 0 {
 		for _, v := range subnetsRaw {
-			port, err := getGWPortFromSubnet(config, v.(string))
+			port, err := getGWPortFromSubnet(config, GetRegion(d, config), v.(string))
 			if err != nil {
 				return err
 			}
@@ -225,6 +231,7 @@ func resourceNetworkACLRead(d *schema.ResourceData, meta interface{}) error {
 
 	log.Printf("[DEBUG] Read FlexibleEngine Firewall group %s: %#v", d.Id(), fwGroup)
 
+	d.Set("region", GetRegion(d, config))
 	d.Set("name", fwGroup.Name)
 	d.Set("status", fwGroup.Status)
 	d.Set("description", fwGroup.Description)
@@ -279,7 +286,7 @@ func resourceNetworkACLUpdate(d *schema.ResourceData, meta interface{}) error {
 		// get port Ids from subnets
 		subnetsRaw := d.Get("subnets").(*schema.Set).List()
 		for _, v := range subnetsRaw {
-			port, err := getGWPortFromSubnet(config, v.(string))
+			port, err := getGWPortFromSubnet(config, GetRegion(d, config), v.(string))
 			if err != nil {
 				return err
 			}
@@ -372,15 +379,15 @@ func resourceNetworkACLDelete(d *schema.ResourceData, meta interface{}) error {
 	return err
 }
 
-func getGWPortFromSubnet(config *Config, subnetID string) (string, error) {
+func getGWPortFromSubnet(config *Config, region, subnetID string) (string, error) {
 	var gatewayIP string
 	var gatewayPort string
 
-	subnetClient, err := config.NetworkingV1Client(config.Region)
+	subnetClient, err := config.NetworkingV1Client(region)
 	if err != nil {
 		return "", fmt.Errorf("Error creating FlexibleEngine vpc client: %s", err)
 	}
-	networkingClient, err := config.NetworkingV2Client(config.Region)
+	networkingClient, err := config.NetworkingV2Client(region)
 	if err != nil {
 		return "", fmt.Errorf("Error creating FlexibleEngine networking client: %s", err)
 	}
@@ -22,6 +22,8 @@ func resourceObsBucket() *schema.Resource {
 			State: schema.ImportStatePassthrough,
 		},
 
+		CustomizeDiff: setEnterpriseProjectDiff,
+
 		Schema: map[string]*schema.Schema{
 			"bucket": {
 				Type:     schema.TypeString,
@@ -245,6 +247,7 @@ func resourceObsBucket() *schema.Resource {
 				Computed: true,
 				ForceNew: true,
 			},
+			"enterprise_project_id": enterpriseProjectSchema(),
 
 			"bucket_domain_name": {
 				Type:     schema.TypeString,
@@ -271,6 +274,7 @@ func resourceObsBucketCreate(d *schema.ResourceData, meta interface{}) error {
 		StorageClass: obs.ParseStringToStorageClassType(class),
 	}
 	opts.Location = region
+	opts.Epid = config.GetEnterpriseProjectID(d)
 	if _, ok := d.GetOk("multi_az"); ok {
 		opts.AvailableZone = "3az"
 	}
@@ -342,6 +346,12 @@ func resourceObsBucketUpdate(d *schema.ResourceData, meta interface{}) error {
 		}
 	}
 
+	if !d.IsNewResource() {
+		if err := updateEnterpriseProject(d, config, epsResourceTypeOBS, d.Id(), true); err != nil {
+			return err
+		}
+	}
+
 	return resourceObsBucketRead(d, meta)
 }
 
@@ -864,6 +874,7 @@ func setObsBucketMetadata(obsClient *obs.ObsClient, d *schema.ResourceData) erro
 	} else {
 		d.Set("multi_az", false)
 	}
+	d.Set("enterprise_project_id", output.Epid)
 
 	return nil
 }
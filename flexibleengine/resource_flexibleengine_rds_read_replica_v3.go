@@ -28,6 +28,8 @@ func resourceRdsReadReplicaInstance() *schema.Resource {
 			Delete: schema.DefaultTimeout(30 * time.Minute),
 		},
 
+		CustomizeDiff: setTagsAllDiff,
+
 		Schema: map[string]*schema.Schema{
 			"region": {
 				Type:     schema.TypeString,
@@ -151,7 +153,8 @@ func resourceRdsReadReplicaInstance() *schema.Resource {
 				},
 			},
 
-			"tags": tagsSchema(),
+			"tags":     tagsSchema(),
+			"tags_all": tagsAllSchema(),
 		},
 	}
 }
@@ -204,7 +207,7 @@ func resourceRdsReadReplicaInstanceCreate(d *schema.ResourceData, meta interface
 		return fmt.Errorf("Error creating instance (%s): %s", instanceID, err)
 	}
 
-	tagRaw := d.Get("tags").(map[string]interface{})
+	tagRaw := getResourceTags(d, meta)
 	if len(tagRaw) > 0 {
 		tagList := expandResourceTags(tagRaw)
 		err := tags.Create(client, "instances", instanceID, tagList).ExtractErr()
@@ -244,7 +247,7 @@ func resourceRdsReadReplicaInstanceRead(d *schema.ResourceData, meta interface{}
 	d.Set("security_group_id", instance.SecurityGroupId)
 	d.Set("type", instance.Type)
 	d.Set("status", instance.Status)
-	d.Set("tags", tagsToMap(instance.Tags))
+	setResourceTags(d, meta, tagsToMap(instance.Tags))
 
 	az := expandAvailabilityZone(instance)
 	d.Set("availability_zone", az)
@@ -293,7 +296,7 @@ func resourceRdsReadReplicaInstanceUpdate(d *schema.ResourceData, meta interface
 		return fmt.Errorf("[ERROR] %s", err)
 	}
 
-	if d.HasChange("tags") {
+	if d.HasChange("tags_all") {
 		tagErr := UpdateResourceTags(client, d, "instances", instanceID)
 		if tagErr != nil {
 			return fmt.Errorf("Error updating tags of RDS read replica instance: %s, err: %s", instanceID, tagErr)
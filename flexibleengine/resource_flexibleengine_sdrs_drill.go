@@ -17,7 +17,7 @@ func resourceSdrsDrillV1() *schema.Resource {
 		Update: resourceSdrsDrillV1Update,
 		Delete: resourceSdrsDrillV1Delete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: importStatePassthroughWithRegion,
 		},
 
 		Timeouts: &schema.ResourceTimeout{
@@ -26,6 +26,12 @@ func resourceSdrsDrillV1() *schema.Resource {
 		},
 
 		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
 			"name": {
 				Type:     schema.TypeString,
 				Required: true,
@@ -103,6 +109,7 @@ func resourceSdrsDrillV1Read(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("Error retrieving FlexibleEngine SDRS DR drill: %s", err)
 	}
 
+	d.Set("region", GetRegion(d, config))
 	d.Set("name", n.Name)
 	d.Set("group_id", n.GroupID)
 	d.Set("drill_vpc_id", n.DrillVpcID)
@@ -23,6 +23,12 @@ func resourceSdrsProtectedInstanceV1() *schema.Resource {
 		},
 
 		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
 			"group_id": {
 				Type:     schema.TypeString,
 				Required: true,
@@ -136,6 +142,7 @@ func resourceSdrsProtectedInstanceV1Read(d *schema.ResourceData, meta interface{
 		return fmt.Errorf("Error retrieving FlexibleEngine SDRS ProtectedInstance: %s", err)
 	}
 
+	d.Set("region", GetRegion(d, config))
 	d.Set("name", n.Name)
 	d.Set("description", n.Description)
 	d.Set("group_id", n.GroupID)
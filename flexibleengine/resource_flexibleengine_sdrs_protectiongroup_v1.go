@@ -17,7 +17,7 @@ func resourceSdrsProtectiongroupV1() *schema.Resource {
 		Update: resourceSdrsProtectiongroupV1Update,
 		Delete: resourceSdrsProtectiongroupV1Delete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: importStatePassthroughWithRegion,
 		},
 
 		Timeouts: &schema.ResourceTimeout{
@@ -26,6 +26,12 @@ func resourceSdrsProtectiongroupV1() *schema.Resource {
 		},
 
 		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
 			"name": {
 				Type:     schema.TypeString,
 				Required: true,
@@ -139,6 +145,7 @@ func resourceSdrsProtectiongroupV1Read(d *schema.ResourceData, meta interface{})
 		return fmt.Errorf("Error retrieving FlexibleEngine SDRS Protectiongroup: %s", err)
 	}
 
+	d.Set("region", GetRegion(d, config))
 	d.Set("name", n.Name)
 	d.Set("description", n.Description)
 	d.Set("source_availability_zone", n.SourceAZ)
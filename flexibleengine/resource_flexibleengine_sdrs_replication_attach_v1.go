@@ -39,6 +39,12 @@ func resourceSdrsReplicationAttachV1() *schema.Resource {
 		},
 
 		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
 			"instance_id": {
 				Type:     schema.TypeString,
 				Required: true,
@@ -128,6 +134,7 @@ func resourceSdrsReplicationAttachV1Read(d *schema.ResourceData, meta interface{
 	}
 	log.Printf("[DEBUG] Retrieved replication attachment: %#v", attach)
 
+	d.Set("region", GetRegion(d, config))
 	d.Set("device", attach.Device)
 	d.Set("replication_id", attach.Replication)
 	d.Set("status", n.Status)
@@ -18,7 +18,7 @@ func resourceSdrsReplicationPairV1() *schema.Resource {
 		Update: resourceSdrsReplicationPairV1Update,
 		Delete: resourceSdrsReplicationPairV1Delete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: importStatePassthroughWithRegion,
 		},
 
 		Timeouts: &schema.ResourceTimeout{
@@ -27,6 +27,12 @@ func resourceSdrsReplicationPairV1() *schema.Resource {
 		},
 
 		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
 			"name": {
 				Type:     schema.TypeString,
 				Required: true,
@@ -134,6 +140,7 @@ func resourceSdrsReplicationPairV1Read(d *schema.ResourceData, meta interface{})
 		return fmt.Errorf("Error retrieving FlexibleEngine SDRS Replication pair: %s", err)
 	}
 
+	d.Set("region", GetRegion(d, config))
 	d.Set("name", n.Name)
 	d.Set("description", n.Description)
 	d.Set("group_id", n.GroupID)
@@ -28,6 +28,8 @@ func resourceSFSTurbo() *schema.Resource {
 			Delete: schema.DefaultTimeout(10 * time.Minute),
 		},
 
+		CustomizeDiff: setEnterpriseProjectDiff,
+
 		Schema: map[string]*schema.Schema{
 			"region": {
 				Type:     schema.TypeString,
@@ -83,6 +85,7 @@ func resourceSFSTurbo() *schema.Resource {
 				Optional: true,
 				ForceNew: true,
 			},
+			"enterprise_project_id": enterpriseProjectSchema(),
 
 			"status": {
 				Type:     schema.TypeString,
@@ -120,6 +123,8 @@ func resourceSFSTurboCreate(d *schema.ResourceData, meta interface{}) error {
 		SubnetID:         d.Get("subnet_id").(string),
 		SecurityGroupID:  d.Get("security_group_id").(string),
 		AvailabilityZone: d.Get("availability_zone").(string),
+
+		EnterpriseProjectId: config.GetEnterpriseProjectID(d),
 	}
 
 	metaOpts := shares.Metadata{}
@@ -175,6 +180,7 @@ func resourceSFSTurboRead(d *schema.ResourceData, meta interface{}) error {
 	d.Set("available_capacity", n.AvailCapacity)
 	d.Set("export_location", n.ExportLocation)
 	d.Set("crypt_key_id", n.CryptKeyID)
+	d.Set("enterprise_project_id", n.EnterpriseProjectId)
 
 	// n.Size is a string of float64, should convert it to int
 	if fsize, err := strconv.ParseFloat(n.Size, 64); err == nil {
@@ -229,6 +235,10 @@ func resourceSFSTurboUpdate(d *schema.ResourceData, meta interface{}) error {
 		}
 	}
 
+	if err := updateEnterpriseProject(d, config, epsResourceTypeSFSTurbo, d.Id(), false); err != nil {
+		return err
+	}
+
 	return resourceSFSTurboRead(d, meta)
 }
 
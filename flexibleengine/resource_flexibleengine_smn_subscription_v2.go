@@ -16,6 +16,12 @@ func resourceSubscription() *schema.Resource {
 		Delete: resourceSubscriptionDelete,
 
 		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
 			"topic_urn": {
 				Type:     schema.TypeString,
 				Required: true,
@@ -136,6 +142,7 @@ func resourceSubscriptionRead(d *schema.ResourceData, meta interface{}) error {
 		}
 	}
 
+	d.Set("region", GetRegion(d, config))
 	log.Printf("[DEBUG] Successfully get subscription %s", id)
 	return nil
 }
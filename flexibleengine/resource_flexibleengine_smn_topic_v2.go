@@ -17,6 +17,12 @@ func resourceTopic() *schema.Resource {
 		Update: resourceTopicUpdate,
 
 		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
 			"name": {
 				Type:     schema.TypeString,
 				Required: true,
@@ -89,6 +95,7 @@ func resourceTopicRead(d *schema.ResourceData, meta interface{}) error {
 
 	log.Printf("[DEBUG] Retrieved topic %s: %#v", topicUrn, topicGet)
 
+	d.Set("region", GetRegion(d, config))
 	d.Set("topic_urn", topicGet.TopicUrn)
 	d.Set("display_name", topicGet.DisplayName)
 	d.Set("name", topicGet.Name)
@@ -1,11 +1,13 @@
 package flexibleengine
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"time"
 
 	"github.com/chnsz/golangsdk"
+	"github.com/chnsz/golangsdk/openstack/common/structs"
 	"github.com/chnsz/golangsdk/openstack/common/tags"
 	"github.com/chnsz/golangsdk/openstack/networking/v1/bandwidths"
 	"github.com/chnsz/golangsdk/openstack/networking/v1/eips"
@@ -26,9 +28,12 @@ func resourceVpcEIPV1() *schema.Resource {
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
 			Delete: schema.DefaultTimeout(10 * time.Minute),
 		},
 
+		CustomizeDiff: resourceVpcEIPV1CustomizeDiff,
+
 		Schema: map[string]*schema.Schema{
 			"region": {
 				Type:     schema.TypeString,
@@ -91,7 +96,13 @@ func resourceVpcEIPV1() *schema.Resource {
 					},
 				},
 			},
-			"tags": tagsSchema(),
+			"tags":                  tagsSchema(),
+			"tags_all":              tagsAllSchema(),
+			"enterprise_project_id": enterpriseProjectSchema(),
+			"charging_mode":         chargingModeSchema(),
+			"period_unit":           periodUnitSchema(),
+			"period":                periodSchema(),
+			"auto_renew":            autoRenewSchema(),
 
 			"address": {
 				Type:     schema.TypeString,
@@ -105,6 +116,15 @@ func resourceVpcEIPV1() *schema.Resource {
 	}
 }
 
+// resourceVpcEIPV1CustomizeDiff rejects changing the period of the yearly/monthly EIP,
+// and plans tags_all with the default tags of provider.
+func resourceVpcEIPV1CustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if err := validatePeriodDiff(d); err != nil {
+		return err
+	}
+	return setTagsAllAndEnterpriseProjectDiff(ctx, d, meta)
+}
+
 func resourceVpcEIPV1Create(d *schema.ResourceData, meta interface{}) error {
 	config := meta.(*Config)
 	networkingClient, err := config.NetworkingV1Client(GetRegion(d, config))
@@ -113,48 +133,91 @@ func resourceVpcEIPV1Create(d *schema.ResourceData, meta interface{}) error {
 	}
 
 	createOpts := eips.ApplyOpts{
-		IP:        resourcePublicIP(d),
-		Bandwidth: resourceBandWidth(d),
+		IP:                  resourcePublicIP(d),
+		Bandwidth:           resourceBandWidth(d),
+		EnterpriseProjectID: config.GetEnterpriseProjectID(d),
 	}
 
-	log.Printf("[DEBUG] Create Options: %#v", createOpts)
-	eIP, err := eips.Apply(networkingClient, createOpts).Extract()
-	if err != nil {
-		return fmt.Errorf("Error allocating EIP: %s", err)
+	var eipID string
+	if isPrePaid(d) {
+		eipID, err = createPrePaidEIP(d, config, createOpts)
+		if err != nil {
+			return err
+		}
+	} else {
+		log.Printf("[DEBUG] Create Options: %#v", createOpts)
+		eIP, err := eips.Apply(networkingClient, createOpts).Extract()
+		if err != nil {
+			return fmt.Errorf("Error allocating EIP: %s", err)
+		}
+		eipID = eIP.ID
 	}
 
-	d.SetId(eIP.ID)
-	log.Printf("[DEBUG] Waiting for EIP %#v to become available.", eIP)
+	d.SetId(eipID)
+	log.Printf("[DEBUG] Waiting for EIP %s to become available.", eipID)
 
 	timeout := d.Timeout(schema.TimeoutCreate)
-	err = waitForEIPActive(networkingClient, eIP.ID, timeout)
+	err = waitForEIPActive(networkingClient, eipID, timeout)
 	if err != nil {
 		return fmt.Errorf(
 			"Error waiting for EIP (%s) to become ready: %s",
-			eIP.ID, err)
+			eipID, err)
 	}
 
-	err = bindToPort(d, eIP.ID, networkingClient, timeout)
+	err = bindToPort(d, eipID, networkingClient, timeout)
 	if err != nil {
-		return fmt.Errorf("Error binding eip:%s to port: %s", eIP.ID, err)
+		return fmt.Errorf("Error binding eip:%s to port: %s", eipID, err)
 	}
 
 	//set tags
-	tagRaw := d.Get("tags").(map[string]interface{})
+	tagRaw := getResourceTags(d, meta)
 	if len(tagRaw) > 0 {
 		vpcV2Client, err := config.NetworkingV2Client(GetRegion(d, config))
 		if err != nil {
 			return fmt.Errorf("Error creating FlexibleEngine vpc client: %s", err)
 		}
 		taglist := expandResourceTags(tagRaw)
-		if tagErr := tags.Create(vpcV2Client, "publicips", eIP.ID, taglist).ExtractErr(); tagErr != nil {
-			return fmt.Errorf("Error setting tags of EIP %s: %s", eIP.ID, tagErr)
+		if tagErr := tags.Create(vpcV2Client, "publicips", eipID, taglist).ExtractErr(); tagErr != nil {
+			return fmt.Errorf("Error setting tags of EIP %s: %s", eipID, tagErr)
 		}
 	}
 
 	return resourceVpcEIPV1Read(d, meta)
 }
 
+// createPrePaidEIP places the order of the yearly/monthly EIP with the VPC v2.0 API, and returns
+// the ID of the EIP once the order is completed. The order is paid automatically.
+func createPrePaidEIP(d *schema.ResourceData, config *Config, createOpts eips.ApplyOpts) (string, error) {
+	if createOpts.Bandwidth.ShareType != "PER" || createOpts.Bandwidth.ChargeMode != "bandwidth" {
+		return "", fmt.Errorf("the EIP must use a dedicated bandwidth billed by bandwidth in %s charging mode", chargingModePrePaid)
+	}
+
+	prePaid, err := expandPrePaidOpts(d)
+	if err != nil {
+		return "", err
+	}
+	createOpts.ExtendParam = &structs.ChargeInfo{
+		ChargeMode:  chargingModePrePaid,
+		PeriodType:  prePaid.PeriodUnit,
+		PeriodNum:   prePaid.Period,
+		IsAutoRenew: prePaid.AutoRenew,
+		IsAutoPay:   "true",
+	}
+
+	// the charging parameters are only accepted by the v2.0 API
+	vpcV2Client, err := config.NetworkingV2Client(GetRegion(d, config))
+	if err != nil {
+		return "", fmt.Errorf("Error creating FlexibleEngine vpc client: %s", err)
+	}
+
+	log.Printf("[DEBUG] Create Options: %#v", createOpts)
+	eIP, err := eips.Apply(vpcV2Client, createOpts).Extract()
+	if err != nil {
+		return "", fmt.Errorf("Error allocating EIP: %s", err)
+	}
+	return waitForOrderResource(d, config, eIP.OrderID)
+}
+
 func resourceVpcEIPV1Read(d *schema.ResourceData, meta interface{}) error {
 	config := meta.(*Config)
 	networkingClient, err := config.NetworkingV1Client(GetRegion(d, config))
@@ -194,6 +257,11 @@ func resourceVpcEIPV1Read(d *schema.ResourceData, meta interface{}) error {
 	d.Set("region", GetRegion(d, config))
 	d.Set("address", eIP.PublicAddress)
 	d.Set("status", normalizeEIPStatus(eIP.Status))
+	d.Set("enterprise_project_id", eIP.EnterpriseProjectID)
+	// the billing mode is not reported by the API unless the EIP is ordered
+	if eIP.OrderID != "" {
+		setChargingMode(d, true)
+	}
 
 	// save tags
 	vpcV2Client, err := config.NetworkingV2Client(GetRegion(d, config))
@@ -203,7 +271,7 @@ func resourceVpcEIPV1Read(d *schema.ResourceData, meta interface{}) error {
 	resourceTags, err := tags.Get(vpcV2Client, "publicips", d.Id()).Extract()
 	if err == nil {
 		tagmap := tagsToMap(resourceTags.Tags)
-		d.Set("tags", tagmap)
+		setResourceTags(d, meta, tagmap)
 	} else {
 		log.Printf("[WARN] fetching EIP %s tags failed: %s", d.Id(), err)
 	}
@@ -256,8 +324,19 @@ func resourceVpcEIPV1Update(d *schema.ResourceData, meta interface{}) error {
 
 	}
 
+	err = updateChargingMode(d, config, d.Id(), func(opts *prePaidOpts) (string, error) {
+		return changeEIPToPeriod(d, config, opts)
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := updateEnterpriseProject(d, config, epsResourceTypeEIP, d.Id(), false); err != nil {
+		return err
+	}
+
 	//update tags
-	if d.HasChange("tags") {
+	if d.HasChange("tags_all") {
 		vpcV2Client, err := config.NetworkingV2Client(GetRegion(d, config))
 		if err != nil {
 			return fmt.Errorf("Error creating FlexibleEngine vpc client: %s", err)
@@ -285,13 +364,28 @@ func resourceVpcEIPV1Delete(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("Error unbinding eip:%s to port: %s", d.Id(), err)
 	}
 
+	eIP, err := eips.Get(networkingClient, d.Id()).Extract()
+	if err != nil {
+		return CheckDeleted(d, err, "eip")
+	}
+
+	refreshFunc := waitForEIPDelete(networkingClient, d.Id())
+	if eIP.OrderID != "" {
+		// the yearly/monthly EIP can only be deleted by unsubscribing, even if
+		// it is waiting to be switched to pay-per-use billing
+		if err := unsubscribePrePaidResource(d, config, []string{d.Id()}); err != nil {
+			return err
+		}
+		refreshFunc = waitForEIPUnsubscribed(networkingClient, d.Id())
+	}
+
 	stateConf := &resource.StateChangeConf{
 		Pending:    []string{"ACTIVE"},
 		Target:     []string{"DELETED"},
-		Refresh:    waitForEIPDelete(networkingClient, d.Id()),
+		Refresh:    refreshFunc,
 		Timeout:    timeout,
-		Delay:      5 * time.Second,
-		MinTimeout: 3 * time.Second,
+		Delay:      stateRefreshDelay(5 * time.Second),
+		MinTimeout: stateRefreshInterval(3 * time.Second),
 	}
 
 	_, err = stateConf.WaitForState()
@@ -304,6 +398,52 @@ func resourceVpcEIPV1Delete(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
+type changeEIPToPeriodOpts struct {
+	PublicIPIDs []string            `json:"publicip_ids" required:"true"`
+	ExtendParam eipPeriodChargeOpts `json:"extendParam" required:"true"`
+}
+
+type eipPeriodChargeOpts struct {
+	PeriodType  string `json:"period_type" required:"true"`
+	PeriodNum   int    `json:"period_num" required:"true"`
+	IsAutoRenew bool   `json:"is_auto_renew"`
+	IsAutoPay   bool   `json:"is_auto_pay"`
+}
+
+// changeEIPToPeriod places the order to change the pay-per-use EIP to yearly/monthly billing,
+// and returns the ID of the order.
+func changeEIPToPeriod(d *schema.ResourceData, config *Config, opts *prePaidOpts) (string, error) {
+	vpcV2Client, err := config.NetworkingV2Client(GetRegion(d, config))
+	if err != nil {
+		return "", fmt.Errorf("Error creating FlexibleEngine vpc client: %s", err)
+	}
+
+	changeOpts := changeEIPToPeriodOpts{
+		PublicIPIDs: []string{d.Id()},
+		ExtendParam: eipPeriodChargeOpts{
+			PeriodType:  opts.PeriodUnit,
+			PeriodNum:   opts.Period,
+			IsAutoRenew: opts.AutoRenew == "true",
+			IsAutoPay:   true,
+		},
+	}
+	reqBody, err := golangsdk.BuildRequestBody(changeOpts, "")
+	if err != nil {
+		return "", err
+	}
+
+	var rst struct {
+		OrderID string `json:"order_id"`
+	}
+	log.Printf("[DEBUG] Changing the charging mode of EIP %s: %#v", d.Id(), changeOpts)
+	url := vpcV2Client.ServiceURL(vpcV2Client.ProjectID, "publicips", "change-to-period")
+	_, err = vpcV2Client.Post(url, reqBody, &rst, &golangsdk.RequestOpts{OkCodes: []int{200}})
+	if err != nil {
+		return "", err
+	}
+	return rst.OrderID, nil
+}
+
 func resourcePublicIP(d *schema.ResourceData) eips.PublicIpOpts {
 	publicIPRaw := d.Get("publicip").([]interface{})
 	rawMap := publicIPRaw[0].(map[string]interface{})
@@ -387,8 +527,8 @@ func waitForEIPActive(networkingClient *golangsdk.ServiceClient, eipID string, t
 		Target:     []string{"ACTIVE"},
 		Refresh:    getEIPStatus(networkingClient, eipID),
 		Timeout:    timeout,
-		Delay:      5 * time.Second,
-		MinTimeout: 3 * time.Second,
+		Delay:      stateRefreshDelay(5 * time.Second),
+		MinTimeout: stateRefreshInterval(3 * time.Second),
 	}
 
 	_, err := stateConf.WaitForState()
@@ -434,3 +574,18 @@ func waitForEIPDelete(networkingClient *golangsdk.ServiceClient, eId string) res
 		return e, "ACTIVE", nil
 	}
 }
+
+// waitForEIPUnsubscribed waits for the unsubscribed EIP to be released
+func waitForEIPUnsubscribed(networkingClient *golangsdk.ServiceClient, eId string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		e, err := eips.Get(networkingClient, eId).Extract()
+		if err != nil {
+			if _, ok := err.(golangsdk.ErrDefault404); ok {
+				log.Printf("[DEBUG] Successfully deleted EIP %s", eId)
+				return e, "DELETED", nil
+			}
+			return e, "ACTIVE", err
+		}
+		return e, "ACTIVE", nil
+	}
+}
@@ -51,6 +51,36 @@ func TestAccVpcV1EIP_basic(t *testing.T) {
 	})
 }
 
+func TestAccVpcV1EIP_prePaid(t *testing.T) {
+	var eip eips.PublicIp
+	rName := fmt.Sprintf("tf-acc-test-%s", acctest.RandString(5))
+	resourceName := "flexibleengine_vpc_eip.eip_1"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheckChargingMode(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVpcV1EIPDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccVpcV1EIP_prePaid(rName, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVpcV1EIPExists(resourceName, &eip),
+					resource.TestCheckResourceAttr(resourceName, "charging_mode", "prePaid"),
+					resource.TestCheckResourceAttr(resourceName, "bandwidth.0.charge_mode", "bandwidth"),
+					resource.TestCheckResourceAttr(resourceName, "auto_renew", "false"),
+				),
+			},
+			{
+				Config: testAccVpcV1EIP_prePaid(rName, true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVpcV1EIPExists(resourceName, &eip),
+					resource.TestCheckResourceAttr(resourceName, "auto_renew", "true"),
+				),
+			},
+		},
+	})
+}
+
 func testAccCheckVpcV1EIPDestroy(s *terraform.State) error {
 	config := testAccProvider.Meta().(*Config)
 	networkingClient, err := config.NetworkingV1Client(OS_REGION_NAME)
@@ -137,3 +167,24 @@ resource "flexibleengine_vpc_eip" "eip_1" {
 }
 `, rName)
 }
+
+func testAccVpcV1EIP_prePaid(rName string, autoRenew bool) string {
+	return fmt.Sprintf(`
+resource "flexibleengine_vpc_eip" "eip_1" {
+  charging_mode = "prePaid"
+  period_unit   = "month"
+  period        = 1
+  auto_renew    = "%t"
+
+  publicip {
+    type = "5_bgp"
+  }
+  bandwidth {
+    share_type  = "PER"
+    name        = "%s"
+    size        = 5
+    charge_mode = "bandwidth"
+  }
+}
+`, autoRenew, rName)
+}
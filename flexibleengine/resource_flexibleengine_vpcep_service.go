@@ -29,6 +29,8 @@ func resourceVPCEndpointService() *schema.Resource {
 			Delete: schema.DefaultTimeout(10 * time.Minute),
 		},
 
+		CustomizeDiff: setTagsAllDiff,
+
 		Schema: map[string]*schema.Schema{
 			"region": {
 				Type:     schema.TypeString,
@@ -127,7 +129,8 @@ func resourceVPCEndpointService() *schema.Resource {
 					},
 				},
 			},
-			"tags": tagsSchema(),
+			"tags":     tagsSchema(),
+			"tags_all": tagsAllSchema(),
 		},
 	}
 }
@@ -183,7 +186,7 @@ func resourceVPCEndpointServiceCreate(d *schema.ResourceData, meta interface{})
 		Ports:       expandPortMappingOpts(d),
 	}
 	//set tags
-	tagRaw := d.Get("tags").(map[string]interface{})
+	tagRaw := getResourceTags(d, meta)
 	if len(tagRaw) > 0 {
 		taglist := expandResourceTags(tagRaw)
 		createOpts.Tags = taglist
@@ -269,7 +272,7 @@ func resourceVPCEndpointServiceRead(d *schema.ResourceData, meta interface{}) er
 	for _, val := range n.Tags {
 		tagmap[val.Key] = val.Value
 	}
-	d.Set("tags", tagmap)
+	setResourceTags(d, meta, tagmap)
 
 	// fetch connections
 	if conns, err := flattenVPCEndpointConnections(vpcepClient, d.Id()); err == nil {
@@ -313,7 +316,7 @@ func resourceVPCEndpointServiceUpdate(d *schema.ResourceData, meta interface{})
 	}
 
 	//update tags
-	if d.HasChange("tags") {
+	if d.HasChange("tags_all") {
 		tagErr := UpdateResourceTags(vpcepClient, d, tagVPCEPService, d.Id())
 		if tagErr != nil {
 			return fmt.Errorf("Error updating tags of VPC endpoint service %s: %s", d.Id(), tagErr)
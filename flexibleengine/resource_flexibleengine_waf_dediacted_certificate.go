@@ -11,7 +11,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/common"
-	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
 )
 
 func ResourceWafDedicatedCertificateV1() *schema.Resource {
@@ -61,7 +60,7 @@ func ResourceWafDedicatedCertificateV1() *schema.Resource {
 }
 
 func resourceWafDedicatedCertificateV1Create(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*config.Config)
+	config := meta.(*Config)
 	wafClient, err := wafDedicatedv1Client(config, config.GetRegion(d))
 	if err != nil {
 		return fmt.Errorf("error creating Flexibleengine WAF Client: %s", err)
@@ -85,7 +84,7 @@ func resourceWafDedicatedCertificateV1Create(d *schema.ResourceData, meta interf
 }
 
 func resourceWafDedicatedCertificateV1Read(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*config.Config)
+	config := meta.(*Config)
 	wafClient, err := wafDedicatedv1Client(config, config.GetRegion(d))
 	if err != nil {
 		return fmt.Errorf("error creating Flexibleengine WAF Client: %s", err)
@@ -106,7 +105,7 @@ func resourceWafDedicatedCertificateV1Read(d *schema.ResourceData, meta interfac
 }
 
 func resourceWafDedicatedCertificateV1Update(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*config.Config)
+	config := meta.(*Config)
 	wafClient, err := wafDedicatedv1Client(config, config.GetRegion(d))
 	if err != nil {
 		return fmt.Errorf("error creating Flexibleengine WAF Client: %s", err)
@@ -124,7 +123,7 @@ func resourceWafDedicatedCertificateV1Update(d *schema.ResourceData, meta interf
 }
 
 func resourceWafDedicatedCertificateV1Delete(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*config.Config)
+	config := meta.(*Config)
 	wafClient, err := wafDedicatedv1Client(config, config.GetRegion(d))
 	if err != nil {
 		return fmt.Errorf("error creating Flexibleengine WAF Client: %s", err)
@@ -15,7 +15,6 @@ import (
 	instances "github.com/chnsz/golangsdk/openstack/waf_hw/v1/premium_instances"
 
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/common"
-	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
 )
 
 const (
@@ -179,7 +178,7 @@ func waitForInstanceCreated(c *golangsdk.ServiceClient, id string) resource.Stat
 }
 
 func resourceDedicatedInstanceCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	conf := meta.(*config.Config)
+	conf := meta.(*Config)
 	client, err := conf.WafDedicatedV1Client(conf.GetRegion(d))
 	if err != nil {
 		return diag.Errorf("error creating Flexibleengine WAF dedicated client : %s", err)
@@ -214,7 +213,7 @@ func resourceDedicatedInstanceCreate(ctx context.Context, d *schema.ResourceData
 }
 
 func resourceDedicatedInstanceRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	config := meta.(*config.Config)
+	config := meta.(*Config)
 	client, err := config.WafDedicatedV1Client(config.GetRegion(d))
 	if err != nil {
 		return diag.Errorf("error creating Flexibleengine WAF dedicated client: %s", err)
@@ -265,7 +264,7 @@ func updateInstanceName(c *golangsdk.ServiceClient, id, name string) error {
 
 func resourceDedicatedInstanceUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	if d.HasChanges("name") {
-		config := meta.(*config.Config)
+		config := meta.(*Config)
 		client, err := config.WafDedicatedV1Client(config.GetRegion(d))
 		if err != nil {
 			return diag.Errorf("error creating Flexibleengine WAF dedicated client: %s", err)
@@ -304,7 +303,7 @@ func waitForInstanceDeleted(c *golangsdk.ServiceClient, id string) resource.Stat
 }
 
 func resourceDedicatedInstanceDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	config := meta.(*config.Config)
+	config := meta.(*Config)
 	client, err := config.WafDedicatedV1Client(config.GetRegion(d))
 	if err != nil {
 		return diag.Errorf("error creating Flexibleengine WAF dedicated client: %s", err)
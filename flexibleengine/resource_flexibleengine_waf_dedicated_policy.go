@@ -8,7 +8,6 @@ import (
 
 	"github.com/chnsz/golangsdk"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/common"
-	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
 
 	"github.com/chnsz/golangsdk/openstack/waf_hw/v1/policies"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -133,7 +132,7 @@ func ResourceWafDedicatedPolicyV1() *schema.Resource {
 }
 
 func resourceWafDedicatedPolicyV1Create(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*config.Config)
+	config := meta.(*Config)
 	wafClient, err := wafDedicatedv1Client(config, config.GetRegion(d))
 	if err != nil {
 		return fmt.Errorf("Error creating Flexibleengine WAF client: %s", err)
@@ -200,7 +199,7 @@ func checkAndUpdateDefaultVal(wafClient *golangsdk.ServiceClient, d *schema.Reso
 }
 
 func resourceWafDedicatedPolicyV1Read(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*config.Config)
+	config := meta.(*Config)
 	wafClient, err := wafDedicatedv1Client(config, config.GetRegion(d))
 	if err != nil {
 		return fmt.Errorf("error creating Flexibleengine WAF client: %s", err)
@@ -240,7 +239,7 @@ func resourceWafDedicatedPolicyV1Read(d *schema.ResourceData, meta interface{})
 }
 
 func resourceWafDedicatedPolicyV1Update(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*config.Config)
+	config := meta.(*Config)
 	wafClient, err := wafDedicatedv1Client(config, config.GetRegion(d))
 	if err != nil {
 		return fmt.Errorf("error creating Flexibleengine WAF Client: %s", err)
@@ -265,7 +264,7 @@ func resourceWafDedicatedPolicyV1Update(d *schema.ResourceData, meta interface{}
 }
 
 func resourceWafDedicatedPolicyV1Delete(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*config.Config)
+	config := meta.(*Config)
 	wafClient, err := wafDedicatedv1Client(config, config.GetRegion(d))
 	if err != nil {
 		return fmt.Errorf("error creating Flexibleengine WAF client: %s", err)
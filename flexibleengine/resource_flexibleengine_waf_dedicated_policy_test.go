@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"testing"
 
-	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/acceptance"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -53,7 +52,7 @@ func TestAccWafDedicatedPolicyV1_basic(t *testing.T) {
 }
 
 func testAccCheckWafDedicatedPolicyV1Destroy(s *terraform.State) error {
-	config := testAccProvider.Meta().(*config.Config)
+	config := testAccProvider.Meta().(*Config)
 	wafClient, err := wafDedicatedv1Client(config, OS_REGION_NAME)
 	if err != nil {
 		return fmt.Errorf("error creating Flexibleengine WAF client: %s", err)
@@ -82,7 +81,7 @@ func testAccCheckWafDedicatedPolicyV1Exists(n string, policy *policies.Policy) r
 			return fmt.Errorf("No ID is set")
 		}
 
-		config := testAccProvider.Meta().(*config.Config)
+		config := testAccProvider.Meta().(*Config)
 		wafClient, err := wafDedicatedv1Client(config, OS_REGION_NAME)
 		if err != nil {
 			return fmt.Errorf("error creating Flexibleengine WAF client: %s", err)
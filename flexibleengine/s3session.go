@@ -47,6 +47,10 @@ func computeS3conn(c *Config, region string) (*s3.S3, error) {
 
 	endpoint := getOssEndpoint(c, region)
 	awsS3Sess := s3Session.Copy(&aws.Config{Endpoint: aws.String(endpoint)})
+	// the session is shared by the Configs, so the audited client is set for each connection
+	if client := auditHTTPClient(c); client != nil {
+		awsS3Sess.Config.HTTPClient = client
+	}
 	s3conn := s3.New(awsS3Sess)
 
 	return s3conn, nil
@@ -0,0 +1,152 @@
+package flexibleengine
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mitchellh/go-homedir"
+	"gopkg.in/ini.v1"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	defaultSharedConfigFile = "~/.flexibleengine/config"
+	defaultProfileName      = "default"
+	endpointKeyPrefix       = "endpoints."
+)
+
+// sharedProfile is a named profile in the shared config file
+type sharedProfile struct {
+	Region        string            `yaml:"region" ini:"region"`
+	AuthURL       string            `yaml:"auth_url" ini:"auth_url"`
+	Cloud         string            `yaml:"cloud" ini:"cloud"`
+	DomainID      string            `yaml:"domain_id" ini:"domain_id"`
+	DomainName    string            `yaml:"domain_name" ini:"domain_name"`
+	TenantID      string            `yaml:"tenant_id" ini:"tenant_id"`
+	TenantName    string            `yaml:"tenant_name" ini:"tenant_name"`
+	AccessKey     string            `yaml:"access_key" ini:"access_key"`
+	SecretKey     string            `yaml:"secret_key" ini:"secret_key"`
+	SecurityToken string            `yaml:"security_token" ini:"security_token"`
+	UserID        string            `yaml:"user_id" ini:"user_id"`
+	UserName      string            `yaml:"user_name" ini:"user_name"`
+	Password      string            `yaml:"password" ini:"password"`
+	Endpoints     map[string]string `yaml:"endpoints" ini:"-"`
+}
+
+// hasCredentials returns whether any authentication info is configured in the profile
+func (p *sharedProfile) hasCredentials() bool {
+	return p.AccessKey != "" || p.Password != ""
+}
+
+// loadSharedProfile reads the named profile from the shared config file.
+// The file can be written in INI format (one section per profile) or in YAML format
+// (one top-level key per profile), the format is determined by the file extension.
+func loadSharedProfile(path, profile string) (*sharedProfile, error) {
+	if path == "" {
+		path = defaultSharedConfigFile
+	}
+	if profile == "" {
+		profile = defaultProfileName
+	}
+
+	configPath, err := homedir.Expand(path)
+	if err != nil {
+		return nil, fmt.Errorf("Error expanding the shared config file path %s: %s", path, err)
+	}
+
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		return nil, fmt.Errorf("the shared config file %s does not exist", configPath)
+	}
+
+	content, err := ioutil.ReadFile(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading the shared config file %s: %s", configPath, err)
+	}
+
+	var result *sharedProfile
+	switch strings.ToLower(filepath.Ext(configPath)) {
+	case ".yaml", ".yml":
+		result, err = parseYAMLProfile(content, profile)
+	default:
+		result, err = parseINIProfile(content, profile)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Error loading profile %s from %s: %s", profile, configPath, err)
+	}
+
+	log.Printf("[DEBUG] loaded profile %s from the shared config file %s", profile, configPath)
+	return result, nil
+}
+
+func parseYAMLProfile(content []byte, profile string) (*sharedProfile, error) {
+	var profiles map[string]*sharedProfile
+	if err := yaml.UnmarshalStrict(content, &profiles); err != nil {
+		return nil, err
+	}
+
+	result, ok := profiles[profile]
+	if !ok || result == nil {
+		return nil, fmt.Errorf("the profile was not found")
+	}
+	return result, nil
+}
+
+func parseINIProfile(content []byte, profile string) (*sharedProfile, error) {
+	file, err := ini.Load(content)
+	if err != nil {
+		return nil, err
+	}
+
+	section, err := file.GetSection(profile)
+	if err != nil {
+		return nil, fmt.Errorf("the profile was not found")
+	}
+
+	var result sharedProfile
+	if err := section.MapTo(&result); err != nil {
+		return nil, err
+	}
+
+	// the custom endpoints are written as "endpoints.<service> = <url>"
+	result.Endpoints = make(map[string]string)
+	for _, key := range section.Keys() {
+		if name := key.Name(); strings.HasPrefix(name, endpointKeyPrefix) {
+			result.Endpoints[strings.TrimPrefix(name, endpointKeyPrefix)] = key.String()
+		}
+	}
+
+	return &result, nil
+}
+
+// mergeSharedProfile fills the fields of Config which are not specified by the provider arguments
+// or environment variables with the values in the shared profile.
+// The authentication info is regarded as a whole, it will be used only when none of the token,
+// password and AK/SK is specified in the provider arguments or environment variables.
+func mergeSharedProfile(c *Config, p *sharedProfile) {
+	setIfEmpty := func(dst *string, src string) {
+		if *dst == "" {
+			*dst = src
+		}
+	}
+
+	setIfEmpty(&c.Region, p.Region)
+	setIfEmpty(&c.IdentityEndpoint, p.AuthURL)
+	setIfEmpty(&c.Cloud, p.Cloud)
+	setIfEmpty(&c.DomainID, p.DomainID)
+	setIfEmpty(&c.DomainName, p.DomainName)
+	setIfEmpty(&c.TenantID, p.TenantID)
+	setIfEmpty(&c.TenantName, p.TenantName)
+
+	if c.Token == "" && c.Password == "" && c.AccessKey == "" && p.hasCredentials() {
+		c.AccessKey = p.AccessKey
+		c.SecretKey = p.SecretKey
+		c.SecurityToken = p.SecurityToken
+		c.UserID = p.UserID
+		c.Username = p.UserName
+		c.Password = p.Password
+	}
+}
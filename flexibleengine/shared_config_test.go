@@ -0,0 +1,131 @@
+package flexibleengine
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testSharedConfigINI = `
+[default]
+region      = eu-west-0
+domain_name = default-domain
+access_key  = default-ak
+secret_key  = default-sk
+
+[dev]
+region      = eu-west-1
+auth_url    = https://iam.eu-west-0.example.com/v3
+domain_name = dev-domain
+tenant_name = eu-west-1_dev
+user_name   = dev-user
+password    = dev-password
+endpoints.ecs = https://ecs.eu-west-1.example.com
+`
+
+const testSharedConfigYAML = `
+default:
+  region: eu-west-0
+  access_key: default-ak
+  secret_key: default-sk
+dev:
+  region: eu-west-1
+  cloud: example.com
+  domain_id: dev-domain-id
+  access_key: dev-ak
+  secret_key: dev-sk
+  endpoints:
+    ecs: https://ecs.eu-west-1.example.com
+`
+
+func writeTestSharedConfig(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("Error writing the shared config file: %s", err)
+	}
+	return path
+}
+
+func TestSharedConfig_loadINI(t *testing.T) {
+	path := writeTestSharedConfig(t, "config", testSharedConfigINI)
+
+	profile, err := loadSharedProfile(path, "")
+	if err != nil {
+		t.Fatalf("Error loading the default profile: %s", err)
+	}
+	if profile.Region != "eu-west-0" || profile.AccessKey != "default-ak" || profile.SecretKey != "default-sk" {
+		t.Fatalf("unexpected default profile: %#v", profile)
+	}
+
+	profile, err = loadSharedProfile(path, "dev")
+	if err != nil {
+		t.Fatalf("Error loading the dev profile: %s", err)
+	}
+	if profile.Region != "eu-west-1" || profile.TenantName != "eu-west-1_dev" || profile.Password != "dev-password" {
+		t.Fatalf("unexpected dev profile: %#v", profile)
+	}
+	if profile.Endpoints["ecs"] != "https://ecs.eu-west-1.example.com" {
+		t.Fatalf("unexpected endpoints in dev profile: %#v", profile.Endpoints)
+	}
+
+	if _, err := loadSharedProfile(path, "prod"); err == nil {
+		t.Fatalf("expect an error when loading a nonexistent profile")
+	}
+}
+
+func TestSharedConfig_loadYAML(t *testing.T) {
+	path := writeTestSharedConfig(t, "config.yaml", testSharedConfigYAML)
+
+	profile, err := loadSharedProfile(path, "dev")
+	if err != nil {
+		t.Fatalf("Error loading the dev profile: %s", err)
+	}
+	if profile.Region != "eu-west-1" || profile.Cloud != "example.com" || profile.DomainID != "dev-domain-id" {
+		t.Fatalf("unexpected dev profile: %#v", profile)
+	}
+	if profile.Endpoints["ecs"] != "https://ecs.eu-west-1.example.com" {
+		t.Fatalf("unexpected endpoints in dev profile: %#v", profile.Endpoints)
+	}
+}
+
+func TestSharedConfig_missingFile(t *testing.T) {
+	path := filepath.Join(os.TempDir(), "flexibleengine-nonexistent-config")
+	if _, err := loadSharedProfile(path, "default"); err == nil {
+		t.Fatalf("expect an error when loading a nonexistent shared config file")
+	}
+}
+
+func TestSharedConfig_merge(t *testing.T) {
+	profile := &sharedProfile{
+		Region:     "eu-west-1",
+		DomainName: "profile-domain",
+		TenantName: "profile-project",
+		AccessKey:  "profile-ak",
+		SecretKey:  "profile-sk",
+	}
+
+	// the explicit arguments take precedence over the profile
+	c := &Config{}
+	c.Region = "eu-west-0"
+	c.Username = "user"
+	c.Password = "password"
+	mergeSharedProfile(c, profile)
+
+	if c.Region != "eu-west-0" {
+		t.Fatalf("expect region eu-west-0, but got %s", c.Region)
+	}
+	if c.DomainName != "profile-domain" || c.TenantName != "profile-project" {
+		t.Fatalf("expect domain and project from profile, but got %s and %s", c.DomainName, c.TenantName)
+	}
+	if c.AccessKey != "" || c.SecretKey != "" {
+		t.Fatalf("the AK/SK in profile should not be used when password is specified")
+	}
+
+	// the credentials are loaded from the profile if none was specified
+	c = &Config{}
+	mergeSharedProfile(c, profile)
+	if c.AccessKey != "profile-ak" || c.SecretKey != "profile-sk" {
+		t.Fatalf("expect AK/SK from profile, but got %s and %s", c.AccessKey, c.SecretKey)
+	}
+}
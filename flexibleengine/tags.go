@@ -1,6 +1,7 @@
 package flexibleengine
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/chnsz/golangsdk"
@@ -22,11 +23,80 @@ func tagsSchema() *schema.Schema {
 	}
 }
 
+// tagsAllSchema returns the schema to use for tags_all, which includes the default tags of provider.
+func tagsAllSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeMap,
+		Computed: true,
+		Elem:     &schema.Schema{Type: schema.TypeString},
+	}
+}
+
+// getDefaultTags returns the default tags configured in the provider.
+func getDefaultTags(meta interface{}) map[string]string {
+	config, ok := meta.(*Config)
+	if !ok {
+		return nil
+	}
+	return config.DefaultTags
+}
+
+// mergeDefaultTags returns the resource tags merged with the default tags of provider,
+// the resource tags take precedence over the default tags.
+func mergeDefaultTags(meta interface{}, tagmap map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{})
+	for k, v := range getDefaultTags(meta) {
+		result[k] = v
+	}
+	for k, v := range tagmap {
+		result[k] = v
+	}
+
+	return result
+}
+
+// getResourceTags returns the tags to be sent when creating a resource, including the default tags.
+func getResourceTags(d *schema.ResourceData, meta interface{}) map[string]interface{} {
+	return mergeDefaultTags(meta, d.Get("tags").(map[string]interface{}))
+}
+
+// setResourceTags sets tags_all to all the tags of a resource, and sets tags to the tags which are
+// configured in the resource or not inherited from the default tags of provider.
+func setResourceTags(d *schema.ResourceData, meta interface{}, tagmap map[string]string) error {
+	defaultTags := getDefaultTags(meta)
+	configured := d.Get("tags").(map[string]interface{})
+
+	resourceTags := make(map[string]string)
+	for k, v := range tagmap {
+		if defaultValue, ok := defaultTags[k]; ok && defaultValue == v {
+			if _, isConfigured := configured[k]; !isConfigured {
+				continue
+			}
+		}
+		resourceTags[k] = v
+	}
+
+	if err := d.Set("tags", resourceTags); err != nil {
+		return err
+	}
+	return d.Set("tags_all", tagmap)
+}
+
+// setTagsAllDiff is a CustomizeDiffFunc which plans tags_all with the tags merged with the default tags.
+func setTagsAllDiff(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("tags") {
+		return d.SetNewComputed("tags_all")
+	}
+
+	return d.SetNew("tags_all", mergeDefaultTags(meta, d.Get("tags").(map[string]interface{})))
+}
+
 // UpdateResourceTags is a helper to update the tags for a resource.
-// It expects the tags field to be named "tags"
+// It expects the tags field to be named "tags" and all the tags including
+// the default tags of provider to be named "tags_all"
 func UpdateResourceTags(conn *golangsdk.ServiceClient, d *schema.ResourceData, resourceType, id string) error {
-	if d.HasChange("tags") {
-		oRaw, nRaw := d.GetChange("tags")
+	if d.HasChange("tags_all") {
+		oRaw, nRaw := d.GetChange("tags_all")
 		oMap := oRaw.(map[string]interface{})
 		nMap := nRaw.(map[string]interface{})
 
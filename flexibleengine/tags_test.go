@@ -0,0 +1,76 @@
+package flexibleengine
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func testTagsConfig(defaultTags map[string]string) *Config {
+	return &Config{DefaultTags: defaultTags}
+}
+
+func TestMergeDefaultTags(t *testing.T) {
+	config := testTagsConfig(map[string]string{
+		"owner": "team-a",
+		"env":   "prod",
+	})
+
+	merged := mergeDefaultTags(config, map[string]interface{}{
+		"env":  "dev",
+		"name": "web",
+	})
+	expected := map[string]interface{}{
+		"owner": "team-a",
+		"env":   "dev",
+		"name":  "web",
+	}
+	if !reflect.DeepEqual(merged, expected) {
+		t.Fatalf("expect the merged tags %v, but got %v", expected, merged)
+	}
+
+	// no default tags configured
+	merged = mergeDefaultTags(&Config{}, map[string]interface{}{"name": "web"})
+	if !reflect.DeepEqual(merged, map[string]interface{}{"name": "web"}) {
+		t.Fatalf("expect the resource tags only, but got %v", merged)
+	}
+}
+
+func TestSetResourceTags(t *testing.T) {
+	config := testTagsConfig(map[string]string{
+		"owner": "team-a",
+		"env":   "prod",
+	})
+	resourceSchema := map[string]*schema.Schema{
+		"tags":     tagsSchema(),
+		"tags_all": tagsAllSchema(),
+	}
+	d := schema.TestResourceDataRaw(t, resourceSchema, map[string]interface{}{
+		"tags": map[string]interface{}{
+			"env":  "prod",
+			"name": "web",
+		},
+	})
+
+	remote := map[string]string{
+		"owner": "team-a",
+		"env":   "prod",
+		"name":  "web",
+	}
+	if err := setResourceTags(d, config, remote); err != nil {
+		t.Fatalf("Error setting tags: %s", err)
+	}
+
+	// the default tag "env" is kept as it was configured in the resource
+	expected := map[string]interface{}{
+		"env":  "prod",
+		"name": "web",
+	}
+	if tags := d.Get("tags").(map[string]interface{}); !reflect.DeepEqual(tags, expected) {
+		t.Fatalf("expect tags %v, but got %v", expected, tags)
+	}
+	if tagsAll := d.Get("tags_all").(map[string]interface{}); len(tagsAll) != 3 {
+		t.Fatalf("expect 3 tags in tags_all, but got %v", tagsAll)
+	}
+}
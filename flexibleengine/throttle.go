@@ -0,0 +1,328 @@
+package flexibleengine
+
+import (
+	"context"
+	"io"
+	"log"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// the base and the maximum delay of the backoff when the requests are throttled (429)
+	throttledBackoffBase = 1 * time.Second
+	throttledBackoffMax  = 30 * time.Second
+	// the base and the maximum delay of the backoff when the service is unavailable (5xx)
+	serverErrorBackoffBase = 1 * time.Second
+	serverErrorBackoffMax  = 10 * time.Second
+	// maxRetryAfter is the upper limit of the delay required by the Retry-After header
+	maxRetryAfter = 2 * time.Minute
+)
+
+var (
+	requestThrottles     []*requestThrottle
+	requestThrottlesLock = new(sync.Mutex)
+)
+
+// requestThrottle is a http.RoundTripper which limits the rate and the concurrency of the
+// requests per service. The throttled (429) responses are retried with a jittered backoff
+// or after the delay of the Retry-After header, the server errors (5xx) are only retried
+// for the idempotent requests.
+type requestThrottle struct {
+	next http.RoundTripper
+
+	// requestsPerSecond is the rate of each service, 0 means unlimited
+	requestsPerSecond int
+	// maxConcurrency is the number of concurrent requests of each service, 0 means unlimited
+	maxConcurrency int
+	// maxRetries is the number of retries of the throttled and unavailable requests
+	maxRetries int
+
+	lock     sync.Mutex
+	services map[string]*serviceThrottle
+}
+
+// serviceThrottle holds the limiters and the statistics of a service
+type serviceThrottle struct {
+	bucket *tokenBucket
+	slots  chan struct{}
+
+	lock         sync.Mutex
+	requests     int
+	throttled    int
+	serverErrors int
+	retries      int
+	waited       time.Duration
+}
+
+// newRequestThrottle creates a throttle in front of the round tripper and registers it to
+// the throttling statistics which are logged when the provider shuts down.
+func newRequestThrottle(next http.RoundTripper, requestsPerSecond, maxConcurrency, maxRetries int) *requestThrottle {
+	throttle := &requestThrottle{
+		next:              next,
+		requestsPerSecond: requestsPerSecond,
+		maxConcurrency:    maxConcurrency,
+		maxRetries:        maxRetries,
+		services:          make(map[string]*serviceThrottle),
+	}
+
+	requestThrottlesLock.Lock()
+	requestThrottles = append(requestThrottles, throttle)
+	requestThrottlesLock.Unlock()
+
+	return throttle
+}
+
+// RoundTrip implements the http.RoundTripper interface
+func (t *requestThrottle) RoundTrip(request *http.Request) (*http.Response, error) {
+	ctx := request.Context()
+	service := t.service(endpointServiceName(request.URL.Host))
+
+	for attempt := 0; ; attempt++ {
+		resp, err := t.roundTrip(ctx, service, request)
+		if err != nil {
+			return nil, err
+		}
+
+		var delay time.Duration
+		switch {
+		case resp.StatusCode == http.StatusTooManyRequests:
+			service.record(func() { service.throttled++ })
+			delay = retryAfter(resp.Header)
+			if delay == 0 {
+				delay = jitteredBackoff(throttledBackoffBase, throttledBackoffMax, attempt)
+			}
+		case resp.StatusCode >= http.StatusInternalServerError:
+			service.record(func() { service.serverErrors++ })
+			if !isIdempotentRequest(request) {
+				return resp, nil
+			}
+			delay = retryAfter(resp.Header)
+			if delay == 0 {
+				delay = jitteredBackoff(serverErrorBackoffBase, serverErrorBackoffMax, attempt)
+			}
+		default:
+			return resp, nil
+		}
+
+		if attempt >= t.maxRetries || (request.Body != nil && request.GetBody == nil) {
+			return resp, nil
+		}
+
+		retry := request.Clone(ctx)
+		if request.GetBody != nil {
+			if retry.Body, err = request.GetBody(); err != nil {
+				return resp, nil
+			}
+		}
+
+		log.Printf("[WARN] received status code %d from %s %s, retry %d/%d in %s",
+			resp.StatusCode, request.Method, request.URL, attempt+1, t.maxRetries, delay)
+		// read till EOF, otherwise the connection will be closed and cannot be reused
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		if err := sleepWithContext(ctx, delay); err != nil {
+			return nil, err
+		}
+		service.record(func() {
+			service.retries++
+			service.waited += delay
+		})
+		request = retry
+	}
+}
+
+// roundTrip sends the request once it is allowed by the limiters of the service
+func (t *requestThrottle) roundTrip(ctx context.Context, service *serviceThrottle, request *http.Request) (*http.Response, error) {
+	start := time.Now()
+	if service.slots != nil {
+		select {
+		case service.slots <- struct{}{}:
+			defer func() { <-service.slots }()
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if service.bucket != nil {
+		if err := service.bucket.wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	waited := time.Since(start)
+	service.record(func() {
+		service.requests++
+		service.waited += waited
+	})
+	return t.next.RoundTrip(request)
+}
+
+// service returns the limiters of the service, they will be created if not exist
+func (t *requestThrottle) service(name string) *serviceThrottle {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	service, ok := t.services[name]
+	if !ok {
+		service = &serviceThrottle{}
+		if t.requestsPerSecond > 0 {
+			service.bucket = newTokenBucket(t.requestsPerSecond)
+		}
+		if t.maxConcurrency > 0 {
+			service.slots = make(chan struct{}, t.maxConcurrency)
+		}
+		t.services[name] = service
+	}
+	return service
+}
+
+func (s *serviceThrottle) record(update func()) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	update()
+}
+
+// LogThrottlingStatistics logs the number of the throttled and retried requests of each
+// service, it should be called when the provider shuts down.
+func LogThrottlingStatistics() {
+	requestThrottlesLock.Lock()
+	defer requestThrottlesLock.Unlock()
+
+	for _, throttle := range requestThrottles {
+		throttle.lock.Lock()
+		names := make([]string, 0, len(throttle.services))
+		for name := range throttle.services {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			s := throttle.services[name]
+			s.lock.Lock()
+			if s.requests > 0 {
+				log.Printf("[INFO] throttling statistics of %s: %d requests, %d throttled, %d server errors, "+
+					"%d retries, waited %s", name, s.requests, s.throttled, s.serverErrors, s.retries,
+					s.waited.Round(time.Millisecond))
+			}
+			s.lock.Unlock()
+		}
+		throttle.lock.Unlock()
+	}
+}
+
+// tokenBucket allows the given number of events per second with a burst of the same size
+type tokenBucket struct {
+	lock   sync.Mutex
+	rate   float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate int) *tokenBucket {
+	return &tokenBucket{
+		rate:   float64(rate),
+		tokens: float64(rate),
+		last:   time.Now(),
+	}
+}
+
+// wait blocks until a token is available or the context is done
+func (b *tokenBucket) wait(ctx context.Context) error {
+	for {
+		b.lock.Lock()
+		now := time.Now()
+		b.tokens = math.Min(b.rate, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+		b.last = now
+		if b.tokens >= 1 {
+			b.tokens--
+			b.lock.Unlock()
+			return nil
+		}
+		delay := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+		b.lock.Unlock()
+
+		if err := sleepWithContext(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// retryAfter returns the delay required by the Retry-After header, the value is either
+// the number of seconds or a HTTP date. It returns 0 if the header is missing or invalid.
+func retryAfter(header http.Header) time.Duration {
+	value := strings.TrimSpace(header.Get("Retry-After"))
+	if value == "" {
+		return 0
+	}
+
+	var delay time.Duration
+	if seconds, err := strconv.Atoi(value); err == nil {
+		delay = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(value); err == nil {
+		delay = time.Until(date)
+	}
+
+	if delay < 0 {
+		return 0
+	}
+	if delay > maxRetryAfter {
+		return maxRetryAfter
+	}
+	return delay
+}
+
+// jitteredBackoff returns a random delay between half and the whole of the exponential
+// backoff of the attempt, the backoff will not exceed the max value.
+func jitteredBackoff(base, max time.Duration, attempt int) time.Duration {
+	backoff := max
+	if attempt < 16 {
+		backoff = time.Duration(math.Min(float64(max), float64(base)*math.Pow(2, float64(attempt))))
+	}
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+}
+
+func isIdempotentRequest(request *http.Request) bool {
+	switch request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// endpointServiceName returns the service name of the endpoint host, e.g. "ecs" of
+// "ecs.eu-west-0.prod-cloud-ocb.orange-business.com". The bucket name is skipped for
+// the virtual-host style OBS endpoints.
+func endpointServiceName(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if net.ParseIP(host) != nil {
+		return host
+	}
+
+	labels := strings.Split(host, ".")
+	if len(labels) > 2 && (labels[1] == "obs" || labels[1] == "oss") {
+		return labels[1]
+	}
+	return labels[0]
+}
+
+func sleepWithContext(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
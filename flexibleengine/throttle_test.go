@@ -0,0 +1,179 @@
+package flexibleengine
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testThrottleServer(t *testing.T, statuses ...int) (*httptest.Server, *int32) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(atomic.AddInt32(&calls, 1))
+		if n <= len(statuses) {
+			if statuses[n-1] == http.StatusTooManyRequests {
+				w.Header().Set("Retry-After", "1")
+			}
+			w.WriteHeader(statuses[n-1])
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func TestRequestThrottle_retryAfter(t *testing.T) {
+	server, calls := testThrottleServer(t, http.StatusTooManyRequests)
+	client := &http.Client{Transport: newRequestThrottle(http.DefaultTransport, 0, 0, 2)}
+
+	start := time.Now()
+	resp, err := client.Post(server.URL, "application/json", strings.NewReader(`{"name":"test"}`))
+	if err != nil {
+		t.Fatalf("Error sending the request: %s", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status code 200, got %d", resp.StatusCode)
+	}
+	if *calls != 2 {
+		t.Fatalf("expected 2 calls, got %d", *calls)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Fatalf("expected the retry to wait for the Retry-After delay, waited %s", elapsed)
+	}
+}
+
+func TestRequestThrottle_serverError(t *testing.T) {
+	server, calls := testThrottleServer(t, http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+	client := &http.Client{Transport: newRequestThrottle(http.DefaultTransport, 0, 0, 2)}
+
+	// the non-idempotent requests are not retried
+	resp, err := client.Post(server.URL, "application/json", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatalf("Error sending the request: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable || *calls != 1 {
+		t.Fatalf("expected a single call with status code 503, got %d calls with %d", *calls, resp.StatusCode)
+	}
+
+	resp, err = client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Error sending the request: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || *calls != 3 {
+		t.Fatalf("expected 3 calls with status code 200, got %d calls with %d", *calls, resp.StatusCode)
+	}
+}
+
+func TestRequestThrottle_maxRetries(t *testing.T) {
+	server, calls := testThrottleServer(t, http.StatusBadGateway, http.StatusBadGateway)
+	client := &http.Client{Transport: newRequestThrottle(http.DefaultTransport, 0, 0, 0)}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Error sending the request: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadGateway || *calls != 1 {
+		t.Fatalf("expected a single call with status code 502, got %d calls with %d", *calls, resp.StatusCode)
+	}
+}
+
+func TestRequestThrottle_limits(t *testing.T) {
+	var current, peak int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&current, 1)
+		defer atomic.AddInt32(&current, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	throttle := newRequestThrottle(http.DefaultTransport, 10, 2, 0)
+	client := &http.Client{Transport: throttle}
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 15; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if resp, err := client.Get(server.URL); err == nil {
+				resp.Body.Close()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if peak := atomic.LoadInt32(&peak); peak > 2 {
+		t.Fatalf("expected at most 2 concurrent requests, got %d", peak)
+	}
+	// the burst of 10 requests is sent at once, the other 5 requests wait for the tokens
+	if elapsed := time.Since(start); elapsed < 400*time.Millisecond {
+		t.Fatalf("expected the requests to be limited to 10 per second, all sent in %s", elapsed)
+	}
+	if requests := throttle.service(endpointServiceName(server.Listener.Addr().String())).requests; requests != 15 {
+		t.Fatalf("expected 15 requests in the statistics, got %d", requests)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	cases := map[string]time.Duration{
+		"":          0,
+		"3":         3 * time.Second,
+		"-1":        0,
+		"invalid":   0,
+		"100000000": maxRetryAfter,
+	}
+
+	for value, expected := range cases {
+		header := http.Header{}
+		header.Set("Retry-After", value)
+		if delay := retryAfter(header); delay != expected {
+			t.Errorf("expected the delay of %q to be %s, got %s", value, expected, delay)
+		}
+	}
+
+	header := http.Header{}
+	header.Set("Retry-After", time.Now().Add(time.Minute).UTC().Format(http.TimeFormat))
+	if delay := retryAfter(header); delay <= 50*time.Second || delay > time.Minute {
+		t.Errorf("expected the delay of the HTTP date to be about 1 minute, got %s", delay)
+	}
+}
+
+func TestJitteredBackoff(t *testing.T) {
+	for attempt := 0; attempt < 40; attempt++ {
+		delay := jitteredBackoff(time.Second, 30*time.Second, attempt)
+		if delay < 500*time.Millisecond || delay > 30*time.Second {
+			t.Errorf("the backoff of attempt %d is out of range: %s", attempt, delay)
+		}
+	}
+}
+
+func TestEndpointServiceName(t *testing.T) {
+	cases := map[string]string{
+		"ecs.eu-west-0.prod-cloud-ocb.orange-business.com":           "ecs",
+		"iam.eu-west-0.prod-cloud-ocb.orange-business.com:443":       "iam",
+		"my-bucket.oss.eu-west-0.prod-cloud-ocb.orange-business.com": "oss",
+		"127.0.0.1:8080": "127.0.0.1",
+	}
+
+	for host, expected := range cases {
+		if name := endpointServiceName(host); name != expected {
+			t.Errorf("expected the service name of %s to be %s, got %s", host, expected, name)
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"reflect"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/chnsz/golangsdk"
 	"github.com/hashicorp/go-uuid"
@@ -63,6 +64,26 @@ func GetRegion(d *schema.ResourceData, config *Config) string {
 	return config.Region
 }
 
+// importStatePassthroughWithRegion imports the resource by ID, the ID can be prefixed
+// with the region in the format of <region>/<id> when the resource is not in the
+// provider-level region.
+func importStatePassthroughWithRegion(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.Split(d.Id(), "/")
+	switch len(parts) {
+	case 1:
+		return []*schema.ResourceData{d}, nil
+	case 2:
+		if parts[0] == "" || parts[1] == "" {
+			break
+		}
+		d.SetId(parts[1])
+		d.Set("region", parts[0])
+		return []*schema.ResourceData{d}, nil
+	}
+
+	return nil, fmt.Errorf("Invalid format specified for import ID %q. Format must be <id> or <region>/<id>", d.Id())
+}
+
 func checkForRetryableError(err error) *resource.RetryError {
 	switch errCode := err.(type) {
 	case golangsdk.ErrDefault500:
@@ -200,3 +221,15 @@ func IsUUIDFormat(str string) bool {
 	}
 	return true
 }
+
+// stateRefreshDelay returns the delay to wait before the first refresh of a state change.
+// The unit tests replace it to skip the waiting against the mock cloud.
+var stateRefreshDelay = func(delay time.Duration) time.Duration {
+	return delay
+}
+
+// stateRefreshInterval returns the interval between the refreshes of a state change, which is used
+// as the PollInterval or MinTimeout. The unit tests replace it as well as stateRefreshDelay.
+var stateRefreshInterval = func(interval time.Duration) time.Duration {
+	return interval
+}
@@ -0,0 +1,36 @@
+package flexibleengine
+
+import (
+	"testing"
+)
+
+func TestImportStatePassthroughWithRegion(t *testing.T) {
+	cases := []struct {
+		importID string
+		id       string
+		region   string
+	}{
+		{"7056d636-ac60-4663-8a6c-82d3c32c1c64", "7056d636-ac60-4663-8a6c-82d3c32c1c64", ""},
+		{"eu-west-1/7056d636-ac60-4663-8a6c-82d3c32c1c64", "7056d636-ac60-4663-8a6c-82d3c32c1c64", "eu-west-1"},
+	}
+
+	for _, tc := range cases {
+		d := resourceKmsKeyV1().TestResourceData()
+		d.SetId(tc.importID)
+		if _, err := importStatePassthroughWithRegion(d, nil); err != nil {
+			t.Fatalf("Error importing %s: %s", tc.importID, err)
+		}
+		if d.Id() != tc.id || d.Get("region").(string) != tc.region {
+			t.Errorf("expected %s to be imported as %s in region %q, got %s in region %q",
+				tc.importID, tc.id, tc.region, d.Id(), d.Get("region"))
+		}
+	}
+
+	for _, importID := range []string{"eu-west-1/", "/7056d636", "eu-west-1/group/7056d636"} {
+		d := resourceKmsKeyV1().TestResourceData()
+		d.SetId(importID)
+		if _, err := importStatePassthroughWithRegion(d, nil); err == nil {
+			t.Errorf("expected an error of the invalid import ID %s", importID)
+		}
+	}
+}
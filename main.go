@@ -16,5 +16,7 @@ func main() {
 	plugin.Serve(&plugin.ServeOpts{
 		ProviderFunc: flexibleengine.Provider})
 
+	flexibleengine.LogThrottlingStatistics()
+	flexibleengine.CloseAuditLogs()
 	obs.CloseLog()
 }